@@ -0,0 +1,149 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+)
+
+// The abuse detector watches for pathological patterns that the
+// per-request budgets miss because every individual request looks
+// legitimate: agent loops that hit the iteration limit turn after turn,
+// mutations the user keeps denying, tools erroring over and over. A user
+// crossing a threshold gets an automatic cool-down — their chat turns
+// are rejected until it expires — protecting provider spend and the
+// backing database while the rate limits stay generous for everyone
+// else. Cool-downs are per API server replica, like the in-memory rate
+// limiter backend.
+const (
+	// abuseWindow is the sliding window the signal counts apply over.
+	abuseWindow = 10 * time.Minute
+	// abuseCooldown is how long a triggered user's turns are rejected.
+	abuseCooldown = 15 * time.Minute
+)
+
+// abuseSignal names one kind of pathological behavior.
+type abuseSignal string
+
+const (
+	// abuseSignalLoopLimit is a turn that ran into maxLoopIterations.
+	abuseSignalLoopLimit abuseSignal = "loop_limit"
+	// abuseSignalDeniedMutation is a mutating call the user denied.
+	abuseSignalDeniedMutation abuseSignal = "denied_mutation"
+	// abuseSignalToolError is a tool execution that returned an error.
+	abuseSignalToolError abuseSignal = "tool_error"
+)
+
+// abuseThresholds is how many occurrences of a signal within
+// abuseWindow trigger a cool-down. Tool errors are common during normal
+// investigation, so their threshold sits much higher.
+var abuseThresholds = map[abuseSignal]int{
+	abuseSignalLoopLimit:      3,
+	abuseSignalDeniedMutation: 5,
+	abuseSignalToolError:      25,
+}
+
+// abuseDetector counts abuse signals per user over a sliding window. It
+// is safe for concurrent use.
+type abuseDetector struct {
+	mu    sync.Mutex
+	users map[string]*abuseRecord
+	// now is replaceable in tests.
+	now func() time.Time
+}
+
+type abuseRecord struct {
+	signals       map[abuseSignal][]time.Time
+	cooldownUntil time.Time
+}
+
+func newAbuseDetector() *abuseDetector {
+	return &abuseDetector{users: map[string]*abuseRecord{}, now: time.Now}
+}
+
+// note records one signal and reports whether it began a new cool-down.
+// Signals keep being counted during a cool-down, but do not extend it;
+// the user gets a full window of goodwill back when it expires.
+func (d *abuseDetector) note(userId string, signal abuseSignal) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	record := d.users[userId]
+	if record == nil {
+		record = &abuseRecord{signals: map[abuseSignal][]time.Time{}}
+		d.users[userId] = record
+	}
+	kept := record.signals[signal][:0]
+	for _, at := range record.signals[signal] {
+		if now.Sub(at) < abuseWindow {
+			kept = append(kept, at)
+		}
+	}
+	kept = append(kept, now)
+	record.signals[signal] = kept
+	if now.Before(record.cooldownUntil) {
+		return false
+	}
+	if len(kept) >= abuseThresholds[signal] {
+		record.cooldownUntil = now.Add(abuseCooldown)
+		return true
+	}
+	return false
+}
+
+// cooldownRemaining returns how much of the user's cool-down is left;
+// zero means the user is not cooling down.
+func (d *abuseDetector) cooldownRemaining(userId string) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	record := d.users[userId]
+	if record == nil {
+		return 0
+	}
+	remaining := record.cooldownUntil.Sub(d.now())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// noteAbuseSignal feeds the detector and, when the signal begins a
+// cool-down, alerts operators through the log and the audit trail. Safe
+// on servers constructed without a detector.
+func (s *AIServer) noteAbuseSignal(session *Session, signal abuseSignal) {
+	if s.abuse == nil {
+		return
+	}
+	if !s.abuse.note(session.UserID, signal) {
+		return
+	}
+	detail := "repeated " + string(signal) + " signals within " + abuseWindow.String() +
+		"; chat turns are rejected for " + abuseCooldown.String()
+	glog.Warningf("AI abuse detector: cooling down user %v (session %v): %v", session.UserID, session.ID, detail)
+	if s.audit != nil {
+		s.audit.Record(&model.AIAuditEvent{
+			UserID:      session.UserID,
+			SessionUUID: session.ID,
+			Namespace:   session.Namespace,
+			Tool:        "abuse_detector",
+			Outcome:     actionCooledDown,
+			Detail:      detail,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// frozenDetector returns a detector with a controllable clock.
+func frozenDetector(start time.Time) (*abuseDetector, *time.Time) {
+	clock := start
+	d := newAbuseDetector()
+	d.now = func() time.Time { return clock }
+	return d, &clock
+}
+
+func TestAbuseDetector_TriggersAtTheThreshold(t *testing.T) {
+	d, _ := frozenDetector(time.Now())
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	assert.True(t, d.note("user", abuseSignalLoopLimit))
+	assert.True(t, d.cooldownRemaining("user") > 0)
+	// Other users are unaffected.
+	assert.Equal(t, time.Duration(0), d.cooldownRemaining("other"))
+}
+
+func TestAbuseDetector_ForgetsSignalsOutsideTheWindow(t *testing.T) {
+	d, clock := frozenDetector(time.Now())
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	// The first two signals age out before the third arrives.
+	*clock = clock.Add(abuseWindow + time.Second)
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	assert.Equal(t, time.Duration(0), d.cooldownRemaining("user"))
+}
+
+func TestAbuseDetector_DoesNotRetriggerWhileCooling(t *testing.T) {
+	d, clock := frozenDetector(time.Now())
+	for i := 0; i < 2; i++ {
+		assert.False(t, d.note("user", abuseSignalLoopLimit))
+	}
+	assert.True(t, d.note("user", abuseSignalLoopLimit))
+	// More signals during the cool-down do not start a second one.
+	assert.False(t, d.note("user", abuseSignalLoopLimit))
+	assert.False(t, d.note("user", abuseSignalDeniedMutation))
+	// The cool-down runs out on schedule despite the extra signals.
+	*clock = clock.Add(abuseCooldown + time.Second)
+	assert.Equal(t, time.Duration(0), d.cooldownRemaining("user"))
+}
+
+func TestAbuseDetector_SignalsCountIndependently(t *testing.T) {
+	d, _ := frozenDetector(time.Now())
+	// Two of each signal stays under every threshold even though the
+	// total exceeds the loop-limit threshold.
+	for _, signal := range []abuseSignal{abuseSignalLoopLimit, abuseSignalDeniedMutation, abuseSignalToolError} {
+		assert.False(t, d.note("user", signal))
+		assert.False(t, d.note("user", signal))
+	}
+	assert.Equal(t, time.Duration(0), d.cooldownRemaining("user"))
+}
+
+func TestNoteAbuseSignal_ToleratesAServerWithoutADetector(t *testing.T) {
+	s := &AIServer{}
+	// Must not panic; fixture servers are constructed literally.
+	s.noteAbuseSignal(&Session{ID: "s1", UserID: "user"}, abuseSignalLoopLimit)
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+// Every tool call a session executes (or refuses to execute) is appended
+// to a bounded per-session action trace. The explain_last_actions tool
+// renders that trace verbatim, so when the user asks "what did you just
+// do?" the answer comes from the server-side audit record rather than
+// the model's recollection of its own turn.
+
+const (
+	// explainActionsToolName is the built-in introspection capability.
+	// It is session-scoped and answered from the action trace, so it is
+	// intercepted by the server instead of living in the tool registry.
+	explainActionsToolName = "explain_last_actions"
+	// maxActionRecords caps the per-session trace; older actions fall off.
+	maxActionRecords = 50
+)
+
+// Action outcomes. An action is recorded exactly once, at the point its
+// outcome is known.
+const (
+	actionSucceeded = "succeeded"
+	actionFailed    = "failed"
+	// actionDenied means the user rejected the confirmation request.
+	actionDenied = "denied_by_user"
+	// actionUnauthorized means the call was blocked by a permission check,
+	// either the Agent-mode gate or an RBAC denial from the backend.
+	actionUnauthorized = "unauthorized"
+	// actionEscalated means the confirmation stayed pending past the
+	// escalation deadline; the call's final outcome is recorded
+	// separately once known.
+	actionEscalated = "escalated"
+	// actionCooledDown means the abuse detector started rejecting the
+	// user's chat turns; it is audited under the synthetic tool name
+	// "abuse_detector" rather than a real invocation.
+	actionCooledDown = "cooled_down"
+)
+
+// ActionRecord is one audited tool invocation of a session.
+type ActionRecord struct {
+	Tool          string `json:"tool"`
+	ArgumentsJSON string `json:"arguments_json,omitempty"`
+	Mutating      bool   `json:"mutating"`
+	// Outcome is one of the action* constants above.
+	Outcome string `json:"outcome"`
+	// Detail carries the compacted failure or denial reason.
+	Detail string `json:"detail,omitempty"`
+	// ApprovedBy is the resolved display name of the user who confirmed
+	// a mutating call, for "who approved this" reporting.
+	ApprovedBy string `json:"approved_by,omitempty"`
+	// Time is the epoch second the outcome was recorded at.
+	Time int64 `json:"time"`
+}
+
+// recordAction appends an action to the session's trace, dropping the
+// oldest entries beyond the cap.
+func (s *Session) recordAction(record ActionRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record.Time = time.Now().Unix()
+	s.actions = append(s.actions, record)
+	if over := len(s.actions) - maxActionRecords; over > 0 {
+		s.actions = s.actions[over:]
+	}
+}
+
+// actionsSnapshot returns a copy of the session's action trace, oldest
+// first.
+func (s *Session) actionsSnapshot() []ActionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ActionRecord, len(s.actions))
+	copy(out, s.actions)
+	return out
+}
+
+// actionOutcomeFor classifies a tool execution error: permission errors
+// are distinguished so RBAC denials show up as such in the trace.
+func actionOutcomeFor(err error) string {
+	if category, _ := classifyError(err); category == categoryPermission {
+		return actionUnauthorized
+	}
+	return actionFailed
+}
+
+// actionDetailFor extracts the compacted, model-safe reason from a tool
+// execution error.
+func actionDetailFor(err error) string {
+	_, detail := classifyError(err)
+	return compactText(detail)
+}
+
+// explainActionsTool exposes the session's action trace to the model.
+// Unlike registry tools it holds a session reference, so the server
+// constructs one per turn and resolves its calls itself.
+type explainActionsTool struct {
+	session *Session
+}
+
+func (t *explainActionsTool) Name() string { return explainActionsToolName }
+
+func (t *explainActionsTool) Description() string {
+	return "List the tool calls this conversation actually executed, including failures, user denials and permission " +
+		"rejections, from the server-side audit trail. Use this when the user asks what you did or changed; " +
+		"present the returned actions verbatim and never report an action that is not in the list."
+}
+
+type explainActionsArgs struct{}
+
+func (t *explainActionsTool) InputSchema() map[string]interface{} {
+	return tool.SchemaFor(&explainActionsArgs{})
+}
+
+func (t *explainActionsTool) Mutating() bool { return false }
+
+func (t *explainActionsTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	return tool.JSONResult(map[string]interface{}{
+		"actions": t.session.actionsSnapshot(),
+		"note": "This list is the complete audit record of this session's tool activity. " +
+			"Do not describe any action that is not listed here.",
+	})
+}
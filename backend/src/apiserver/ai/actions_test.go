@@ -0,0 +1,126 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRbacTool fails every execution with a permission error, standing
+// in for a backend RBAC denial.
+type stubRbacTool struct{}
+
+func (t *stubRbacTool) Name() string        { return "stub_rbac" }
+func (t *stubRbacTool) Description() string { return "Always rejected by RBAC." }
+func (t *stubRbacTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *stubRbacTool) Mutating() bool { return false }
+
+func (t *stubRbacTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	return nil, util.NewPermissionDeniedError(errors.New("forbidden"), "User alice is not allowed to list runs")
+}
+
+func actionTraceFixture(t *testing.T, mode tool.Mode) (*AIServer, *Session) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	require.NoError(t, server.registry.Register(&stubBatchTool{}))
+	require.NoError(t, server.registry.Register(&stubRbacTool{}))
+	require.NoError(t, server.registry.Register(&stubValidatedTool{}))
+	session, err := m.GetOrCreate("", "alice", "ns", mode, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestActionTrace_RecordsEachOutcomeOnce(t *testing.T) {
+	server, session := actionTraceFixture(t, tool.ModeAgent)
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			payload := e.Payload.(*ConfirmationRequestPayload)
+			require.NoError(t, session.resolveConfirmation(payload.ToolCallID, false))
+		}
+		return nil
+	}
+
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_batch", ArgumentsJSON: `{"x":1}`}, emit)
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc2", Name: "stub_rbac", ArgumentsJSON: "{}"}, emit)
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc3", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	actions := session.actionsSnapshot()
+	require.Len(t, actions, 3)
+	assert.Equal(t, "stub_batch", actions[0].Tool)
+	assert.Equal(t, actionSucceeded, actions[0].Outcome)
+	assert.Equal(t, `{"x":1}`, actions[0].ArgumentsJSON)
+	assert.Equal(t, actionUnauthorized, actions[1].Outcome)
+	assert.Contains(t, actions[1].Detail, "not allowed")
+	assert.Equal(t, actionDenied, actions[2].Outcome)
+	assert.True(t, actions[2].Mutating)
+}
+
+func TestActionTrace_ModeGateIsRecordedAsUnauthorized(t *testing.T) {
+	server, session := actionTraceFixture(t, tool.ModeAsk)
+	emit := func(Event) error { return nil }
+
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	assert.True(t, result.IsError)
+	actions := session.actionsSnapshot()
+	require.Len(t, actions, 1)
+	assert.Equal(t, actionUnauthorized, actions[0].Outcome)
+	assert.Contains(t, actions[0].Detail, "Agent mode")
+}
+
+func TestActionTrace_IsCapped(t *testing.T) {
+	session := &Session{}
+	for i := 0; i < maxActionRecords+5; i++ {
+		session.recordAction(ActionRecord{Tool: fmt.Sprintf("t%v", i), Outcome: actionSucceeded})
+	}
+
+	actions := session.actionsSnapshot()
+	require.Len(t, actions, maxActionRecords)
+	// The oldest records fall off.
+	assert.Equal(t, "t5", actions[0].Tool)
+}
+
+func TestExplainLastActions_RendersTheRecordedTrace(t *testing.T) {
+	server, session := actionTraceFixture(t, tool.ModeAgent)
+	emit := func(Event) error { return nil }
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_rbac", ArgumentsJSON: "{}"}, emit)
+
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc2", Name: explainActionsToolName, ArgumentsJSON: "{}"}, emit)
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content, `"stub_rbac"`)
+	assert.Contains(t, result.Content, `"unauthorized"`)
+	// Introspection itself is not part of the audit trace.
+	actions := session.actionsSnapshot()
+	require.Len(t, actions, 1)
+	assert.Equal(t, "stub_rbac", actions[0].Tool)
+}
@@ -0,0 +1,1176 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ai implements the Kubeflow Pipelines AI assistant: chat
+// sessions, the agentic tool-execution loop and the SSE endpoints that
+// the frontend consumes.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/audit"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/quota"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	// maxLoopIterations bounds the tool-use loop within a single turn so
+	// a confused model cannot spin forever.
+	maxLoopIterations = 10
+	// confirmationTimeout is how long a mutating tool call waits for the
+	// user's approval before being treated as denied.
+	confirmationTimeout = 10 * time.Minute
+	// partialSalvageMinChars is how much assistant text must have streamed
+	// before a mid-stream provider failure is salvaged instead of failing
+	// the whole turn.
+	partialSalvageMinChars = 200
+)
+
+// continuationPrompt asks the model to resume a salvaged partial answer.
+const continuationPrompt = "The previous response was interrupted mid-stream. Continue exactly where it stopped without repeating earlier text."
+
+// AIServer orchestrates chat turns: it drives the model, executes tool
+// calls and streams events back to the caller.
+type AIServer struct {
+	resourceManager *resource.ResourceManager
+	sessionManager  *SessionManager
+	registry        *tool.Registry
+	model           provider.ChatModel
+	// readOnly marks a deployment whose registry was stripped of mutating
+	// tools at startup; mode switches and approvals are rejected outright.
+	readOnly bool
+	// quota enforces the configured token budgets; nil disables them.
+	quota *quota.Tracker
+	// usage persists per-invocation token consumption for chargeback;
+	// nil disables durable usage records.
+	usage *usageRecorder
+	// tokenizer estimates token counts for providers that do not report
+	// usage on their streams; nil disables the estimate.
+	tokenizer    tokenizer.Tokenizer
+	watcher      *runWatcher
+	logLinks     *logLinkSigner
+	quickActions *quickActionCatalog
+	templates    *templateCatalog
+	// freezes blocks mutating tools during configured change freeze
+	// windows; nil disables the policy.
+	freezes *freezePolicy
+	// capabilities are the provider's tool-calling limits; tool
+	// definitions are adapted to them per request.
+	capabilities provider.Capabilities
+	// shedder admits, degrades or sheds new turns based on the in-flight
+	// count and provider latency; nil disables load shedding.
+	shedder *loadShedder
+	// clusters resolves remote installations read-only tool calls can be
+	// forwarded to; nil disables federation.
+	clusters *clusterDirectory
+	// audit persists every tool invocation for security review; nil
+	// disables the durable log. Attached at startup like the session
+	// store.
+	audit *audit.Logger
+	// sandbox replaces the real resource manager for every tool
+	// execution when set, so mutations land on seeded fake data instead
+	// of real workloads; nil disables sandbox mode.
+	sandbox *resource.ResourceManager
+
+	// mu guards the settings below, which a config reload swaps while
+	// turns are running.
+	mu sync.Mutex
+	// toolTimeout bounds a single tool execution; zero disables the
+	// deadline.
+	toolTimeout time.Duration
+	// contextTokens caps the estimated token count of the history sent
+	// per model invocation; zero disables the budget.
+	contextTokens int
+	// capsFingerprint summarizes the capability-affecting configuration;
+	// capsGen increments whenever it changes, so running turns notice
+	// and refresh their tool set on the next iteration.
+	capsFingerprint string
+	capsGen         int64
+	// maxTurnsPerUser caps the turns one user may have running at the
+	// same time across their sessions; activeTurns tracks the current
+	// count per user. Zero disables the cap.
+	maxTurnsPerUser int
+	activeTurns     map[string]int
+	// escalation is what happens to a confirmation pending past its
+	// deadline; a zero deadline disables escalation.
+	escalation escalationPolicy
+	// groundingCheck enables the post-generation resource ID check in
+	// grounding.go.
+	groundingCheck bool
+	// abuse cools down users generating pathological signal patterns;
+	// nil disables detection.
+	abuse *abuseDetector
+}
+
+// ChatRequest is the body of a chat turn request.
+type ChatRequest struct {
+	// SessionID resumes an existing session; empty starts a new one.
+	SessionID string `json:"session_id,omitempty"`
+	// Message is the user's input for this turn.
+	Message string `json:"message"`
+	// Mode selects ask (read-only) or agent (mutating with confirmation).
+	// Only honored when a new session is created.
+	Mode string `json:"mode,omitempty"`
+	// Namespace scopes the session in multi-user mode.
+	Namespace string `json:"namespace,omitempty"`
+	// Timezone is the user's IANA timezone name, e.g. "Europe/Berlin".
+	// Timestamps in tool results are rendered in this zone. Only honored
+	// when a new session is created; defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// DisableTelemetry opts the session out of usage analytics and
+	// transcript persistence, keeping only operational metrics. It may be
+	// set on any turn; once set it sticks for the session's lifetime.
+	DisableTelemetry bool `json:"disable_telemetry,omitempty"`
+	// QuickAction invokes a catalog quick action by ID instead of free
+	// text; its expanded template replaces Message.
+	QuickAction string `json:"quick_action,omitempty"`
+	// QuickActionParameters fills the action's template parameters.
+	QuickActionParameters map[string]string `json:"quick_action_parameters,omitempty"`
+	// EventTypes limits which event types this connection receives, e.g.
+	// only markdown and errors for a bot. Empty means everything. Error
+	// and done events are always delivered. The filter applies to the
+	// connection only; the session's replay buffer keeps every event.
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+// NewAIServer wires the assistant against the shared resource manager.
+func NewAIServer(resourceManager *resource.ResourceManager, model provider.ChatModel, cfg *config.Config) *AIServer {
+	if cfg.Limits.ProviderStallTimeout > 0 {
+		model = newStreamWatchdog(model, cfg.Limits.ProviderStallTimeout)
+	}
+	sessionManager := NewSessionManager(cfg)
+	watcher := newRunWatcher(resourceManager, sessionManager)
+	go watcher.watchLoop()
+	registry := tool.NewRegistry()
+	if cfg.ReadOnly {
+		// The stripped tools are gone for the lifetime of the process, on
+		// every serving surface sharing this registry.
+		glog.Infof("AI assistant is deployed read-only: stripping mutating tools from the registry")
+		registry.StripMutating()
+	}
+	templates := newTemplateCatalog(cfg)
+	if err := registry.Register(&pipelineTemplatesTool{catalog: templates}); err != nil {
+		// Only possible if a builtin tool claims the same name.
+		glog.Errorf("Failed to register the pipeline template tool: %v", err)
+	}
+	// A model that knows its own limits (e.g. a fallback chain) beats the
+	// published per-provider defaults.
+	capabilities := provider.CapabilitiesFor(cfg.Provider.Name)
+	if reporter, ok := model.(provider.CapabilityReporter); ok {
+		capabilities = reporter.Capabilities()
+	}
+	return &AIServer{
+		resourceManager: resourceManager,
+		sessionManager:  sessionManager,
+		registry:        registry,
+		model:           model,
+		readOnly:        cfg.ReadOnly,
+		quota:           quota.NewTracker(budgetLimits(cfg)),
+		tokenizer:       tokenizer.ForProvider(cfg.Provider.Name),
+		watcher:         watcher,
+		logLinks:        newLogLinkSigner(),
+		quickActions:    newQuickActionCatalog(cfg),
+		templates:       templates,
+		freezes:         newFreezePolicy(cfg),
+		capabilities:    capabilities,
+		shedder:         newLoadShedder(cfg.Limits),
+		clusters:        newClusterDirectory(cfg),
+		toolTimeout:     cfg.Limits.ToolTimeout,
+		contextTokens:   cfg.Limits.ContextTokenBudget,
+		capsFingerprint: capabilityFingerprint(cfg),
+		maxTurnsPerUser: cfg.Limits.MaxTurnsPerUser,
+		activeTurns:     map[string]int{},
+		escalation:      escalationPolicyFor(cfg),
+		groundingCheck:  cfg.GroundingCheck,
+		abuse:           newAbuseDetector(),
+	}
+}
+
+// SessionManager exposes the session store, e.g. for the HTTP handlers.
+func (s *AIServer) SessionManager() *SessionManager {
+	return s.sessionManager
+}
+
+// Registry exposes the tool registry so other serving surfaces (e.g. the
+// MCP endpoint) share the same tool set.
+func (s *AIServer) Registry() *tool.Registry {
+	return s.registry
+}
+
+// UseSandbox routes every tool execution to the given resource manager
+// instead of the real one, for demo deployments. Like the session store
+// it is wired once at startup; sessions are labeled as sandboxed in
+// their metadata.
+func (s *AIServer) UseSandbox(sandbox *resource.ResourceManager) {
+	s.sandbox = sandbox
+}
+
+// UseAuditLog attaches the durable tool invocation log. Like the
+// session store it is wired once at startup, before serving begins.
+func (s *AIServer) UseAuditLog(store audit.Store) {
+	s.audit = audit.NewLogger(store)
+}
+
+// AuditLog exposes the audit logger to the HTTP handlers and the MCP
+// endpoint; nil when no store is attached.
+func (s *AIServer) AuditLog() *audit.Logger {
+	return s.audit
+}
+
+// QuickActions exposes the quick action catalog to the HTTP handlers.
+func (s *AIServer) QuickActions() *quickActionCatalog {
+	return s.quickActions
+}
+
+// ApplyConfig applies reloaded settings that are safe to change at
+// / runtime: session limits, the tool timeout and, when the provider
+// supports it, the model selection.
+func (s *AIServer) ApplyConfig(cfg *config.Config) {
+	s.sessionManager.ApplyConfig(cfg)
+	if s.quota != nil {
+		s.quota.ApplyLimits(budgetLimits(cfg))
+	}
+	if s.quickActions != nil {
+		s.quickActions.applyConfig(cfg)
+	}
+	if s.templates != nil {
+		s.templates.applyConfig(cfg)
+	}
+	if s.freezes != nil {
+		s.freezes.applyConfig(cfg)
+	}
+	if s.shedder != nil {
+		s.shedder.configure(cfg.Limits)
+	}
+	if s.clusters != nil {
+		s.clusters.applyConfig(cfg)
+	}
+	s.mu.Lock()
+	s.toolTimeout = cfg.Limits.ToolTimeout
+	s.contextTokens = cfg.Limits.ContextTokenBudget
+	s.maxTurnsPerUser = cfg.Limits.MaxTurnsPerUser
+	s.escalation = escalationPolicyFor(cfg)
+	s.groundingCheck = cfg.GroundingCheck
+	changed := false
+	if fp := capabilityFingerprint(cfg); fp != s.capsFingerprint {
+		s.capsFingerprint = fp
+		s.capsGen++
+		changed = true
+	}
+	s.mu.Unlock()
+	if changed {
+		s.notifyCapabilitiesUpdated()
+	}
+	if cfg.Provider.Model == "" {
+		return
+	}
+	if selector, ok := s.model.(provider.ModelSelector); ok {
+		selector.SetModel(cfg.Provider.Model)
+	}
+}
+
+// capabilityFingerprint summarizes the configuration that shapes what
+// the assistant may do: policy rules and the federated cluster set.
+// Sessions are told when it changes instead of silently operating with
+// stale capabilities.
+func capabilityFingerprint(cfg *config.Config) string {
+	names := make([]string, 0, len(cfg.Clusters))
+	for _, cluster := range cfg.Clusters {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("read_only=%v freezes=%v clusters=%v", cfg.ReadOnly, cfg.FreezeWindows, names)
+}
+
+// capabilitiesGeneration returns the current capability generation;
+// turns compare it between iterations to notice config reloads.
+func (s *AIServer) capabilitiesGeneration() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.capsGen
+}
+
+// notifyCapabilitiesUpdated pushes a capabilities_updated event into
+// every live session's replay buffer, carrying the tool list the
+// session's mode sees now. Sessions with an attached stream get it
+// immediately; disconnected ones on resume.
+func (s *AIServer) notifyCapabilitiesUpdated() {
+	s.sessionManager.broadcast(func(session *Session) Event {
+		tools := s.registry.ListForMode(session.Mode)
+		names := make([]string, 0, len(tools))
+		for _, t := range tools {
+			names = append(names, t.Name())
+		}
+		return Event{Type: EventTypeCapabilitiesUpdated, Payload: &CapabilitiesUpdatedPayload{
+			Tools:  names,
+			Reason: "the assistant's configuration changed",
+		}}
+	})
+}
+
+// Overloaded reports whether a new chat turn would be shed right now,
+// with a suggested client retry delay. Serving surfaces check it before
+// opening a stream, while a retry hint can still be delivered cleanly.
+func (s *AIServer) Overloaded() (bool, time.Duration) {
+	if s.shedder == nil {
+		return false, 0
+	}
+	return s.shedder.overloaded()
+}
+
+// acquireTurnSlot claims one of the user's concurrent turn slots. A nil
+// exceeded means the slot was taken and release must be called once the
+// turn ends; otherwise the payload carries the counts for the rejection
+// event.
+func (s *AIServer) acquireTurnSlot(userId string) (release func(), exceeded *ConcurrencyExceededPayload) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxTurnsPerUser <= 0 {
+		return func() {}, nil
+	}
+	if s.activeTurns == nil {
+		s.activeTurns = map[string]int{}
+	}
+	if s.activeTurns[userId] >= s.maxTurnsPerUser {
+		return nil, &ConcurrencyExceededPayload{Limit: s.maxTurnsPerUser, Active: s.activeTurns[userId]}
+	}
+	s.activeTurns[userId]++
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.activeTurns[userId] <= 1 {
+			delete(s.activeTurns, userId)
+		} else {
+			s.activeTurns[userId]--
+		}
+	}, nil
+}
+
+// StreamChat runs one conversational turn for the session: it appends
+// the user message, loops the model against the available tools and
+// emits stream events through emit until the turn completes.
+func (s *AIServer) StreamChat(ctx context.Context, session *Session, userMessage string, emit func(Event) error) error {
+	if strings.TrimSpace(userMessage) == "" {
+		return util.NewInvalidInputError("The chat message must not be empty")
+	}
+	if err := session.beginTurn(); err != nil {
+		return err
+	}
+	// The snapshot is persisted after endTurn ran, so it captures the
+	// turn's appended messages with the busy state already cleared.
+	defer s.sessionManager.persist(session)
+	defer session.endTurn()
+	session.setEmitter(emit)
+	defer session.clearEmitter()
+	// A cancellable context lets the cancel endpoint abort the turn —
+	// the provider request and any running tools — server-side, instead
+	// of waiting for the dropped SSE connection to propagate.
+	ctx, cancelTurn := context.WithCancel(ctx)
+	defer cancelTurn()
+	session.setCancel(cancelTurn)
+	defer session.clearCancel()
+
+	// Admission control: a saturated server serves the turn with the
+	// configured cheaper model. The hard shed happens in the serving
+	// surfaces, where a Retry-After hint can still be delivered.
+	degradedModel := ""
+	if s.shedder != nil {
+		model, release := s.shedder.begin()
+		degradedModel = model
+		defer release()
+	}
+
+	// The per-user cap counts across the user's sessions — beginTurn only
+	// serializes turns within one — so a single user cannot keep dozens
+	// of agent loops spinning against the provider at once.
+	release, exceeded := s.acquireTurnSlot(session.UserID)
+	if exceeded != nil {
+		glog.Warningf("Rejecting AI chat turn for session %v: user %v already has %v of %v turns running",
+			session.ID, session.UserID, exceeded.Active, exceeded.Limit)
+		if err := emit(Event{Type: EventTypeConcurrencyExceeded, Payload: exceeded}); err != nil {
+			return err
+		}
+		return emit(Event{Type: EventTypeDone})
+	}
+	defer release()
+
+	// The budget check rejects the turn before the user message even
+	// enters the history, so an out-of-budget user cannot queue up work.
+	if s.quota != nil {
+		if exceeded := s.quota.Exceeded(session.UserID, session.Namespace); exceeded != nil {
+			glog.Warningf("Rejecting AI chat turn for session %v: %v %v exhausted its %v token budget (%v of %v)",
+				session.ID, exceeded.Scope, exceeded.Subject, exceeded.Window, exceeded.SpentTokens, exceeded.LimitTokens)
+			if err := emit(Event{Type: EventTypeQuotaExceeded, Payload: &QuotaExceededPayload{
+				Scope:       exceeded.Scope,
+				Subject:     exceeded.Subject,
+				Window:      exceeded.Window,
+				LimitTokens: exceeded.LimitTokens,
+				SpentTokens: exceeded.SpentTokens,
+				ResetsAt:    exceeded.ResetsAt.Unix(),
+			}}); err != nil {
+				return err
+			}
+			return emit(Event{Type: EventTypeDone})
+		}
+	}
+
+	// A user under an abuse cool-down is rejected the same way: before
+	// the message enters the history, so the backlog cannot grow.
+	if s.abuse != nil {
+		if remaining := s.abuse.cooldownRemaining(session.UserID); remaining > 0 {
+			glog.Warningf("Rejecting AI chat turn for session %v: user %v is cooling down for another %v",
+				session.ID, session.UserID, remaining.Round(time.Second))
+			if err := emit(Event{Type: EventTypeError, Payload: &ErrorPayload{
+				Message: fmt.Sprintf("The assistant is temporarily paused for your account after a burst of unusual activity; try again in %v.", remaining.Round(time.Second)),
+			}}); err != nil {
+				return err
+			}
+			return emit(Event{Type: EventTypeDone})
+		}
+	}
+
+	// Full histories are compacted into a rolling summary before the new
+	// message enters them, so earlier findings survive instead of being
+	// trimmed away.
+	s.compactHistory(ctx, session)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: userMessage})
+
+	tools := s.registry.ListForMode(session.Mode)
+	// The explain tool reads this session's action trace, so it cannot
+	// live in the shared registry; it joins the turn's tool set here and
+	// executeToolCall resolves its calls directly.
+	tools = append(tools, &explainActionsTool{session: session})
+	capsGen := s.capabilitiesGeneration()
+	toolNames := make([]string, 0, len(tools))
+	for _, t := range tools {
+		toolNames = append(toolNames, t.Name())
+	}
+	// Greetings and pure documentation questions take a fast path with no
+	// tool definitions attached: the prompt stays small and the model
+	// cannot wander into a pointless tool round trip. The session's tool
+	// availability is unchanged, so the metadata below still lists them.
+	if isTrivialQuery(userMessage) {
+		glog.V(2).Infof("AI session %v: answering without tools, the message classified as trivial", session.ID)
+		tools = nil
+	}
+	if err := emit(Event{Type: EventTypeSessionMetadata, Payload: &SessionMetadataPayload{
+		SessionID: session.ID,
+		Mode:      string(session.Mode),
+		Tools:     toolNames,
+		Sandbox:   s.sandbox != nil,
+	}}); err != nil {
+		return err
+	}
+	if degradedModel != "" {
+		glog.Warningf("AI session %v: serving the turn with degraded model %v, the system is saturated", session.ID, degradedModel)
+		if err := emit(Event{Type: EventTypeModelDegraded, Payload: &ModelDegradedPayload{Model: degradedModel}}); err != nil {
+			return err
+		}
+	}
+	// Confirmations denied while the user was away (their session was
+	// reclaimed mid-approval) are reported on the next stream instead of
+	// silently vanishing.
+	if lost := s.sessionManager.takeLostApprovals(session.UserID); len(lost) > 0 {
+		if err := emit(Event{Type: EventTypeApprovalsLost, Payload: &ApprovalsLostPayload{Approvals: lost}}); err != nil {
+			return err
+		}
+	}
+	// A turn that died with a previous process is reported once, so the
+	// client can tell the user the investigation survived up to the last
+	// checkpoint. endTurn clears the marker when this turn completes.
+	if interrupted := session.interruptedTurn(); !interrupted.IsZero() {
+		if err := emit(Event{Type: EventTypeTurnInterrupted, Payload: &TurnInterruptedPayload{
+			CheckpointedAt: interrupted.Unix(),
+		}}); err != nil {
+			return err
+		}
+	}
+
+	// turnUsage accumulates token consumption over the model invocations
+	// this turn makes; it is reported once, just before done.
+	var turnUsage provider.Usage
+	emitUsage := func() error {
+		if turnUsage == (provider.Usage{}) {
+			// Not every provider reports usage; say nothing over guessing.
+			return nil
+		}
+		sessionUsage := session.usageSnapshot()
+		return emit(Event{Type: EventTypeUsage, Payload: &UsagePayload{
+			InputTokens:         turnUsage.InputTokens,
+			OutputTokens:        turnUsage.OutputTokens,
+			SessionInputTokens:  sessionUsage.InputTokens,
+			SessionOutputTokens: sessionUsage.OutputTokens,
+		}})
+	}
+
+	// The grounding tracker is seeded with the whole conversation so far,
+	// so IDs the user typed or earlier turns already fetched are not
+	// flagged as fabricated when the answer repeats them.
+	var grounding *groundingTracker
+	if s.groundingEnabled() {
+		grounding = newGroundingTracker()
+		for _, m := range session.Messages() {
+			// The model's own prior claims are not evidence.
+			if m.Role == provider.RoleAssistant {
+				continue
+			}
+			grounding.addEvidence(m.Content)
+		}
+	}
+
+	salvaged := false
+	hinted := false
+	for iteration := 0; iteration < maxLoopIterations; iteration++ {
+		// Every completed iteration appended a consistent batch of
+		// messages — tool results included — so the session is
+		// checkpointed here. If the process dies mid-turn, a restart
+		// resumes from the last completed iteration instead of losing
+		// the whole investigation.
+		if iteration > 0 {
+			s.sessionManager.checkpoint(session)
+		}
+		// A config reload mid-turn (tools toggled, rules changed, clusters
+		// registered) refreshes the tool set for the next iteration
+		// instead of finishing the turn against a stale list.
+		if gen := s.capabilitiesGeneration(); gen != capsGen && tools != nil {
+			capsGen = gen
+			tools = append(s.registry.ListForMode(session.Mode), &explainActionsTool{session: session})
+		}
+		assistantText, toolCalls, err := s.runModelTurn(ctx, session, tools, degradedModel, &turnUsage, emit)
+		if err != nil {
+			// A failure after substantial text is salvaged once: the partial
+			// message stays in history and the model is asked to continue.
+			if salvaged || len(assistantText) < partialSalvageMinChars {
+				return err
+			}
+			salvaged = true
+			glog.Warningf("Salvaging %v chars of partial response in AI session %v after provider failure: %v",
+				len(assistantText), session.ID, err)
+			session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: assistantText})
+			if err := emit(Event{Type: EventTypePartialResponse, Payload: &PartialResponsePayload{
+				Chars:    len(assistantText),
+				Resuming: true,
+			}}); err != nil {
+				return err
+			}
+			session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: continuationPrompt})
+			continue
+		}
+		session.AppendMessage(provider.Message{
+			Role:      provider.RoleAssistant,
+			Content:   assistantText,
+			ToolCalls: toolCalls,
+		})
+		if len(toolCalls) == 0 {
+			// An uncertain answer to an operational question usually means
+			// tool discovery failed on fringe phrasing. Once per turn, a
+			// hint naming the closest-matching tools gives the model a
+			// second chance before the turn ends.
+			if !hinted && len(tools) > 0 && isOperationalQuery(userMessage) && looksUncertain(assistantText) {
+				if hints := suggestToolsForIntent(userMessage, tools); len(hints) > 0 {
+					hinted = true
+					glog.V(2).Infof("AI session %v: suggesting tools [%v] after an uncertain answer",
+						session.ID, strings.Join(hints, ", "))
+					session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: toolHintNote(hints)})
+					continue
+				}
+			}
+			if grounding != nil {
+				if ids := grounding.ungrounded(assistantText); len(ids) > 0 {
+					glog.Warningf("AI session %v: the answer references resource IDs absent from this turn's evidence: %v",
+						session.ID, strings.Join(ids, ", "))
+					if err := emit(Event{Type: EventTypeGroundingWarning, Payload: &GroundingWarningPayload{
+						IDs:     ids,
+						Message: "The answer mentions resources the assistant did not fetch this turn; verify these IDs before acting on them.",
+					}}); err != nil {
+						return err
+					}
+				}
+			}
+			s.emitSuggestions(ctx, session, emit)
+			if err := emitUsage(); err != nil {
+				return err
+			}
+			return emit(Event{Type: EventTypeDone})
+		}
+		var mutations []*tool.Mutation
+		for _, call := range toolCalls {
+			result := s.executeToolCall(ctx, session, call, emit)
+			if grounding != nil {
+				grounding.addEvidence(result.Content)
+			}
+			session.AppendMessage(provider.Message{
+				Role:       provider.RoleTool,
+				Content:    result.Content,
+				ToolCallID: call.ID,
+			})
+			if err := emit(Event{Type: EventTypeToolResult, Payload: &ToolResultPayload{
+				ToolCallID: call.ID,
+				Tool:       call.Name,
+				Content:    result.Content,
+				IsError:    result.IsError,
+			}}); err != nil {
+				return err
+			}
+			if result.Mutation != nil && !result.IsError {
+				mutations = append(mutations, result.Mutation)
+			}
+		}
+		// Injected after the batch so the hint does not split the tool
+		// results the model is waiting for.
+		for _, mutation := range mutations {
+			s.applyMutationHint(session, mutation)
+		}
+	}
+	glog.Warningf("AI session %v hit the tool loop limit of %v iterations", session.ID, maxLoopIterations)
+	s.noteAbuseSignal(session, abuseSignalLoopLimit)
+	if err := emit(Event{Type: EventTypeError, Payload: &ErrorPayload{
+		Message: "The assistant stopped after reaching the tool call limit for a single turn.",
+	}}); err != nil {
+		return err
+	}
+	if err := emitUsage(); err != nil {
+		return err
+	}
+	return emit(Event{Type: EventTypeDone})
+}
+
+// runModelTurn performs one model invocation and returns the assistant
+// text and any requested tool calls. Reported token usage is added to
+// turnUsage and the session and user totals.
+func (s *AIServer) runModelTurn(ctx context.Context, session *Session, tools []tool.Tool, modelOverride string, turnUsage *provider.Usage, emit func(Event) error) (string, []provider.ToolCall, error) {
+	var text strings.Builder
+	var toolCalls []provider.ToolCall
+	codeBlocks := &codeBlockTracker{}
+	messages := providerWindow(session.Messages())
+	if budget := s.currentContextTokens(); budget > 0 {
+		window, dropped := tokenWindow(messages, s.tokenizer, budget)
+		if dropped > 0 {
+			// The stored history is untouched; only this invocation's
+			// context shrank. The client is told so the user knows.
+			if err := emit(Event{Type: EventTypeContextTruncated, Payload: &ContextTruncatedPayload{
+				DroppedMessages: dropped,
+				BudgetTokens:    budget,
+			}}); err != nil {
+				return "", nil, err
+			}
+			messages = window
+		}
+	}
+	defs := provider.AdaptTools(tool.Definitions(tools), s.capabilities)
+	if s.clusters != nil {
+		if clusters := s.clusters.clusterNames(); len(clusters) > 0 {
+			defs = withClusterParameter(defs, clusters)
+		}
+	}
+	req := &provider.ChatRequest{
+		System:   buildSystemPrompt(session),
+		Messages: messages,
+		Tools:    defs,
+		Model:    modelOverride,
+	}
+	usageReported := false
+	modelCallStart := time.Now()
+	err := s.model.StreamChat(ctx, req, func(event provider.StreamEvent) error {
+		switch event.Type {
+		case provider.StreamEventTextDelta:
+			text.WriteString(event.Delta.Text)
+			if err := emit(Event{Type: EventTypeMarkdown, Payload: &MarkdownPayload{Text: event.Delta.Text}}); err != nil {
+				return err
+			}
+			// Completed fenced code blocks are re-emitted in structured
+			// form alongside the markdown.
+			for _, block := range codeBlocks.feed(event.Delta.Text) {
+				if err := emit(Event{Type: EventTypeCodeBlock, Payload: block}); err != nil {
+					return err
+				}
+			}
+			return nil
+		case provider.StreamEventToolCall:
+			toolCalls = append(toolCalls, *event.ToolCall)
+		case provider.StreamEventProviderSwitched:
+			return emit(Event{Type: EventTypeProviderSwitched, Payload: &ProviderSwitchedPayload{Provider: event.Provider}})
+		case provider.StreamEventUsage:
+			usageReported = true
+			s.recordUsage(session, turnUsage, *event.Usage, req.Model)
+		}
+		return nil
+	})
+	if s.shedder != nil {
+		s.shedder.observe(time.Since(modelCallStart))
+	}
+	if err != nil {
+		// The text streamed so far is returned so the caller can salvage
+		// it when the failure happened mid-answer.
+		return text.String(), nil, util.Wrap(err, "The model call failed")
+	}
+	// A message may end on its closing fence without a trailing newline.
+	if block := codeBlocks.finish(); block != nil {
+		if err := emit(Event{Type: EventTypeCodeBlock, Payload: block}); err != nil {
+			return text.String(), nil, err
+		}
+	}
+	// Providers that report no usage are charged by estimate, so quotas
+	// and usage reporting still see every invocation.
+	if !usageReported && s.tokenizer != nil {
+		s.recordUsage(session, turnUsage, provider.Usage{
+			InputTokens:  s.tokenizer.CountText(req.System) + tokenizer.CountMessages(s.tokenizer, req.Messages),
+			OutputTokens: s.tokenizer.CountText(text.String()),
+		}, req.Model)
+	}
+	return text.String(), toolCalls, nil
+}
+
+// recordUsage accumulates one model invocation's token consumption into
+// the turn, session, user and quota counters. modelName is the
+// per-request override; empty means the provider's configured model.
+func (s *AIServer) recordUsage(session *Session, turnUsage *provider.Usage, usage provider.Usage, modelName string) {
+	turnUsage.InputTokens += usage.InputTokens
+	turnUsage.OutputTokens += usage.OutputTokens
+	session.addUsage(usage)
+	if !session.telemetryDisabled() {
+		// Opted-out sessions stay out of the cross-session usage report;
+		// budget enforcement below is operational and still applies.
+		s.sessionManager.recordUsage(session.UserID, usage)
+	}
+	if s.quota != nil {
+		s.quota.Record(session.UserID, session.Namespace, usage.InputTokens+usage.OutputTokens)
+	}
+	// Chargeback records are billing, not analytics, so like the quota
+	// counters they are kept for opted-out sessions too.
+	if s.usage != nil {
+		s.usage.record(&model.AIUsageRecord{
+			UserID:       session.UserID,
+			Namespace:    session.Namespace,
+			SessionUUID:  session.ID,
+			InputTokens:  usage.InputTokens,
+			OutputTokens: usage.OutputTokens,
+			Model:        modelName,
+		})
+	}
+}
+
+// applyMutationHint updates conversation state after a successful
+// mutating tool call. Deleted runs are dropped from the watcher so the
+// client gets no stale state updates, and a read-after-write note is
+// injected so the model uses the returned identifiers directly instead
+// of immediately re-listing a resource that eventually consistent
+// listings may not surface yet.
+func (s *AIServer) applyMutationHint(session *Session, mutation *tool.Mutation) {
+	if mutation.Action == tool.MutationDeleted && mutation.ResourceType == "run" {
+		session.untrackRun(mutation.ResourceID)
+	}
+	summary, err := json.Marshal(mutation)
+	if err != nil {
+		glog.Errorf("Failed to marshal the mutation summary for AI session %v: %v", session.ID, err)
+		return
+	}
+	session.AppendMessage(provider.Message{
+		Role: provider.RoleUser,
+		Content: fmt.Sprintf("[System note] read_after_write: %s. Listings may not reflect this change yet; "+
+			"use these identifiers directly instead of re-listing to verify it.", summary),
+	})
+}
+
+// executeToolCall resolves and runs a single tool call, including the
+// confirmation flow for mutating tools. Failures are compacted into
+// model-friendly error results rather than aborting the turn.
+func (s *AIServer) executeToolCall(ctx context.Context, session *Session, call provider.ToolCall, emit func(Event) error) *tool.Result {
+	// Introspection of the action trace is answered by the server; it is
+	// not recorded itself so it cannot crowd real actions out of the trace.
+	if call.Name == explainActionsToolName {
+		return s.explainActions(session, call, emit)
+	}
+	t := s.registry.Get(call.Name)
+	if t == nil {
+		return &tool.Result{Content: CompactToolError(call.Name, util.NewInvalidInputError("Tool %q is not available", call.Name)), IsError: true}
+	}
+	// Each path below records the call's audited outcome exactly once,
+	// both in the session's trace and in the durable audit log.
+	// approvedBy is filled once the user confirms a mutating call.
+	started := time.Now()
+	var approvedBy string
+	record := func(outcome, detail string) {
+		session.recordAction(ActionRecord{
+			Tool:          call.Name,
+			ArgumentsJSON: call.ArgumentsJSON,
+			Mutating:      t.Mutating(),
+			Outcome:       outcome,
+			Detail:        detail,
+			ApprovedBy:    approvedBy,
+		})
+		if s.audit != nil {
+			s.audit.Record(&model.AIAuditEvent{
+				UserID:      session.UserID,
+				SessionUUID: session.ID,
+				Namespace:   session.Namespace,
+				Tool:        call.Name,
+				Arguments:   model.LargeText(call.ArgumentsJSON),
+				Mutating:    t.Mutating(),
+				Outcome:     outcome,
+				Detail:      detail,
+				ApprovedBy:  approvedBy,
+				DurationMs:  time.Since(started).Milliseconds(),
+			})
+		}
+	}
+	// Arguments are parsed before the confirmation flow so malformed
+	// calls fail without bothering the user for an approval.
+	var args map[string]interface{}
+	if call.ArgumentsJSON != "" {
+		if err := json.Unmarshal([]byte(call.ArgumentsJSON), &args); err != nil {
+			record(actionFailed, "the tool arguments were not valid JSON")
+			return &tool.Result{Content: CompactToolError(call.Name, util.NewInvalidInputError("The tool arguments were not valid JSON: %s", err.Error())), IsError: true}
+		}
+	}
+	// A call naming a registered remote cluster is forwarded to that
+	// cluster's assistant instead of executing locally.
+	if clusterName, ok := args["cluster"].(string); ok && clusterName != "" &&
+		s.clusters != nil && len(s.clusters.clusterNames()) > 0 {
+		return s.executeFederatedCall(ctx, session, t, call, clusterName, args, record, emit)
+	}
+	resourceManager := s.resourceManager
+	if s.sandbox != nil {
+		resourceManager = s.sandbox
+	}
+	ec := &tool.ExecContext{
+		ResourceManager: resourceManager,
+		UserID:          session.UserID,
+		Namespace:       session.Namespace,
+		SignedLogLink:   s.logLinks.Link,
+		Timeout:         s.currentToolTimeout(),
+		Progress: func(update tool.ProgressUpdate) {
+			// Progress is decoration on a live stream; a write failure is
+			// surfaced by the next regular event.
+			_ = emit(Event{Type: EventTypeToolProgress, Payload: &ToolProgressPayload{
+				ToolCallID: call.ID,
+				Tool:       call.Name,
+				Item:       update.Item,
+				Completed:  update.Completed,
+				Total:      update.Total,
+				Error:      update.Err,
+			}})
+		},
+	}
+	if t.Mutating() {
+		if s.freezes != nil {
+			if reason, frozen := s.freezes.activeReason(time.Now()); frozen {
+				record(actionUnauthorized, "blocked by a change freeze")
+				return &tool.Result{Content: CompactToolError(call.Name, util.NewPermissionDeniedError(errors.New("change freeze"),
+					"Mutating tools are temporarily blocked: %s. Tell the user about the restriction and do not retry until the freeze window ends.", reason)), IsError: true}
+			}
+		}
+		if session.Mode != tool.ModeAgent {
+			record(actionUnauthorized, "mutating tools require Agent mode")
+			return &tool.Result{Content: CompactToolError(call.Name, util.NewPermissionDeniedError(errors.New("mode is not agent"), "Mutating tools require Agent mode")), IsError: true}
+		}
+		approved, err := s.awaitConfirmation(ctx, session, ec, t, call, args, emit)
+		if err != nil {
+			record(actionOutcomeFor(err), actionDetailFor(err))
+			return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+		}
+		if !approved {
+			record(actionDenied, "the user denied the confirmation request")
+			s.noteAbuseSignal(session, abuseSignalDeniedMutation)
+			return &tool.Result{Content: "The user denied this tool call. Do not retry it without being asked to.", IsError: true}
+		}
+		// Only the session owner can approve, so their resolved identity is
+		// the approver.
+		approvedBy = s.sessionManager.displayName(session.UserID)
+	}
+
+	if err := emit(Event{Type: EventTypeToolCall, Payload: &ToolCallPayload{
+		ToolCallID:    call.ID,
+		Tool:          call.Name,
+		ArgumentsJSON: call.ArgumentsJSON,
+	}}); err != nil {
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+
+	// The execution context is bounded so a slow backend call cannot hold
+	// the turn open past the per-tool timeout. The confirmation wait above
+	// deliberately runs on the raw stream context: waiting on the user is
+	// not tool work.
+	execCtx, cancel := ec.BoundContext(ctx)
+	defer cancel()
+	result, err := t.Execute(execCtx, ec, args)
+	if err != nil {
+		record(actionOutcomeFor(err), actionDetailFor(err))
+		s.noteAbuseSignal(session, abuseSignalToolError)
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+	record(actionSucceeded, "")
+	trackFromToolCall(session, args, result)
+	result.Content = annotateTimestamps(annotateUnits(result.Content), session.Location())
+	return result
+}
+
+// executeFederatedCall forwards a read-only tool call to a registered
+// remote cluster and shapes its answer like a local result, with the
+// cluster named so the model attributes the data correctly. The remote
+// cluster enforces RBAC for the credential mapped to the caller.
+func (s *AIServer) executeFederatedCall(ctx context.Context, session *Session, t tool.Tool, call provider.ToolCall,
+	clusterName string, args map[string]interface{}, record func(outcome, detail string), emit func(Event) error) *tool.Result {
+	if t.Mutating() {
+		record(actionUnauthorized, "mutating tools cannot target a remote cluster")
+		return &tool.Result{Content: CompactToolError(call.Name, util.NewInvalidInputError(
+			"Only read-only tools can target a remote cluster; run %q against cluster %q directly instead", call.Name, clusterName)), IsError: true}
+	}
+	if err := emit(Event{Type: EventTypeToolCall, Payload: &ToolCallPayload{
+		ToolCallID:    call.ID,
+		Tool:          call.Name,
+		ArgumentsJSON: call.ArgumentsJSON,
+	}}); err != nil {
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+	// The remote tool's schema has no cluster parameter; it is consumed
+	// here. The call is bounded like a local execution.
+	delete(args, "cluster")
+	if timeout := s.currentToolTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	content, isError, err := s.clusters.call(ctx, clusterName, session.UserID, call.Name, args)
+	if err != nil {
+		record(actionOutcomeFor(err), actionDetailFor(err))
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+	record(actionSucceeded, fmt.Sprintf("on cluster %v", clusterName))
+	return &tool.Result{Content: fmt.Sprintf("[cluster %v] %v", clusterName, content), IsError: isError}
+}
+
+// explainActions resolves an explain_last_actions call from the
+// session's recorded trace. It mirrors the event flow of a regular tool
+// call so the frontend renders it like any other.
+func (s *AIServer) explainActions(session *Session, call provider.ToolCall, emit func(Event) error) *tool.Result {
+	if err := emit(Event{Type: EventTypeToolCall, Payload: &ToolCallPayload{
+		ToolCallID:    call.ID,
+		Tool:          call.Name,
+		ArgumentsJSON: call.ArgumentsJSON,
+	}}); err != nil {
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+	result, err := (&explainActionsTool{session: session}).Execute(context.Background(), nil, nil)
+	if err != nil {
+		return &tool.Result{Content: CompactToolError(call.Name, err), IsError: true}
+	}
+	return result
+}
+
+// currentToolTimeout returns the tool timeout under the lock, since a
+// config reload can change it concurrently.
+func (s *AIServer) currentToolTimeout() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.toolTimeout
+}
+
+// currentContextTokens returns the context token budget under the lock.
+func (s *AIServer) currentContextTokens() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.contextTokens
+}
+
+// awaitConfirmation emits a confirmation request and blocks until the
+// user approves or denies the call, the timeout elapses, or the stream
+// context ends. Tools that support validation are dry-run first: a
+// validation failure rejects the call outright, and a successful one is
+// attached to the confirmation request so the user approves the
+// resolved call rather than the raw arguments.
+func (s *AIServer) awaitConfirmation(ctx context.Context, session *Session, ec *tool.ExecContext, t tool.Tool, call provider.ToolCall, args map[string]interface{}, emit func(Event) error) (bool, error) {
+	payload := &ConfirmationRequestPayload{
+		ToolCallID:    call.ID,
+		Tool:          call.Name,
+		ArgumentsJSON: call.ArgumentsJSON,
+		Description:   t.Description(),
+	}
+	if validator, ok := t.(tool.Validator); ok {
+		// The dry run does the same backend work as the execution, so it
+		// runs under the same per-tool deadline.
+		validateCtx, cancel := ec.BoundContext(ctx)
+		validationJSON, err := validator.Validate(validateCtx, ec, args)
+		cancel()
+		if err != nil {
+			return false, util.Wrapf(err, "The %v call failed validation", call.Name)
+		}
+		payload.ValidationJSON = validationJSON
+	}
+	// Oversized arguments are not embedded in the event; the client
+	// fetches them through the returned reference instead.
+	summarizeConfirmationArgs(session.ID, payload)
+	decision := session.setConfirmation(call, payload)
+	// Persisted with the confirmation pending, so the store reflects what
+	// the session is blocked on while it waits.
+	s.sessionManager.persist(session)
+	if err := emit(Event{Type: EventTypeConfirmationRequest, Payload: payload}); err != nil {
+		return false, err
+	}
+	// A confirmation pending past the escalation deadline notifies the
+	// configured fallback approver and, under an auto-deny policy, is
+	// rejected without waiting out the full timeout.
+	policy := s.escalationSettings()
+	timeout := time.NewTimer(confirmationTimeout)
+	defer timeout.Stop()
+	var escalate <-chan time.Time
+	if policy.after > 0 && policy.after < confirmationTimeout {
+		escalation := time.NewTimer(policy.after)
+		defer escalation.Stop()
+		escalate = escalation.C
+	}
+	for {
+		select {
+		case approved := <-decision:
+			if approved {
+				// Hand the preview to the execution: the user approved the
+				// resolved call, so tools acting on a resolved resource set
+				// must not re-resolve their criteria after the wait.
+				ec.ApprovedValidationJSON = payload.ValidationJSON
+			}
+			return approved, nil
+		case <-escalate:
+			escalate = nil
+			s.escalateConfirmation(session, payload, policy)
+			if policy.autoDeny {
+				return false, util.NewFailedPreconditionError(errors.New("confirmation escalated"),
+					"The confirmation for tool %v was not approved within %v and was denied by the escalation policy", call.Name, policy.after)
+			}
+		case <-timeout.C:
+			return false, util.NewFailedPreconditionError(errors.New("confirmation timed out"), "The confirmation for tool %v timed out", call.Name)
+		case <-ctx.Done():
+			return false, util.Wrap(ctx.Err(), "The chat stream ended while waiting for confirmation")
+		}
+	}
+}
+
+// SwitchMode changes a session's mode between turns, so a conversation
+// started in Ask mode can continue in Agent mode without losing its
+// history. The refreshed tool availability is returned and also pushed
+// as a session_metadata event on an open stream, and a system note about
+// the changed tool set is injected so the model knows what it may now
+// (or may no longer) do.
+func (s *AIServer) SwitchMode(sessionId, userId, modeStr string) (*SessionMetadataPayload, error) {
+	session, err := s.sessionManager.Get(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := tool.ParseMode(modeStr)
+	if err != nil {
+		return nil, err
+	}
+	if s.readOnly && mode == tool.ModeAgent {
+		return nil, util.NewPermissionDeniedError(errors.New("deployment is read-only"),
+			"This deployment runs the AI assistant read-only; Agent mode is disabled")
+	}
+	previous, changed, err := session.switchMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := s.registry.ListForMode(mode)
+	toolNames := make([]string, 0, len(tools))
+	for _, t := range tools {
+		toolNames = append(toolNames, t.Name())
+	}
+	payload := &SessionMetadataPayload{
+		SessionID: session.ID,
+		Mode:      string(mode),
+		Tools:     toolNames,
+		Sandbox:   s.sandbox != nil,
+	}
+	if changed {
+		session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: s.modeSwitchNote(previous, mode)})
+		session.emitAsync(Event{Type: EventTypeSessionMetadata, Payload: payload})
+		s.sessionManager.persist(session)
+	}
+	return payload, nil
+}
+
+// modeSwitchNote renders the model-facing note injected after a mode
+// switch, naming the mutating tools that became available or unusable.
+func (s *AIServer) modeSwitchNote(previous, mode tool.Mode) string {
+	var mutating []string
+	for _, t := range s.registry.ListForMode(tool.ModeAgent) {
+		if t.Mutating() {
+			mutating = append(mutating, t.Name())
+		}
+	}
+	if mode == tool.ModeAgent {
+		return fmt.Sprintf("[System note] The user switched this session from %v to %v mode. "+
+			"Mutating tools are now available (%v); each mutating call still requires the user's confirmation before it executes.",
+			previous, mode, strings.Join(mutating, ", "))
+	}
+	return fmt.Sprintf("[System note] The user switched this session from %v to %v mode. "+
+		"Mutating tools (%v) are no longer available; do not attempt them.",
+		previous, mode, strings.Join(mutating, ", "))
+}
+
+// budgetLimits maps the configured budgets onto the quota tracker's
+// limits.
+func budgetLimits(cfg *config.Config) quota.Limits {
+	return quota.Limits{
+		UserDailyTokens:        cfg.Budgets.UserDailyTokens,
+		UserMonthlyTokens:      cfg.Budgets.UserMonthlyTokens,
+		NamespaceDailyTokens:   cfg.Budgets.NamespaceDailyTokens,
+		NamespaceMonthlyTokens: cfg.Budgets.NamespaceMonthlyTokens,
+	}
+}
+
+// ApproveToolCall records the user's decision for a pending mutating
+// tool call in the given session.
+func (s *AIServer) ApproveToolCall(sessionId, userId, toolCallId string, approved bool) error {
+	if s.readOnly {
+		// Nothing can be pending — mutating tools do not exist here — but
+		// the rejection keeps the guarantee explicit for auditors.
+		return util.NewPermissionDeniedError(errors.New("deployment is read-only"),
+			"This deployment runs the AI assistant read-only; there are no tool calls to approve")
+	}
+	session, err := s.sessionManager.Get(sessionId, userId)
+	if err != nil {
+		return err
+	}
+	return session.resolveConfirmation(toolCallId, approved)
+}
+
+// CancelTurn aborts the session's in-flight chat turn server-side: the
+// provider request and any running tool executions are cancelled through
+// the turn's context, and a confirmation wait unblocks.
+func (s *AIServer) CancelTurn(sessionId, userId string) error {
+	session, err := s.sessionManager.Get(sessionId, userId)
+	if err != nil {
+		return err
+	}
+	if !session.cancelActiveTurn() {
+		return util.NewFailedPreconditionError(errors.New("session is idle"),
+			"Session %v has no chat turn in flight", sessionId)
+	}
+	glog.Infof("AI chat turn for session %v was cancelled by the user", sessionId)
+	return nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubValidatedTool is a mutating tool with a scripted validation
+// outcome, for exercising the two-phase confirmation flow.
+type stubValidatedTool struct {
+	validationJSON string
+	validateErr    error
+	executed       bool
+}
+
+func (t *stubValidatedTool) Name() string        { return "stub_mutate" }
+func (t *stubValidatedTool) Description() string { return "A scripted mutating tool." }
+func (t *stubValidatedTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *stubValidatedTool) Mutating() bool { return true }
+
+func (t *stubValidatedTool) Validate(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (string, error) {
+	return t.validationJSON, t.validateErr
+}
+
+func (t *stubValidatedTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	t.executed = true
+	return &tool.Result{Content: "done"}, nil
+}
+
+func modeSwitchFixture(t *testing.T) (*AIServer, *Session) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestAIServer_SwitchModeInjectsNoteAndRefreshesTools(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+
+	metadata, err := server.SwitchMode(session.ID, "alice", "agent")
+	require.NoError(t, err)
+	assert.Equal(t, "agent", metadata.Mode)
+	assert.Contains(t, metadata.Tools, "delete_run")
+
+	messages := session.Messages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Content, "agent mode")
+	assert.Contains(t, messages[0].Content, "delete_run")
+	assert.Equal(t, tool.ModeAgent, session.Mode)
+}
+
+func TestAIServer_SwitchModeSameModeIsNoOp(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+
+	metadata, err := server.SwitchMode(session.ID, "alice", "ask")
+	require.NoError(t, err)
+	assert.Equal(t, "ask", metadata.Mode)
+	assert.Empty(t, session.Messages())
+}
+
+func TestAIServer_SwitchModeRejectsBusySession(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+	require.NoError(t, session.beginTurn())
+	defer session.endTurn()
+
+	_, err := server.SwitchMode(session.ID, "alice", "agent")
+	assert.Error(t, err)
+	assert.Equal(t, tool.ModeAsk, session.Mode)
+}
+
+func TestAIServer_ApplyMutationHintInjectsReadAfterWriteNote(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+
+	server.applyMutationHint(session, &tool.Mutation{
+		ResourceType: "run", ResourceID: "r1", DisplayName: "training", Action: tool.MutationCreated,
+	})
+
+	messages := session.Messages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Content, "read_after_write")
+	assert.Contains(t, messages[0].Content, `"resource_id":"r1"`)
+	assert.Contains(t, messages[0].Content, `"action":"created"`)
+}
+
+func TestAIServer_ApplyMutationHintUntracksDeletedRuns(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+	session.trackRun("r1")
+
+	server.applyMutationHint(session, &tool.Mutation{
+		ResourceType: "run", ResourceID: "r1", Action: tool.MutationDeleted,
+	})
+
+	assert.Empty(t, session.referencedRunsSnapshot())
+	require.Len(t, session.Messages(), 1)
+}
+
+func TestAIServer_SwitchModeRejectsUnknownMode(t *testing.T) {
+	server, session := modeSwitchFixture(t)
+
+	_, err := server.SwitchMode(session.ID, "alice", "autopilot")
+	assert.Error(t, err)
+}
+
+// stubBatchTool reports scripted item progress while executing.
+type stubBatchTool struct{}
+
+func (t *stubBatchTool) Name() string        { return "stub_batch" }
+func (t *stubBatchTool) Description() string { return "A scripted batch tool." }
+func (t *stubBatchTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *stubBatchTool) Mutating() bool { return false }
+
+func (t *stubBatchTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	ec.ReportProgress(tool.ProgressUpdate{Completed: 1, Total: 2, Item: "r1"})
+	ec.ReportProgress(tool.ProgressUpdate{Completed: 2, Total: 2, Item: "r2", Err: "boom"})
+	return &tool.Result{Content: "done"}, nil
+}
+
+func TestAIServer_ToolProgressIsStreamed(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	require.NoError(t, server.registry.Register(&stubBatchTool{}))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var progress []*ToolProgressPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeToolProgress {
+			progress = append(progress, e.Payload.(*ToolProgressPayload))
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_batch", ArgumentsJSON: "{}"}, emit)
+
+	assert.False(t, result.IsError)
+	require.Len(t, progress, 2)
+	assert.Equal(t, "r1", progress[0].Item)
+	assert.Equal(t, 2, progress[0].Total)
+	assert.Equal(t, "boom", progress[1].Error)
+	assert.Equal(t, "stub_batch", progress[1].Tool)
+}
+
+// stubDeadlineTool records whether its context carried a deadline.
+type stubDeadlineTool struct {
+	deadlineSet bool
+}
+
+func (t *stubDeadlineTool) Name() string        { return "stub_deadline" }
+func (t *stubDeadlineTool) Description() string { return "Records its context deadline." }
+func (t *stubDeadlineTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *stubDeadlineTool) Mutating() bool { return false }
+
+func (t *stubDeadlineTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	_, t.deadlineSet = ctx.Deadline()
+	return &tool.Result{Content: "ok"}, nil
+}
+
+func TestAIServer_ToolExecutionIsDeadlineBounded(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), toolTimeout: time.Minute}
+	stub := &stubDeadlineTool{}
+	require.NoError(t, server.registry.Register(stub))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	emit := func(Event) error { return nil }
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_deadline", ArgumentsJSON: "{}"}, emit)
+
+	assert.False(t, result.IsError)
+	assert.True(t, stub.deadlineSet)
+}
+
+func TestAIServer_ZeroToolTimeoutLeavesContextUnbounded(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	stub := &stubDeadlineTool{}
+	require.NoError(t, server.registry.Register(stub))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	emit := func(Event) error { return nil }
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_deadline", ArgumentsJSON: "{}"}, emit)
+
+	assert.False(t, result.IsError)
+	assert.False(t, stub.deadlineSet)
+}
+
+func confirmationFixture(t *testing.T, stub *stubValidatedTool) (*AIServer, *Session) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	require.NoError(t, server.registry.Register(stub))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAgent, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestAIServer_ConfirmationCarriesValidationResult(t *testing.T) {
+	stub := &stubValidatedTool{validationJSON: `{"resolved":true}`}
+	server, session := confirmationFixture(t, stub)
+
+	var confirmation *ConfirmationRequestPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmation = e.Payload.(*ConfirmationRequestPayload)
+			require.NoError(t, session.resolveConfirmation(confirmation.ToolCallID, false))
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	require.NotNil(t, confirmation)
+	assert.Equal(t, `{"resolved":true}`, confirmation.ValidationJSON)
+	// The call was denied, so it must not have executed.
+	assert.True(t, result.IsError)
+	assert.False(t, stub.executed)
+}
+
+func TestAIServer_ValidationFailureSkipsConfirmation(t *testing.T) {
+	stub := &stubValidatedTool{validateErr: util.NewInvalidInputError("experiment e1 does not exist")}
+	server, session := confirmationFixture(t, stub)
+
+	confirmationRequested := false
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmationRequested = true
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "experiment e1 does not exist")
+	assert.False(t, confirmationRequested)
+	assert.False(t, stub.executed)
+}
@@ -0,0 +1,315 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/storage"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	// attachmentKeyPrefix namespaces attachment blobs in the shared
+	// object store, away from pipeline files.
+	attachmentKeyPrefix = "ai/attachments/"
+	// maxAttachmentBytes caps a single attachment. Attachments carry
+	// working files of a conversation — specs, reports, uploaded YAML —
+	// not datasets.
+	maxAttachmentBytes = 2 * 1024 * 1024
+	// maxAttachmentsPerSession bounds how many attachments one session
+	// may reference at a time.
+	maxAttachmentsPerSession = 20
+)
+
+// Attachment describes one file held for a chat session. Messages
+// reference it by hash instead of inlining the content, so transcripts
+// and model context stay small.
+type Attachment struct {
+	// Hash is the hex SHA-256 of the content and doubles as the
+	// attachment's identifier: identical content is stored once however
+	// many sessions reference it.
+	Hash      string `json:"hash"`
+	Name      string `json:"name,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	SizeBytes int    `json:"size_bytes"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// AttachmentStore keeps session attachments in the object store, keyed
+// by content hash. References are tracked per session in memory — like
+// the sessions themselves — and a blob is removed once its last
+// referencing session is gone. A restart drops the references with the
+// sessions; blobs orphaned that way are rewritten under the same key the
+// next time their content is attached.
+type AttachmentStore struct {
+	mu      sync.Mutex
+	objects storage.ObjectStore
+	// meta holds each stored blob's metadata; refs maps a blob hash to
+	// the sessions referencing it.
+	meta map[string]*Attachment
+	refs map[string]map[string]bool
+}
+
+// NewAttachmentStore creates a store over the given object store.
+func NewAttachmentStore(objects storage.ObjectStore) *AttachmentStore {
+	return &AttachmentStore{
+		objects: objects,
+		meta:    map[string]*Attachment{},
+		refs:    map[string]map[string]bool{},
+	}
+}
+
+// attachmentKey is the object store path of a blob.
+func attachmentKey(hash string) string {
+	return attachmentKeyPrefix + hash
+}
+
+// Put stores content for the session and returns its metadata. Content
+// already held — by this or any other session — is referenced instead of
+// written again.
+func (s *AttachmentStore) Put(ctx context.Context, sessionId, name, mediaType string, content []byte) (*Attachment, error) {
+	if len(content) == 0 {
+		return nil, util.NewInvalidInputError("The attachment content must not be empty")
+	}
+	if len(content) > maxAttachmentBytes {
+		return nil, util.NewInvalidInputError("The attachment is %v bytes; the limit is %v", len(content), maxAttachmentBytes)
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	if sessions := s.sessionAttachments(sessionId); len(sessions) >= maxAttachmentsPerSession && !s.refs[hash][sessionId] {
+		s.mu.Unlock()
+		return nil, util.NewInvalidInputError("Session %v already holds %v attachments; the limit is %v", sessionId, len(sessions), maxAttachmentsPerSession)
+	}
+	stored := s.meta[hash] != nil
+	s.mu.Unlock()
+
+	if !stored {
+		if err := s.objects.AddFile(ctx, content, attachmentKey(hash)); err != nil {
+			return nil, util.Wrapf(err, "Failed to store attachment %v", hash)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attachment := s.meta[hash]
+	if attachment == nil {
+		attachment = &Attachment{
+			Hash:      hash,
+			Name:      name,
+			MediaType: mediaType,
+			SizeBytes: len(content),
+			CreatedAt: time.Now().Unix(),
+		}
+		s.meta[hash] = attachment
+	}
+	if s.refs[hash] == nil {
+		s.refs[hash] = map[string]bool{}
+	}
+	s.refs[hash][sessionId] = true
+	return attachment, nil
+}
+
+// Get returns the metadata and content of an attachment the session
+// references. Sessions can only fetch their own attachments, so a leaked
+// hash alone grants nothing. The content is verified against the hash on
+// the way out — a content-addressable store detects corruption for free.
+func (s *AttachmentStore) Get(ctx context.Context, sessionId, hash string) (*Attachment, []byte, error) {
+	s.mu.Lock()
+	attachment := s.meta[hash]
+	referenced := s.refs[hash][sessionId]
+	s.mu.Unlock()
+	if attachment == nil || !referenced {
+		return nil, nil, util.NewResourceNotFoundError("Attachment", hash)
+	}
+	content, err := s.objects.GetFile(ctx, attachmentKey(hash))
+	if err != nil {
+		return nil, nil, util.Wrapf(err, "Failed to fetch attachment %v", hash)
+	}
+	if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != hash {
+		return nil, nil, util.NewInternalServerError(
+			fmt.Errorf("stored content does not match hash %v", hash),
+			"Attachment %v is corrupted in the object store", hash)
+	}
+	return attachment, content, nil
+}
+
+// List returns the session's attachments, oldest first.
+func (s *AttachmentStore) List(sessionId string) []Attachment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.sessionAttachments(sessionId)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt != out[j].CreatedAt {
+			return out[i].CreatedAt < out[j].CreatedAt
+		}
+		return out[i].Hash < out[j].Hash
+	})
+	return out
+}
+
+// sessionAttachments collects the attachments referencing the session.
+// Callers must hold s.mu.
+func (s *AttachmentStore) sessionAttachments(sessionId string) []Attachment {
+	out := []Attachment{}
+	for hash, sessions := range s.refs {
+		if sessions[sessionId] {
+			out = append(out, *s.meta[hash])
+		}
+	}
+	return out
+}
+
+// ReleaseSession drops the session's references and removes blobs no
+// other session holds. The session manager calls it while cleaning up,
+// possibly under its own lock, so the object store deletions run in the
+// background and are best-effort: a blob that outlives its deletion
+// attempt is rewritten in place if its content ever returns.
+func (s *AttachmentStore) ReleaseSession(sessionId string) {
+	s.mu.Lock()
+	var doomed []string
+	for hash, sessions := range s.refs {
+		if !sessions[sessionId] {
+			continue
+		}
+		delete(sessions, sessionId)
+		if len(sessions) == 0 {
+			delete(s.refs, hash)
+			delete(s.meta, hash)
+			doomed = append(doomed, hash)
+		}
+	}
+	s.mu.Unlock()
+	if len(doomed) == 0 {
+		return
+	}
+	go func() {
+		for _, hash := range doomed {
+			if err := s.objects.DeleteFile(context.Background(), attachmentKey(hash)); err != nil {
+				glog.Errorf("Failed to delete unreferenced AI attachment %v: %v", hash, err)
+			}
+		}
+	}()
+}
+
+// attachmentSession resolves the request's session after checking the
+// caller's identity and that attachments are enabled. It writes the
+// error response itself and returns an empty session ID on failure.
+func (h *SSEHandler) attachmentSession(w http.ResponseWriter, r *http.Request) (*AttachmentStore, string) {
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return nil, ""
+	}
+	store := h.aiServer.SessionManager().Attachments()
+	if store == nil {
+		h.writeError(w, http.StatusNotImplemented, fmt.Errorf("attachments are not enabled on this server"))
+		return nil, ""
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return nil, ""
+	}
+	// Ownership first: foreign sessions read as missing, matching Get.
+	if _, err := h.aiServer.SessionManager().Get(sessionId, userId); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return nil, ""
+	}
+	return store, sessionId
+}
+
+// UploadAttachment handles POST /apis/v2beta1/ai/sessions/{session_id}/attachments.
+// The raw body is stored content-addressed and the attachment metadata
+// returned; messages then reference the file by its hash.
+func (h *SSEHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	store, sessionId := h.attachmentSession(w, r)
+	if store == nil {
+		return
+	}
+	content, err := io.ReadAll(io.LimitReader(r.Body, maxAttachmentBytes+1))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	attachment, err := store.Put(r.Context(), sessionId, r.URL.Query().Get("name"), r.Header.Get("Content-Type"), content)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"attachment": attachment})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// ListAttachments handles GET /apis/v2beta1/ai/sessions/{session_id}/attachments.
+func (h *SSEHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	store, sessionId := h.attachmentSession(w, r)
+	if store == nil {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"attachments": store.List(sessionId)})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// GetAttachment handles GET /apis/v2beta1/ai/sessions/{session_id}/attachments/{hash}
+// and returns the attachment content.
+func (h *SSEHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	store, sessionId := h.attachmentSession(w, r)
+	if store == nil {
+		return
+	}
+	hash := mux.Vars(r)["hash"]
+	attachment, content, err := store.Get(r.Context(), sessionId, hash)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	mediaType := attachment.MediaType
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Cache-Control", "no-cache, private")
+	if attachment.Name != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.Name))
+	}
+	w.Write(content)
+}
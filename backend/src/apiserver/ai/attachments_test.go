@@ -0,0 +1,203 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectStore is an in-memory stand-in for the object store.
+type fakeObjectStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{files: map[string][]byte{}}
+}
+
+func (f *fakeObjectStore) AddFile(ctx context.Context, content []byte, filePath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[filePath] = append([]byte{}, content...)
+	return nil
+}
+
+func (f *fakeObjectStore) DeleteFile(ctx context.Context, filePath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.files, filePath)
+	return nil
+}
+
+func (f *fakeObjectStore) GetFile(ctx context.Context, filePath string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.files[filePath]
+	if !ok {
+		return nil, util.NewResourceNotFoundError("File", filePath)
+	}
+	return append([]byte{}, content...), nil
+}
+
+func (f *fakeObjectStore) GetFileReader(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	content, err := f.GetFile(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (f *fakeObjectStore) AddAsYamlFile(ctx context.Context, o interface{}, filePath string) error {
+	return nil
+}
+
+func (f *fakeObjectStore) GetFromYamlFile(ctx context.Context, o interface{}, filePath string) error {
+	return nil
+}
+
+func (f *fakeObjectStore) GetPipelineKey(pipelineId string) string { return pipelineId }
+
+func (f *fakeObjectStore) fileCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.files)
+}
+
+func TestAttachments_ContentIsStoredOnce(t *testing.T) {
+	objects := newFakeObjectStore()
+	store := NewAttachmentStore(objects)
+	ctx := context.Background()
+
+	first, err := store.Put(ctx, "s1", "spec.yaml", "application/yaml", []byte("pipeline spec"))
+	require.NoError(t, err)
+	second, err := store.Put(ctx, "s2", "copy.yaml", "application/yaml", []byte("pipeline spec"))
+	require.NoError(t, err)
+
+	// Identical content dedupes to one blob under one hash.
+	assert.Equal(t, first.Hash, second.Hash)
+	assert.Equal(t, 1, objects.fileCount())
+
+	attachment, content, err := store.Get(ctx, "s1", first.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, "pipeline spec", string(content))
+	assert.Equal(t, "spec.yaml", attachment.Name)
+}
+
+func TestAttachments_GetIsScopedToTheReferencingSession(t *testing.T) {
+	store := NewAttachmentStore(newFakeObjectStore())
+	ctx := context.Background()
+
+	attachment, err := store.Put(ctx, "s1", "report.md", "text/markdown", []byte("findings"))
+	require.NoError(t, err)
+
+	// A session that never attached the content cannot fetch it, even
+	// with the right hash.
+	_, _, err = store.Get(ctx, "s2", attachment.Hash)
+	assert.Error(t, err)
+	_, _, err = store.Get(ctx, "s1", attachment.Hash)
+	assert.NoError(t, err)
+}
+
+func TestAttachments_DetectsCorruptedContent(t *testing.T) {
+	objects := newFakeObjectStore()
+	store := NewAttachmentStore(objects)
+	ctx := context.Background()
+
+	attachment, err := store.Put(ctx, "s1", "", "", []byte("original"))
+	require.NoError(t, err)
+	require.NoError(t, objects.AddFile(ctx, []byte("tampered"), attachmentKey(attachment.Hash)))
+
+	_, _, err = store.Get(ctx, "s1", attachment.Hash)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "corrupted")
+}
+
+func TestAttachments_ReleaseDeletesOnlyUnreferencedBlobs(t *testing.T) {
+	objects := newFakeObjectStore()
+	store := NewAttachmentStore(objects)
+	ctx := context.Background()
+
+	shared, err := store.Put(ctx, "s1", "", "", []byte("shared"))
+	require.NoError(t, err)
+	_, err = store.Put(ctx, "s2", "", "", []byte("shared"))
+	require.NoError(t, err)
+	only, err := store.Put(ctx, "s1", "", "", []byte("s1 only"))
+	require.NoError(t, err)
+
+	store.ReleaseSession("s1")
+	// s1's references are gone immediately; the blob deletion runs in the
+	// background.
+	_, _, err = store.Get(ctx, "s1", shared.Hash)
+	assert.Error(t, err)
+	assert.Eventually(t, func() bool { return objects.fileCount() == 1 },
+		5*time.Second, 10*time.Millisecond, "only the shared blob should remain")
+	_ = only
+
+	// The shared blob survives until its last referencing session goes.
+	_, _, err = store.Get(ctx, "s2", shared.Hash)
+	assert.NoError(t, err)
+	store.ReleaseSession("s2")
+	assert.Eventually(t, func() bool { return objects.fileCount() == 0 },
+		5*time.Second, 10*time.Millisecond, "releasing the last reference should delete the blob")
+}
+
+func TestAttachments_Bounds(t *testing.T) {
+	store := NewAttachmentStore(newFakeObjectStore())
+	ctx := context.Background()
+
+	_, err := store.Put(ctx, "s1", "", "", nil)
+	assert.Error(t, err)
+
+	_, err = store.Put(ctx, "s1", "", "", make([]byte, maxAttachmentBytes+1))
+	assert.Error(t, err)
+
+	for i := 0; i < maxAttachmentsPerSession; i++ {
+		_, err = store.Put(ctx, "s1", "", "", []byte{byte(i)})
+		require.NoError(t, err)
+	}
+	_, err = store.Put(ctx, "s1", "", "", []byte("one too many"))
+	assert.Error(t, err)
+	// Re-attaching content the session already holds is not a new
+	// attachment and stays allowed.
+	_, err = store.Put(ctx, "s1", "", "", []byte{0})
+	assert.NoError(t, err)
+}
+
+func TestSessionManager_RemovalReleasesAttachments(t *testing.T) {
+	objects := newFakeObjectStore()
+	store := NewAttachmentStore(objects)
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	m.UseAttachments(store)
+
+	session, err := m.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), session.ID, "", "", []byte("working file"))
+	require.NoError(t, err)
+
+	require.NoError(t, m.Delete(session.ID, "alice"))
+	assert.Eventually(t, func() bool { return objects.fileCount() == 0 },
+		5*time.Second, 10*time.Millisecond, "deleting the session should release its attachments")
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records every tool invocation of the AI assistant to a
+// durable store, so security teams can review what the agent did on
+// their clusters. It complements the bounded in-memory action trace a
+// session keeps for its own introspection.
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+)
+
+// bufferSize bounds the records awaiting their database write; beyond
+// it new records are dropped rather than stalling chat turns.
+const bufferSize = 256
+
+// Store persists and lists audit events. The database-backed
+// implementation lives in the storage package.
+type Store interface {
+	AddEvent(event *model.AIAuditEvent) error
+	ListEvents(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error)
+}
+
+// Logger hands audit events to the store off the turn's critical path:
+// recording never blocks on the database, and a write failure costs the
+// record, not the chat turn.
+type Logger struct {
+	store  Store
+	events chan *model.AIAuditEvent
+	// pending tracks queued records so tests and shutdown hooks can wait
+	// for the writer to drain.
+	pending sync.WaitGroup
+}
+
+// NewLogger wraps a store and starts the background writer.
+func NewLogger(store Store) *Logger {
+	l := &Logger{store: store, events: make(chan *model.AIAuditEvent, bufferSize)}
+	go l.writeLoop()
+	return l
+}
+
+// Record queues one event for persistence, filling in its identity and
+// timestamp. A full buffer drops the record with a log entry.
+func (l *Logger) Record(event *model.AIAuditEvent) {
+	if event.UUID == "" {
+		event.UUID = uuid.NewString()
+	}
+	if event.CreatedAtInSec == 0 {
+		event.CreatedAtInSec = time.Now().Unix()
+	}
+	l.pending.Add(1)
+	select {
+	case l.events <- event:
+	default:
+		l.pending.Done()
+		glog.Warningf("The AI audit buffer is full; dropping the record for tool %v by %v", event.Tool, event.UserID)
+	}
+}
+
+// Events lists persisted events matching the query, newest-first.
+func (l *Logger) Events(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error) {
+	return l.store.ListEvents(query)
+}
+
+// Flush blocks until every queued record reached the store.
+func (l *Logger) Flush() {
+	l.pending.Wait()
+}
+
+func (l *Logger) writeLoop() {
+	for event := range l.events {
+		if err := l.store.AddEvent(event); err != nil {
+			glog.Errorf("Failed to persist AI audit event %v for tool %v: %v", event.UUID, event.Tool, err)
+		}
+		l.pending.Done()
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore collects added events in memory.
+type fakeStore struct {
+	mu     sync.Mutex
+	events []*model.AIAuditEvent
+	addErr error
+}
+
+func (f *fakeStore) AddEvent(event *model.AIAuditEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeStore) ListEvents(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.events, nil
+}
+
+func (f *fakeStore) added() []*model.AIAuditEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.AIAuditEvent{}, f.events...)
+}
+
+func TestRecord_PersistsWithIdentityAndTimestamp(t *testing.T) {
+	store := &fakeStore{}
+	logger := NewLogger(store)
+
+	logger.Record(&model.AIAuditEvent{UserID: "alice", Tool: "list_runs", Outcome: "succeeded"})
+	logger.Flush()
+
+	events := store.added()
+	require.Len(t, events, 1)
+	assert.NotEmpty(t, events[0].UUID)
+	assert.NotZero(t, events[0].CreatedAtInSec)
+	assert.Equal(t, "alice", events[0].UserID)
+}
+
+func TestRecord_KeepsAPresetIdentity(t *testing.T) {
+	store := &fakeStore{}
+	logger := NewLogger(store)
+
+	logger.Record(&model.AIAuditEvent{UUID: "fixed", CreatedAtInSec: 42, Tool: "list_runs"})
+	logger.Flush()
+
+	events := store.added()
+	require.Len(t, events, 1)
+	assert.Equal(t, "fixed", events[0].UUID)
+	assert.Equal(t, int64(42), events[0].CreatedAtInSec)
+}
+
+func TestRecord_AStoreFailureCostsOnlyTheRecord(t *testing.T) {
+	store := &fakeStore{addErr: errors.New("database is down")}
+	logger := NewLogger(store)
+
+	logger.Record(&model.AIAuditEvent{Tool: "list_runs"})
+	// Flush returning proves the writer did not wedge on the error.
+	logger.Flush()
+
+	assert.Empty(t, store.added())
+}
+
+func TestEvents_ReadsThroughToTheStore(t *testing.T) {
+	store := &fakeStore{}
+	logger := NewLogger(store)
+
+	logger.Record(&model.AIAuditEvent{Tool: "list_runs"})
+	logger.Flush()
+
+	events, err := logger.Events(&model.AIAuditQuery{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+)
+
+// AuditEventSummary is the client-facing form of one audited tool
+// invocation.
+type AuditEventSummary struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	SessionID     string `json:"session_id"`
+	Namespace     string `json:"namespace,omitempty"`
+	Tool          string `json:"tool"`
+	ArgumentsJSON string `json:"arguments_json,omitempty"`
+	Mutating      bool   `json:"mutating"`
+	Outcome       string `json:"outcome"`
+	Detail        string `json:"detail,omitempty"`
+	ApprovedBy    string `json:"approved_by,omitempty"`
+	DurationMs    int64  `json:"duration_ms"`
+	Time          int64  `json:"time"`
+}
+
+// AuditLog handles GET /apis/v2beta1/ai/audit. It lists the persisted
+// tool invocations newest-first, filtered by the query parameters
+// user_id, session_id, tool, outcome, mutating=true, since/until (epoch
+// seconds) and limit. Callers see their own events; identities listed
+// in AIAssistantAuditViewers may query across users.
+func (h *SSEHandler) AuditLog(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	log := h.aiServer.AuditLog()
+	if log == nil {
+		h.writeError(w, http.StatusNotImplemented, fmt.Errorf("the audit log is not enabled on this server"))
+		return
+	}
+	query, err := parseAuditQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !h.isAuditViewer(userId) {
+		// Non-viewers are pinned to their own events regardless of what
+		// the query asked for.
+		query.UserID = userId
+	}
+	events, err := log.Events(query)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	summaries := make([]*AuditEventSummary, 0, len(events))
+	for _, event := range events {
+		summaries = append(summaries, &AuditEventSummary{
+			ID:            event.UUID,
+			UserID:        event.UserID,
+			SessionID:     event.SessionUUID,
+			Namespace:     event.Namespace,
+			Tool:          event.Tool,
+			ArgumentsJSON: string(event.Arguments),
+			Mutating:      event.Mutating,
+			Outcome:       event.Outcome,
+			Detail:        event.Detail,
+			ApprovedBy:    event.ApprovedBy,
+			DurationMs:    event.DurationMs,
+			Time:          event.CreatedAtInSec,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"events": summaries})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// isAuditViewer reports whether the identity may query other users'
+// audit events.
+func (h *SSEHandler) isAuditViewer(userId string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.auditViewers[userId]
+}
+
+// viewerSet indexes the configured audit viewer identities.
+func viewerSet(viewers []string) map[string]bool {
+	set := make(map[string]bool, len(viewers))
+	for _, viewer := range viewers {
+		set[viewer] = true
+	}
+	return set
+}
+
+// parseAuditQuery translates the endpoint's query parameters into a
+// store query.
+func parseAuditQuery(values url.Values) (*model.AIAuditQuery, error) {
+	query := &model.AIAuditQuery{
+		UserID:       values.Get("user_id"),
+		SessionUUID:  values.Get("session_id"),
+		Tool:         values.Get("tool"),
+		Outcome:      values.Get("outcome"),
+		MutatingOnly: values.Get("mutating") == "true",
+	}
+	var err error
+	if query.SinceInSec, err = epochParam(values, "since"); err != nil {
+		return nil, err
+	}
+	if query.UntilInSec, err = epochParam(values, "until"); err != nil {
+		return nil, err
+	}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q; expected a positive integer", raw)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// epochParam parses an optional epoch-seconds query parameter.
+func epochParam(values url.Values, name string) (int64, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %v %q; expected epoch seconds", name, raw)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/audit"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryAuditStore records the query it was asked to list.
+type queryAuditStore struct {
+	mu        sync.Mutex
+	lastQuery *model.AIAuditQuery
+}
+
+func (s *queryAuditStore) AddEvent(event *model.AIAuditEvent) error { return nil }
+
+func (s *queryAuditStore) ListEvents(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastQuery = query
+	return []*model.AIAuditEvent{}, nil
+}
+
+func (s *queryAuditStore) last() *model.AIAuditQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastQuery
+}
+
+func auditLogFixture(store audit.Store, viewers []string) *SSEHandler {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	if store != nil {
+		server.UseAuditLog(store)
+	}
+	return NewSSEHandler(server, &config.Config{
+		Limits:       config.Limits{ChatRateLimit: 10},
+		AuditViewers: viewers,
+	}, nil)
+}
+
+func TestAuditLog_ReportsWhenTheLogIsDisabled(t *testing.T) {
+	h := auditLogFixture(nil, nil)
+	w := httptest.NewRecorder()
+
+	h.AuditLog(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/audit", nil))
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestAuditLog_PinsNonViewersToTheirOwnEvents(t *testing.T) {
+	store := &queryAuditStore{}
+	h := auditLogFixture(store, nil)
+	w := httptest.NewRecorder()
+
+	h.AuditLog(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/audit?user_id=someone-else", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, store.last())
+	assert.Equal(t, anonymousUser, store.last().UserID)
+}
+
+func TestAuditLog_ViewersMayQueryAcrossUsers(t *testing.T) {
+	store := &queryAuditStore{}
+	h := auditLogFixture(store, []string{anonymousUser})
+	w := httptest.NewRecorder()
+
+	h.AuditLog(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/audit?user_id=someone-else&tool=create_run", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, store.last())
+	assert.Equal(t, "someone-else", store.last().UserID)
+	assert.Equal(t, "create_run", store.last().Tool)
+}
+
+func TestAuditLog_RejectsMalformedWindows(t *testing.T) {
+	store := &queryAuditStore{}
+	h := auditLogFixture(store, nil)
+	w := httptest.NewRecorder()
+
+	h.AuditLog(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/audit?since=yesterday", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, store.last())
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Benchmarks for end-to-end StreamChat throughput with a synthetic
+// provider, establishing baselines (ns/op, B/op, MB/s) before
+// performance-oriented refactors of the streaming path.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+// benchModel emits deltas text chunks per turn; when toolFirst is set,
+// the opening turn of each conversation requests one tool call instead.
+// The suggestions pass is answered with an empty stream so the numbers
+// measure exactly one scripted conversation per iteration.
+type benchModel struct {
+	deltas    int
+	deltaText string
+	toolFirst bool
+}
+
+func (m *benchModel) Name() string { return "bench" }
+
+func (m *benchModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	if req.System == suggestionsSystemPrompt {
+		return nil
+	}
+	if m.toolFirst {
+		// A single user message means this is the opening turn.
+		if len(req.Messages) == 1 {
+			return cb(provider.StreamEvent{Type: provider.StreamEventToolCall, ToolCall: &provider.ToolCall{ID: "tc1", Name: "bench_result", ArgumentsJSON: "{}"}})
+		}
+		return nil
+	}
+	for i := 0; i < m.deltas; i++ {
+		if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: m.deltaText}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// benchResultTool returns a fixed large payload.
+type benchResultTool struct {
+	content string
+}
+
+func (t *benchResultTool) Name() string        { return "bench_result" }
+func (t *benchResultTool) Description() string { return "Returns a large synthetic payload." }
+func (t *benchResultTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *benchResultTool) Mutating() bool { return false }
+
+func (t *benchResultTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	return &tool.Result{Content: t.content}, nil
+}
+
+func benchSession(b *testing.B, m *SessionManager) *Session {
+	session, err := m.GetOrCreate("", "bench", "ns", tool.ModeAsk, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return session
+}
+
+// BenchmarkStreamChat_TextThroughput streams 1 MiB of assistant text per
+// conversation through the full turn pipeline.
+func BenchmarkStreamChat_TextThroughput(b *testing.B) {
+	const deltas, deltaSize = 256, 4096
+	model := &benchModel{deltas: deltas, deltaText: strings.Repeat("t", deltaSize)}
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, maxMessages: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+	emit := func(Event) error { return nil }
+
+	b.ReportAllocs()
+	b.SetBytes(deltas * deltaSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session := benchSession(b, m)
+		if err := server.StreamChat(context.Background(), session, "go", emit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkStreamChat_LargeToolResults runs a turn whose single tool
+// call returns a 256 KiB payload, covering the result post-processing
+// (timestamp and unit annotation) path.
+func BenchmarkStreamChat_LargeToolResults(b *testing.B) {
+	const resultSize = 256 * 1024
+	model := &benchModel{toolFirst: true}
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, maxMessages: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+	if err := server.registry.Register(&benchResultTool{content: strings.Repeat("r", resultSize)}); err != nil {
+		b.Fatal(err)
+	}
+	emit := func(Event) error { return nil }
+
+	b.ReportAllocs()
+	b.SetBytes(resultSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session := benchSession(b, m)
+		if err := server.StreamChat(context.Background(), session, "go", emit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingModel hangs until its context is cancelled, standing in for a
+// slow provider call.
+type blockingModel struct {
+	started chan struct{}
+}
+
+func (m *blockingModel) Name() string { return "blocking" }
+
+func (m *blockingModel) StreamChat(ctx context.Context, req *provider.ChatRequest, callback provider.StreamCallback) error {
+	close(m.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func cancelFixture(t *testing.T) (*AIServer, *blockingModel, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	model := &blockingModel{started: make(chan struct{})}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+	return server, model, session
+}
+
+func TestCancelTurn_AbortsTheInFlightTurn(t *testing.T) {
+	server, model, session := cancelFixture(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.StreamChat(context.Background(), session, "inspect all failing runs", func(Event) error { return nil })
+	}()
+	<-model.started
+	require.NoError(t, server.CancelTurn(session.ID, "alice"))
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the turn did not stop after cancellation")
+	}
+	// The session is idle again; a second cancel has nothing to abort.
+	assert.Error(t, server.CancelTurn(session.ID, "alice"))
+}
+
+func TestCancelTurn_RequiresATurnInFlight(t *testing.T) {
+	server, _, session := cancelFixture(t)
+	assert.Error(t, server.CancelTurn(session.ID, "alice"))
+}
+
+func TestCancelTurn_RejectsOtherUsers(t *testing.T) {
+	server, _, session := cancelFixture(t)
+	assert.Error(t, server.CancelTurn(session.ID, "mallory"))
+}
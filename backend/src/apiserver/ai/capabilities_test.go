@@ -0,0 +1,98 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deepToolDefinition() provider.ToolDefinition {
+	return provider.ToolDefinition{
+		Name:        "create_run",
+		Description: "Creates a run.",
+		InputSchema: map[string]interface{}{
+			"type":        "object",
+			"description": "Run creation arguments.",
+			"properties": map[string]interface{}{
+				"parameters": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"values": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"nested": map[string]interface{}{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestAdaptTools_UnlimitedCapabilitiesPassThrough(t *testing.T) {
+	defs := []provider.ToolDefinition{deepToolDefinition()}
+	assert.Equal(t, defs, provider.AdaptTools(defs, provider.Capabilities{}))
+}
+
+func TestAdaptTools_SimplifiesSchemasBeyondTheDepthLimit(t *testing.T) {
+	defs := []provider.ToolDefinition{deepToolDefinition()}
+
+	adapted := provider.AdaptTools(defs, provider.Capabilities{MaxSchemaDepth: 2})
+	require.Len(t, adapted, 1)
+	schema := adapted[0].InputSchema
+	// The top level survives with its description; the structure below
+	// the cutoff collapses to a permissive object, so the tool remains
+	// callable.
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, "Run creation arguments.", schema["description"])
+	properties, ok := schema["properties"].(map[string]interface{})
+	require.True(t, ok)
+	parameters, ok := properties["parameters"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"type": "object"}, parameters)
+	// The original definition is untouched.
+	assert.Equal(t, deepToolDefinition(), defs[0])
+}
+
+func TestAdaptTools_DropsToolsBeyondTheCountLimit(t *testing.T) {
+	var defs []provider.ToolDefinition
+	for i := 0; i < 5; i++ {
+		defs = append(defs, provider.ToolDefinition{
+			Name:        fmt.Sprintf("tool_%v", i),
+			InputSchema: map[string]interface{}{"type": "object"},
+		})
+	}
+
+	adapted := provider.AdaptTools(defs, provider.Capabilities{MaxTools: 3})
+	require.Len(t, adapted, 3)
+	// Registration order is preserved, so the core tools registered first
+	// survive and later additions are dropped.
+	for i, def := range adapted {
+		assert.Equal(t, fmt.Sprintf("tool_%v", i), def.Name)
+	}
+}
+
+func TestCapabilitiesFor_KnownProviderLimits(t *testing.T) {
+	assert.Equal(t, provider.Capabilities{MaxTools: 32, MaxSchemaDepth: 5}, provider.CapabilitiesFor("local"))
+	assert.Equal(t, provider.Capabilities{MaxTools: 64, MaxSchemaDepth: 6}, provider.CapabilitiesFor("vertex"))
+	assert.Equal(t, provider.Capabilities{}, provider.CapabilitiesFor("anthropic"))
+	assert.Equal(t, provider.Capabilities{}, provider.CapabilitiesFor(""))
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func capabilitiesFixture(t *testing.T) (*AIServer, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager:  m,
+		registry:        tool.NewRegistry(),
+		capsFingerprint: capabilityFingerprint(&config.Config{}),
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestApplyConfig_NotifiesOpenSessionsOfCapabilityChanges(t *testing.T) {
+	server, session := capabilitiesFixture(t)
+	var received []numberedEvent
+	detach := session.eventLog().Subscribe(func(ev numberedEvent) error {
+		received = append(received, ev)
+		return nil
+	})
+	defer detach()
+
+	server.ApplyConfig(&config.Config{FreezeWindows: []config.FreezeWindow{{
+		Cron:     "0 0 * * 6",
+		Duration: "48h",
+		Reason:   "release weekend",
+	}}})
+
+	require.Len(t, received, 1)
+	event := received[0].Event
+	assert.Equal(t, EventTypeCapabilitiesUpdated, event.Type)
+	payload := event.Payload.(*CapabilitiesUpdatedPayload)
+	tools := server.registry.ListForMode(tool.ModeAsk)
+	require.Len(t, payload.Tools, len(tools))
+	assert.NotEmpty(t, payload.Reason)
+}
+
+func TestApplyConfig_StaysQuietWhenCapabilitiesAreUnchanged(t *testing.T) {
+	server, session := capabilitiesFixture(t)
+	var received []numberedEvent
+	detach := session.eventLog().Subscribe(func(ev numberedEvent) error {
+		received = append(received, ev)
+		return nil
+	})
+	defer detach()
+
+	// Reloading limits does not touch capabilities, so open sessions
+	// are not bothered.
+	server.ApplyConfig(&config.Config{Limits: config.Limits{ChatRateLimit: 20}})
+
+	assert.Empty(t, received)
+	assert.Equal(t, int64(0), server.capabilitiesGeneration())
+}
+
+func TestApplyConfig_BumpsTheCapabilityGenerationOncePerChange(t *testing.T) {
+	server, _ := capabilitiesFixture(t)
+	cfg := &config.Config{Clusters: []config.Cluster{{Name: "eu-prod", Endpoint: "https://eu.example.com"}}}
+
+	server.ApplyConfig(cfg)
+	assert.Equal(t, int64(1), server.capabilitiesGeneration())
+
+	// The same configuration applied again is not a change.
+	server.ApplyConfig(cfg)
+	assert.Equal(t, int64(1), server.capabilitiesGeneration())
+}
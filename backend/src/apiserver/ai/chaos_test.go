@@ -0,0 +1,223 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Chaos harness for the agentic loop: a scenario-driven fault-injecting
+// ChatModel and Tool, plus invariant checks, covering the provider and
+// tool failure modes seen in production (malformed tool arguments,
+// mid-stream disconnects, stalls, flaky tools).
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chaosStep is one scripted provider behavior within a model turn.
+type chaosStep struct {
+	// delay before the step takes effect.
+	delay time.Duration
+	// text emits an incremental text delta.
+	text string
+	// toolCall emits a tool call request, possibly with malformed
+	// arguments.
+	toolCall *provider.ToolCall
+	// err aborts the stream at this point, simulating a provider
+	// disconnect.
+	err error
+}
+
+// chaosModel replays one step script per model turn. Turns beyond the
+// scenario produce an empty, clean stream so every scenario terminates.
+type chaosModel struct {
+	mu    sync.Mutex
+	turns [][]chaosStep
+	turn  int
+}
+
+func (m *chaosModel) Name() string { return "chaos" }
+
+func (m *chaosModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	m.mu.Lock()
+	var steps []chaosStep
+	if m.turn < len(m.turns) {
+		steps = m.turns[m.turn]
+		m.turn++
+	}
+	m.mu.Unlock()
+	for _, step := range steps {
+		if step.delay > 0 {
+			select {
+			case <-time.After(step.delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if step.err != nil {
+			return step.err
+		}
+		if step.text != "" {
+			if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: step.text}}); err != nil {
+				return err
+			}
+		}
+		if step.toolCall != nil {
+			if err := cb(provider.StreamEvent{Type: provider.StreamEventToolCall, ToolCall: step.toolCall}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chaosFlakyTool fails with the scripted error, after the scripted
+// delay.
+type chaosFlakyTool struct {
+	delay time.Duration
+	err   error
+}
+
+func (t *chaosFlakyTool) Name() string        { return "chaos_flaky" }
+func (t *chaosFlakyTool) Description() string { return "A scripted flaky tool." }
+func (t *chaosFlakyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *chaosFlakyTool) Mutating() bool { return false }
+
+func (t *chaosFlakyTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	if t.delay > 0 {
+		select {
+		case <-time.After(t.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &tool.Result{Content: "ok"}, nil
+}
+
+// assertSessionConsistent checks the session invariants every scenario
+// must uphold: the turn lock is released and no confirmation is left
+// pending, so the session remains usable.
+func assertSessionConsistent(t *testing.T, session *Session) {
+	t.Helper()
+	require.NoError(t, session.beginTurn())
+	session.endTurn()
+	assert.Nil(t, session.PendingConfirmation())
+}
+
+func TestStreamChat_SurvivesChaosScenarios(t *testing.T) {
+	goroutinesBefore := runtime.NumGoroutine()
+
+	scenarios := []struct {
+		name     string
+		turns    [][]chaosStep
+		flaky    *chaosFlakyTool
+		watchdog time.Duration
+		wantErr  bool
+	}{
+		{
+			name: "malformed tool arguments recover in the next turn",
+			turns: [][]chaosStep{
+				{{text: "checking"}, {toolCall: &provider.ToolCall{ID: "tc1", Name: "chaos_flaky", ArgumentsJSON: `{"broken":`}}},
+				{{text: "recovered"}},
+			},
+			flaky: &chaosFlakyTool{},
+		},
+		{
+			name: "unknown tool is reported, not fatal",
+			turns: [][]chaosStep{
+				{{toolCall: &provider.ToolCall{ID: "tc1", Name: "no_such_tool", ArgumentsJSON: "{}"}}},
+				{{text: "recovered"}},
+			},
+		},
+		{
+			name: "flaky tool failure is compacted into the turn",
+			turns: [][]chaosStep{
+				{{toolCall: &provider.ToolCall{ID: "tc1", Name: "chaos_flaky", ArgumentsJSON: "{}"}}},
+				{{text: "recovered"}},
+			},
+			flaky: &chaosFlakyTool{delay: 5 * time.Millisecond, err: errors.New("chaos: backend reset")},
+		},
+		{
+			name: "disconnect before substantial text fails the turn",
+			turns: [][]chaosStep{
+				{{text: "a"}, {err: errors.New("chaos: connection reset")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "disconnect after substantial text is salvaged",
+			turns: [][]chaosStep{
+				{{text: strings.Repeat("x", partialSalvageMinChars+1)}, {err: errors.New("chaos: connection reset")}},
+				{{text: "the rest"}},
+			},
+		},
+		{
+			name: "stalled provider is cut off by the watchdog",
+			turns: [][]chaosStep{
+				{{delay: time.Hour, text: "never"}},
+			},
+			watchdog: 20 * time.Millisecond,
+			wantErr:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var model provider.ChatModel = &chaosModel{turns: scenario.turns}
+			if scenario.watchdog > 0 {
+				model = newStreamWatchdog(model, scenario.watchdog)
+			}
+			m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+			server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+			if scenario.flaky != nil {
+				require.NoError(t, server.registry.Register(scenario.flaky))
+			}
+			session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+			require.NoError(t, err)
+
+			var done bool
+			err = server.StreamChat(context.Background(), session, "hello", func(e Event) error {
+				if e.Type == EventTypeDone {
+					done = true
+				}
+				return nil
+			})
+			if scenario.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, done)
+			}
+			assertSessionConsistent(t, session)
+		})
+	}
+
+	// No scenario may leak goroutines; allow a little scheduler noise.
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutinesBefore+3)
+}
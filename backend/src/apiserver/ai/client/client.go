@@ -0,0 +1,300 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a typed Go client for the AI assistant HTTP API, so
+// internal services and tests can drive chat turns, approvals and
+// session management without duplicating the auth header and SSE
+// protocol handling. It reuses the serving package's request and
+// response types; the wire format has a single definition.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+)
+
+// apiPrefix is where main.go mounts the assistant's routes.
+const apiPrefix = "/apis/v2beta1/ai"
+
+// maxEventBytes bounds a single SSE line; large confirmation payloads
+// are summarized server-side well below this.
+const maxEventBytes = 1 << 20
+
+// Client calls the AI assistant HTTP API of one API server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	// apiKey authenticates as a configured API-key identity via a bearer
+	// token; identity presents the Kubeflow identity header instead.
+	// With neither set the client is anonymous, which only works against
+	// a single-user deployment.
+	apiKey   string
+	identity string
+}
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithAPIKey authenticates with a configured static API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithIdentity presents the given user via the Kubeflow identity
+// header, for callers running behind the auth proxy boundary.
+func WithIdentity(user string) Option {
+	return func(c *Client) { c.identity = user }
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to add custom
+// transports or timeouts. Note that a client-level timeout also cuts
+// off long-running chat streams; prefer per-call contexts.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// New returns a client for the assistant API served at baseURL, e.g.
+// "http://ml-pipeline.kubeflow:8888".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is a non-2xx response from the API, carrying the HTTP status
+// and the server's user-safe message.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("the AI assistant API returned HTTP %v: %v", e.Status, e.Message)
+}
+
+// Event is one server-sent event from a chat stream. The payload stays
+// raw so callers decode only the event types they care about, matching
+// the payload structs in the ai package.
+type Event struct {
+	// ID is the stream sequence number, usable as Last-Event-ID when
+	// resuming a dropped stream.
+	ID   string
+	Type ai.EventType
+	Data json.RawMessage
+}
+
+// StreamChat posts one chat turn and delivers each event of the SSE
+// response to onEvent in order. It returns once the turn's done event
+// was delivered, or with the first error from the transport or the
+// callback. The client does not reconnect on its own; callers wanting
+// resumption keep the last event ID and call ResumeChat.
+func (c *Client) StreamChat(ctx context.Context, request *ai.ChatRequest, onEvent func(Event) error) error {
+	return c.streamChat(ctx, request, "", onEvent)
+}
+
+// ResumeChat reattaches to a session's running (or just finished) turn,
+// replaying every event after lastEventID before carrying the live rest
+// of the stream.
+func (c *Client) ResumeChat(ctx context.Context, sessionId, lastEventID string, onEvent func(Event) error) error {
+	return c.streamChat(ctx, &ai.ChatRequest{SessionID: sessionId}, lastEventID, onEvent)
+}
+
+func (c *Client) streamChat(ctx context.Context, request *ai.ChatRequest, lastEventID string, onEvent func(Event) error) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the chat request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+apiPrefix+"/chat", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build the chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	c.authorize(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("chat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return readEvents(resp.Body, onEvent)
+}
+
+// readEvents parses an SSE stream and delivers its events until the
+// done event, a callback error or the end of the stream.
+func readEvents(body io.Reader, onEvent func(Event) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventBytes)
+	var event Event
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// Blank line terminates one event. Comment pings produce empty
+			// dispatches, skipped here.
+			if event.Type != "" {
+				if err := onEvent(event); err != nil {
+					return err
+				}
+				if event.Type == ai.EventTypeDone {
+					return nil
+				}
+			}
+			event = Event{}
+		case strings.HasPrefix(line, ":"):
+			// Heartbeat comment; ignored.
+		case strings.HasPrefix(line, "id:"):
+			event.ID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			event.Type = ai.EventType(strings.TrimSpace(strings.TrimPrefix(line, "event:")))
+		case strings.HasPrefix(line, "data:"):
+			event.Data = json.RawMessage(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("the chat stream failed: %w", err)
+	}
+	return fmt.Errorf("the chat stream ended without a done event")
+}
+
+// Approve resolves a pending mutating tool call. Set an idempotency key
+// on the request when retrying over an unreliable network.
+func (c *Client) Approve(ctx context.Context, request *ai.ApproveRequest) error {
+	return c.doJSON(ctx, http.MethodPost, "/chat/approve", request, nil)
+}
+
+// Cancel aborts the session's in-flight chat turn server-side.
+func (c *Client) Cancel(ctx context.Context, sessionId string) error {
+	return c.doJSON(ctx, http.MethodPost, "/chat/cancel", &ai.CancelRequest{SessionID: sessionId}, nil)
+}
+
+// ListSessions returns the caller's sessions, newest first.
+func (c *Client) ListSessions(ctx context.Context) ([]ai.SessionSummary, error) {
+	var out struct {
+		Sessions []ai.SessionSummary `json:"sessions"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/sessions", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Sessions, nil
+}
+
+// GetSession returns the full session view: history, pending
+// confirmation and metadata.
+func (c *Client) GetSession(ctx context.Context, sessionId string) (*ai.SessionDetail, error) {
+	detail := &ai.SessionDetail{}
+	if err := c.doJSON(ctx, http.MethodGet, "/sessions/"+sessionId, nil, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// DeleteSession deletes (or, with persistence enabled, soft-deletes)
+// the session.
+func (c *Client) DeleteSession(ctx context.Context, sessionId string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/sessions/"+sessionId, nil, nil)
+}
+
+// QuickActions returns the quick action catalog the server offers.
+func (c *Client) QuickActions(ctx context.Context) ([]config.QuickAction, error) {
+	var out struct {
+		QuickActions []config.QuickAction `json:"quick_actions"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/quick_actions", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.QuickActions, nil
+}
+
+// doJSON performs one JSON request/response round trip. A nil out
+// discards the response body.
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	var body *bytes.Reader
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the request body: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+apiPrefix+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build the request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %v failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode the response from %v: %w", path, err)
+	}
+	return nil
+}
+
+// authorize attaches the configured credentials to the request.
+func (c *Client) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		return
+	}
+	if c.identity != "" {
+		req.Header.Set(common.GetKubeflowUserIDHeader(), common.GetKubeflowUserIDPrefix()+c.identity)
+	}
+}
+
+// decodeError turns a non-2xx response into an APIError, using the
+// server's {"error": ...} body when present.
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{Status: resp.StatusCode, Message: resp.Status}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+		apiErr.Message = body.Error
+	}
+	return apiErr
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamChat_DeliversEventsUntilDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiPrefix+"/chat", r.URL.Path)
+		assert.Contains(t, r.Header.Get(common.GetKubeflowUserIDHeader()), "alice@example.com")
+		var request ai.ChatRequest
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "list my runs", request.Message)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": ping\n\n")
+		fmt.Fprint(w, "id: 0\nevent: markdown\ndata: {\"text\":\"Here\"}\n\n")
+		fmt.Fprint(w, "id: 1\nevent: done\ndata: null\n\n")
+		// Anything after done must not reach the callback.
+		fmt.Fprint(w, "id: 2\nevent: markdown\ndata: {\"text\":\"stray\"}\n\n")
+	}))
+	defer server.Close()
+
+	var events []Event
+	err := New(server.URL, WithIdentity("alice@example.com")).StreamChat(context.Background(),
+		&ai.ChatRequest{Message: "list my runs"}, func(ev Event) error {
+			events = append(events, ev)
+			return nil
+		})
+	require.Nil(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, ai.EventTypeMarkdown, events[0].Type)
+	assert.Equal(t, "0", events[0].ID)
+	assert.JSONEq(t, `{"text":"Here"}`, string(events[0].Data))
+	assert.Equal(t, ai.EventTypeDone, events[1].Type)
+}
+
+func TestStreamChat_SurfacesHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": "too many chat requests, retry later"}`)
+	}))
+	defer server.Close()
+
+	err := New(server.URL).StreamChat(context.Background(), &ai.ChatRequest{Message: "hi"},
+		func(Event) error { t.Fatal("no events expected"); return nil })
+	apiErr, ok := err.(*APIError)
+	require.True(t, ok, "expected an APIError, got %v", err)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.Status)
+	assert.Contains(t, apiErr.Message, "too many chat requests")
+}
+
+func TestStreamChat_RejectsTruncatedStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 0\nevent: markdown\ndata: {\"text\":\"partial\"}\n\n")
+	}))
+	defer server.Close()
+
+	err := New(server.URL).StreamChat(context.Background(), &ai.ChatRequest{Message: "hi"},
+		func(Event) error { return nil })
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "without a done event")
+}
+
+func TestApprove_SendsBearerTokenAndBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiPrefix+"/chat/approve", r.URL.Path)
+		assert.Equal(t, "Bearer sk-bot", r.Header.Get("Authorization"))
+		var request ai.ApproveRequest
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "s1", request.SessionID)
+		assert.True(t, request.Approved)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	err := New(server.URL, WithAPIKey("sk-bot")).Approve(context.Background(),
+		&ai.ApproveRequest{SessionID: "s1", ToolCallID: "tc1", Approved: true})
+	assert.Nil(t, err)
+}
+
+func TestListSessions_DecodesTheListing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, `{"sessions": [{"session_id": "s1", "title": "why did my run fail", "mode": "ask"}]}`)
+	}))
+	defer server.Close()
+
+	sessions, err := New(server.URL).ListSessions(context.Background())
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "s1", sessions[0].SessionID)
+	assert.Equal(t, "why did my run fail", sessions[0].Title)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Assistant answers often contain fenced code blocks: pipeline specs in
+// YAML, SDK snippets in Python, kubectl one-liners. The raw markdown
+// stream is awkward for clients that want to offer copy, download or
+// apply actions on a snippet, so completed blocks are additionally
+// parsed server-side and emitted as structured code_block events with
+// the declared language and a stable content-derived ID.
+
+// codeBlockTracker incrementally scans streamed assistant text for
+// fenced code blocks. Deltas may split anywhere, including inside a
+// fence marker, so input is buffered per line and only complete lines
+// are interpreted.
+type codeBlockTracker struct {
+	// pending holds the trailing partial line of the stream.
+	pending string
+	// inBlock and language track the currently open fence, lines its
+	// accumulated content.
+	inBlock  bool
+	language string
+	lines    []string
+	// count numbers the completed blocks of this stream, for the ID.
+	count int
+}
+
+// feed consumes the next text delta and returns the code blocks it
+// completed, in order. A block only completes on its closing fence: a
+// block left open when the stream ends is treated as malformed output
+// and never reported.
+func (t *codeBlockTracker) feed(delta string) []*CodeBlockPayload {
+	t.pending += delta
+	var completed []*CodeBlockPayload
+	for {
+		newline := strings.IndexByte(t.pending, '\n')
+		if newline < 0 {
+			return completed
+		}
+		line := t.pending[:newline]
+		t.pending = t.pending[newline+1:]
+		if block := t.consumeLine(line); block != nil {
+			completed = append(completed, block)
+		}
+	}
+}
+
+// finish interprets the stream's final line, which has no trailing
+// newline, and returns a block it completes. Messages often end on the
+// closing fence itself.
+func (t *codeBlockTracker) finish() *CodeBlockPayload {
+	line := t.pending
+	t.pending = ""
+	if line == "" {
+		return nil
+	}
+	return t.consumeLine(line)
+}
+
+// consumeLine interprets one complete line, returning the finished
+// block when the line closes a fence.
+func (t *codeBlockTracker) consumeLine(line string) *CodeBlockPayload {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "```") {
+		if t.inBlock {
+			t.lines = append(t.lines, line)
+		}
+		return nil
+	}
+	if !t.inBlock {
+		t.inBlock = true
+		t.language = strings.TrimSpace(strings.TrimLeft(trimmed, "`"))
+		t.lines = nil
+		return nil
+	}
+	code := strings.Join(t.lines, "\n")
+	t.count++
+	block := &CodeBlockPayload{
+		ID:       codeBlockID(t.count, t.language, code),
+		Language: t.language,
+		Code:     code,
+	}
+	t.inBlock = false
+	t.language = ""
+	t.lines = nil
+	return block
+}
+
+// codeBlockID derives a stable ID from the block's position and
+// content, so re-rendered or re-extracted snippets keep their identity.
+func codeBlockID(ordinal int, language, code string) string {
+	sum := sha256.Sum256([]byte(language + "\x00" + code))
+	return fmt.Sprintf("code-%v-%x", ordinal, sum[:4])
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedAll streams the text through the tracker in the given chunk
+// sizes and collects the completed blocks, including the final line.
+func feedAll(t *codeBlockTracker, text string, chunk int) []*CodeBlockPayload {
+	var blocks []*CodeBlockPayload
+	for len(text) > 0 {
+		n := chunk
+		if n > len(text) {
+			n = len(text)
+		}
+		blocks = append(blocks, t.feed(text[:n])...)
+		text = text[n:]
+	}
+	if block := t.finish(); block != nil {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestCodeBlockTracker_ExtractsBlocksAcrossArbitrarySplits(t *testing.T) {
+	text := "Here is the spec:\n```yaml\nkind: Pipeline\nname: train\n```\nAnd a snippet:\n```python\nprint(1)\n```\nDone."
+	// Chunk sizes chosen to split inside fence markers and content lines.
+	for _, chunk := range []int{1, 3, 7, len(text)} {
+		blocks := feedAll(&codeBlockTracker{}, text, chunk)
+		require.Len(t, blocks, 2, "chunk size %v", chunk)
+		assert.Equal(t, "yaml", blocks[0].Language)
+		assert.Equal(t, "kind: Pipeline\nname: train", blocks[0].Code)
+		assert.Equal(t, "python", blocks[1].Language)
+		assert.Equal(t, "print(1)", blocks[1].Code)
+	}
+}
+
+func TestCodeBlockTracker_IDsAreStableAndOrdered(t *testing.T) {
+	text := "```yaml\na: 1\n```\n"
+	first := feedAll(&codeBlockTracker{}, text, 4)
+	second := feedAll(&codeBlockTracker{}, text, 11)
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	// The same content at the same position gets the same ID regardless
+	// of how the stream was chunked.
+	assert.Equal(t, first[0].ID, second[0].ID)
+	assert.Contains(t, first[0].ID, "code-1-")
+}
+
+func TestCodeBlockTracker_ClosingFenceWithoutTrailingNewline(t *testing.T) {
+	blocks := feedAll(&codeBlockTracker{}, "```sh\nkubectl get runs\n```", 5)
+
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "sh", blocks[0].Language)
+	assert.Equal(t, "kubectl get runs", blocks[0].Code)
+}
+
+func TestCodeBlockTracker_UnterminatedBlockIsDropped(t *testing.T) {
+	tracker := &codeBlockTracker{}
+	blocks := feedAll(tracker, "```yaml\nkind: Pipeline\n", 6)
+
+	assert.Empty(t, blocks)
+}
+
+func TestCodeBlockTracker_PlainTextYieldsNothing(t *testing.T) {
+	assert.Empty(t, feedAll(&codeBlockTracker{}, "No code here, just prose.\nAnother line.\n", 9))
+}
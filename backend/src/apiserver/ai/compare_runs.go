@@ -0,0 +1,162 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"google.golang.org/grpc/metadata"
+)
+
+// compareSummaryMaxTokens bounds the constrained narration pass; a few
+// paragraphs over up to ten runs fit comfortably within it.
+const compareSummaryMaxTokens = 600
+
+// compareSummarySystemPrompt constrains the narration pass to prose the
+// run comparison page can show verbatim.
+const compareSummarySystemPrompt = `You summarize a side-by-side comparison of Kubeflow Pipelines runs for a UI panel.
+Given the structured comparison JSON, reply with a short plain-text summary: what differs between the runs
+(state, duration, pipeline version, parameters) and anything that stands out, such as one run failing where
+the others succeeded. Two or three short paragraphs at most. No markdown headings, no code fences, no JSON.`
+
+// RunComparisonReport is the response of the compare-runs endpoint: the
+// structured side-by-side comparison plus a natural-language summary of
+// it. The structured part is authoritative; the summary is best-effort
+// and is omitted when the narration pass fails.
+type RunComparisonReport struct {
+	Comparison json.RawMessage `json:"comparison"`
+	Summary    string          `json:"summary,omitempty"`
+}
+
+// CompareRuns produces a comparison report for the given runs without a
+// chat session, so the run comparison page can embed it directly. The
+// structured half reuses the compare_runs tool — including its per-run
+// authorization — and the prose half is a constrained model pass over
+// the tool's output.
+func (s *AIServer) CompareRuns(ctx context.Context, userId string, runIds []string) (*RunComparisonReport, error) {
+	t := s.registry.Get("compare_runs")
+	if t == nil {
+		return nil, util.NewInternalServerError(fmt.Errorf("the compare_runs tool is not registered"), "Run comparison is not available")
+	}
+	// Tools authorize through the resource manager, which reads the
+	// identity from gRPC metadata; mirror what grpc-gateway would attach.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		strings.ToLower(common.GetKubeflowUserIDHeader()), common.GetKubeflowUserIDPrefix()+userId,
+	))
+	resourceManager := s.resourceManager
+	if s.sandbox != nil {
+		resourceManager = s.sandbox
+	}
+	ec := &tool.ExecContext{
+		ResourceManager: resourceManager,
+		UserID:          userId,
+		SignedLogLink:   s.logLinks.Link,
+		Timeout:         s.currentToolTimeout(),
+	}
+	execCtx, cancel := ec.BoundContext(ctx)
+	defer cancel()
+	result, err := t.Execute(execCtx, ec, map[string]interface{}{"run_ids": runIds})
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to compare the runs")
+	}
+	report := &RunComparisonReport{Comparison: json.RawMessage(result.Content)}
+	summary, err := s.narrateComparison(ctx, result.Content)
+	if err != nil {
+		glog.Warningf("Skipping the comparison summary for user %v: %v", userId, err)
+		return report, nil
+	}
+	report.Summary = summary
+	return report, nil
+}
+
+// narrateComparison turns the structured comparison into prose with a
+// constrained model pass: no tools and a tight token budget.
+func (s *AIServer) narrateComparison(ctx context.Context, comparisonJSON string) (string, error) {
+	var text strings.Builder
+	err := s.model.StreamChat(ctx, &provider.ChatRequest{
+		System:    compareSummarySystemPrompt,
+		Messages:  []provider.Message{{Role: provider.RoleUser, Content: comparisonJSON}},
+		MaxTokens: compareSummaryMaxTokens,
+	}, func(event provider.StreamEvent) error {
+		if event.Type == provider.StreamEventTextDelta {
+			text.WriteString(event.Delta.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", util.Wrap(err, "The comparison narration call failed")
+	}
+	summary := strings.TrimSpace(text.String())
+	if summary == "" {
+		return "", util.NewInternalServerError(fmt.Errorf("the model returned no text"), "The comparison narration produced no summary")
+	}
+	return summary, nil
+}
+
+// CompareRunsRequest is the body of the compare-runs endpoint.
+type CompareRunsRequest struct {
+	RunIDs []string `json:"run_ids"`
+}
+
+// CompareRuns handles POST /apis/v2beta1/ai/compare-runs. It returns a
+// comparison report as JSON rather than an SSE stream, since the run
+// comparison page renders it as a static panel.
+func (h *SSEHandler) CompareRuns(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	// The narration pass spends model tokens, so the endpoint shares the
+	// chat endpoint's rate limit and load shedding.
+	if !h.allowRequest(userId) {
+		h.writeError(w, http.StatusTooManyRequests, fmt.Errorf("too many requests, retry later"))
+		return
+	}
+	if shed, retryAfter := h.aiServer.Overloaded(); shed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		h.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("the assistant is overloaded, retry later"))
+		return
+	}
+	var request CompareRunsRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatRequestBytes)).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid compare-runs request body: %w", err))
+		return
+	}
+	report, err := h.aiServer.CompareRuns(r.Context(), userId, request.RunIDs)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNarrateComparison_ReturnsTheModelProse(t *testing.T) {
+	model := &summaryStubModel{reply: "  Run a failed on OOM while run b succeeded.\n"}
+	server := &AIServer{model: model}
+
+	summary, err := server.narrateComparison(context.Background(), `{"runs":[]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Run a failed on OOM while run b succeeded.", summary)
+	// The structured comparison is what the model narrates.
+	assert.Equal(t, `{"runs":[]}`, model.lastPrompt)
+}
+
+func TestNarrateComparison_RejectsEmptyAndFailedReplies(t *testing.T) {
+	server := &AIServer{model: &summaryStubModel{reply: "  "}}
+	_, err := server.narrateComparison(context.Background(), "{}")
+	assert.Error(t, err)
+
+	server = &AIServer{model: &summaryStubModel{err: errors.New("provider down")}}
+	_, err = server.narrateComparison(context.Background(), "{}")
+	assert.Error(t, err)
+}
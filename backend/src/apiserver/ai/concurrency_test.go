@@ -0,0 +1,142 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stallingModel announces every call on started and then hangs until its
+// context is cancelled, so tests can hold several turns open at once.
+type stallingModel struct {
+	started chan struct{}
+}
+
+func (m *stallingModel) Name() string { return "stalling" }
+
+func (m *stallingModel) StreamChat(ctx context.Context, req *provider.ChatRequest, callback provider.StreamCallback) error {
+	m.started <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func concurrencyFixture(t *testing.T, limit int) (*AIServer, *stallingModel) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	model := &stallingModel{started: make(chan struct{}, 4)}
+	server := &AIServer{
+		sessionManager:  m,
+		registry:        tool.NewRegistry(),
+		model:           model,
+		maxTurnsPerUser: limit,
+	}
+	return server, model
+}
+
+func awaitModelStart(t *testing.T, model *stallingModel) {
+	t.Helper()
+	select {
+	case <-model.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the turn never reached the model")
+	}
+}
+
+func TestStreamChat_RejectsTurnsOverThePerUserCap(t *testing.T) {
+	server, model := concurrencyFixture(t, 1)
+	first, err := server.sessionManager.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+	second, err := server.sessionManager.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.StreamChat(context.Background(), first, "inspect all failing runs", func(Event) error { return nil })
+	}()
+	awaitModelStart(t, model)
+
+	// The second session belongs to the same user, so the cap applies
+	// across both: the turn is rejected with the counts, not queued.
+	var events []Event
+	err = server.StreamChat(context.Background(), second, "and now compare them", func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventTypeConcurrencyExceeded, events[0].Type)
+	payload, ok := events[0].Payload.(*ConcurrencyExceededPayload)
+	require.True(t, ok)
+	assert.Equal(t, 1, payload.Limit)
+	assert.Equal(t, 1, payload.Active)
+	assert.Equal(t, EventTypeDone, events[1].Type)
+
+	require.NoError(t, server.CancelTurn(first.ID, "alice"))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestStreamChat_TheCapIsPerUser(t *testing.T) {
+	server, model := concurrencyFixture(t, 1)
+	alice, err := server.sessionManager.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+	bob, err := server.sessionManager.GetOrCreate("", "bob", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- server.StreamChat(context.Background(), alice, "inspect all failing runs", func(Event) error { return nil })
+	}()
+	awaitModelStart(t, model)
+
+	// Bob is under his own cap, so his turn runs while Alice's blocks.
+	go func() {
+		errs <- server.StreamChat(context.Background(), bob, "list my experiments", func(Event) error { return nil })
+	}()
+	awaitModelStart(t, model)
+
+	require.NoError(t, server.CancelTurn(alice.ID, "alice"))
+	require.NoError(t, server.CancelTurn(bob.ID, "bob"))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestStreamChat_AFinishedTurnFreesTheSlot(t *testing.T) {
+	server, model := concurrencyFixture(t, 1)
+	session, err := server.sessionManager.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.StreamChat(context.Background(), session, "inspect all failing runs", func(Event) error { return nil })
+	}()
+	awaitModelStart(t, model)
+	require.NoError(t, server.CancelTurn(session.ID, "alice"))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+
+	// The slot is back; the next turn is admitted, not rejected.
+	go func() {
+		errs <- server.StreamChat(context.Background(), session, "try again", func(Event) error { return nil })
+	}()
+	awaitModelStart(t, model)
+	require.NoError(t, server.CancelTurn(session.ID, "alice"))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"sigs.k8s.io/yaml"
+)
+
+// BundleVersion is the current bundle format version. Imports of other
+// versions are rejected rather than guessed at.
+const BundleVersion = 1
+
+// Bundle is the promotable subset of the assistant configuration as one
+// versioned YAML document, for carrying settings from a staging cluster
+// to production. It deliberately excludes credentials (provider keys,
+// API keys) and deployment-bound settings (enabling the subsystem,
+// provider selection, persistence), which stay with each cluster.
+//
+// A nil section leaves the corresponding running settings unchanged on
+// import; a present section replaces them. Durations are Go duration
+// strings, e.g. "30m"; an empty duration leaves the running value.
+type Bundle struct {
+	Version int `json:"version"`
+	// ExportedAt records when the bundle was produced (RFC3339);
+	// informational only.
+	ExportedAt string `json:"exported_at,omitempty"`
+	// Model overrides the provider's model identifier when non-empty.
+	Model         string               `json:"model,omitempty"`
+	Sessions      *BundleSessions      `json:"sessions,omitempty"`
+	Limits        *BundleLimits        `json:"limits,omitempty"`
+	Budgets       *BundleBudgets       `json:"budgets,omitempty"`
+	Confirmations *BundleConfirmations `json:"confirmations,omitempty"`
+	// The policy and catalog sections replace their running counterparts
+	// wholesale when present, including with an explicit empty list.
+	TelemetryOptOutNamespaces []string           `json:"telemetry_opt_out_namespaces,omitempty"`
+	AuditViewers              []string           `json:"audit_viewers,omitempty"`
+	QuickActions              []QuickAction      `json:"quick_actions,omitempty"`
+	PipelineTemplates         []PipelineTemplate `json:"pipeline_templates,omitempty"`
+	FreezeWindows             []FreezeWindow     `json:"freeze_windows,omitempty"`
+	IdentityDirectory         map[string]string  `json:"identity_directory,omitempty"`
+}
+
+// BundleSessions mirrors Sessions with portable duration strings.
+type BundleSessions struct {
+	Timeout               string `json:"timeout,omitempty"`
+	MaxAge                string `json:"max_age,omitempty"`
+	DeletedRetention      string `json:"deleted_retention,omitempty"`
+	MaxSessions           int    `json:"max_sessions"`
+	MaxMessagesPerSession int    `json:"max_messages_per_session"`
+	MaxSessionsPerUser    int    `json:"max_sessions_per_user"`
+	MaxMessagesPerUser    int    `json:"max_messages_per_user"`
+}
+
+// BundleLimits mirrors Limits with portable duration strings.
+type BundleLimits struct {
+	WriteTimeout         string `json:"write_timeout,omitempty"`
+	ProviderStallTimeout string `json:"provider_stall_timeout,omitempty"`
+	ToolTimeout          string `json:"tool_timeout,omitempty"`
+	HeartbeatInterval    string `json:"heartbeat_interval,omitempty"`
+	DegradeLatency       string `json:"degrade_latency,omitempty"`
+	DegradedModel        string `json:"degraded_model"`
+	ChatRateLimit        int    `json:"chat_rate_limit"`
+	ChatRateWindow       string `json:"chat_rate_window,omitempty"`
+	MCPRateWindow        string `json:"mcp_rate_window,omitempty"`
+	MaxInFlightTurns     int    `json:"max_in_flight_turns"`
+	MaxTurnsPerUser      int    `json:"max_turns_per_user"`
+	MCPRateLimit         int    `json:"mcp_rate_limit"`
+	ContextTokenBudget   int    `json:"context_token_budget"`
+}
+
+// BundleConfirmations mirrors Confirmations with a portable duration
+// string.
+type BundleConfirmations struct {
+	EscalateAfter     string `json:"escalate_after,omitempty"`
+	EscalationWebhook string `json:"escalation_webhook,omitempty"`
+	AutoDeny          bool   `json:"auto_deny"`
+}
+
+// BundleBudgets mirrors Budgets. Zero disables a budget, as at load.
+type BundleBudgets struct {
+	UserDailyTokens        int `json:"user_daily_tokens"`
+	UserMonthlyTokens      int `json:"user_monthly_tokens"`
+	NamespaceDailyTokens   int `json:"namespace_daily_tokens"`
+	NamespaceMonthlyTokens int `json:"namespace_monthly_tokens"`
+}
+
+// ExportBundle captures the promotable subset of a configuration.
+func ExportBundle(cfg *Config) *Bundle {
+	return &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Model:      cfg.Provider.Model,
+		Sessions: &BundleSessions{
+			Timeout:               cfg.Sessions.Timeout.String(),
+			MaxAge:                cfg.Sessions.MaxAge.String(),
+			DeletedRetention:      cfg.Sessions.DeletedRetention.String(),
+			MaxSessions:           cfg.Sessions.MaxSessions,
+			MaxMessagesPerSession: cfg.Sessions.MaxMessagesPerSession,
+			MaxSessionsPerUser:    cfg.Sessions.MaxSessionsPerUser,
+			MaxMessagesPerUser:    cfg.Sessions.MaxMessagesPerUser,
+		},
+		Limits: &BundleLimits{
+			WriteTimeout:         cfg.Limits.WriteTimeout.String(),
+			ProviderStallTimeout: cfg.Limits.ProviderStallTimeout.String(),
+			ToolTimeout:          cfg.Limits.ToolTimeout.String(),
+			HeartbeatInterval:    cfg.Limits.HeartbeatInterval.String(),
+			DegradeLatency:       cfg.Limits.DegradeLatency.String(),
+			DegradedModel:        cfg.Limits.DegradedModel,
+			ChatRateLimit:        cfg.Limits.ChatRateLimit,
+			ChatRateWindow:       cfg.Limits.ChatRateWindow.String(),
+			MCPRateWindow:        cfg.Limits.MCPRateWindow.String(),
+			MaxInFlightTurns:     cfg.Limits.MaxInFlightTurns,
+			MaxTurnsPerUser:      cfg.Limits.MaxTurnsPerUser,
+			MCPRateLimit:         cfg.Limits.MCPRateLimit,
+			ContextTokenBudget:   cfg.Limits.ContextTokenBudget,
+		},
+		Confirmations: &BundleConfirmations{
+			EscalateAfter:     cfg.Confirmations.EscalateAfter.String(),
+			EscalationWebhook: cfg.Confirmations.EscalationWebhook,
+			AutoDeny:          cfg.Confirmations.AutoDeny,
+		},
+		Budgets: &BundleBudgets{
+			UserDailyTokens:        cfg.Budgets.UserDailyTokens,
+			UserMonthlyTokens:      cfg.Budgets.UserMonthlyTokens,
+			NamespaceDailyTokens:   cfg.Budgets.NamespaceDailyTokens,
+			NamespaceMonthlyTokens: cfg.Budgets.NamespaceMonthlyTokens,
+		},
+		TelemetryOptOutNamespaces: cfg.TelemetryOptOutNamespaces,
+		AuditViewers:              cfg.AuditViewers,
+		QuickActions:              cfg.QuickActions,
+		PipelineTemplates:         cfg.PipelineTemplates,
+		FreezeWindows:             cfg.FreezeWindows,
+		IdentityDirectory:         cfg.IdentityDirectory,
+	}
+}
+
+// YAML renders the bundle as a YAML document.
+func (b *Bundle) YAML() ([]byte, error) {
+	out, err := yaml.Marshal(b)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to render the configuration bundle")
+	}
+	return out, nil
+}
+
+// ParseBundle parses a YAML (or JSON) bundle document.
+func ParseBundle(data []byte) (*Bundle, error) {
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict(data, bundle); err != nil {
+		return nil, util.NewInvalidInputError("Invalid configuration bundle: %v", err)
+	}
+	return bundle, nil
+}
+
+// Apply merges the bundle into a copy of the given configuration and
+// validates the result. The original is not modified.
+func (b *Bundle) Apply(cfg *Config) (*Config, error) {
+	if b.Version != BundleVersion {
+		return nil, util.NewInvalidInputError(
+			"Unsupported configuration bundle version %v; this server supports version %v", b.Version, BundleVersion)
+	}
+	merged := *cfg
+	if b.Model != "" {
+		merged.Provider.Model = b.Model
+	}
+	if b.Sessions != nil {
+		if err := applyDuration(&merged.Sessions.Timeout, b.Sessions.Timeout, "sessions.timeout"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Sessions.MaxAge, b.Sessions.MaxAge, "sessions.max_age"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Sessions.DeletedRetention, b.Sessions.DeletedRetention, "sessions.deleted_retention"); err != nil {
+			return nil, err
+		}
+		merged.Sessions.MaxSessions = b.Sessions.MaxSessions
+		merged.Sessions.MaxMessagesPerSession = b.Sessions.MaxMessagesPerSession
+		merged.Sessions.MaxSessionsPerUser = b.Sessions.MaxSessionsPerUser
+		merged.Sessions.MaxMessagesPerUser = b.Sessions.MaxMessagesPerUser
+	}
+	if b.Limits != nil {
+		if err := applyDuration(&merged.Limits.WriteTimeout, b.Limits.WriteTimeout, "limits.write_timeout"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.ProviderStallTimeout, b.Limits.ProviderStallTimeout, "limits.provider_stall_timeout"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.ToolTimeout, b.Limits.ToolTimeout, "limits.tool_timeout"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.HeartbeatInterval, b.Limits.HeartbeatInterval, "limits.heartbeat_interval"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.DegradeLatency, b.Limits.DegradeLatency, "limits.degrade_latency"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.ChatRateWindow, b.Limits.ChatRateWindow, "limits.chat_rate_window"); err != nil {
+			return nil, err
+		}
+		if err := applyDuration(&merged.Limits.MCPRateWindow, b.Limits.MCPRateWindow, "limits.mcp_rate_window"); err != nil {
+			return nil, err
+		}
+		merged.Limits.DegradedModel = b.Limits.DegradedModel
+		merged.Limits.ChatRateLimit = b.Limits.ChatRateLimit
+		merged.Limits.MaxInFlightTurns = b.Limits.MaxInFlightTurns
+		merged.Limits.MaxTurnsPerUser = b.Limits.MaxTurnsPerUser
+		merged.Limits.MCPRateLimit = b.Limits.MCPRateLimit
+		merged.Limits.ContextTokenBudget = b.Limits.ContextTokenBudget
+	}
+	if b.Confirmations != nil {
+		if err := applyDuration(&merged.Confirmations.EscalateAfter, b.Confirmations.EscalateAfter, "confirmations.escalate_after"); err != nil {
+			return nil, err
+		}
+		merged.Confirmations.EscalationWebhook = b.Confirmations.EscalationWebhook
+		merged.Confirmations.AutoDeny = b.Confirmations.AutoDeny
+	}
+	if b.Budgets != nil {
+		merged.Budgets = Budgets{
+			UserDailyTokens:        b.Budgets.UserDailyTokens,
+			UserMonthlyTokens:      b.Budgets.UserMonthlyTokens,
+			NamespaceDailyTokens:   b.Budgets.NamespaceDailyTokens,
+			NamespaceMonthlyTokens: b.Budgets.NamespaceMonthlyTokens,
+		}
+	}
+	if b.TelemetryOptOutNamespaces != nil {
+		merged.TelemetryOptOutNamespaces = b.TelemetryOptOutNamespaces
+	}
+	if b.AuditViewers != nil {
+		merged.AuditViewers = b.AuditViewers
+	}
+	if b.QuickActions != nil {
+		merged.QuickActions = b.QuickActions
+	}
+	if b.PipelineTemplates != nil {
+		merged.PipelineTemplates = b.PipelineTemplates
+	}
+	if b.FreezeWindows != nil {
+		merged.FreezeWindows = b.FreezeWindows
+	}
+	if b.IdentityDirectory != nil {
+		merged.IdentityDirectory = b.IdentityDirectory
+	}
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// applyDuration parses a bundle duration into its target; an empty
+// string leaves the running value unchanged.
+func applyDuration(target *time.Duration, raw, field string) error {
+	if raw == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return util.NewInvalidInputError("Invalid duration %q for %v in the configuration bundle: %v", raw, field, err)
+	}
+	*target = parsed
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RoundTripsThroughYAML(t *testing.T) {
+	cfg := validConfig()
+	cfg.Provider.Model = "claude-sonnet-4-20250514"
+	cfg.Provider.APIKey = "sk-secret"
+	cfg.Budgets.UserDailyTokens = 50000
+	cfg.QuickActions = []QuickAction{{ID: "triage", Title: "Triage", MessageTemplate: "triage {{run}}"}}
+	cfg.FreezeWindows = []FreezeWindow{{Cron: "@weekly", Duration: "48h", Reason: "weekend"}}
+
+	rendered, err := ExportBundle(cfg).YAML()
+	require.NoError(t, err)
+	// Credentials never leave the cluster in a bundle.
+	assert.NotContains(t, string(rendered), "sk-secret")
+
+	bundle, err := ParseBundle(rendered)
+	require.NoError(t, err)
+	applied, err := bundle.Apply(validConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "claude-sonnet-4-20250514", applied.Provider.Model)
+	assert.Equal(t, 50000, applied.Budgets.UserDailyTokens)
+	assert.Equal(t, cfg.QuickActions, applied.QuickActions)
+	assert.Equal(t, cfg.FreezeWindows, applied.FreezeWindows)
+	assert.Equal(t, DefaultSessionTimeout, applied.Sessions.Timeout)
+}
+
+func TestBundle_RejectsUnsupportedVersions(t *testing.T) {
+	_, err := (&Bundle{Version: 99}).Apply(validConfig())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version 99")
+}
+
+func TestBundle_RejectsInvalidResults(t *testing.T) {
+	bundle := &Bundle{Version: BundleVersion, Sessions: &BundleSessions{Timeout: "nonsense"}}
+	_, err := bundle.Apply(validConfig())
+	assert.Error(t, err)
+
+	// A bundle that merges into an invalid configuration is rejected as
+	// a whole; nothing is half-applied.
+	bundle = &Bundle{Version: BundleVersion, Limits: &BundleLimits{ChatRateLimit: -1}}
+	_, err = bundle.Apply(validConfig())
+	assert.Error(t, err)
+}
+
+func TestBundle_NilSectionsLeaveTheRunningValues(t *testing.T) {
+	cfg := validConfig()
+	cfg.Sessions.Timeout = 2 * time.Hour
+	cfg.QuickActions = []QuickAction{{ID: "keep", Title: "Keep", MessageTemplate: "keep"}}
+
+	applied, err := (&Bundle{Version: BundleVersion, Model: "new-model"}).Apply(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "new-model", applied.Provider.Model)
+	assert.Equal(t, 2*time.Hour, applied.Sessions.Timeout)
+	assert.Equal(t, cfg.QuickActions, applied.QuickActions)
+}
+
+func TestStore_ImportBundleAppliesLikeAReload(t *testing.T) {
+	store := NewStore(validConfig())
+	bundle := ExportBundle(store.Get())
+	bundle.Limits.ChatRateLimit = 7
+	bundle.QuickActions = []QuickAction{{ID: "promoted", Title: "Promoted", MessageTemplate: "go"}}
+
+	var notified *Config
+	store.Subscribe(func(cfg *Config) { notified = cfg })
+	require.NoError(t, store.ImportBundle(bundle))
+	assert.Equal(t, 7, store.Get().Limits.ChatRateLimit)
+	require.Len(t, store.Get().QuickActions, 1)
+	assert.Equal(t, "promoted", store.Get().QuickActions[0].ID)
+	require.NotNil(t, notified)
+	assert.Equal(t, 7, notified.Limits.ChatRateLimit)
+}
+
+func TestParseBundle_RejectsUnknownFields(t *testing.T) {
+	_, err := ParseBundle([]byte(strings.Join([]string{
+		"version: 1",
+		"rules: []",
+	}, "\n")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,726 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads and validates the AI assistant configuration in
+// one place. Values come through viper like the rest of the API server
+// configuration — environment variables and the mounted ConfigMap — but
+// the subsystem consumes a typed Config instead of scattering key
+// lookups across server, session, handler and provider code.
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/robfig/cron"
+)
+
+// Defaults applied when a key is unset.
+const (
+	DefaultProvider              = "anthropic"
+	DefaultSessionTimeout        = 30 * time.Minute
+	DefaultMaxSessions           = 1000
+	DefaultMaxMessagesPerSession = 200
+	// Per-user fairness caps, so one heavy user cannot push other users'
+	// sessions out of the global stores.
+	DefaultMaxSessionsPerUser   = 20
+	DefaultMaxMessagesPerUser   = 2000
+	DefaultWriteTimeout         = 2 * time.Minute
+	DefaultProviderStallTimeout = 60 * time.Second
+	// DefaultToolTimeout bounds a single tool execution, so one slow
+	// backend call cannot hold a chat turn open indefinitely.
+	DefaultToolTimeout   = 30 * time.Second
+	DefaultChatRateLimit = 20
+	// DefaultMCPRateLimit is higher than the chat budget because external
+	// agent loops legitimately make many more calls.
+	DefaultMCPRateLimit = 120
+	// DefaultRateLimitWindow is the window the request budgets apply
+	// over unless configured otherwise.
+	DefaultRateLimitWindow = time.Minute
+	// DefaultRateLimiterBackend keeps request counters per replica; the
+	// "redis" backend shares them so budgets hold across replicas.
+	DefaultRateLimiterBackend = "memory"
+	// DefaultMaxTurnsPerUser caps how many chat turns one user may have
+	// running at the same time across their sessions.
+	DefaultMaxTurnsPerUser = 3
+	// DefaultHeartbeatInterval spaces the keepalive comments written on
+	// idle SSE streams so ingress controllers do not cut them off.
+	DefaultHeartbeatInterval = 30 * time.Second
+	// DefaultContextTokenBudget caps the estimated token count of the
+	// history sent per model invocation, with headroom under mainstream
+	// model context windows.
+	DefaultContextTokenBudget = 100000
+	// DefaultDeletedSessionRetention keeps soft-deleted persisted
+	// sessions restorable for this long before they are purged.
+	DefaultDeletedSessionRetention = 72 * time.Hour
+)
+
+// Configuration keys, overridable via environment or the ConfigMap.
+const (
+	enabledKey               = "AIAssistantEnabled"
+	readOnlyKey              = "AIAssistantReadOnly"
+	sandboxKey               = "AIAssistantSandboxMode"
+	providerKey              = "AIAssistantProvider"
+	modelKey                 = "AIAssistantModel"
+	apiKeyKey                = "AIAssistantAPIKey"
+	providerAPIKeysKey       = "AIAssistantProviderAPIKeys"
+	baseURLKey               = "AIAssistantBaseURL"
+	credentialsJSONKey       = "AIAssistantCredentialsJSON"
+	fallbackProvidersKey     = "AIAssistantFallbackProviders"
+	providerMaxRetriesKey    = "AIAssistantProviderMaxRetries"
+	persistSessionsKey       = "AIAssistantPersistSessions"
+	sessionTimeoutKey        = "AIAssistantSessionTimeout"
+	deletedRetentionKey      = "AIAssistantDeletedSessionRetention"
+	maxSessionAgeKey         = "AIAssistantMaxSessionAge"
+	maxSessionsKey           = "AIAssistantMaxSessions"
+	maxMessagesPerSessionKey = "AIAssistantMaxMessagesPerSession"
+	maxSessionsPerUserKey    = "AIAssistantMaxSessionsPerUser"
+	maxMessagesPerUserKey    = "AIAssistantMaxMessagesPerUser"
+	writeTimeoutKey          = "AIAssistantWriteTimeout"
+	providerStallTimeoutKey  = "AIAssistantProviderStallTimeout"
+	toolTimeoutKey           = "AIAssistantToolTimeout"
+	chatRateLimitKey         = "AIAssistantRateLimit"
+	chatRateWindowKey        = "AIAssistantRateLimitWindow"
+	mcpRateWindowKey         = "AIAssistantMCPRateLimitWindow"
+	rateLimiterBackendKey    = "AIAssistantRateLimiterBackend"
+	redisAddressKey          = "AIAssistantRedisAddress"
+	redisPasswordKey         = "AIAssistantRedisPassword"
+	heartbeatIntervalKey     = "AIAssistantHeartbeatInterval"
+	maxInFlightTurnsKey      = "AIAssistantMaxInFlightTurns"
+	maxTurnsPerUserKey       = "AIAssistantMaxTurnsPerUser"
+	degradeLatencyKey        = "AIAssistantDegradeLatencyThreshold"
+	degradedModelKey         = "AIAssistantDegradedModel"
+	contextTokenBudgetKey    = "AIAssistantContextTokenBudget"
+	mcpRateLimitKey          = "AIAssistantMCPRateLimit"
+	userDailyTokensKey       = "AIAssistantUserDailyTokenBudget"
+	userMonthlyTokensKey     = "AIAssistantUserMonthlyTokenBudget"
+	nsDailyTokensKey         = "AIAssistantNamespaceDailyTokenBudget"
+	nsMonthlyTokensKey       = "AIAssistantNamespaceMonthlyTokenBudget"
+	telemetryOptOutKey       = "AIAssistantTelemetryOptOutNamespaces"
+	auditViewersKey          = "AIAssistantAuditViewers"
+	apiKeysKey               = "AIAssistantAPIKeys"
+	apiKeyNamespacesKey      = "AIAssistantAPIKeyNamespaces"
+	quickActionsKey          = "AIAssistantQuickActions"
+	pipelineTemplatesKey     = "AIAssistantPipelineTemplates"
+	freezeWindowsKey         = "AIAssistantFreezeWindows"
+	clustersKey              = "AIAssistantClusters"
+	identityDirectoryKey     = "AIAssistantIdentityDirectory"
+	escalateAfterKey         = "AIAssistantConfirmationEscalateAfter"
+	escalationWebhookKey     = "AIAssistantConfirmationEscalationWebhook"
+	escalationAutoDenyKey    = "AIAssistantConfirmationAutoDeny"
+	groundingCheckKey        = "AIAssistantGroundingCheck"
+)
+
+// QuickActionParameter declares one template parameter of a quick
+// action.
+type QuickActionParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// QuickAction is a parameterized prompt template offered to clients as a
+// one-click action. The message template references its parameters as
+// {{name}} placeholders.
+type QuickAction struct {
+	ID              string                 `json:"id"`
+	Title           string                 `json:"title"`
+	Description     string                 `json:"description,omitempty"`
+	MessageTemplate string                 `json:"message_template"`
+	Parameters      []QuickActionParameter `json:"parameters,omitempty"`
+}
+
+// PipelineTemplateParameter documents one parameter of a gallery
+// template.
+type PipelineTemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PipelineTemplate is one entry of the template gallery the assistant
+// recommends starting points from. A template either references a
+// pipeline shared in the cluster by name or points at a bundled example
+// by URL.
+type PipelineTemplate struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Tags are the keywords recommendations are matched against, e.g.
+	// "training" or "tabular".
+	Tags []string `json:"tags,omitempty"`
+	// PipelineName names an uploaded pipeline in the shared pipelines
+	// namespace that implements the template.
+	PipelineName string `json:"pipeline_name,omitempty"`
+	// SourceURL links to the template's source, e.g. a bundled example.
+	SourceURL  string                      `json:"source_url,omitempty"`
+	Parameters []PipelineTemplateParameter `json:"parameters,omitempty"`
+}
+
+// FreezeWindow is a recurring change freeze during which mutating tools
+// are blocked, e.g. weekends or release freezes. The cron expression
+// (https://godoc.org/github.com/robfig/cron) marks when each window
+// opens and the duration how long it stays open.
+type FreezeWindow struct {
+	Cron     string `json:"cron"`
+	Duration string `json:"duration"`
+	// Reason is included in the tool-blocked message shown to the model
+	// and the user, e.g. "weekend change freeze".
+	Reason string `json:"reason,omitempty"`
+}
+
+// Cluster registers one remote Kubeflow Pipelines installation a
+// federated assistant can query. Read-only tools accept the cluster
+// name as an optional parameter and forward such calls to the remote
+// assistant's MCP endpoint.
+type Cluster struct {
+	// Name identifies the cluster in tool calls and results.
+	Name string `json:"name"`
+	// Endpoint is the base URL of the remote API server, e.g.
+	// "https://kfp-eu.example.com".
+	Endpoint string `json:"endpoint"`
+	// APIKey authenticates against the remote assistant for callers
+	// without an identity-specific key; empty means only mapped
+	// identities may query this cluster.
+	APIKey string `json:"api_key,omitempty"`
+	// IdentityKeys maps a local identity to its own API key on the
+	// remote cluster, so the remote side enforces that user's RBAC
+	// instead of a shared service identity.
+	IdentityKeys map[string]string `json:"identity_keys,omitempty"`
+}
+
+// ProviderKey is one provider API key with its rotation weight, for
+// installations that spread load over several keys or orgs.
+type ProviderKey struct {
+	// Key is the API key itself.
+	Key string `json:"key"`
+	// Weight sets this key's share of the rotation relative to the other
+	// keys; zero counts as one.
+	Weight int `json:"weight,omitempty"`
+}
+
+// Provider selects and authenticates the chat model backend.
+type Provider struct {
+	// Name of the provider implementation, e.g. "anthropic".
+	Name string
+	// Model identifier passed to the provider.
+	Model string
+	// APIKey authenticates against the provider.
+	APIKey string
+	// Keys optionally rotates requests over several API keys by weight,
+	// so large installations can spread load across keys and survive one
+	// key hitting its org-level rate limit. When set it takes precedence
+	// over APIKey. Configured as a JSON list of {key, weight} objects.
+	Keys []ProviderKey
+	// BaseURL overrides the provider endpoint, e.g. for a proxy.
+	BaseURL string
+	// CredentialsJSON holds provider credentials that are not a plain API
+	// key, e.g. a GCP service-account key for the vertex provider.
+	CredentialsJSON string
+	// Fallbacks lists providers tried in order when the primary fails
+	// transiently. Configured as a comma-separated list of provider names;
+	// all providers share the credential settings above.
+	Fallbacks []string
+	// MaxRetries bounds pre-stream retries of transiently rejected
+	// provider requests. Zero selects the provider default; a negative
+	// value disables retries.
+	MaxRetries int
+}
+
+// Sessions bounds the in-memory session store.
+type Sessions struct {
+	// Persist mirrors sessions into the apiserver database, so they
+	// survive restarts and can be resumed on another replica. The
+	// in-memory store remains authoritative while the process runs.
+	Persist bool
+	// Timeout after which an idle session is reclaimed.
+	Timeout time.Duration
+	// MaxAge after which even an active session is reclaimed; zero
+	// disables the age cap.
+	MaxAge time.Duration
+	// MaxSessions caps concurrently held sessions; the least recently
+	// used one is evicted beyond that.
+	MaxSessions int
+	// MaxMessagesPerSession caps a session's history before trimming.
+	MaxMessagesPerSession int
+	// MaxSessionsPerUser caps one user's concurrent sessions; the user's
+	// own least recently used session is evicted beyond that.
+	MaxSessionsPerUser int
+	// MaxMessagesPerUser caps one user's total stored messages across
+	// sessions; history is trimmed oldest-first from the user's least
+	// recently used sessions beyond that.
+	MaxMessagesPerUser int
+	// DeletedRetention is how long deleted or expired persisted sessions
+	// stay soft-deleted and restorable before they are purged. Zero
+	// hard-deletes immediately. Only meaningful with Persist.
+	DeletedRetention time.Duration
+}
+
+// Limits throttles request handling.
+type Limits struct {
+	// WriteTimeout bounds writes on non-stream AI endpoints.
+	WriteTimeout time.Duration
+	// ProviderStallTimeout aborts a model stream with no events for this
+	// long; zero disables the watchdog.
+	ProviderStallTimeout time.Duration
+	// ToolTimeout bounds a single tool execution (including its dry-run
+	// validation); zero disables the deadline.
+	ToolTimeout time.Duration
+	// ChatRateLimit is the per-user chat request budget per
+	// ChatRateWindow.
+	ChatRateLimit int
+	// ChatRateWindow is the window the chat budget applies over.
+	ChatRateWindow time.Duration
+	// HeartbeatInterval spaces the keepalive comments written on an SSE
+	// stream while a turn produces no events, e.g. during a long tool
+	// execution or a pending confirmation; zero disables them.
+	HeartbeatInterval time.Duration
+	// MaxInFlightTurns caps chat turns running concurrently across all
+	// users and serving surfaces; beyond it new turns are shed with a
+	// retry hint instead of queueing up against the provider. Zero
+	// disables load shedding.
+	MaxInFlightTurns int
+	// MaxTurnsPerUser caps chat turns one user may have running at the
+	// same time across their sessions, so a single user cannot keep
+	// dozens of agent loops spinning against the provider. Zero
+	// disables the cap.
+	MaxTurnsPerUser int
+	// DegradeLatency degrades new turns to DegradedModel once the
+	// smoothed provider latency exceeds it; zero leaves only the
+	// in-flight saturation trigger.
+	DegradeLatency time.Duration
+	// DegradedModel is the cheaper model served while the system is
+	// saturated; empty disables degradation, so an overloaded server
+	// only sheds.
+	DegradedModel string
+	// MCPRateLimit is the per-client MCP request budget per
+	// MCPRateWindow; zero or negative disables MCP rate limiting.
+	MCPRateLimit int
+	// MCPRateWindow is the window the MCP budget applies over.
+	MCPRateWindow time.Duration
+	// ContextTokenBudget caps the estimated token count of the history
+	// sent per model invocation; older messages beyond it are dropped
+	// from the request (not from the stored session). Zero disables the
+	// budget.
+	ContextTokenBudget int
+}
+
+// Confirmations governs what happens to a mutating tool call whose
+// confirmation stays pending, which matters for semi-attended agent
+// workflows where the requesting user may have stepped away. By default
+// a confirmation simply waits for the user until the builtin timeout.
+type Confirmations struct {
+	// EscalateAfter is how long a confirmation may stay pending before
+	// the escalation fires; zero disables escalation.
+	EscalateAfter time.Duration
+	// EscalationWebhook is the URL notified with a JSON POST when a
+	// confirmation escalates, e.g. a Slack incoming webhook. The payload
+	// carries a ready-to-render text field plus the call's details.
+	EscalationWebhook string
+	// AutoDeny denies the escalated call with a recorded reason instead
+	// of leaving it pending until the confirmation timeout.
+	AutoDeny bool
+}
+
+// RateLimiter selects the backend enforcing the request budgets. The
+// in-memory backend counts per replica, so with N replicas a user
+// effectively gets N times the budget; the redis backend shares the
+// counters across replicas.
+type RateLimiter struct {
+	// Backend is "memory" or "redis".
+	Backend string
+	// RedisAddress is the host:port of the Redis server; required for
+	// the redis backend.
+	RedisAddress string
+	// RedisPassword authenticates against Redis; empty for an open
+	// server.
+	RedisPassword string
+}
+
+// Budgets caps token consumption over calendar windows (UTC). A zero
+// value disables the corresponding budget; by default none is enforced.
+type Budgets struct {
+	// UserDailyTokens and UserMonthlyTokens cap one user's consumption.
+	UserDailyTokens   int
+	UserMonthlyTokens int
+	// NamespaceDailyTokens and NamespaceMonthlyTokens cap the combined
+	// consumption of all sessions scoped to one namespace.
+	NamespaceDailyTokens   int
+	NamespaceMonthlyTokens int
+}
+
+// Config is the full AI assistant configuration.
+type Config struct {
+	// Enabled gates the whole subsystem.
+	Enabled bool
+	// ReadOnly forces the whole deployment into Ask mode: mutating tools
+	// are stripped from the registry at startup, sessions cannot be
+	// switched into Agent mode and approvals are rejected, regardless of
+	// what individual requests ask for.
+	ReadOnly bool
+	// Sandbox runs every tool against an in-memory fake resource manager
+	// seeded with sample pipelines and runs, so Agent mode can be tried
+	// and demoed without touching real workloads. Sessions are labeled
+	// as sandboxed in their metadata. Wired at startup, not reloadable.
+	Sandbox bool
+	// GroundingCheck enables a post-generation guardrail that verifies
+	// resource IDs mentioned in the final assistant answer against the
+	// turn's tool results, warning the client when the model cites a
+	// resource it never fetched.
+	GroundingCheck bool
+	Provider       Provider
+	Sessions       Sessions
+	Limits         Limits
+	Budgets        Budgets
+	Confirmations  Confirmations
+	// RateLimiter selects how the request budgets in Limits are
+	// enforced. The backend choice is wired at startup and not
+	// reloadable; the budgets and windows themselves are.
+	RateLimiter RateLimiter
+	// TelemetryOptOutNamespaces lists namespaces whose sessions are opted
+	// out of usage analytics and transcript persistence, for teams with
+	// stricter data handling requirements in a shared cluster. Configured
+	// as a comma-separated list; operational metrics and budget
+	// enforcement still apply.
+	TelemetryOptOutNamespaces []string
+	// AuditViewers lists identities allowed to query the tool invocation
+	// audit log across all users, e.g. a security team. Everyone else
+	// only sees their own events. Configured as a comma-separated list.
+	AuditViewers []string
+	// APIKeys maps an identity to its static API key, for non-browser
+	// clients that cannot present the Kubeflow identity header. Keyed by
+	// identity rather than by key because viper lowercases map keys,
+	// which would corrupt case-sensitive secrets.
+	APIKeys map[string]string
+	// APIKeyNamespaces maps an API-key identity to the namespace its
+	// sessions default to.
+	APIKeyNamespaces map[string]string
+	// QuickActions extends (or, by matching ID, overrides) the builtin
+	// quick action catalog. Configured as a JSON array.
+	QuickActions []QuickAction
+	// PipelineTemplates extends (or, by matching ID, overrides) the
+	// builtin template gallery. Configured as a JSON array.
+	PipelineTemplates []PipelineTemplate
+	// FreezeWindows lists recurring change freezes during which mutating
+	// tools are blocked regardless of session mode. Configured as a JSON
+	// array.
+	FreezeWindows []FreezeWindow
+	// Clusters lists remote installations a federated assistant forwards
+	// read-only tool calls to. Configured as a JSON array; it carries
+	// credentials and is therefore excluded from configuration bundles.
+	Clusters []Cluster
+	// IdentityDirectory maps a raw user identity to the display name used
+	// in audit output and session sharing, e.g. "alice@example.com" to
+	// "Alice Jones". Identities without an entry fall back to an installed
+	// directory hook and finally to the raw value.
+	IdentityDirectory map[string]string
+}
+
+// Load resolves the assistant configuration and validates it.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Enabled:        common.GetBoolConfigWithDefault(enabledKey, false),
+		ReadOnly:       common.GetBoolConfigWithDefault(readOnlyKey, false),
+		Sandbox:        common.GetBoolConfigWithDefault(sandboxKey, false),
+		GroundingCheck: common.GetBoolConfigWithDefault(groundingCheckKey, false),
+		Provider: Provider{
+			Name:            common.GetStringConfigWithDefault(providerKey, DefaultProvider),
+			Model:           common.GetStringConfigWithDefault(modelKey, ""),
+			APIKey:          common.GetStringConfigWithDefault(apiKeyKey, ""),
+			BaseURL:         common.GetStringConfigWithDefault(baseURLKey, ""),
+			CredentialsJSON: common.GetStringConfigWithDefault(credentialsJSONKey, ""),
+			Fallbacks:       splitList(common.GetStringConfigWithDefault(fallbackProvidersKey, "")),
+			MaxRetries:      common.GetIntConfigWithDefault(providerMaxRetriesKey, 0),
+		},
+		Sessions: Sessions{
+			Persist:               common.GetBoolConfigWithDefault(persistSessionsKey, false),
+			MaxSessions:           common.GetIntConfigWithDefault(maxSessionsKey, DefaultMaxSessions),
+			MaxMessagesPerSession: common.GetIntConfigWithDefault(maxMessagesPerSessionKey, DefaultMaxMessagesPerSession),
+			MaxSessionsPerUser:    common.GetIntConfigWithDefault(maxSessionsPerUserKey, DefaultMaxSessionsPerUser),
+			MaxMessagesPerUser:    common.GetIntConfigWithDefault(maxMessagesPerUserKey, DefaultMaxMessagesPerUser),
+		},
+		Limits: Limits{
+			ChatRateLimit:      common.GetIntConfigWithDefault(chatRateLimitKey, DefaultChatRateLimit),
+			MaxInFlightTurns:   common.GetIntConfigWithDefault(maxInFlightTurnsKey, 0),
+			MaxTurnsPerUser:    common.GetIntConfigWithDefault(maxTurnsPerUserKey, DefaultMaxTurnsPerUser),
+			DegradedModel:      common.GetStringConfigWithDefault(degradedModelKey, ""),
+			MCPRateLimit:       common.GetIntConfigWithDefault(mcpRateLimitKey, DefaultMCPRateLimit),
+			ContextTokenBudget: common.GetIntConfigWithDefault(contextTokenBudgetKey, DefaultContextTokenBudget),
+		},
+		RateLimiter: RateLimiter{
+			Backend:       common.GetStringConfigWithDefault(rateLimiterBackendKey, DefaultRateLimiterBackend),
+			RedisAddress:  common.GetStringConfigWithDefault(redisAddressKey, ""),
+			RedisPassword: common.GetStringConfigWithDefault(redisPasswordKey, ""),
+		},
+		Budgets: Budgets{
+			UserDailyTokens:        common.GetIntConfigWithDefault(userDailyTokensKey, 0),
+			UserMonthlyTokens:      common.GetIntConfigWithDefault(userMonthlyTokensKey, 0),
+			NamespaceDailyTokens:   common.GetIntConfigWithDefault(nsDailyTokensKey, 0),
+			NamespaceMonthlyTokens: common.GetIntConfigWithDefault(nsMonthlyTokensKey, 0),
+		},
+		Confirmations: Confirmations{
+			EscalationWebhook: common.GetStringConfigWithDefault(escalationWebhookKey, ""),
+			AutoDeny:          common.GetBoolConfigWithDefault(escalationAutoDenyKey, false),
+		},
+		TelemetryOptOutNamespaces: splitList(common.GetStringConfigWithDefault(telemetryOptOutKey, "")),
+		AuditViewers:              splitList(common.GetStringConfigWithDefault(auditViewersKey, "")),
+		APIKeys:                   common.GetMapConfig(apiKeysKey),
+		APIKeyNamespaces:          common.GetMapConfig(apiKeyNamespacesKey),
+		IdentityDirectory:         common.GetMapConfig(identityDirectoryKey),
+	}
+	var err error
+	if cfg.Sessions.Timeout, err = duration(sessionTimeoutKey, DefaultSessionTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.Sessions.MaxAge, err = duration(maxSessionAgeKey, 0); err != nil {
+		return nil, err
+	}
+	if cfg.Sessions.DeletedRetention, err = duration(deletedRetentionKey, DefaultDeletedSessionRetention); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.WriteTimeout, err = duration(writeTimeoutKey, DefaultWriteTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.ProviderStallTimeout, err = duration(providerStallTimeoutKey, DefaultProviderStallTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.ToolTimeout, err = duration(toolTimeoutKey, DefaultToolTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.HeartbeatInterval, err = duration(heartbeatIntervalKey, DefaultHeartbeatInterval); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.DegradeLatency, err = duration(degradeLatencyKey, 0); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.ChatRateWindow, err = duration(chatRateWindowKey, DefaultRateLimitWindow); err != nil {
+		return nil, err
+	}
+	if cfg.Limits.MCPRateWindow, err = duration(mcpRateWindowKey, DefaultRateLimitWindow); err != nil {
+		return nil, err
+	}
+	if cfg.Confirmations.EscalateAfter, err = duration(escalateAfterKey, 0); err != nil {
+		return nil, err
+	}
+	if raw := common.GetStringConfigWithDefault(providerAPIKeysKey, ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.Provider.Keys); err != nil {
+			return nil, util.NewInvalidInputError("Invalid %v: %v", providerAPIKeysKey, err)
+		}
+	}
+	if raw := common.GetStringConfigWithDefault(quickActionsKey, ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.QuickActions); err != nil {
+			return nil, util.NewInvalidInputError("Invalid %v: %v", quickActionsKey, err)
+		}
+	}
+	if raw := common.GetStringConfigWithDefault(pipelineTemplatesKey, ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.PipelineTemplates); err != nil {
+			return nil, util.NewInvalidInputError("Invalid %v: %v", pipelineTemplatesKey, err)
+		}
+	}
+	if raw := common.GetStringConfigWithDefault(freezeWindowsKey, ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.FreezeWindows); err != nil {
+			return nil, util.NewInvalidInputError("Invalid %v: %v", freezeWindowsKey, err)
+		}
+	}
+	if raw := common.GetStringConfigWithDefault(clustersKey, ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.Clusters); err != nil {
+			return nil, util.NewInvalidInputError("Invalid %v: %v", clustersKey, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate rejects configurations the subsystem cannot run with. It only
+// applies when the assistant is enabled, so a disabled deployment never
+// fails on leftover AI keys.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Provider.Name == "" {
+		return util.NewInvalidInputError("%v must not be empty", providerKey)
+	}
+	for i, key := range c.Provider.Keys {
+		if key.Key == "" {
+			return util.NewInvalidInputError("%v entry %v must carry a key", providerAPIKeysKey, i)
+		}
+		if key.Weight < 0 {
+			return util.NewInvalidInputError("%v entry %v must not have a negative weight, got %v", providerAPIKeysKey, i, key.Weight)
+		}
+	}
+	if c.Sessions.Timeout <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", sessionTimeoutKey, c.Sessions.Timeout)
+	}
+	if c.Sessions.MaxAge < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", maxSessionAgeKey, c.Sessions.MaxAge)
+	}
+	if c.Sessions.DeletedRetention < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", deletedRetentionKey, c.Sessions.DeletedRetention)
+	}
+	if c.Sessions.MaxSessions <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", maxSessionsKey, c.Sessions.MaxSessions)
+	}
+	if c.Sessions.MaxMessagesPerSession < 2 {
+		// A single turn needs at least a user and an assistant message.
+		return util.NewInvalidInputError("%v must be at least 2, got %v", maxMessagesPerSessionKey, c.Sessions.MaxMessagesPerSession)
+	}
+	if c.Sessions.MaxSessionsPerUser <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", maxSessionsPerUserKey, c.Sessions.MaxSessionsPerUser)
+	}
+	if c.Sessions.MaxMessagesPerUser < c.Sessions.MaxMessagesPerSession {
+		return util.NewInvalidInputError("%v must be at least %v (%v), got %v",
+			maxMessagesPerUserKey, maxMessagesPerSessionKey, c.Sessions.MaxMessagesPerSession, c.Sessions.MaxMessagesPerUser)
+	}
+	if c.Limits.ToolTimeout < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", toolTimeoutKey, c.Limits.ToolTimeout)
+	}
+	if c.Limits.ChatRateLimit <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", chatRateLimitKey, c.Limits.ChatRateLimit)
+	}
+	if c.Limits.ChatRateWindow <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", chatRateWindowKey, c.Limits.ChatRateWindow)
+	}
+	if c.Limits.MCPRateWindow <= 0 {
+		return util.NewInvalidInputError("%v must be positive, got %v", mcpRateWindowKey, c.Limits.MCPRateWindow)
+	}
+	switch c.RateLimiter.Backend {
+	// An unset backend means in-memory, so hand-built configurations
+	// keep working without naming one.
+	case "", "memory":
+	case "redis":
+		if c.RateLimiter.RedisAddress == "" {
+			return util.NewInvalidInputError("%v is required when %v is redis", redisAddressKey, rateLimiterBackendKey)
+		}
+	default:
+		return util.NewInvalidInputError("%v must be memory or redis, got %q", rateLimiterBackendKey, c.RateLimiter.Backend)
+	}
+	if c.Limits.HeartbeatInterval < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", heartbeatIntervalKey, c.Limits.HeartbeatInterval)
+	}
+	if c.Limits.MaxInFlightTurns < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", maxInFlightTurnsKey, c.Limits.MaxInFlightTurns)
+	}
+	if c.Limits.MaxTurnsPerUser < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", maxTurnsPerUserKey, c.Limits.MaxTurnsPerUser)
+	}
+	if c.Limits.DegradeLatency < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", degradeLatencyKey, c.Limits.DegradeLatency)
+	}
+	if c.Limits.ContextTokenBudget < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", contextTokenBudgetKey, c.Limits.ContextTokenBudget)
+	}
+	if c.Confirmations.EscalateAfter < 0 {
+		return util.NewInvalidInputError("%v must not be negative, got %v", escalateAfterKey, c.Confirmations.EscalateAfter)
+	}
+	if c.Confirmations.EscalateAfter > 0 && c.Confirmations.EscalationWebhook == "" && !c.Confirmations.AutoDeny {
+		return util.NewInvalidInputError("%v is set but configures no action; set %v or %v",
+			escalateAfterKey, escalationWebhookKey, escalationAutoDenyKey)
+	}
+	if webhook := c.Confirmations.EscalationWebhook; webhook != "" {
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return util.NewInvalidInputError("%v must be an http(s) URL, got %q", escalationWebhookKey, webhook)
+		}
+	}
+	for key, budget := range map[string]int{
+		userDailyTokensKey:   c.Budgets.UserDailyTokens,
+		userMonthlyTokensKey: c.Budgets.UserMonthlyTokens,
+		nsDailyTokensKey:     c.Budgets.NamespaceDailyTokens,
+		nsMonthlyTokensKey:   c.Budgets.NamespaceMonthlyTokens,
+	} {
+		if budget < 0 {
+			return util.NewInvalidInputError("%v must not be negative, got %v", key, budget)
+		}
+	}
+	for identity := range c.APIKeyNamespaces {
+		if _, ok := c.APIKeys[identity]; !ok {
+			return util.NewInvalidInputError("%v names identity %q that has no key in %v", apiKeyNamespacesKey, identity, apiKeysKey)
+		}
+	}
+	seenActions := map[string]bool{}
+	for _, action := range c.QuickActions {
+		if action.ID == "" || action.MessageTemplate == "" {
+			return util.NewInvalidInputError("Each entry in %v needs an id and a message_template", quickActionsKey)
+		}
+		if seenActions[action.ID] {
+			return util.NewInvalidInputError("%v defines quick action %q twice", quickActionsKey, action.ID)
+		}
+		seenActions[action.ID] = true
+	}
+	seenTemplates := map[string]bool{}
+	for _, template := range c.PipelineTemplates {
+		if template.ID == "" || template.Title == "" {
+			return util.NewInvalidInputError("Each entry in %v needs an id and a title", pipelineTemplatesKey)
+		}
+		if seenTemplates[template.ID] {
+			return util.NewInvalidInputError("%v defines template %q twice", pipelineTemplatesKey, template.ID)
+		}
+		seenTemplates[template.ID] = true
+	}
+	seenClusters := map[string]bool{}
+	for _, cluster := range c.Clusters {
+		if cluster.Name == "" || cluster.Endpoint == "" {
+			return util.NewInvalidInputError("Each entry in %v needs a name and an endpoint", clustersKey)
+		}
+		if seenClusters[cluster.Name] {
+			return util.NewInvalidInputError("%v defines cluster %q twice", clustersKey, cluster.Name)
+		}
+		seenClusters[cluster.Name] = true
+	}
+	for _, window := range c.FreezeWindows {
+		if _, err := cron.Parse(window.Cron); err != nil {
+			return util.NewInvalidInputError("Invalid cron expression %q in %v: %v", window.Cron, freezeWindowsKey, err)
+		}
+		parsed, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return util.NewInvalidInputError("Invalid duration %q in %v: %v", window.Duration, freezeWindowsKey, err)
+		}
+		if parsed <= 0 {
+			return util.NewInvalidInputError("Freeze window durations in %v must be positive, got %q", freezeWindowsKey, window.Duration)
+		}
+	}
+	return nil
+}
+
+// splitList parses a comma-separated list value, dropping empty entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// duration resolves a duration override, e.g. "2h" or "45m".
+func duration(key string, fallback time.Duration) (time.Duration, error) {
+	raw := common.GetStringConfigWithDefault(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, util.NewInvalidInputError("Invalid %v %q: %v", key, raw, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Enabled:  true,
+		Provider: Provider{Name: DefaultProvider},
+		Sessions: Sessions{
+			Timeout:               DefaultSessionTimeout,
+			MaxSessions:           DefaultMaxSessions,
+			MaxMessagesPerSession: DefaultMaxMessagesPerSession,
+			MaxSessionsPerUser:    DefaultMaxSessionsPerUser,
+			MaxMessagesPerUser:    DefaultMaxMessagesPerUser,
+		},
+		Limits: Limits{
+			WriteTimeout:   DefaultWriteTimeout,
+			ToolTimeout:    DefaultToolTimeout,
+			ChatRateLimit:  DefaultChatRateLimit,
+			ChatRateWindow: DefaultRateLimitWindow,
+			MCPRateLimit:   DefaultMCPRateLimit,
+			MCPRateWindow:  DefaultRateLimitWindow,
+		},
+	}
+}
+
+func TestValidate_AcceptsDefaults(t *testing.T) {
+	assert.Nil(t, validConfig().Validate())
+}
+
+func TestValidate_SkipsDisabledDeployments(t *testing.T) {
+	cfg := validConfig()
+	cfg.Enabled = false
+	cfg.Sessions.Timeout = -time.Minute
+	assert.Nil(t, cfg.Validate())
+}
+
+func TestValidate_RejectsBadLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"empty provider", func(c *Config) { c.Provider.Name = "" }},
+		{"provider key entry without a key", func(c *Config) { c.Provider.Keys = []ProviderKey{{Weight: 2}} }},
+		{"provider key with a negative weight", func(c *Config) { c.Provider.Keys = []ProviderKey{{Key: "sk-1", Weight: -1}} }},
+		{"non-positive session timeout", func(c *Config) { c.Sessions.Timeout = 0 }},
+		{"negative session age", func(c *Config) { c.Sessions.MaxAge = -time.Hour }},
+		{"non-positive session cap", func(c *Config) { c.Sessions.MaxSessions = 0 }},
+		{"message cap below one turn", func(c *Config) { c.Sessions.MaxMessagesPerSession = 1 }},
+		{"non-positive chat rate limit", func(c *Config) { c.Limits.ChatRateLimit = 0 }},
+		{"non-positive chat rate window", func(c *Config) { c.Limits.ChatRateWindow = 0 }},
+		{"non-positive mcp rate window", func(c *Config) { c.Limits.MCPRateWindow = -time.Second }},
+		{"unknown rate limiter backend", func(c *Config) { c.RateLimiter.Backend = "memcached" }},
+		{"redis backend without an address", func(c *Config) { c.RateLimiter.Backend = "redis" }},
+		{"negative tool timeout", func(c *Config) { c.Limits.ToolTimeout = -time.Second }},
+		{"negative per-user turn cap", func(c *Config) { c.Limits.MaxTurnsPerUser = -1 }},
+		{"non-positive per-user session cap", func(c *Config) { c.Sessions.MaxSessionsPerUser = 0 }},
+		{"per-user message cap below per-session cap", func(c *Config) { c.Sessions.MaxMessagesPerUser = c.Sessions.MaxMessagesPerSession - 1 }},
+		{"negative escalation deadline", func(c *Config) { c.Confirmations.EscalateAfter = -time.Minute }},
+		{"escalation without an action", func(c *Config) { c.Confirmations.EscalateAfter = time.Minute }},
+		{"escalation webhook without a scheme", func(c *Config) {
+			c.Confirmations = Confirmations{EscalateAfter: time.Minute, EscalationWebhook: "hooks.example.com/ai"}
+		}},
+		{"freeze window with bad cron", func(c *Config) { c.FreezeWindows = []FreezeWindow{{Cron: "not cron", Duration: "48h"}} }},
+		{"freeze window with bad duration", func(c *Config) { c.FreezeWindows = []FreezeWindow{{Cron: "@weekly", Duration: "two days"}} }},
+		{"freeze window with non-positive duration", func(c *Config) { c.FreezeWindows = []FreezeWindow{{Cron: "@weekly", Duration: "0s"}} }},
+	}
+	for _, test := range tests {
+		cfg := validConfig()
+		test.mutate(cfg)
+		assert.NotNil(t, cfg.Validate(), test.name)
+	}
+}
+
+func TestValidate_RejectsNamespaceForUnknownIdentity(t *testing.T) {
+	cfg := validConfig()
+	cfg.APIKeyNamespaces = map[string]string{"bot": "team-a"}
+	require.NotNil(t, cfg.Validate())
+
+	cfg.APIKeys = map[string]string{"bot": "secret"}
+	assert.Nil(t, cfg.Validate())
+}
@@ -0,0 +1,280 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Store holds the live assistant configuration and applies safe runtime
+// changes when the underlying ConfigMap or environment is re-read.
+// Settings that cannot take effect without a restart (enabling the
+// subsystem, switching or re-authenticating the provider) are reported
+// but left unchanged.
+type Store struct {
+	mu      sync.RWMutex
+	current *Config
+	// subscribers are notified with the new configuration after a reload
+	// changed at least one applied setting.
+	subscribers []func(*Config)
+	// load resolves the candidate configuration; overridable in tests.
+	load func() (*Config, error)
+}
+
+// NewStore wraps the initially loaded configuration.
+func NewStore(cfg *Config) *Store {
+	return &Store{current: cfg, load: Load}
+}
+
+// Get returns the current configuration. Callers must not mutate it.
+func (s *Store) Get() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Subscribe registers a callback invoked after each applied reload.
+func (s *Store) Subscribe(fn func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Reload re-resolves the configuration and applies the safe-to-change
+// subset. Each applied change is recorded in the server log as an audit
+// entry with the previous and new values and when it took effect.
+func (s *Store) Reload() error {
+	next, err := s.load()
+	if err != nil {
+		return util.Wrap(err, "Failed to reload the AI assistant configuration")
+	}
+	return s.applyNext(next)
+}
+
+// ImportBundle applies a configuration bundle like a reload: the safe
+// subset takes effect immediately, settings needing a restart are
+// reported. The bundle only changes the process; the mounted ConfigMap
+// stays authoritative and the next reload reverts to it, so promotion
+// still means updating the target cluster's ConfigMap with the bundle's
+// values.
+func (s *Store) ImportBundle(bundle *Bundle) error {
+	next, err := bundle.Apply(s.Get())
+	if err != nil {
+		return err
+	}
+	return s.applyNext(next)
+}
+
+// applyNext merges a candidate configuration into the running one.
+func (s *Store) applyNext(next *Config) error {
+	s.mu.Lock()
+	applied, changes, deferred := mergeReloadable(s.current, next)
+	if len(changes) == 0 && len(deferred) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	s.current = applied
+	subscribers := make([]func(*Config), len(s.subscribers))
+	copy(subscribers, s.subscribers)
+	s.mu.Unlock()
+
+	// The change arrives through the mounted ConfigMap or environment, so
+	// the actor is whoever edited those; the audit trail for "who" lives
+	// with the Kubernetes audit log of the ConfigMap itself.
+	if len(changes) > 0 {
+		glog.Infof("AI assistant configuration reloaded at %v: %v", time.Now().Format(time.RFC3339), strings.Join(changes, "; "))
+	}
+	for _, key := range deferred {
+		glog.Warningf("AI assistant configuration change to %v requires a restart to take effect", key)
+	}
+	if len(changes) > 0 {
+		for _, fn := range subscribers {
+			fn(applied)
+		}
+	}
+	return nil
+}
+
+// mergeReloadable combines the running configuration with the reloadable
+// fields of the newly loaded one. It returns the merged configuration,
+// audit entries for applied changes and the keys of changes that need a
+// restart.
+func mergeReloadable(current, next *Config) (*Config, []string, []string) {
+	merged := *current
+	var changes, deferred []string
+
+	apply := func(key string, old, new interface{}, set func()) {
+		if old == new {
+			return
+		}
+		set()
+		changes = append(changes, fmt.Sprintf("%v: %v -> %v", key, old, new))
+	}
+
+	apply(modelKey, merged.Provider.Model, next.Provider.Model, func() { merged.Provider.Model = next.Provider.Model })
+	apply(groundingCheckKey, merged.GroundingCheck, next.GroundingCheck, func() { merged.GroundingCheck = next.GroundingCheck })
+	apply(sessionTimeoutKey, merged.Sessions.Timeout, next.Sessions.Timeout, func() { merged.Sessions.Timeout = next.Sessions.Timeout })
+	apply(maxSessionAgeKey, merged.Sessions.MaxAge, next.Sessions.MaxAge, func() { merged.Sessions.MaxAge = next.Sessions.MaxAge })
+	apply(deletedRetentionKey, merged.Sessions.DeletedRetention, next.Sessions.DeletedRetention, func() {
+		merged.Sessions.DeletedRetention = next.Sessions.DeletedRetention
+	})
+	apply(maxSessionsKey, merged.Sessions.MaxSessions, next.Sessions.MaxSessions, func() { merged.Sessions.MaxSessions = next.Sessions.MaxSessions })
+	apply(maxMessagesPerSessionKey, merged.Sessions.MaxMessagesPerSession, next.Sessions.MaxMessagesPerSession, func() {
+		merged.Sessions.MaxMessagesPerSession = next.Sessions.MaxMessagesPerSession
+	})
+	apply(maxSessionsPerUserKey, merged.Sessions.MaxSessionsPerUser, next.Sessions.MaxSessionsPerUser, func() { merged.Sessions.MaxSessionsPerUser = next.Sessions.MaxSessionsPerUser })
+	apply(maxMessagesPerUserKey, merged.Sessions.MaxMessagesPerUser, next.Sessions.MaxMessagesPerUser, func() { merged.Sessions.MaxMessagesPerUser = next.Sessions.MaxMessagesPerUser })
+	apply(writeTimeoutKey, merged.Limits.WriteTimeout, next.Limits.WriteTimeout, func() { merged.Limits.WriteTimeout = next.Limits.WriteTimeout })
+	apply(toolTimeoutKey, merged.Limits.ToolTimeout, next.Limits.ToolTimeout, func() { merged.Limits.ToolTimeout = next.Limits.ToolTimeout })
+	apply(chatRateLimitKey, merged.Limits.ChatRateLimit, next.Limits.ChatRateLimit, func() { merged.Limits.ChatRateLimit = next.Limits.ChatRateLimit })
+	apply(chatRateWindowKey, merged.Limits.ChatRateWindow, next.Limits.ChatRateWindow, func() { merged.Limits.ChatRateWindow = next.Limits.ChatRateWindow })
+	apply(mcpRateWindowKey, merged.Limits.MCPRateWindow, next.Limits.MCPRateWindow, func() { merged.Limits.MCPRateWindow = next.Limits.MCPRateWindow })
+	apply(heartbeatIntervalKey, merged.Limits.HeartbeatInterval, next.Limits.HeartbeatInterval, func() { merged.Limits.HeartbeatInterval = next.Limits.HeartbeatInterval })
+	apply(maxInFlightTurnsKey, merged.Limits.MaxInFlightTurns, next.Limits.MaxInFlightTurns, func() { merged.Limits.MaxInFlightTurns = next.Limits.MaxInFlightTurns })
+	apply(maxTurnsPerUserKey, merged.Limits.MaxTurnsPerUser, next.Limits.MaxTurnsPerUser, func() { merged.Limits.MaxTurnsPerUser = next.Limits.MaxTurnsPerUser })
+	apply(degradeLatencyKey, merged.Limits.DegradeLatency, next.Limits.DegradeLatency, func() { merged.Limits.DegradeLatency = next.Limits.DegradeLatency })
+	apply(degradedModelKey, merged.Limits.DegradedModel, next.Limits.DegradedModel, func() { merged.Limits.DegradedModel = next.Limits.DegradedModel })
+	apply(mcpRateLimitKey, merged.Limits.MCPRateLimit, next.Limits.MCPRateLimit, func() { merged.Limits.MCPRateLimit = next.Limits.MCPRateLimit })
+	apply(contextTokenBudgetKey, merged.Limits.ContextTokenBudget, next.Limits.ContextTokenBudget, func() {
+		merged.Limits.ContextTokenBudget = next.Limits.ContextTokenBudget
+	})
+	apply(escalateAfterKey, merged.Confirmations.EscalateAfter, next.Confirmations.EscalateAfter, func() {
+		merged.Confirmations.EscalateAfter = next.Confirmations.EscalateAfter
+	})
+	apply(escalationWebhookKey, merged.Confirmations.EscalationWebhook, next.Confirmations.EscalationWebhook, func() {
+		merged.Confirmations.EscalationWebhook = next.Confirmations.EscalationWebhook
+	})
+	apply(escalationAutoDenyKey, merged.Confirmations.AutoDeny, next.Confirmations.AutoDeny, func() { merged.Confirmations.AutoDeny = next.Confirmations.AutoDeny })
+	apply(userDailyTokensKey, merged.Budgets.UserDailyTokens, next.Budgets.UserDailyTokens, func() { merged.Budgets.UserDailyTokens = next.Budgets.UserDailyTokens })
+	apply(userMonthlyTokensKey, merged.Budgets.UserMonthlyTokens, next.Budgets.UserMonthlyTokens, func() { merged.Budgets.UserMonthlyTokens = next.Budgets.UserMonthlyTokens })
+	apply(nsDailyTokensKey, merged.Budgets.NamespaceDailyTokens, next.Budgets.NamespaceDailyTokens, func() { merged.Budgets.NamespaceDailyTokens = next.Budgets.NamespaceDailyTokens })
+	apply(nsMonthlyTokensKey, merged.Budgets.NamespaceMonthlyTokens, next.Budgets.NamespaceMonthlyTokens, func() {
+		merged.Budgets.NamespaceMonthlyTokens = next.Budgets.NamespaceMonthlyTokens
+	})
+
+	if !reflect.DeepEqual(merged.TelemetryOptOutNamespaces, next.TelemetryOptOutNamespaces) {
+		merged.TelemetryOptOutNamespaces = next.TelemetryOptOutNamespaces
+		changes = append(changes, fmt.Sprintf("%v: %v -> %v", telemetryOptOutKey, current.TelemetryOptOutNamespaces, next.TelemetryOptOutNamespaces))
+	}
+	if !reflect.DeepEqual(merged.AuditViewers, next.AuditViewers) {
+		merged.AuditViewers = next.AuditViewers
+		changes = append(changes, fmt.Sprintf("%v: %v -> %v", auditViewersKey, current.AuditViewers, next.AuditViewers))
+	}
+
+	// Key material is masked in the audit entry: only the identity set is
+	// reported.
+	if !sameStringMap(merged.APIKeys, next.APIKeys) {
+		merged.APIKeys = next.APIKeys
+		changes = append(changes, fmt.Sprintf("%v: identities %v -> %v", apiKeysKey, identities(current.APIKeys), identities(next.APIKeys)))
+	}
+	if !sameStringMap(merged.APIKeyNamespaces, next.APIKeyNamespaces) {
+		merged.APIKeyNamespaces = next.APIKeyNamespaces
+		changes = append(changes, fmt.Sprintf("%v updated", apiKeyNamespacesKey))
+	}
+	if !sameStringMap(merged.IdentityDirectory, next.IdentityDirectory) {
+		merged.IdentityDirectory = next.IdentityDirectory
+		changes = append(changes, fmt.Sprintf("%v updated (%v identities)", identityDirectoryKey, len(next.IdentityDirectory)))
+	}
+	if !reflect.DeepEqual(merged.QuickActions, next.QuickActions) {
+		merged.QuickActions = next.QuickActions
+		changes = append(changes, fmt.Sprintf("%v updated (%v actions)", quickActionsKey, len(next.QuickActions)))
+	}
+	if !reflect.DeepEqual(merged.PipelineTemplates, next.PipelineTemplates) {
+		merged.PipelineTemplates = next.PipelineTemplates
+		changes = append(changes, fmt.Sprintf("%v updated (%v templates)", pipelineTemplatesKey, len(next.PipelineTemplates)))
+	}
+	if !reflect.DeepEqual(merged.FreezeWindows, next.FreezeWindows) {
+		merged.FreezeWindows = next.FreezeWindows
+		changes = append(changes, fmt.Sprintf("%v updated (%v windows)", freezeWindowsKey, len(next.FreezeWindows)))
+	}
+	// Cluster entries carry credentials; only the count is reported.
+	if !reflect.DeepEqual(merged.Clusters, next.Clusters) {
+		merged.Clusters = next.Clusters
+		changes = append(changes, fmt.Sprintf("%v updated (%v clusters)", clustersKey, len(next.Clusters)))
+	}
+
+	if merged.Enabled != next.Enabled {
+		deferred = append(deferred, enabledKey)
+	}
+	if merged.ReadOnly != next.ReadOnly {
+		// Mutating tools are stripped from the registry at startup, so the
+		// flag cannot be applied (in either direction) to a running server.
+		deferred = append(deferred, readOnlyKey)
+	}
+	if merged.Sandbox != next.Sandbox {
+		// The sandbox resource manager is wired at startup.
+		deferred = append(deferred, sandboxKey)
+	}
+	if merged.Provider.Name != next.Provider.Name {
+		deferred = append(deferred, providerKey)
+	}
+	if merged.Provider.APIKey != next.Provider.APIKey {
+		deferred = append(deferred, apiKeyKey)
+	}
+	if !reflect.DeepEqual(merged.Provider.Keys, next.Provider.Keys) {
+		// The key ring is handed to the provider at construction time.
+		deferred = append(deferred, providerAPIKeysKey)
+	}
+	if merged.Provider.CredentialsJSON != next.Provider.CredentialsJSON {
+		deferred = append(deferred, credentialsJSONKey)
+	}
+	if merged.Provider.BaseURL != next.Provider.BaseURL {
+		deferred = append(deferred, baseURLKey)
+	}
+	if !reflect.DeepEqual(merged.Provider.Fallbacks, next.Provider.Fallbacks) {
+		// The fallback chain is constructed at startup alongside the
+		// primary provider.
+		deferred = append(deferred, fallbackProvidersKey)
+	}
+	if merged.Provider.MaxRetries != next.Provider.MaxRetries {
+		// Providers resolve their retry budget at construction time.
+		deferred = append(deferred, providerMaxRetriesKey)
+	}
+	if merged.Sessions.Persist != next.Sessions.Persist {
+		// The session store is attached at startup.
+		deferred = append(deferred, persistSessionsKey)
+	}
+	return &merged, changes, deferred
+}
+
+func sameStringMap(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// identities lists a key map's identities in stable order for logging.
+func identities(keys map[string]string) []string {
+	out := make([]string, 0, len(keys))
+	for identity := range keys {
+		out = append(out, identity)
+	}
+	sort.Strings(out)
+	return out
+}
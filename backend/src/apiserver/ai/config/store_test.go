@@ -0,0 +1,97 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReloadable_AppliesSafeChanges(t *testing.T) {
+	current := validConfig()
+	next := validConfig()
+	next.Provider.Model = "claude-3-5-haiku-latest"
+	next.Limits.ChatRateLimit = 50
+	next.Sessions.Timeout = time.Hour
+
+	merged, changes, deferred := mergeReloadable(current, next)
+	assert.Equal(t, "claude-3-5-haiku-latest", merged.Provider.Model)
+	assert.Equal(t, 50, merged.Limits.ChatRateLimit)
+	assert.Equal(t, time.Hour, merged.Sessions.Timeout)
+	assert.Len(t, changes, 3)
+	assert.Empty(t, deferred)
+}
+
+func TestMergeReloadable_DefersRestartOnlyChanges(t *testing.T) {
+	current := validConfig()
+	next := validConfig()
+	next.Provider.Name = "other"
+	next.Provider.APIKey = "rotated"
+
+	merged, changes, deferred := mergeReloadable(current, next)
+	assert.Equal(t, current.Provider.Name, merged.Provider.Name)
+	assert.Equal(t, current.Provider.APIKey, merged.Provider.APIKey)
+	assert.Empty(t, changes)
+	assert.Len(t, deferred, 2)
+}
+
+func TestMergeReloadable_MasksAPIKeysInAuditEntries(t *testing.T) {
+	current := validConfig()
+	next := validConfig()
+	next.APIKeys = map[string]string{"bot": "s3cret"}
+	next.APIKeyNamespaces = map[string]string{"bot": "team-a"}
+
+	merged, changes, _ := mergeReloadable(current, next)
+	assert.Equal(t, next.APIKeys, merged.APIKeys)
+	require.Len(t, changes, 2)
+	for _, change := range changes {
+		assert.False(t, strings.Contains(change, "s3cret"), change)
+	}
+}
+
+func TestMergeReloadable_NoChanges(t *testing.T) {
+	current := validConfig()
+	_, changes, deferred := mergeReloadable(current, validConfig())
+	assert.Empty(t, changes)
+	assert.Empty(t, deferred)
+}
+
+func TestStore_ReloadNotifiesSubscribers(t *testing.T) {
+	store := NewStore(validConfig())
+	next := validConfig()
+	next.Limits.ChatRateLimit = 7
+	store.load = func() (*Config, error) { return next, nil }
+	var seen *Config
+	store.Subscribe(func(cfg *Config) { seen = cfg })
+
+	require.Nil(t, store.Reload())
+	require.NotNil(t, seen)
+	assert.Equal(t, 7, seen.Limits.ChatRateLimit)
+	assert.Equal(t, 7, store.Get().Limits.ChatRateLimit)
+}
+
+func TestStore_ReloadWithoutChangesIsQuiet(t *testing.T) {
+	store := NewStore(validConfig())
+	store.load = func() (*Config, error) { return validConfig(), nil }
+	notified := false
+	store.Subscribe(func(*Config) { notified = true })
+
+	require.Nil(t, store.Reload())
+	assert.False(t, notified)
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"google.golang.org/grpc/metadata"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// ConfigBundleHandler serves export and import of the promotable
+// configuration bundle, so policies, quotas and catalogs can be carried
+// from a staging cluster to production as one YAML document.
+type ConfigBundleHandler struct {
+	aiServer *AIServer
+	store    *config.Store
+	// identity resolves the calling user, shared with the SSE handler.
+	identity func(r *http.Request) (string, error)
+}
+
+// NewConfigBundleHandler creates the handler against the live
+// configuration store.
+func NewConfigBundleHandler(aiServer *AIServer, store *config.Store, identity func(r *http.Request) (string, error)) *ConfigBundleHandler {
+	return &ConfigBundleHandler{aiServer: aiServer, store: store, identity: identity}
+}
+
+// Export handles GET /apis/v2beta1/ai/config/bundle. It returns the
+// running configuration's promotable subset as a versioned YAML bundle;
+// credentials are never included.
+func (h *ConfigBundleHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userId, err := h.identity(r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.aiServer.authorizeConfigAdmin(r.Context(), userId); err != nil {
+		writeJSONError(w, httpStatus(err), err)
+		return
+	}
+	body, err := config.ExportBundle(h.store.Get()).YAML()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Cache-Control", "no-cache, private")
+	w.Header().Set("Content-Disposition", `attachment; filename="ai-config-bundle.yaml"`)
+	w.Write(body)
+}
+
+// Import handles POST /apis/v2beta1/ai/config/bundle. The bundle is
+// applied like a configuration reload: the safe subset takes effect
+// immediately and settings needing a restart are reported in the log.
+// The mounted ConfigMap stays authoritative across reloads.
+func (h *ConfigBundleHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userId, err := h.identity(r)
+	if err != nil {
+		writeJSONError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.aiServer.authorizeConfigAdmin(r.Context(), userId); err != nil {
+		writeJSONError(w, httpStatus(err), err)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxChatRequestBytes))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	bundle, err := config.ParseBundle(body)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.store.ImportBundle(bundle); err != nil {
+		writeJSONError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+// authorizeConfigAdmin verifies the caller may administer the live
+// assistant configuration. The check mirrors what changing it normally
+// takes: update access to ConfigMaps in the server's own namespace.
+// Single-user deployments skip authorization like the rest of the API.
+func (s *AIServer) authorizeConfigAdmin(ctx context.Context, userId string) error {
+	// Mirror what grpc-gateway would attach, as the chat handler does.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		strings.ToLower(common.GetKubeflowUserIDHeader()), common.GetKubeflowUserIDPrefix()+userId,
+	))
+	return s.resourceManager.IsAuthorized(ctx, &authorizationv1.ResourceAttributes{
+		Namespace: common.GetPodNamespace(),
+		Verb:      common.RbacResourceVerbUpdate,
+		Version:   "v1",
+		Resource:  "configmaps",
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mutating tool calls normally embed their raw arguments in the
+// confirmation_request event. Calls like create_pipeline_version carry a
+// full pipeline spec that can run to hundreds of kilobytes, which
+// overflows SSE clients with bounded event buffers. Oversized arguments
+// therefore stay server-side with the pending confirmation: the event
+// carries a bounded diff-style preview plus a reference from which the
+// client fetches the full payload on demand.
+
+const (
+	// maxInlineConfirmationArgs is the largest arguments_json embedded
+	// directly in a confirmation_request event.
+	maxInlineConfirmationArgs = 16 * 1024
+	// maxPreviewValueChars bounds each argument value in the preview.
+	maxPreviewValueChars = 120
+	// confirmationArgumentsPathFormat is the endpoint serving the full
+	// arguments of a summarized confirmation, keyed by session and call.
+	confirmationArgumentsPathFormat = "/apis/v2beta1/ai/sessions/%v/confirmations/%v/arguments"
+)
+
+// summarizeConfirmationArgs replaces the payload's inline arguments with
+// a preview and a fetch reference when they exceed the inline limit.
+// Small arguments pass through untouched.
+func summarizeConfirmationArgs(sessionId string, payload *ConfirmationRequestPayload) {
+	if len(payload.ArgumentsJSON) <= maxInlineConfirmationArgs {
+		return
+	}
+	payload.ArgumentsBytes = len(payload.ArgumentsJSON)
+	payload.ArgumentsPreview = previewArguments(payload.ArgumentsJSON)
+	payload.ArgumentsRef = fmt.Sprintf(confirmationArgumentsPathFormat, sessionId, payload.ToolCallID)
+	payload.ArgumentsJSON = ""
+}
+
+// previewArguments renders a diff-style preview of the call arguments:
+// one added line per top-level argument, with long values elided down to
+// a prefix and their size so the user still sees what each field is.
+func previewArguments(argumentsJSON string) string {
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+		return fmt.Sprintf("+ (unparseable arguments, %v bytes)", len(argumentsJSON))
+	}
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		value := string(args[key])
+		if len(value) > maxPreviewValueChars {
+			value = fmt.Sprintf("%v... (%v bytes total)", value[:maxPreviewValueChars], len(args[key]))
+		}
+		fmt.Fprintf(&b, "+ %v: %v\n", key, value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
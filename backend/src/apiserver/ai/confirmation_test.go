@@ -0,0 +1,88 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewArguments_ElidesLongValues(t *testing.T) {
+	preview := previewArguments(fmt.Sprintf(`{"name":"training","pipeline_spec":"%v"}`, strings.Repeat("x", 500)))
+
+	lines := strings.Split(preview, "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `+ name: "training"`, lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], `+ pipeline_spec: "xxx`))
+	assert.Contains(t, lines[1], "(502 bytes total)")
+}
+
+func TestPreviewArguments_SurvivesUnparseableInput(t *testing.T) {
+	assert.Contains(t, previewArguments("not json"), "unparseable arguments")
+}
+
+func TestAIServer_OversizedConfirmationArgsAreSummarized(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+	argumentsJSON := fmt.Sprintf(`{"name":"big","pipeline_spec":"%v"}`, strings.Repeat("x", maxInlineConfirmationArgs))
+
+	var confirmation *ConfirmationRequestPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmation = e.Payload.(*ConfirmationRequestPayload)
+			// The full payload stays retrievable while the call is pending.
+			full, err := session.pendingArguments(confirmation.ToolCallID)
+			require.NoError(t, err)
+			assert.Equal(t, argumentsJSON, full)
+			require.NoError(t, session.resolveConfirmation(confirmation.ToolCallID, false))
+		}
+		return nil
+	}
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: argumentsJSON}, emit)
+
+	require.NotNil(t, confirmation)
+	assert.Empty(t, confirmation.ArgumentsJSON)
+	assert.Equal(t, len(argumentsJSON), confirmation.ArgumentsBytes)
+	assert.Contains(t, confirmation.ArgumentsPreview, `+ name: "big"`)
+	assert.Equal(t, fmt.Sprintf(confirmationArgumentsPathFormat, session.ID, "tc1"), confirmation.ArgumentsRef)
+}
+
+func TestAIServer_SmallConfirmationArgsStayInline(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+
+	var confirmation *ConfirmationRequestPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmation = e.Payload.(*ConfirmationRequestPayload)
+			require.NoError(t, session.resolveConfirmation(confirmation.ToolCallID, false))
+		}
+		return nil
+	}
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: `{"name":"small"}`}, emit)
+
+	require.NotNil(t, confirmation)
+	assert.Equal(t, `{"name":"small"}`, confirmation.ArgumentsJSON)
+	assert.Empty(t, confirmation.ArgumentsRef)
+	assert.Empty(t, confirmation.ArgumentsPreview)
+}
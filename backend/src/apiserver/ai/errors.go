@@ -0,0 +1,138 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"google.golang.org/grpc/codes"
+)
+
+// maxCompactErrorLength bounds the error text forwarded to the model.
+// Long stack traces and SQL statements waste context tokens and tend to
+// derail the model into quoting them back at the user.
+const maxCompactErrorLength = 240
+
+var (
+	whitespaceRegexp = regexp.MustCompile(`\s+`)
+	// grpcErrorRegexp matches the prefix gRPC client errors carry, e.g.
+	// "rpc error: code = NotFound desc = run not found".
+	grpcErrorRegexp = regexp.MustCompile(`rpc error: code = (\w+) desc = `)
+	// sqlErrorRegexp matches MySQL driver errors ("Error 1062: ...") and
+	// database/sql sentinel errors.
+	sqlErrorRegexp = regexp.MustCompile(`(Error \d{4}[:(]|sql: |database is locked|SQLSTATE)`)
+)
+
+// errorCategory is the coarse classification included in compacted
+// errors so the model can react appropriately without the raw details.
+type errorCategory string
+
+const (
+	categoryNotFound     errorCategory = "not found"
+	categoryInvalidInput errorCategory = "invalid input"
+	categoryPermission   errorCategory = "permission denied"
+	categoryConflict     errorCategory = "already exists"
+	categoryUnavailable  errorCategory = "temporarily unavailable"
+	categoryDatabase     errorCategory = "database error"
+	categoryInternal     errorCategory = "internal error"
+)
+
+// CompactToolError converts a tool execution error into a single short
+// line suitable for the model context. The full error is logged
+// server-side so nothing is lost for operators.
+func CompactToolError(toolName string, err error) string {
+	glog.Errorf("AI tool %v failed: %+v", toolName, err)
+	category, detail := classifyError(err)
+	detail = compactText(detail)
+	if detail == "" {
+		return fmt.Sprintf("Tool %v failed: %v.", toolName, category)
+	}
+	return fmt.Sprintf("Tool %v failed (%v): %v", toolName, category, detail)
+}
+
+// classifyError maps an error onto a category and the most user-relevant
+// message fragment.
+func classifyError(err error) (errorCategory, string) {
+	if err == nil {
+		return categoryInternal, ""
+	}
+	if userErr, ok := err.(*util.UserError); ok {
+		return categoryForCode(userErr.ExternalStatusCode()), userErr.ExternalMessage()
+	}
+	message := err.Error()
+	if match := grpcErrorRegexp.FindStringSubmatchIndex(message); match != nil {
+		code := message[match[2]:match[3]]
+		// Keep only the description following the last matched prefix;
+		// wrapped gRPC errors repeat the prefix for each layer.
+		detail := message[match[1]:]
+		if idx := grpcErrorRegexp.FindStringIndex(detail); idx != nil {
+			detail = detail[idx[1]:]
+		}
+		return categoryForGrpcCodeName(code), detail
+	}
+	if sqlErrorRegexp.MatchString(message) {
+		// Never forward SQL statements or driver details to the model.
+		return categoryDatabase, "the query failed; this is a server-side problem"
+	}
+	return categoryInternal, message
+}
+
+func categoryForCode(code codes.Code) errorCategory {
+	switch code {
+	case codes.NotFound:
+		return categoryNotFound
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return categoryInvalidInput
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return categoryPermission
+	case codes.AlreadyExists, codes.Aborted:
+		return categoryConflict
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return categoryUnavailable
+	default:
+		return categoryInternal
+	}
+}
+
+func categoryForGrpcCodeName(name string) errorCategory {
+	switch name {
+	case "NotFound":
+		return categoryNotFound
+	case "InvalidArgument", "FailedPrecondition", "OutOfRange":
+		return categoryInvalidInput
+	case "PermissionDenied", "Unauthenticated":
+		return categoryPermission
+	case "AlreadyExists", "Aborted":
+		return categoryConflict
+	case "Unavailable", "DeadlineExceeded", "ResourceExhausted":
+		return categoryUnavailable
+	default:
+		return categoryInternal
+	}
+}
+
+// compactText collapses a possibly multi-line error into one bounded
+// line.
+func compactText(s string) string {
+	s = strings.TrimSpace(whitespaceRegexp.ReplaceAllString(s, " "))
+	if len(s) > maxCompactErrorLength {
+		s = s[:maxCompactErrorLength-3] + "..."
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactToolError_UserError(t *testing.T) {
+	err := util.NewResourceNotFoundError("Run", "run-123")
+	compacted := CompactToolError("get_run", err)
+	assert.Contains(t, compacted, "get_run")
+	assert.Contains(t, compacted, "not found")
+	assert.Contains(t, compacted, "run-123")
+	assert.NotContains(t, compacted, "\n")
+}
+
+func TestCompactToolError_GrpcTrace(t *testing.T) {
+	err := errors.New("rpc error: code = PermissionDenied desc = user alice cannot list runs in namespace team-a")
+	compacted := CompactToolError("list_runs", err)
+	assert.Contains(t, compacted, "permission denied")
+	assert.Contains(t, compacted, "namespace team-a")
+	assert.NotContains(t, compacted, "rpc error")
+}
+
+func TestCompactToolError_NestedGrpcTrace(t *testing.T) {
+	err := errors.New("rpc error: code = Internal desc = rpc error: code = Unavailable desc = connection refused")
+	compacted := CompactToolError("get_run", err)
+	assert.NotContains(t, compacted, "rpc error")
+	assert.Contains(t, compacted, "connection refused")
+}
+
+func TestCompactToolError_SQLErrorHidden(t *testing.T) {
+	err := errors.New("Error 1062: Duplicate entry 'abc' for key 'PRIMARY'\nSELECT * FROM run_details WHERE UUID = 'abc'")
+	compacted := CompactToolError("create_run", err)
+	assert.Contains(t, compacted, "database error")
+	assert.NotContains(t, compacted, "SELECT")
+	assert.NotContains(t, compacted, "1062")
+}
+
+func TestCompactToolError_CollapsesAndTruncates(t *testing.T) {
+	err := errors.New("line one\nline two\t\t" + strings.Repeat("x", 500))
+	compacted := CompactToolError("list_runs", err)
+	assert.NotContains(t, compacted, "\n")
+	assert.LessOrEqual(t, len(compacted), maxCompactErrorLength+len("Tool list_runs failed (internal error): "))
+	assert.Contains(t, compacted, "...")
+}
+
+func TestCompactToolError_CategoryOnly(t *testing.T) {
+	compacted := CompactToolError("get_run", nil)
+	assert.Equal(t, "Tool get_run failed: internal error.", compacted)
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+)
+
+// In semi-attended batch workflows the user who started an agent turn
+// may not be watching when a mutating call asks for approval, and the
+// confirmation then idles until its timeout. The escalation policy adds
+// a deadline: a confirmation pending past it notifies a fallback
+// approver through a webhook (e.g. a Slack incoming webhook), can
+// auto-deny the call with a recorded reason, and leaves an escalation
+// entry in the audit log either way.
+
+// escalationWebhookTimeout bounds the notification POST; the webhook is
+// called off the turn's critical path, so a slow receiver only delays
+// its own notice.
+const escalationWebhookTimeout = 10 * time.Second
+
+var escalationClient = &http.Client{Timeout: escalationWebhookTimeout}
+
+// escalationPolicy is the reloadable snapshot of the confirmation
+// escalation settings a waiting confirmation operates under.
+type escalationPolicy struct {
+	after    time.Duration
+	webhook  string
+	autoDeny bool
+}
+
+// escalationSettings returns the current policy under the lock, since a
+// configuration reload may swap it while confirmations are pending.
+func (s *AIServer) escalationSettings() escalationPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.escalation
+}
+
+// escalationNotice is the JSON document POSTed to the escalation
+// webhook. The text field renders as-is in Slack-compatible receivers;
+// the remaining fields let custom receivers route on the details.
+type escalationNotice struct {
+	Text       string `json:"text"`
+	SessionID  string `json:"session_id"`
+	UserID     string `json:"user_id"`
+	Namespace  string `json:"namespace,omitempty"`
+	Tool       string `json:"tool"`
+	ToolCallID string `json:"tool_call_id"`
+	PendingFor string `json:"pending_for"`
+	AutoDenied bool   `json:"auto_denied"`
+}
+
+// escalateConfirmation records the escalation in the session trace and
+// the audit log, and notifies the configured webhook in the background.
+// The pending confirmation itself is untouched; the caller decides
+// whether the call keeps waiting or is auto-denied.
+func (s *AIServer) escalateConfirmation(session *Session, payload *ConfirmationRequestPayload, policy escalationPolicy) {
+	detail := fmt.Sprintf("the confirmation stayed pending for %v", policy.after)
+	if policy.autoDeny {
+		detail += "; the call was auto-denied"
+	}
+	glog.Warningf("AI confirmation for tool %v in session %v escalated: %v", payload.Tool, session.ID, detail)
+	session.recordAction(ActionRecord{
+		Tool:          payload.Tool,
+		ArgumentsJSON: payload.ArgumentsJSON,
+		Mutating:      true,
+		Outcome:       actionEscalated,
+		Detail:        detail,
+	})
+	if s.audit != nil {
+		s.audit.Record(&model.AIAuditEvent{
+			UserID:      session.UserID,
+			SessionUUID: session.ID,
+			Namespace:   session.Namespace,
+			Tool:        payload.Tool,
+			Arguments:   model.LargeText(payload.ArgumentsJSON),
+			Mutating:    true,
+			Outcome:     actionEscalated,
+			Detail:      detail,
+		})
+	}
+	if policy.webhook == "" {
+		return
+	}
+	notice := &escalationNotice{
+		Text: fmt.Sprintf("Kubeflow Pipelines assistant: the %v call of %v in session %v has waited %v for approval.",
+			payload.Tool, session.UserID, session.ID, policy.after),
+		SessionID:  session.ID,
+		UserID:     session.UserID,
+		Namespace:  session.Namespace,
+		Tool:       payload.Tool,
+		ToolCallID: payload.ToolCallID,
+		PendingFor: policy.after.String(),
+		AutoDenied: policy.autoDeny,
+	}
+	if policy.autoDeny {
+		notice.Text += " The call was auto-denied by the escalation policy."
+	}
+	go notifyEscalationWebhook(policy.webhook, notice)
+}
+
+// notifyEscalationWebhook delivers one notice. Failures are logged, not
+// retried: the audit log already holds the escalation, and the regular
+// confirmation timeout remains the backstop.
+func notifyEscalationWebhook(webhook string, notice *escalationNotice) {
+	body, err := json.Marshal(notice)
+	if err != nil {
+		glog.Errorf("Failed to marshal the escalation notice for session %v: %v", notice.SessionID, err)
+		return
+	}
+	response, err := escalationClient.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		glog.Errorf("Failed to notify the confirmation escalation webhook for session %v: %v", notice.SessionID, err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		glog.Errorf("The confirmation escalation webhook returned %v for session %v", response.Status, notice.SessionID)
+	}
+}
+
+// escalationPolicyFor derives the snapshot from the configuration.
+func escalationPolicyFor(cfg *config.Config) escalationPolicy {
+	return escalationPolicy{
+		after:    cfg.Confirmations.EscalateAfter,
+		webhook:  cfg.Confirmations.EscalationWebhook,
+		autoDeny: cfg.Confirmations.AutoDeny,
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIServer_EscalationAutoDeniesAPendingConfirmation(t *testing.T) {
+	notices := make(chan escalationNotice, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notice escalationNotice
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&notice))
+		notices <- notice
+	}))
+	defer webhook.Close()
+
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+	server.escalation = escalationPolicy{after: 10 * time.Millisecond, webhook: webhook.URL, autoDeny: true}
+
+	// Nobody approves; the escalation deadline fires first.
+	emit := func(Event) error { return nil }
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: `{"name":"batch"}`}, emit)
+
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content, "escalation policy")
+	assert.False(t, stub.executed)
+
+	select {
+	case notice := <-notices:
+		assert.Equal(t, "stub_mutate", notice.Tool)
+		assert.Equal(t, "alice", notice.UserID)
+		assert.True(t, notice.AutoDenied)
+		assert.Contains(t, notice.Text, "waited 10ms for approval")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the escalation webhook was never notified")
+	}
+
+	// The escalation itself is in the trace next to the final outcome.
+	var outcomes []string
+	for _, action := range session.actionsSnapshot() {
+		outcomes = append(outcomes, action.Outcome)
+	}
+	assert.Contains(t, outcomes, actionEscalated)
+}
+
+func TestAIServer_EscalationWithoutAutoDenyKeepsWaiting(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+	server.escalation = escalationPolicy{after: 10 * time.Millisecond, autoDeny: false}
+
+	// Approval arrives only after the escalation deadline passed; the
+	// call must still go through.
+	var confirmation *ConfirmationRequestPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmation = e.Payload.(*ConfirmationRequestPayload)
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				_ = session.resolveConfirmation(confirmation.ToolCallID, true)
+			}()
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: `{"name":"batch"}`}, emit)
+
+	assert.False(t, result.IsError)
+	assert.True(t, stub.executed)
+}
+
+func TestAIServer_NoEscalationConfiguredLeavesConfirmationsAlone(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			require.NoError(t, session.resolveConfirmation(e.Payload.(*ConfirmationRequestPayload).ToolCallID, true))
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: `{"name":"batch"}`}, emit)
+
+	assert.False(t, result.IsError)
+	assert.True(t, stub.executed)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// knownEventTypes lists every event type a chat stream can carry, for
+// validating client-supplied event filters. Kept alongside the
+// EventType constants in events.go.
+var knownEventTypes = map[EventType]bool{
+	EventTypeSessionMetadata:     true,
+	EventTypeMarkdown:            true,
+	EventTypeCodeBlock:           true,
+	EventTypeToolCall:            true,
+	EventTypeToolProgress:        true,
+	EventTypeToolResult:          true,
+	EventTypeConfirmationRequest: true,
+	EventTypeApprovalsLost:       true,
+	EventTypeProviderSwitched:    true,
+	EventTypeModelDegraded:       true,
+	EventTypeCapabilitiesUpdated: true,
+	EventTypeTurnInterrupted:     true,
+	EventTypeResourceUpdate:      true,
+	EventTypePartialResponse:     true,
+	EventTypeSessionExpiring:     true,
+	EventTypeQuotaExceeded:       true,
+	EventTypeConcurrencyExceeded: true,
+	EventTypeUsage:               true,
+	EventTypeContextTruncated:    true,
+	EventTypeSuggestions:         true,
+	EventTypeGroundingWarning:    true,
+	EventTypeError:               true,
+	EventTypeDone:                true,
+}
+
+// eventFilter selects which event types a subscription receives, so
+// low-bandwidth clients and bots can drop chatter like tool progress or
+// suggestions server-side instead of discarding it after transfer. A
+// nil filter passes everything. The turn itself is unaffected: filtered
+// events are still recorded in the session's replay buffer, only the
+// connection skips them.
+type eventFilter map[EventType]bool
+
+// newEventFilter builds a filter from the requested type names. An
+// empty list disables filtering; unknown names are rejected so a typo
+// does not silently produce an empty stream.
+func newEventFilter(types []string) (eventFilter, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	filter := eventFilter{}
+	for _, raw := range types {
+		eventType := EventType(strings.TrimSpace(raw))
+		if !knownEventTypes[eventType] {
+			return nil, util.NewInvalidInputError("Unknown event type %q in event_types", raw)
+		}
+		filter[eventType] = true
+	}
+	return filter, nil
+}
+
+// allows reports whether the subscription wants the event type. Error
+// and done events always pass: they terminate the protocol and a client
+// cannot meaningfully opt out of them.
+func (f eventFilter) allows(eventType EventType) bool {
+	if f == nil {
+		return true
+	}
+	if eventType == EventTypeError || eventType == EventTypeDone {
+		return true
+	}
+	return f[eventType]
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFilter_EmptyListPassesEverything(t *testing.T) {
+	filter, err := newEventFilter(nil)
+	require.Nil(t, err)
+	assert.True(t, filter.allows(EventTypeMarkdown))
+	assert.True(t, filter.allows(EventTypeToolProgress))
+}
+
+func TestEventFilter_PassesOnlyTheRequestedTypesPlusProtocolEvents(t *testing.T) {
+	filter, err := newEventFilter([]string{"markdown", " code_block "})
+	require.Nil(t, err)
+	assert.True(t, filter.allows(EventTypeMarkdown))
+	assert.True(t, filter.allows(EventTypeCodeBlock))
+	assert.False(t, filter.allows(EventTypeToolProgress))
+	assert.False(t, filter.allows(EventTypeSuggestions))
+	// The events terminating the protocol cannot be filtered away.
+	assert.True(t, filter.allows(EventTypeError))
+	assert.True(t, filter.allows(EventTypeDone))
+}
+
+func TestEventFilter_RejectsUnknownTypes(t *testing.T) {
+	_, err := newEventFilter([]string{"markdwon"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "markdwon")
+}
@@ -0,0 +1,321 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+// EventType enumerates the events streamed to chat clients over SSE.
+type EventType string
+
+const (
+	// EventTypeSessionMetadata is sent once per stream and carries the
+	// session ID, mode and available tools.
+	EventTypeSessionMetadata EventType = "session_metadata"
+	// EventTypeMarkdown carries an incremental chunk of assistant text.
+	EventTypeMarkdown EventType = "markdown"
+	// EventTypeCodeBlock carries a fenced code block completed in the
+	// assistant text, parsed server-side for copy/download/apply actions.
+	EventTypeCodeBlock EventType = "code_block"
+	// EventTypeToolCall announces that a tool is about to execute.
+	EventTypeToolCall EventType = "tool_call"
+	// EventTypeToolProgress carries item-level progress of a batch tool
+	// while it is still executing.
+	EventTypeToolProgress EventType = "tool_progress"
+	// EventTypeToolResult carries the outcome of a tool execution.
+	EventTypeToolResult EventType = "tool_result"
+	// EventTypeConfirmationRequest asks the user to approve a mutating
+	// tool call before it executes.
+	EventTypeConfirmationRequest EventType = "confirmation_request"
+	// EventTypeApprovalsLost informs a returning user that confirmations
+	// pending their decision were resolved as denied while they had no
+	// open stream, e.g. because the session holding them was reclaimed.
+	EventTypeApprovalsLost EventType = "approvals_lost"
+	// EventTypeProviderSwitched reports that the configured fallback chain
+	// moved the turn to another model provider after a transient failure.
+	EventTypeProviderSwitched EventType = "provider_switched"
+	// EventTypeModelDegraded reports that an overloaded server served
+	// this turn with the configured degraded model instead of the
+	// primary one.
+	EventTypeModelDegraded EventType = "model_degraded"
+	// EventTypeCapabilitiesUpdated notifies open sessions that the
+	// assistant's tool set or policies changed, e.g. after a config
+	// reload; the next model iteration uses the refreshed tools.
+	EventTypeCapabilitiesUpdated EventType = "capabilities_updated"
+	// EventTypeTurnInterrupted informs a returning user that a previous
+	// turn died with the server, e.g. during a restart, and that the
+	// history ends at the turn's last completed iteration. Clients
+	// typically offer a "continue" action in response.
+	EventTypeTurnInterrupted EventType = "turn_interrupted"
+	// EventTypeResourceUpdate notifies the client that a resource the
+	// conversation referenced changed state, e.g. a run completing.
+	EventTypeResourceUpdate EventType = "resource_update"
+	// EventTypePartialResponse marks that the assistant text streamed so
+	// far was cut off by a provider failure but is kept, and whether a
+	// continuation retry follows.
+	EventTypePartialResponse EventType = "partial_response"
+	// EventTypeSessionExpiring warns that the session will be removed
+	// shortly because it is reaching its absolute maximum lifetime.
+	EventTypeSessionExpiring EventType = "session_expiring"
+	// EventTypeQuotaExceeded reports that the turn was rejected because a
+	// configured token budget is exhausted.
+	EventTypeQuotaExceeded EventType = "quota_exceeded"
+	// EventTypeConcurrencyExceeded reports that the turn was rejected
+	// because the user already has the maximum number of turns running.
+	EventTypeConcurrencyExceeded EventType = "concurrency_exceeded"
+	// EventTypeUsage reports the token consumption of the completed turn
+	// and the session's running totals, sent once before done.
+	EventTypeUsage EventType = "usage"
+	// EventTypeContextTruncated reports that earlier messages were
+	// dropped from the model's context to stay within the token budget.
+	EventTypeContextTruncated EventType = "context_truncated"
+	// EventTypeSuggestions carries up to a few suggested follow-up
+	// actions for the client's quick-reply chips, sent at the end of a
+	// successful turn.
+	EventTypeSuggestions EventType = "suggestions"
+	// EventTypeGroundingWarning flags resource IDs the final answer
+	// mentions without having fetched them this turn, so clients can
+	// present those claims as unverified.
+	EventTypeGroundingWarning EventType = "grounding_warning"
+	// EventTypeError reports a turn-level failure.
+	EventTypeError EventType = "error"
+	// EventTypeDone terminates the stream for the current turn.
+	EventTypeDone EventType = "done"
+)
+
+// Event is a single message on the chat stream. Payload is marshaled as
+// the SSE data field.
+type Event struct {
+	Type    EventType   `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// SessionMetadataPayload describes the session to the client.
+type SessionMetadataPayload struct {
+	SessionID string   `json:"session_id"`
+	Mode      string   `json:"mode"`
+	Tools     []string `json:"tools"`
+	// Sandbox marks sessions served by a sandboxed deployment: every
+	// tool runs against seeded fake data, never real workloads.
+	Sandbox bool `json:"sandbox,omitempty"`
+}
+
+// MarkdownPayload is an incremental chunk of assistant output.
+type MarkdownPayload struct {
+	Text string `json:"text"`
+}
+
+// CodeBlockPayload is a completed fenced code block from the assistant
+// text. The markdown stream still contains the block; this is a
+// structured duplicate keyed by a stable ID.
+type CodeBlockPayload struct {
+	// ID is derived from the block's position and content, so the same
+	// snippet keeps its identity across re-renders.
+	ID string `json:"id"`
+	// Language is the fence's info string, e.g. "yaml"; may be empty.
+	Language string `json:"language,omitempty"`
+	Code     string `json:"code"`
+}
+
+// ToolCallPayload announces a tool execution.
+type ToolCallPayload struct {
+	ToolCallID    string `json:"tool_call_id"`
+	Tool          string `json:"tool"`
+	ArgumentsJSON string `json:"arguments_json"`
+}
+
+// ToolProgressPayload reports one processed item of a batch tool.
+type ToolProgressPayload struct {
+	ToolCallID string `json:"tool_call_id"`
+	Tool       string `json:"tool"`
+	Item       string `json:"item,omitempty"`
+	Completed  int    `json:"completed"`
+	Total      int    `json:"total"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ToolResultPayload carries a tool outcome.
+type ToolResultPayload struct {
+	ToolCallID string `json:"tool_call_id"`
+	Tool       string `json:"tool"`
+	Content    string `json:"content"`
+	IsError    bool   `json:"is_error,omitempty"`
+}
+
+// ConfirmationRequestPayload asks the user to approve a mutating tool
+// call. The client responds through the approve endpoint.
+type ConfirmationRequestPayload struct {
+	ToolCallID string `json:"tool_call_id"`
+	Tool       string `json:"tool"`
+	// ArgumentsJSON carries the raw call arguments. It is empty when they
+	// exceed the inline size limit; the fields below then describe them
+	// instead.
+	ArgumentsJSON string `json:"arguments_json"`
+	// ArgumentsRef is the endpoint serving the full arguments of a
+	// summarized confirmation while it is pending.
+	ArgumentsRef string `json:"arguments_ref,omitempty"`
+	// ArgumentsPreview is a diff-style per-argument summary with long
+	// values elided.
+	ArgumentsPreview string `json:"arguments_preview,omitempty"`
+	// ArgumentsBytes is the size of the full arguments_json.
+	ArgumentsBytes int    `json:"arguments_bytes,omitempty"`
+	Description    string `json:"description"`
+	// ValidationJSON is the server-side dry-run result for tools that
+	// support validation: the call as it actually resolved (names,
+	// versions, parameters), so the user approves that rather than the
+	// raw arguments.
+	ValidationJSON string `json:"validation_json,omitempty"`
+}
+
+// LostApproval describes one pending confirmation that was resolved as
+// denied without the user's decision.
+type LostApproval struct {
+	SessionID  string `json:"session_id"`
+	ToolCallID string `json:"tool_call_id"`
+	Tool       string `json:"tool"`
+	// Reason is why the decision was lost, e.g. "session_expired".
+	Reason string `json:"reason"`
+	// LostAt is the epoch second the confirmation was denied.
+	LostAt int64 `json:"lost_at"`
+}
+
+// ApprovalsLostPayload lists the confirmations denied while the user was
+// away, so the client can tell them which approvals never took effect.
+type ApprovalsLostPayload struct {
+	Approvals []LostApproval `json:"approvals"`
+}
+
+// TurnInterruptedPayload tells a returning user when the interrupted
+// turn's last checkpoint was written.
+type TurnInterruptedPayload struct {
+	CheckpointedAt int64 `json:"checkpointed_at"`
+}
+
+// ProviderSwitchedPayload names the provider a fallback chain switched
+// to mid-turn, so the client can tell which model answered.
+type ProviderSwitchedPayload struct {
+	Provider string `json:"provider"`
+}
+
+// ModelDegradedPayload names the cheaper model an overloaded server
+// served the turn with.
+type ModelDegradedPayload struct {
+	Model string `json:"model"`
+}
+
+// CapabilitiesUpdatedPayload carries the session's refreshed tool list
+// after the assistant's capabilities changed.
+type CapabilitiesUpdatedPayload struct {
+	Tools  []string `json:"tools"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// ResourceUpdatePayload reports a state transition of a referenced
+// resource.
+type ResourceUpdatePayload struct {
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	DisplayName  string `json:"display_name,omitempty"`
+	OldState     string `json:"old_state"`
+	NewState     string `json:"new_state"`
+}
+
+// PartialResponsePayload marks a salvaged partial assistant message.
+type PartialResponsePayload struct {
+	// Chars is the length of the salvaged text.
+	Chars int `json:"chars"`
+	// Resuming reports whether an automatic continuation follows on the
+	// same stream.
+	Resuming bool `json:"resuming"`
+}
+
+// SessionExpiringPayload warns the client ahead of a forced session
+// removal so the conversation can be wrapped up or restarted.
+type SessionExpiringPayload struct {
+	SessionID string `json:"session_id"`
+	// ExpiresAt is the epoch second at which the session is removed.
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// Suggestion is one proposed follow-up action. It either carries a
+// prepared chat message to send on the user's behalf or a tool
+// invocation for the client to offer.
+type Suggestion struct {
+	// Label is the short chip text shown to the user.
+	Label string `json:"label"`
+	// Message is the prepared chat message the chip sends when clicked.
+	Message string `json:"message,omitempty"`
+	// Tool optionally names a tool the chip invokes instead of sending a
+	// message.
+	Tool string `json:"tool,omitempty"`
+	// ArgumentsJSON carries the arguments for Tool.
+	ArgumentsJSON string `json:"arguments_json,omitempty"`
+}
+
+// SuggestionsPayload lists the suggested follow-up actions for a turn.
+type SuggestionsPayload struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// GroundingWarningPayload lists the resource IDs the answer referenced
+// without evidence from this turn's tool results.
+type GroundingWarningPayload struct {
+	IDs     []string `json:"ids"`
+	Message string   `json:"message"`
+}
+
+// QuotaExceededPayload tells the client which token budget rejected the
+// turn and when it refills.
+type QuotaExceededPayload struct {
+	// Scope is "user" or "namespace"; Subject names who the budget
+	// applies to.
+	Scope   string `json:"scope"`
+	Subject string `json:"subject"`
+	// Window is "daily" or "monthly".
+	Window      string `json:"window"`
+	LimitTokens int    `json:"limit_tokens"`
+	SpentTokens int    `json:"spent_tokens"`
+	// ResetsAt is the epoch second at which the window rolls over.
+	ResetsAt int64 `json:"resets_at"`
+}
+
+// ConcurrencyExceededPayload tells the client how many turns the user
+// already has running and where the cap sits; any of them finishing
+// frees a slot.
+type ConcurrencyExceededPayload struct {
+	Limit  int `json:"limit"`
+	Active int `json:"active"`
+}
+
+// UsagePayload reports token consumption: the completed turn's count
+// across all model invocations it made, plus the session running totals.
+type UsagePayload struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// SessionInputTokens and SessionOutputTokens accumulate over the
+	// session's lifetime, including this turn.
+	SessionInputTokens  int `json:"session_input_tokens"`
+	SessionOutputTokens int `json:"session_output_tokens"`
+}
+
+// ContextTruncatedPayload tells the client that earlier messages were
+// dropped from the model's context to fit the token budget; the stored
+// session history is unaffected.
+type ContextTruncatedPayload struct {
+	DroppedMessages int `json:"dropped_messages"`
+	BudgetTokens    int `json:"budget_tokens"`
+}
+
+// ErrorPayload reports a turn-level failure to the client.
+type ErrorPayload struct {
+	Message string `json:"message"`
+}
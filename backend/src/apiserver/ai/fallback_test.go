@@ -0,0 +1,130 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fallbackStubModel streams a fixed text, or fails. When failAfterText
+// is set the error arrives after the text has already been delivered.
+type fallbackStubModel struct {
+	name          string
+	text          string
+	err           error
+	failAfterText bool
+	calls         int
+}
+
+func (m *fallbackStubModel) Name() string { return m.name }
+
+func (m *fallbackStubModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	m.calls++
+	if m.err != nil && !m.failAfterText {
+		return m.err
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: m.text}}); err != nil {
+		return err
+	}
+	if m.err != nil {
+		return m.err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
+
+func TestFallbackChatModel_FailsOverOnTransientErrors(t *testing.T) {
+	primary := &fallbackStubModel{name: "anthropic", err: util.NewUnavailableServerError(errors.New("boom"), "The provider is down")}
+	secondary := &fallbackStubModel{name: "local", text: "hello"}
+	chain, err := provider.NewFallbackChatModel([]provider.ChatModel{primary, secondary})
+	require.NoError(t, err)
+
+	var events []provider.StreamEvent
+	err = chain.StreamChat(context.Background(), &provider.ChatRequest{}, func(e provider.StreamEvent) error {
+		events = append(events, e)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, provider.StreamEventProviderSwitched, events[0].Type)
+	assert.Equal(t, "local", events[0].Provider)
+	assert.Equal(t, "hello", events[1].Delta.Text)
+	assert.Equal(t, provider.StreamEventDone, events[2].Type)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+}
+
+func TestFallbackChatModel_DoesNotRetryNonTransientErrors(t *testing.T) {
+	primary := &fallbackStubModel{name: "anthropic", err: util.NewInvalidInputError("the request is malformed")}
+	secondary := &fallbackStubModel{name: "local", text: "hello"}
+	chain, err := provider.NewFallbackChatModel([]provider.ChatModel{primary, secondary})
+	require.NoError(t, err)
+
+	err = chain.StreamChat(context.Background(), &provider.ChatRequest{}, func(provider.StreamEvent) error { return nil })
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestFallbackChatModel_DoesNotRetryAfterDeliveredOutput(t *testing.T) {
+	primary := &fallbackStubModel{
+		name: "anthropic", text: "partial answer", failAfterText: true,
+		err: util.NewUnavailableServerError(errors.New("boom"), "The stream broke"),
+	}
+	secondary := &fallbackStubModel{name: "local", text: "hello"}
+	chain, err := provider.NewFallbackChatModel([]provider.ChatModel{primary, secondary})
+	require.NoError(t, err)
+
+	err = chain.StreamChat(context.Background(), &provider.ChatRequest{}, func(provider.StreamEvent) error { return nil })
+
+	// Replaying elsewhere would duplicate the delivered text; the server's
+	// partial-response salvage owns this case.
+	assert.Error(t, err)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestAIServer_ProviderSwitchIsStreamedToTheClient(t *testing.T) {
+	primary := &fallbackStubModel{name: "anthropic", err: util.NewUnavailableServerError(errors.New("boom"), "The provider is down")}
+	secondary := &fallbackStubModel{name: "local", text: "hello"}
+	chain, err := provider.NewFallbackChatModel([]provider.ChatModel{primary, secondary})
+	require.NoError(t, err)
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: chain}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var switched []*ProviderSwitchedPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeProviderSwitched {
+			switched = append(switched, e.Payload.(*ProviderSwitchedPayload))
+		}
+		return nil
+	}
+	text, toolCalls, err := server.runModelTurn(context.Background(), session, nil, "", &provider.Usage{}, emit)
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello", text)
+	assert.Empty(t, toolCalls)
+	require.Len(t, switched, 1)
+	assert.Equal(t, "local", switched[0].Provider)
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/mcp"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/pkg/errors"
+)
+
+// maxFederatedResultBytes bounds a remote cluster's response; the remote
+// side chunks large results, so hitting this means a misbehaving peer.
+const maxFederatedResultBytes = 1 << 20
+
+// clusterDirectory resolves the remote installations a federated
+// assistant can forward read-only tool calls to. Each call goes to the
+// remote assistant's MCP endpoint with the credential mapped to the
+// calling identity, so the remote cluster enforces that user's RBAC.
+type clusterDirectory struct {
+	mu       sync.Mutex
+	clusters map[string]config.Cluster
+	names    []string
+	client   *http.Client
+}
+
+func newClusterDirectory(cfg *config.Config) *clusterDirectory {
+	d := &clusterDirectory{client: &http.Client{}}
+	d.applyConfig(cfg)
+	return d
+}
+
+// applyConfig swaps in the reloaded cluster registry.
+func (d *clusterDirectory) applyConfig(cfg *config.Config) {
+	clusters := make(map[string]config.Cluster, len(cfg.Clusters))
+	names := make([]string, 0, len(cfg.Clusters))
+	for _, cluster := range cfg.Clusters {
+		clusters[cluster.Name] = cluster
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clusters = clusters
+	d.names = names
+}
+
+// clusterNames lists the registered clusters in stable order.
+func (d *clusterDirectory) clusterNames() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.names
+}
+
+// call forwards one tool call to the named cluster and returns the
+// textual result and whether the remote tool reported an error.
+func (d *clusterDirectory) call(ctx context.Context, clusterName, userId, toolName string, args map[string]interface{}) (string, bool, error) {
+	d.mu.Lock()
+	cluster, ok := d.clusters[clusterName]
+	names := d.names
+	client := d.client
+	d.mu.Unlock()
+	if !ok {
+		return "", false, util.NewInvalidInputError("Unknown cluster %q; registered clusters: %v", clusterName, strings.Join(names, ", "))
+	}
+	key := cluster.IdentityKeys[userId]
+	if key == "" {
+		key = cluster.APIKey
+	}
+	if key == "" {
+		return "", false, util.NewPermissionDeniedError(errors.New("no mapped credential"),
+			"No credential maps your identity to cluster %q", clusterName)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": toolName, "arguments": args},
+	})
+	if err != nil {
+		return "", false, util.NewInternalServerError(err, "Failed to marshal the federated tool call")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(cluster.Endpoint, "/")+mcp.Path, bytes.NewReader(body))
+	if err != nil {
+		return "", false, util.NewInternalServerError(err, "Failed to build the federated tool call for cluster %q", clusterName)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+key)
+	response, err := client.Do(request)
+	if err != nil {
+		return "", false, util.NewUnavailableServerError(err, "Cluster %q is unreachable", clusterName)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", false, util.NewUnavailableServerError(errors.Errorf("HTTP %v", response.StatusCode),
+			"Cluster %q rejected the call (HTTP %v)", clusterName, response.StatusCode)
+	}
+
+	var envelope struct {
+		Result struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(nil, response.Body, maxFederatedResultBytes)).Decode(&envelope); err != nil {
+		return "", false, util.NewInternalServerError(err, "Cluster %q returned an unreadable response", clusterName)
+	}
+	if envelope.Error != nil {
+		return "", false, util.NewInternalServerError(errors.Errorf("code %v", envelope.Error.Code),
+			"Cluster %q failed the call: %v", clusterName, envelope.Error.Message)
+	}
+	var text strings.Builder
+	for _, content := range envelope.Result.Content {
+		if content.Type == "text" {
+			text.WriteString(content.Text)
+		}
+	}
+	return text.String(), envelope.Result.IsError, nil
+}
+
+// withClusterParameter advertises the optional cluster parameter on
+// read-only tool definitions when remote clusters are registered, so
+// the model can direct a question at a federated installation. The
+// definitions are copied; mutating tools stay local-only.
+func withClusterParameter(defs []provider.ToolDefinition, clusters []string) []provider.ToolDefinition {
+	out := make([]provider.ToolDefinition, len(defs))
+	for i, def := range defs {
+		out[i] = def
+		if !def.Annotations.ReadOnly {
+			continue
+		}
+		schema := make(map[string]interface{}, len(def.InputSchema)+1)
+		for k, v := range def.InputSchema {
+			schema[k] = v
+		}
+		properties := map[string]interface{}{}
+		if existing, ok := schema["properties"].(map[string]interface{}); ok {
+			for k, v := range existing {
+				properties[k] = v
+			}
+		}
+		properties["cluster"] = map[string]interface{}{
+			"type": "string",
+			"description": fmt.Sprintf("Optional remote cluster to query instead of this one. Registered clusters: %v.",
+				strings.Join(clusters, ", ")),
+		}
+		schema["properties"] = properties
+		out[i].InputSchema = schema
+	}
+	return out
+}
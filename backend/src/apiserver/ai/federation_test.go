@@ -0,0 +1,140 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteMCPStub emulates a remote cluster's MCP endpoint: it records
+// the authorization and forwarded call, and answers with a fixed text.
+func remoteMCPStub(t *testing.T, reply string) (*httptest.Server, *remoteCall) {
+	recorded := &remoteCall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded.authorization = r.Header.Get("Authorization")
+		var request struct {
+			Method string `json:"method"`
+			Params struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		recorded.method = request.Method
+		recorded.tool = request.Params.Name
+		recorded.arguments = request.Params.Arguments
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":%q}],"isError":false}}`, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+type remoteCall struct {
+	authorization string
+	method        string
+	tool          string
+	arguments     map[string]interface{}
+}
+
+func federationFixture(t *testing.T, clusters []config.Cluster) (*AIServer, *Session) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		clusters:       newClusterDirectory(&config.Config{Clusters: clusters}),
+	}
+	require.NoError(t, server.registry.Register(&stubRbacTool{}))
+	require.NoError(t, server.registry.Register(&stubValidatedTool{}))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAgent, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestExecuteToolCall_ForwardsToTheNamedCluster(t *testing.T) {
+	remote, recorded := remoteMCPStub(t, "2 runs failed in eu-prod today")
+	server, session := federationFixture(t, []config.Cluster{{
+		Name:         "eu-prod",
+		Endpoint:     remote.URL,
+		IdentityKeys: map[string]string{"alice": "alice-eu-key"},
+	}})
+
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_rbac", ArgumentsJSON: `{"cluster":"eu-prod","experiment":"tabular"}`},
+		func(Event) error { return nil })
+
+	require.False(t, result.IsError)
+	// The cluster names its results and the local tool never ran.
+	assert.Contains(t, result.Content, "[cluster eu-prod]")
+	assert.Contains(t, result.Content, "2 runs failed")
+	assert.Equal(t, "Bearer alice-eu-key", recorded.authorization)
+	assert.Equal(t, "tools/call", recorded.method)
+	assert.Equal(t, "stub_rbac", recorded.tool)
+	// The cluster selector is consumed locally, the rest forwards as-is.
+	assert.Equal(t, map[string]interface{}{"experiment": "tabular"}, recorded.arguments)
+}
+
+func TestExecuteToolCall_RefusesMutatingFederation(t *testing.T) {
+	remote, recorded := remoteMCPStub(t, "should never be reached")
+	server, session := federationFixture(t, []config.Cluster{{Name: "eu-prod", Endpoint: remote.URL, APIKey: "shared"}})
+
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: `{"cluster":"eu-prod","name":"x"}`},
+		func(Event) error { return nil })
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "read-only tools")
+	assert.Empty(t, recorded.method)
+}
+
+func TestClusterDirectory_RequiresAMappedCredential(t *testing.T) {
+	directory := newClusterDirectory(&config.Config{Clusters: []config.Cluster{{
+		Name:         "eu-prod",
+		Endpoint:     "https://kfp-eu.example.com",
+		IdentityKeys: map[string]string{"bob": "bob-key"},
+	}}})
+
+	_, _, err := directory.call(context.Background(), "eu-prod", "alice", "stub_rbac", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No credential maps your identity")
+
+	_, _, err = directory.call(context.Background(), "us-west", "alice", "stub_rbac", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Unknown cluster "us-west"`)
+}
+
+func TestWithClusterParameter_OnlyAnnotatesReadOnlyTools(t *testing.T) {
+	defs := tool.Definitions([]tool.Tool{&stubRbacTool{}, &stubValidatedTool{}})
+	annotated := withClusterParameter(defs, []string{"eu-prod", "us-west"})
+
+	readOnly := annotated[0].InputSchema["properties"].(map[string]interface{})
+	parameter := readOnly["cluster"].(map[string]interface{})
+	assert.Contains(t, parameter["description"], "eu-prod, us-west")
+	// Mutating tools stay local-only, and the originals are untouched.
+	_, hasProperties := annotated[1].InputSchema["properties"]
+	assert.False(t, hasProperties)
+	_, mutated := defs[0].InputSchema["properties"]
+	assert.False(t, mutated)
+}
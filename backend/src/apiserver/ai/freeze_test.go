@@ -0,0 +1,91 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weekendFreeze opens every Saturday at midnight UTC for 48 hours.
+func weekendFreeze() *freezePolicy {
+	return newFreezePolicy(&config.Config{FreezeWindows: []config.FreezeWindow{
+		{Cron: "0 0 0 * * SAT", Duration: "48h", Reason: "weekend change freeze"},
+	}})
+}
+
+func TestFreezePolicy_ActiveOnlyInsideTheWindow(t *testing.T) {
+	policy := weekendFreeze()
+
+	// 2026-08-29 is a Saturday.
+	reason, frozen := policy.activeReason(time.Date(2026, 8, 29, 12, 0, 0, 0, time.UTC))
+	assert.True(t, frozen)
+	assert.Equal(t, "weekend change freeze", reason)
+
+	_, frozen = policy.activeReason(time.Date(2026, 8, 26, 12, 0, 0, 0, time.UTC))
+	assert.False(t, frozen)
+
+	// The window closes 48 hours after it opened.
+	_, frozen = policy.activeReason(time.Date(2026, 8, 31, 12, 0, 0, 0, time.UTC))
+	assert.False(t, frozen)
+}
+
+func TestFreezePolicy_FallsBackToTheDefaultReason(t *testing.T) {
+	policy := newFreezePolicy(&config.Config{FreezeWindows: []config.FreezeWindow{
+		{Cron: "@daily", Duration: "24h"},
+	}})
+
+	reason, frozen := policy.activeReason(time.Now())
+	assert.True(t, frozen)
+	assert.Equal(t, defaultFreezeReason, reason)
+}
+
+func TestFreezePolicy_NoWindowsNeverFreezes(t *testing.T) {
+	policy := newFreezePolicy(&config.Config{})
+
+	_, frozen := policy.activeReason(time.Now())
+	assert.False(t, frozen)
+}
+
+func TestAIServer_MutatingToolsAreBlockedDuringAFreeze(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+	// A window that is always open stands in for an active freeze.
+	server.freezes = newFreezePolicy(&config.Config{FreezeWindows: []config.FreezeWindow{
+		{Cron: "@daily", Duration: "24h", Reason: "release freeze for 3.2"},
+	}})
+
+	confirmationRequested := false
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			confirmationRequested = true
+		}
+		return nil
+	}
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "release freeze for 3.2")
+	assert.False(t, confirmationRequested)
+	assert.False(t, stub.executed)
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The grounding guardrail is a cheap post-generation check against
+// hallucinated resources: every run, pipeline or experiment ID the final
+// assistant answer mentions must have appeared somewhere in the turn's
+// evidence — the user's message or a tool result. IDs the model
+// introduces on its own are reported to the client as a warning so the
+// UI can flag the claim instead of rendering it as fact. The check is
+// deliberately syntactic; it catches fabricated identifiers, not
+// fabricated prose about real ones.
+
+func (s *AIServer) groundingEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.groundingCheck
+}
+
+// resourceIDPattern matches the UUIDs Kubeflow Pipelines assigns to
+// runs, pipelines, versions and experiments.
+var resourceIDPattern = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+
+// groundingTracker accumulates the resource IDs a turn has evidence for.
+// It is used by a single turn goroutine and needs no locking.
+type groundingTracker struct {
+	evidence map[string]bool
+}
+
+func newGroundingTracker() *groundingTracker {
+	return &groundingTracker{evidence: map[string]bool{}}
+}
+
+// addEvidence records every resource ID in the text as grounded.
+func (g *groundingTracker) addEvidence(text string) {
+	for _, id := range resourceIDPattern.FindAllString(text, -1) {
+		g.evidence[strings.ToLower(id)] = true
+	}
+}
+
+// ungrounded returns the resource IDs the text mentions without
+// evidence, deduplicated, in order of first appearance.
+func (g *groundingTracker) ungrounded(text string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range resourceIDPattern.FindAllString(text, -1) {
+		key := strings.ToLower(id)
+		if g.evidence[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
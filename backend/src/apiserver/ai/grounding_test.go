@@ -0,0 +1,42 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroundingTracker_FlagsIDsWithoutEvidence(t *testing.T) {
+	g := newGroundingTracker()
+	g.addEvidence(`{"run_id": "11111111-2222-3333-4444-555555555555"}`)
+
+	answer := "Run 11111111-2222-3333-4444-555555555555 failed, " +
+		"likely because of run aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee. " +
+		"Compare it with aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee again."
+	assert.Equal(t, []string{"aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"}, g.ungrounded(answer))
+}
+
+func TestGroundingTracker_MatchesIDsCaseInsensitively(t *testing.T) {
+	g := newGroundingTracker()
+	g.addEvidence("fetched AAAAAAAA-BBBB-CCCC-DDDD-EEEEEEEEEEEE")
+	assert.Empty(t, g.ungrounded("run aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee looks fine"))
+}
+
+func TestGroundingTracker_IgnoresProseWithoutIDs(t *testing.T) {
+	g := newGroundingTracker()
+	assert.Empty(t, g.ungrounded("All runs in namespace team-a completed."))
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	apiv2beta1 "github.com/kubeflow/pipelines/backend/api/v2beta1/go_client"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GRPCServer adapts the AI assistant to the AIService gRPC interface for
+// SDK and CLI clients. It shares the SSE handler's authentication and
+// rate limiting, so a caller gets the same identity and limits on both
+// surfaces.
+type GRPCServer struct {
+	apiv2beta1.UnimplementedAIServiceServer
+	aiServer *AIServer
+	handler  *SSEHandler
+}
+
+// NewGRPCServer wires the gRPC chat service to an AIServer. The SSE
+// handler supplies credential resolution and the per-user rate limit.
+func NewGRPCServer(aiServer *AIServer, handler *SSEHandler) *GRPCServer {
+	return &GRPCServer{aiServer: aiServer, handler: handler}
+}
+
+// StreamChat runs one chat turn and streams its events. Events mirror
+// the SSE stream one-to-one; the turn's failure, if any, is returned as
+// the stream's trailing status instead of an in-band error event.
+func (g *GRPCServer) StreamChat(request *apiv2beta1.StreamChatRequest, stream apiv2beta1.AIService_StreamChatServer) error {
+	userId, err := g.handler.userIdentityFromContext(stream.Context())
+	if err != nil {
+		return util.ToGRPCError(util.NewUnauthenticatedError(err, "Failed to resolve the caller identity"))
+	}
+	if !g.handler.allowRequest(userId) {
+		return util.ToGRPCError(util.NewUnavailableServerError(errors.New("rate limit exceeded"), "Too many chat requests, retry later"))
+	}
+	if shed, retryAfter := g.aiServer.Overloaded(); shed {
+		return util.ToGRPCError(util.NewUnavailableServerError(errors.New("load shed"), "The assistant is overloaded, retry in %v", retryAfter))
+	}
+	mode, err := tool.ParseMode(request.Mode)
+	if err != nil {
+		return util.ToGRPCError(err)
+	}
+	session, err := g.aiServer.SessionManager().GetOrCreate(request.SessionId, userId, request.Namespace, mode, request.Timezone)
+	if err != nil {
+		return util.ToGRPCError(err)
+	}
+
+	// Tools authorize through the resource manager, which reads the
+	// identity from gRPC metadata. Attach the resolved identity rather
+	// than trusting the client's own metadata, which is absent for
+	// API-key callers.
+	ctx := metadata.NewIncomingContext(stream.Context(), metadata.Pairs(
+		strings.ToLower(common.GetKubeflowUserIDHeader()), common.GetKubeflowUserIDPrefix()+userId,
+	))
+
+	var sendMu sync.Mutex
+	emit := func(event Event) error {
+		payload := ""
+		if event.Payload != nil {
+			marshaled, err := json.Marshal(event.Payload)
+			if err != nil {
+				return util.NewInternalServerError(err, "Failed to marshal a chat event payload")
+			}
+			payload = string(marshaled)
+		}
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(&apiv2beta1.ChatEvent{Type: string(event.Type), PayloadJson: payload})
+	}
+	if err := g.aiServer.StreamChat(ctx, session, request.Message, emit); err != nil {
+		glog.Errorf("AI gRPC chat turn failed for session %v: %+v", session.ID, err)
+		return util.ToGRPCError(err)
+	}
+	return nil
+}
+
+// ApproveToolCall resolves a pending mutating tool call confirmation.
+func (g *GRPCServer) ApproveToolCall(ctx context.Context, request *apiv2beta1.ApproveToolCallRequest) (*emptypb.Empty, error) {
+	userId, err := g.handler.userIdentityFromContext(ctx)
+	if err != nil {
+		return nil, util.NewUnauthenticatedError(err, "Failed to resolve the caller identity")
+	}
+	if request.SessionId == "" || request.ToolCallId == "" {
+		return nil, util.NewInvalidInputError("session_id and tool_call_id are required")
+	}
+	if err := g.aiServer.ApproveToolCall(request.SessionId, userId, request.ToolCallId, request.Approved); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	apiv2beta1 "github.com/kubeflow/pipelines/backend/api/v2beta1/go_client"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeChatStream records the events of one streamed turn. Only the
+// methods the adapter uses are implemented.
+type fakeChatStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*apiv2beta1.ChatEvent
+}
+
+func (s *fakeChatStream) Context() context.Context { return s.ctx }
+
+func (s *fakeChatStream) Send(event *apiv2beta1.ChatEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeChatStream) eventTypes() []string {
+	types := []string{}
+	for _, event := range s.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func grpcFixture(t *testing.T, reply string) *GRPCServer {
+	t.Helper()
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+	}
+	aiServer := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &summaryStubModel{reply: reply},
+	}
+	handler := NewSSEHandler(aiServer, &config.Config{Limits: config.Limits{ChatRateLimit: 10}}, nil)
+	return NewGRPCServer(aiServer, handler)
+}
+
+func TestGRPCStreamChat_StreamsTheTurnEvents(t *testing.T) {
+	g := grpcFixture(t, "your last run failed on OOM")
+	stream := &fakeChatStream{ctx: context.Background()}
+
+	err := g.StreamChat(&apiv2beta1.StreamChatRequest{Message: "why did my run fail?"}, stream)
+	require.NoError(t, err)
+
+	types := stream.eventTypes()
+	assert.Contains(t, types, string(EventTypeSessionMetadata))
+	assert.Contains(t, types, string(EventTypeMarkdown))
+	// The done event closes the turn, exactly as on the SSE stream.
+	assert.Equal(t, string(EventTypeDone), types[len(types)-1])
+	for _, event := range stream.events {
+		if event.Type == string(EventTypeMarkdown) {
+			assert.Contains(t, event.PayloadJson, "your last run failed on OOM")
+		}
+	}
+}
+
+func TestGRPCStreamChat_RejectsUnknownModes(t *testing.T) {
+	g := grpcFixture(t, "unused")
+	stream := &fakeChatStream{ctx: context.Background()}
+
+	err := g.StreamChat(&apiv2beta1.StreamChatRequest{Message: "hi", Mode: "bogus"}, stream)
+	require.Error(t, err)
+	assert.Empty(t, stream.events)
+}
+
+func TestGRPCApproveToolCall_RequiresSessionAndToolCall(t *testing.T) {
+	g := grpcFixture(t, "unused")
+
+	_, err := g.ApproveToolCall(context.Background(), &apiv2beta1.ApproveToolCallRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required")
+}
+
+func TestGRPCApproveToolCall_RequiresAPendingConfirmation(t *testing.T) {
+	g := grpcFixture(t, "your last run failed on OOM")
+	stream := &fakeChatStream{ctx: context.Background()}
+	require.NoError(t, g.StreamChat(&apiv2beta1.StreamChatRequest{Message: "hello"}, stream))
+	sessions, err := g.aiServer.SessionManager().ListForUser(anonymousUser)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	_, err = g.ApproveToolCall(context.Background(), &apiv2beta1.ApproveToolCallRequest{
+		SessionId:  sessions[0].SessionID,
+		ToolCallId: "call-1",
+		Approved:   true,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowModel stalls before answering, like a long provider call.
+type slowModel struct {
+	delay time.Duration
+}
+
+func (m *slowModel) Name() string { return "slow" }
+
+func (m *slowModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "done waiting"}}); err != nil {
+		return err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
+
+func heartbeatFixture(interval time.Duration, delay time.Duration) *SSEHandler {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: &slowModel{delay: delay}}
+	return NewSSEHandler(server, &config.Config{Limits: config.Limits{
+		ChatRateLimit:     10,
+		HeartbeatInterval: interval,
+	}}, nil)
+}
+
+func TestChat_PingsTheStreamWhileTheTurnIsIdle(t *testing.T) {
+	h := heartbeatFixture(5*time.Millisecond, 80*time.Millisecond)
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"message":"why did my run fail?"}`))
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+
+	body := w.Body.String()
+	assert.Contains(t, body, ": ping\n\n")
+	assert.Contains(t, body, "event: done")
+}
+
+func TestChat_NoPingsWhenTheHeartbeatIsDisabled(t *testing.T) {
+	h := heartbeatFixture(0, 20*time.Millisecond)
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"message":"why did my run fail?"}`))
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+
+	assert.NotContains(t, w.Body.String(), ": ping")
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+)
+
+const (
+	// historyWindowMessages is the history length beyond which the
+	// provider no longer receives the whole conversation per iteration.
+	historyWindowMessages = 40
+	// historyRecentMessages is the trailing slice always sent once the
+	// window applies. It is sized for several tool-heavy turns of context.
+	historyRecentMessages = 30
+)
+
+// providerWindow selects the slice of a long history that is sent to the
+// model for one iteration: the most recent messages, plus pinned older
+// ones the model should not lose — the opening message that states the
+// conversation's goal and injected system notes (mode switches, mutation
+// hints). Long sessions otherwise resend hundreds of stale tool results
+// on every iteration.
+func providerWindow(messages []provider.Message) []provider.Message {
+	if len(messages) <= historyWindowMessages {
+		return messages
+	}
+	start := len(messages) - historyRecentMessages
+	// The window must not open on tool results whose originating call
+	// falls outside it; providers reject results without a matching call.
+	for start < len(messages) && messages[start].Role == provider.RoleTool {
+		start++
+	}
+
+	window := make([]provider.Message, 0, historyRecentMessages+8)
+	for i, m := range messages[:start] {
+		if pinnedInWindow(i, m) {
+			window = append(window, m)
+		}
+	}
+	omitted := start - len(window)
+	if omitted > 0 {
+		window = append(window, provider.Message{
+			Role: provider.RoleUser,
+			Content: fmt.Sprintf("[System note] %v earlier message(s) of this conversation were omitted to stay within the context budget. "+
+				"Ask the user to repeat earlier details if they are needed.", omitted),
+		})
+	}
+	return append(window, messages[start:]...)
+}
+
+// tokenWindow further trims a window to the estimated token budget.
+// Message-count windowing above is blind to size — one message can
+// carry a huge pipeline spec — so the tail is re-measured with the
+// provider's tokenizer and older messages are dropped until it fits.
+// It returns the trimmed window and how many messages were dropped;
+// pinned messages ride along like in providerWindow and a system note
+// tells the model that context is missing.
+func tokenWindow(messages []provider.Message, tk tokenizer.Tokenizer, budget int) ([]provider.Message, int) {
+	if tk == nil || budget <= 0 || tokenizer.CountMessages(tk, messages) <= budget {
+		return messages, 0
+	}
+	// Walk back from the most recent message until the budget is spent.
+	start := len(messages)
+	remaining := budget
+	for start > 0 {
+		cost := tokenizer.CountMessages(tk, messages[start-1:start])
+		if cost > remaining {
+			break
+		}
+		remaining -= cost
+		start--
+	}
+	if start == len(messages) {
+		// Even the newest message alone busts the budget. It is sent
+		// regardless; the provider gives the authoritative rejection.
+		start--
+	}
+	// Never open on tool results whose originating call was dropped.
+	for start < len(messages)-1 && messages[start].Role == provider.RoleTool {
+		start++
+	}
+	if start == 0 {
+		return messages, 0
+	}
+
+	window := make([]provider.Message, 0, len(messages)-start+8)
+	dropped := start
+	for i, m := range messages[:start] {
+		if pinnedInWindow(i, m) {
+			window = append(window, m)
+			dropped--
+		}
+	}
+	if dropped > 0 {
+		window = append(window, provider.Message{
+			Role: provider.RoleUser,
+			Content: fmt.Sprintf("[System note] %v earlier message(s) were dropped to fit the model's context budget. "+
+				"Ask the user to repeat earlier details if they are needed.", dropped),
+		})
+	}
+	return append(window, messages[start:]...), dropped
+}
+
+// pinnedInWindow reports whether an older message survives windowing.
+func pinnedInWindow(index int, m provider.Message) bool {
+	// The opening message states what the user came to do.
+	if index == 0 && m.Role == provider.RoleUser {
+		return true
+	}
+	// System notes carry state the model must not forget, e.g. which tools
+	// a mode switch enabled or that a resource it created may lag reads.
+	return m.Role == provider.RoleUser && strings.HasPrefix(m.Content, "[System note]")
+}
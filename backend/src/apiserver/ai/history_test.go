@@ -0,0 +1,156 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderWindow_ShortHistoryPassesThrough(t *testing.T) {
+	messages := []provider.Message{
+		{Role: provider.RoleUser, Content: "hello"},
+		{Role: provider.RoleAssistant, Content: "hi"},
+	}
+	assert.Equal(t, messages, providerWindow(messages))
+}
+
+func TestProviderWindow_KeepsOpeningMessageSystemNotesAndRecentTail(t *testing.T) {
+	messages := []provider.Message{
+		{Role: provider.RoleUser, Content: "investigate my failing pipeline"},
+		{Role: provider.RoleAssistant, Content: "on it"},
+		{Role: provider.RoleUser, Content: "[System note] The user switched this session from ask to agent mode."},
+	}
+	for i := 0; i < 100; i++ {
+		messages = append(messages, provider.Message{Role: provider.RoleUser, Content: fmt.Sprintf("filler %v", i)})
+	}
+
+	window := providerWindow(messages)
+	require.True(t, len(window) < len(messages))
+	assert.Equal(t, "investigate my failing pipeline", window[0].Content)
+	assert.Contains(t, window[1].Content, "switched this session")
+	// The elision note tells the model context was dropped.
+	assert.Contains(t, window[2].Content, "omitted")
+	// The tail is the most recent messages, in order.
+	assert.Equal(t, "filler 99", window[len(window)-1].Content)
+	assert.Equal(t, messages[len(messages)-historyRecentMessages:], window[len(window)-historyRecentMessages:])
+}
+
+func TestProviderWindow_NeverOpensOnDanglingToolResults(t *testing.T) {
+	messages := []provider.Message{{Role: provider.RoleUser, Content: "go"}}
+	for i := 0; i < 60; i++ {
+		id := fmt.Sprintf("tc%v", i)
+		messages = append(messages,
+			provider.Message{Role: provider.RoleAssistant, ToolCalls: []provider.ToolCall{{ID: id, Name: "list_runs"}}},
+			provider.Message{Role: provider.RoleTool, ToolCallID: id, Content: "{}"},
+		)
+	}
+
+	window := providerWindow(messages)
+	seenCalls := map[string]bool{}
+	for _, m := range window {
+		for _, call := range m.ToolCalls {
+			seenCalls[call.ID] = true
+		}
+		if m.Role == provider.RoleTool {
+			assert.True(t, seenCalls[m.ToolCallID], "tool result %v has no matching call in the window", m.ToolCallID)
+		}
+	}
+	// Pinned messages and the elision note precede the recent tail.
+	assert.Equal(t, "go", window[0].Content)
+	assert.True(t, strings.HasPrefix(window[1].Content, "[System note]"))
+}
+
+func TestTokenWindow_WithinBudgetPassesThrough(t *testing.T) {
+	tk := tokenizer.ForProvider("anthropic")
+	messages := []provider.Message{
+		{Role: provider.RoleUser, Content: "hello"},
+		{Role: provider.RoleAssistant, Content: "hi"},
+	}
+
+	window, dropped := tokenWindow(messages, tk, 1000)
+	assert.Equal(t, messages, window)
+	assert.Zero(t, dropped)
+}
+
+func TestTokenWindow_DropsOldestUntilTheBudgetFits(t *testing.T) {
+	tk := tokenizer.ForProvider("anthropic")
+	messages := []provider.Message{{Role: provider.RoleUser, Content: "investigate my failing pipeline"}}
+	for i := 0; i < 20; i++ {
+		// Each filler message is roughly 290 tokens under the Claude
+		// estimate, so a 1000-token budget keeps only a short tail.
+		messages = append(messages, provider.Message{
+			Role:    provider.RoleAssistant,
+			Content: fmt.Sprintf("%v %v", i, strings.Repeat("x", 1000)),
+		})
+	}
+
+	window, dropped := tokenWindow(messages, tk, 1000)
+	require.True(t, dropped > 0)
+	require.True(t, len(window) < len(messages))
+	// The opening message is pinned, the note explains the gap and the
+	// most recent messages survive in order.
+	assert.Equal(t, "investigate my failing pipeline", window[0].Content)
+	assert.Contains(t, window[1].Content, "dropped to fit the model's context budget")
+	assert.Equal(t, messages[len(messages)-1], window[len(window)-1])
+	// The unpinned tail fits the budget.
+	assert.True(t, tokenizer.CountMessages(tk, window[2:]) <= 1000)
+}
+
+func TestTokenWindow_OneOversizedMessageIsStillSent(t *testing.T) {
+	tk := tokenizer.ForProvider("anthropic")
+	messages := []provider.Message{
+		{Role: provider.RoleUser, Content: "deploy my pipeline"},
+		{Role: provider.RoleAssistant, Content: "which one?"},
+		{Role: provider.RoleUser, Content: strings.Repeat("spec ", 5000)},
+	}
+
+	window, dropped := tokenWindow(messages, tk, 100)
+	// The newest message always goes out, even alone over budget; the
+	// pinned opening message rides along.
+	assert.Equal(t, messages[2], window[len(window)-1])
+	assert.Equal(t, "deploy my pipeline", window[0].Content)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestTokenWindow_NeverOpensOnDanglingToolResults(t *testing.T) {
+	tk := tokenizer.ForProvider("anthropic")
+	messages := []provider.Message{{Role: provider.RoleUser, Content: "go"}}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("tc%v", i)
+		messages = append(messages,
+			provider.Message{Role: provider.RoleAssistant, Content: strings.Repeat("y", 700), ToolCalls: []provider.ToolCall{{ID: id, Name: "list_runs"}}},
+			provider.Message{Role: provider.RoleTool, ToolCallID: id, Content: "{}"},
+		)
+	}
+
+	window, dropped := tokenWindow(messages, tk, 800)
+	require.True(t, dropped > 0)
+	seenCalls := map[string]bool{}
+	for _, m := range window {
+		for _, call := range m.ToolCalls {
+			seenCalls[call.ID] = true
+		}
+		if m.Role == provider.RoleTool {
+			assert.True(t, seenCalls[m.ToolCallID], "tool result %v has no matching call in the window", m.ToolCallID)
+		}
+	}
+}
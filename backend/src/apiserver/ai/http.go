@@ -0,0 +1,101 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// WithCompression negotiates gzip or deflate for a non-stream endpoint.
+// The SSE chat stream must not go through this wrapper: buffering
+// compressors defeat flushing semantics.
+func WithCompression(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next(w, r)
+			return
+		}
+		var compressor io.WriteCloser
+		switch encoding {
+		case "gzip":
+			compressor = gzip.NewWriter(w)
+		case "deflate":
+			// The only error cause is an invalid level; -1 is always valid.
+			compressor, _ = flate.NewWriter(w, flate.DefaultCompression)
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Header().Add("Vary", "Accept-Encoding")
+		defer compressor.Close()
+		next(&compressedResponseWriter{ResponseWriter: w, compressor: compressor}, r)
+	}
+}
+
+// negotiateEncoding picks a supported content coding from an
+// Accept-Encoding header, preferring gzip.
+func negotiateEncoding(acceptEncoding string) string {
+	deflate := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch coding {
+		case "gzip":
+			return "gzip"
+		case "deflate":
+			deflate = true
+		}
+	}
+	if deflate {
+		return "deflate"
+	}
+	return ""
+}
+
+// compressedResponseWriter routes the body through the negotiated
+// compressor while headers pass through unchanged.
+type compressedResponseWriter struct {
+	http.ResponseWriter
+	compressor io.Writer
+}
+
+func (w *compressedResponseWriter) WriteHeader(status int) {
+	// The compressed length is unknown up front.
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressedResponseWriter) Write(p []byte) (int, error) {
+	return w.compressor.Write(p)
+}
+
+// setWriteDeadline applies the configured per-request write deadline.
+// Works on both HTTP/1.1 and HTTP/2 via the response controller; a zero
+// timeout clears any server-wide deadline, which the chat stream relies
+// on to outlive global timeouts.
+func setWriteDeadline(w http.ResponseWriter, timeout time.Duration) {
+	deadline := time.Time{}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+		glog.V(4).Infof("Failed to set AI endpoint write deadline: %v", err)
+	}
+}
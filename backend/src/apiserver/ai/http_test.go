@@ -0,0 +1,64 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, deflate, br"))
+	assert.Equal(t, "gzip", negotiateEncoding("deflate;q=0.5, gzip;q=1.0"))
+	assert.Equal(t, "deflate", negotiateEncoding("deflate"))
+	assert.Equal(t, "", negotiateEncoding("br"))
+	assert.Equal(t, "", negotiateEncoding(""))
+}
+
+func TestWithCompression_GzipsWhenAccepted(t *testing.T) {
+	handler := WithCompression(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hello":"world"}`))
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"hello":"world"}`, string(body))
+}
+
+func TestWithCompression_PassesThroughWithoutAcceptEncoding(t *testing.T) {
+	handler := WithCompression(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{}`, w.Body.String())
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// idempotencyKeyTTL is how long a recorded response is replayed for
+	// retries carrying the same key.
+	idempotencyKeyTTL = 10 * time.Minute
+	// maxIdempotencyEntries bounds the cache; oldest entries are pruned
+	// beyond this.
+	maxIdempotencyEntries = 10000
+)
+
+// cachedResponse is the recorded outcome of an idempotent request.
+type cachedResponse struct {
+	status   int
+	body     []byte
+	storedAt time.Time
+}
+
+// idempotencyCache deduplicates retried POST submissions: the response
+// to the first request carrying a key is recorded and replayed for
+// retries, instead of re-executing an action that already took effect.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{entries: map[string]cachedResponse{}}
+}
+
+// Lookup returns the recorded response for the key, if one is fresh.
+func (c *idempotencyCache) Lookup(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.storedAt) > idempotencyKeyTTL {
+		return cachedResponse{}, false
+	}
+	return entry, true
+}
+
+// Store records the response for the key, pruning stale entries.
+func (c *idempotencyCache) Store(key string, status int, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.storedAt) > idempotencyKeyTTL {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) >= maxIdempotencyEntries {
+		oldestKey := ""
+		oldestAt := now
+		for k, entry := range c.entries {
+			if entry.storedAt.Before(oldestAt) {
+				oldestKey, oldestAt = k, entry.storedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = cachedResponse{status: status, body: body, storedAt: now}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyCache_ReplaysStoredResponse(t *testing.T) {
+	cache := newIdempotencyCache()
+	_, ok := cache.Lookup("alice\x00k1")
+	assert.False(t, ok)
+
+	cache.Store("alice\x00k1", http.StatusOK, []byte(`{}`))
+	cached, ok := cache.Lookup("alice\x00k1")
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, cached.status)
+	assert.Equal(t, `{}`, string(cached.body))
+
+	// Keys are user scoped; a different user's key misses.
+	_, ok = cache.Lookup("bob\x00k1")
+	assert.False(t, ok)
+}
+
+func TestIdempotencyCache_ExpiresEntries(t *testing.T) {
+	cache := newIdempotencyCache()
+	cache.Store("alice\x00k1", http.StatusOK, []byte(`{}`))
+	cache.mu.Lock()
+	entry := cache.entries["alice\x00k1"]
+	entry.storedAt = time.Now().Add(-idempotencyKeyTTL - time.Second)
+	cache.entries["alice\x00k1"] = entry
+	cache.mu.Unlock()
+
+	_, ok := cache.Lookup("alice\x00k1")
+	assert.False(t, ok)
+}
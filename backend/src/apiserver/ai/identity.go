@@ -0,0 +1,73 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sync"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+)
+
+// IdentityResolver maps raw user identities — the value of the Kubeflow
+// user ID header — to human-readable display names for audit output,
+// session sharing and approval reporting, instead of exposing header
+// values everywhere. Resolution order: the configured directory, then
+// an installed lookup hook (e.g. a Kubeflow Profile or company
+// directory integration), then the raw identity itself so the result is
+// always usable.
+type IdentityResolver struct {
+	mu        sync.RWMutex
+	directory map[string]string
+	lookup    func(userId string) (string, bool)
+}
+
+func newIdentityResolver(cfg *config.Config) *IdentityResolver {
+	r := &IdentityResolver{}
+	r.applyConfig(cfg)
+	return r
+}
+
+// applyConfig swaps in the reloaded directory.
+func (r *IdentityResolver) applyConfig(cfg *config.Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.directory = cfg.IdentityDirectory
+}
+
+// SetLookup installs the directory hook consulted when the configured
+// directory has no entry for an identity.
+func (r *IdentityResolver) SetLookup(lookup func(userId string) (string, bool)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookup = lookup
+}
+
+// DisplayName resolves an identity to its display form. Unknown
+// identities resolve to themselves.
+func (r *IdentityResolver) DisplayName(userId string) string {
+	r.mu.RLock()
+	name, ok := r.directory[userId]
+	lookup := r.lookup
+	r.mu.RUnlock()
+	if ok && name != "" {
+		return name
+	}
+	if lookup != nil {
+		if name, ok := lookup(userId); ok && name != "" {
+			return name
+		}
+	}
+	return userId
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityResolver_ResolutionOrder(t *testing.T) {
+	resolver := newIdentityResolver(&config.Config{IdentityDirectory: map[string]string{
+		"alice@example.com": "Alice Jones",
+	}})
+
+	// The configured directory wins.
+	assert.Equal(t, "Alice Jones", resolver.DisplayName("alice@example.com"))
+	// Unknown identities resolve to themselves.
+	assert.Equal(t, "bob@example.com", resolver.DisplayName("bob@example.com"))
+
+	// An installed hook covers identities the directory misses.
+	resolver.SetLookup(func(userId string) (string, bool) {
+		if userId == "bob@example.com" {
+			return "Bob Smith", true
+		}
+		return "", false
+	})
+	assert.Equal(t, "Bob Smith", resolver.DisplayName("bob@example.com"))
+	assert.Equal(t, "Alice Jones", resolver.DisplayName("alice@example.com"))
+	assert.Equal(t, "carol@example.com", resolver.DisplayName("carol@example.com"))
+}
+
+func TestIdentityResolver_DirectoryIsReloadable(t *testing.T) {
+	resolver := newIdentityResolver(&config.Config{})
+	assert.Equal(t, "alice@example.com", resolver.DisplayName("alice@example.com"))
+
+	resolver.applyConfig(&config.Config{IdentityDirectory: map[string]string{"alice@example.com": "Alice Jones"}})
+	assert.Equal(t, "Alice Jones", resolver.DisplayName("alice@example.com"))
+}
+
+func TestListForUser_ResolvesTheOwnerDisplayName(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		maxSessions: 10,
+		identities: newIdentityResolver(&config.Config{IdentityDirectory: map[string]string{
+			"alice@example.com": "Alice Jones",
+		}}),
+	}
+	_, err := m.GetOrCreate("", "alice@example.com", "team-a", "ask", "")
+	require.NoError(t, err)
+
+	sessions, err := m.ListForUser("alice@example.com")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "Alice Jones", sessions[0].User)
+}
+
+func TestExecuteToolCall_RecordsWhoApproved(t *testing.T) {
+	stub := &stubValidatedTool{}
+	server, session := confirmationFixture(t, stub)
+	server.sessionManager.identities = newIdentityResolver(&config.Config{IdentityDirectory: map[string]string{
+		"alice": "Alice Jones",
+	}})
+
+	emit := func(e Event) error {
+		if e.Type == EventTypeConfirmationRequest {
+			payload := e.Payload.(*ConfirmationRequestPayload)
+			require.NoError(t, session.resolveConfirmation(payload.ToolCallID, true))
+		}
+		return nil
+	}
+	server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "stub_mutate", ArgumentsJSON: "{}"}, emit)
+
+	require.True(t, stub.executed)
+	actions := session.actionsSnapshot()
+	require.NotEmpty(t, actions)
+	assert.Equal(t, "Alice Jones", actions[len(actions)-1].ApprovedBy)
+}
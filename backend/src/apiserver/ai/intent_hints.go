@@ -0,0 +1,168 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+// When an operational question gets a hedging answer with no tool call,
+// the model usually failed tool discovery on fringe phrasing rather
+// than lacking the capability. A lightweight matcher scores the tool
+// descriptions against the user's words and injects one hint naming the
+// closest candidates, giving the model a second chance before the turn
+// ends. Like the trivial-query classifier, it errs toward doing
+// nothing: a missed hint just keeps today's behavior, while a wrong one
+// wastes an iteration.
+
+const (
+	// maxToolHints bounds how many candidate tools one hint names.
+	maxToolHints = 3
+	// minHintOverlap is the informative-word overlap a tool needs before
+	// it is suggested; one shared word is too often coincidence.
+	minHintOverlap = 2
+)
+
+// operationalWords are resource and activity terms that mark a question
+// as being about the deployment rather than about concepts.
+var operationalWords = map[string]bool{
+	"run": true, "runs": true, "pipeline": true, "pipelines": true,
+	"experiment": true, "experiments": true, "job": true, "jobs": true,
+	"recurring": true, "schedule": true, "scheduled": true, "schedules": true,
+	"artifact": true, "artifacts": true, "log": true, "logs": true,
+	"task": true, "tasks": true, "pod": true, "pods": true,
+	"metric": true, "metrics": true, "version": true, "versions": true,
+	"namespace": true, "namespaces": true, "execution": true, "executions": true,
+}
+
+// hedgingMarkers are phrasings of an answer that concedes rather than
+// answers.
+var hedgingMarkers = []string{
+	"i'm not sure", "i am not sure", "not sure", "i don't know", "i do not know",
+	"i cannot", "i can't", "i am unable", "i'm unable", "unable to",
+	"don't have access", "do not have access", "don't have the ability",
+	"no way to", "can't directly", "cannot directly", "i don't have",
+}
+
+// hintStopWords are words too common to signal intent, including the
+// question scaffolding the operational check already ignores.
+var hintStopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "is": true, "are": true, "was": true,
+	"were": true, "be": true, "been": true, "do": true, "does": true, "did": true,
+	"can": true, "could": true, "will": true, "would": true, "should": true,
+	"i": true, "me": true, "my": true, "our": true, "you": true, "your": true,
+	"it": true, "its": true, "this": true, "that": true, "these": true,
+	"what": true, "which": true, "who": true, "when": true, "where": true,
+	"how": true, "why": true, "to": true, "of": true, "in": true, "on": true,
+	"for": true, "and": true, "or": true, "with": true, "all": true,
+	"any": true, "some": true, "about": true, "please": true, "there": true,
+}
+
+// isOperationalQuery reports whether a message asks about the user's
+// deployment — the kind of question that should be grounded in a tool
+// call rather than answered from memory.
+func isOperationalQuery(message string) bool {
+	if isTrivialQuery(message) {
+		return false
+	}
+	normalized := strings.ToLower(strings.TrimSpace(message))
+	for _, word := range strings.Fields(strings.Map(stripPunct, normalized)) {
+		if operationalWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// looksUncertain reports whether assistant text reads as a hedge: empty,
+// or conceding that it cannot answer or access something.
+func looksUncertain(text string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	if normalized == "" {
+		return true
+	}
+	for _, marker := range hedgingMarkers {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestToolsForIntent returns up to maxToolHints tool names whose name
+// or description overlaps the message's informative words, best matches
+// first. Ties keep the tool order, which lists builtins before
+// later registrations.
+func suggestToolsForIntent(message string, tools []tool.Tool) []string {
+	words := intentWords(message)
+	if len(words) == 0 {
+		return nil
+	}
+	type scored struct {
+		name  string
+		score int
+	}
+	candidates := []scored{}
+	for _, t := range tools {
+		vocabulary := intentWords(strings.ReplaceAll(t.Name(), "_", " ") + " " + t.Description())
+		score := 0
+		for word := range words {
+			if vocabulary[word] {
+				score++
+			}
+		}
+		if score >= minHintOverlap {
+			candidates = append(candidates, scored{name: t.Name(), score: score})
+		}
+	}
+	// Insertion sort by descending score; stable, and the lists are tiny.
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && candidates[j].score > candidates[j-1].score; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+	if len(candidates) > maxToolHints {
+		candidates = candidates[:maxToolHints]
+	}
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, candidate.name)
+	}
+	return names
+}
+
+// intentWords extracts the informative words of a text: lowercased,
+// punctuation stripped, stop words dropped, and plurals folded onto
+// their singular so "runs" matches "run".
+func intentWords(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.Fields(strings.Map(stripPunct, strings.ToLower(text))) {
+		if len(word) < 3 || hintStopWords[word] {
+			continue
+		}
+		words[strings.TrimSuffix(word, "s")] = true
+	}
+	return words
+}
+
+// toolHintNote renders the injected hint message.
+func toolHintNote(names []string) string {
+	return fmt.Sprintf("[System note] The question looks operational but no tool was called. "+
+		"These available tools may apply: %s. Call one if it can ground the answer in live data; "+
+		"otherwise answer as before.", strings.Join(names, ", "))
+}
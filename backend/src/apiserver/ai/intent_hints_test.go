@@ -0,0 +1,137 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hintTool is a named description carrier for the intent matcher tests.
+type hintTool struct {
+	name        string
+	description string
+}
+
+func (t *hintTool) Name() string                        { return t.name }
+func (t *hintTool) Description() string                 { return t.description }
+func (t *hintTool) InputSchema() map[string]interface{} { return map[string]interface{}{} }
+func (t *hintTool) Mutating() bool                      { return false }
+func (t *hintTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	return &tool.Result{}, nil
+}
+
+func TestIsOperationalQuery(t *testing.T) {
+	assert.True(t, isOperationalQuery("why did my run fail?"))
+	assert.True(t, isOperationalQuery("any pods stuck in the kubeflow namespace?"))
+	// Documentation questions and greetings are not operational.
+	assert.False(t, isOperationalQuery("what is a pipeline?"))
+	assert.False(t, isOperationalQuery("hello there"))
+	// No resource vocabulary at all.
+	assert.False(t, isOperationalQuery("tell me a joke"))
+}
+
+func TestLooksUncertain(t *testing.T) {
+	assert.True(t, looksUncertain("I'm not sure I can help with that."))
+	assert.True(t, looksUncertain("Unfortunately I don't have access to scheduling data."))
+	assert.True(t, looksUncertain("   "))
+	assert.False(t, looksUncertain("Run 42 failed because its main container was OOM killed."))
+}
+
+func TestSuggestToolsForIntent_RanksByWordOverlap(t *testing.T) {
+	tools := []tool.Tool{
+		&hintTool{name: "get_run", description: "Fetches one run by its ID."},
+		&hintTool{name: "list_runs", description: "Lists runs in an experiment, optionally filtered by state."},
+		&hintTool{name: "upload_pipeline", description: "Uploads a new pipeline package."},
+	}
+
+	hints := suggestToolsForIntent("list the failed runs in my experiment", tools)
+	require.NotEmpty(t, hints)
+	assert.Equal(t, "list_runs", hints[0])
+	assert.NotContains(t, hints, "upload_pipeline")
+
+	// A single shared word is not enough evidence.
+	assert.Empty(t, suggestToolsForIntent("sing about a run", tools))
+}
+
+// intentModel hedges until a tool hint arrives, then answers concretely.
+type intentModel struct {
+	calls int
+}
+
+func (m *intentModel) Name() string { return "intent-stub" }
+
+func (m *intentModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	// The follow-up suggestion call at the end of the turn is not a
+	// conversational iteration.
+	if req.System != suggestionsSystemPrompt {
+		m.calls++
+	}
+	reply := "I'm not sure I can help with that."
+	if strings.Contains(req.Messages[len(req.Messages)-1].Content, "[System note] The question looks operational") {
+		reply = "Run run-1 failed because its main container was OOM killed."
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: reply}}); err != nil {
+		return err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
+
+func TestStreamChat_InjectsAToolHintAfterAnUncertainAnswer(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	model := &intentModel{}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var markdown strings.Builder
+	err = server.StreamChat(context.Background(), session, "list the failed runs in my experiment from today", func(event Event) error {
+		if event.Type == EventTypeMarkdown {
+			markdown.WriteString(event.Payload.(*MarkdownPayload).Text)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The hedge triggered exactly one extra iteration with the hint.
+	assert.Equal(t, 2, model.calls)
+	assert.Contains(t, markdown.String(), "OOM killed")
+	hinted := false
+	for _, message := range session.Messages() {
+		if message.Role == provider.RoleUser && strings.Contains(message.Content, "These available tools may apply") {
+			hinted = true
+		}
+	}
+	assert.True(t, hinted)
+}
+
+func TestStreamChat_LeavesConfidentAnswersAlone(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	model := &summaryStubModel{reply: "Run 42 failed because its main container was OOM killed."}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: model}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	require.NoError(t, server.StreamChat(context.Background(), session, "why did my run fail?", func(Event) error { return nil }))
+	for _, message := range session.Messages() {
+		assert.NotContains(t, message.Content, "These available tools may apply")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+)
+
+// Bounds on the retry hint handed to shed clients, so they neither
+// hammer a struggling server nor give up on a healthy one.
+const (
+	minShedRetryAfter = 5 * time.Second
+	maxShedRetryAfter = time.Minute
+)
+
+// loadShedder is an adaptive admission controller for chat turns. It
+// tracks how many turns are in flight across all serving surfaces and a
+// smoothed provider latency; once the system looks saturated new turns
+// are degraded to a cheaper model, and at the in-flight cap they are
+// shed outright with a retry hint, so a busy assistant cannot starve
+// the rest of the API server.
+type loadShedder struct {
+	mu       sync.Mutex
+	inFlight int
+	// latency is an exponentially weighted moving average over the
+	// provider calls of recent turns.
+	latency time.Duration
+
+	// The thresholds mirror the Limits fields of the same names; a
+	// configuration reload swaps them while turns are running.
+	maxInFlight    int
+	degradeLatency time.Duration
+	degradedModel  string
+}
+
+func newLoadShedder(limits config.Limits) *loadShedder {
+	shedder := &loadShedder{}
+	shedder.configure(limits)
+	return shedder
+}
+
+// configure applies (re)loaded limits.
+func (l *loadShedder) configure(limits config.Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxInFlight = limits.MaxInFlightTurns
+	l.degradeLatency = limits.DegradeLatency
+	l.degradedModel = limits.DegradedModel
+}
+
+// overloaded reports whether a new turn should be shed right now and
+// how long the client should wait before retrying.
+func (l *loadShedder) overloaded() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxInFlight <= 0 || l.inFlight < l.maxInFlight {
+		return false, 0
+	}
+	// Roughly two model calls' worth of breathing room.
+	retry := 2 * l.latency
+	if retry < minShedRetryAfter {
+		retry = minShedRetryAfter
+	}
+	if retry > maxShedRetryAfter {
+		retry = maxShedRetryAfter
+	}
+	return true, retry
+}
+
+// begin admits a turn and returns the model override to serve it with —
+// empty for a regular turn — and the matching completion callback.
+func (l *loadShedder) begin() (string, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight++
+	model := ""
+	if l.degradedModel != "" && l.saturated() {
+		model = l.degradedModel
+	}
+	return model, l.end
+}
+
+func (l *loadShedder) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+}
+
+// saturated reports whether the system is close enough to its limits
+// that new turns should degrade: the smoothed latency passed its
+// threshold, or the in-flight count passed three quarters of the cap.
+// Callers hold mu.
+func (l *loadShedder) saturated() bool {
+	if l.degradeLatency > 0 && l.latency >= l.degradeLatency {
+		return true
+	}
+	return l.maxInFlight > 0 && 4*l.inFlight >= 3*l.maxInFlight
+}
+
+// observe folds one provider call's latency into the moving average.
+func (l *loadShedder) observe(elapsed time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.latency == 0 {
+		l.latency = elapsed
+		return
+	}
+	l.latency = (3*l.latency + elapsed) / 4
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShedder_ShedsAtTheInFlightCap(t *testing.T) {
+	shedder := newLoadShedder(config.Limits{MaxInFlightTurns: 2})
+	_, end1 := shedder.begin()
+	_, end2 := shedder.begin()
+
+	shed, retryAfter := shedder.overloaded()
+	assert.True(t, shed)
+	assert.GreaterOrEqual(t, retryAfter, minShedRetryAfter)
+	assert.LessOrEqual(t, retryAfter, maxShedRetryAfter)
+
+	end1()
+	shed, _ = shedder.overloaded()
+	assert.False(t, shed)
+	end2()
+}
+
+func TestLoadShedder_DegradesNearTheInFlightCap(t *testing.T) {
+	shedder := newLoadShedder(config.Limits{MaxInFlightTurns: 4, DegradedModel: "small"})
+	model1, _ := shedder.begin()
+	model2, _ := shedder.begin()
+	// The third turn crosses three quarters of the cap.
+	model3, _ := shedder.begin()
+	assert.Empty(t, model1)
+	assert.Empty(t, model2)
+	assert.Equal(t, "small", model3)
+}
+
+func TestLoadShedder_DegradesOnProviderLatency(t *testing.T) {
+	shedder := newLoadShedder(config.Limits{DegradeLatency: 100 * time.Millisecond, DegradedModel: "small"})
+	model, end := shedder.begin()
+	assert.Empty(t, model)
+	end()
+
+	shedder.observe(400 * time.Millisecond)
+	model, end = shedder.begin()
+	assert.Equal(t, "small", model)
+	end()
+
+	// Without a degraded model configured there is nothing to degrade to.
+	shedder.configure(config.Limits{DegradeLatency: 100 * time.Millisecond})
+	model, end = shedder.begin()
+	assert.Empty(t, model)
+	end()
+}
+
+func TestLoadShedder_SmoothsObservedLatency(t *testing.T) {
+	shedder := newLoadShedder(config.Limits{})
+	shedder.observe(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, shedder.latency)
+	shedder.observe(200 * time.Millisecond)
+	assert.Equal(t, 125*time.Millisecond, shedder.latency)
+}
+
+func TestStreamChat_DegradedTurnsUseTheConfiguredModel(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	model := &modelRecorder{}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          model,
+		shedder:        newLoadShedder(config.Limits{DegradeLatency: time.Millisecond, DegradedModel: "small"}),
+	}
+	server.shedder.observe(time.Second)
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	degraded := false
+	err = server.StreamChat(context.Background(), session, "why did my run fail?", func(event Event) error {
+		if event.Type == EventTypeModelDegraded {
+			degraded = true
+			assert.Equal(t, "small", event.Payload.(*ModelDegradedPayload).Model)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, degraded)
+	assert.Equal(t, "small", model.requestedModel)
+}
+
+func TestChat_ShedsWithARetryHintWhenSaturated(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &modelRecorder{},
+		shedder:        newLoadShedder(config.Limits{MaxInFlightTurns: 1}),
+	}
+	_, end := server.shedder.begin()
+	defer end()
+	h := NewSSEHandler(server, &config.Config{Limits: config.Limits{ChatRateLimit: 10}}, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"message":"why did my run fail?"}`))
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+}
+
+// modelRecorder remembers the per-request model override it was asked
+// to serve.
+type modelRecorder struct {
+	requestedModel string
+}
+
+func (m *modelRecorder) Name() string { return "recorder" }
+
+func (m *modelRecorder) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	// The end-of-turn suggestions call is not the turn under test.
+	if req.System != suggestionsSystemPrompt {
+		m.requestedModel = req.Model
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "ok"}}); err != nil {
+		return err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLinkSigner_RoundTrip(t *testing.T) {
+	signer := newLogLinkSigner()
+	link, expiresAt := signer.Link("run-1", "pod-1")
+	assert.Greater(t, expiresAt, time.Now().Unix())
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, LogDownloadPath))
+	query := parsed.Query()
+	assert.NoError(t, signer.Verify("run-1", "pod-1", query.Get("expires"), query.Get("token")))
+}
+
+func TestLogLinkSigner_RejectsTampering(t *testing.T) {
+	signer := newLogLinkSigner()
+	link, _ := signer.Link("run-1", "pod-1")
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	query := parsed.Query()
+
+	assert.Error(t, signer.Verify("run-2", "pod-1", query.Get("expires"), query.Get("token")))
+	assert.Error(t, signer.Verify("run-1", "pod-2", query.Get("expires"), query.Get("token")))
+	assert.Error(t, signer.Verify("run-1", "pod-1", query.Get("expires"), "deadbeef"))
+	assert.Error(t, signer.Verify("run-1", "pod-1", "not-a-number", query.Get("token")))
+}
+
+func TestLogLinkSigner_RejectsExpiredLinks(t *testing.T) {
+	signer := newLogLinkSigner()
+	expiresAt := time.Now().Add(-time.Minute).Unix()
+	token := signer.sign("run-1", "pod-1", expiresAt)
+	err := signer.Verify("run-1", "pod-1", strconv.FormatInt(expiresAt, 10), token)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+}
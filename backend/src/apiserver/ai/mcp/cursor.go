@@ -0,0 +1,119 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxResultChunkBytes is the largest tool result returned in a single
+	// JSON-RPC response; longer results are paginated with cursors so MCP
+	// clients that truncate giant responses still see everything.
+	maxResultChunkBytes = 32 * 1024
+	// resultCursorTTL is how long an unread continuation stays available.
+	resultCursorTTL = 5 * time.Minute
+	// maxResultCursors bounds the continuation store.
+	maxResultCursors = 256
+)
+
+// chunkedResult is the not-yet-delivered remainder of a large tool
+// result.
+type chunkedResult struct {
+	userID    string
+	remaining string
+	isError   bool
+	createdAt time.Time
+}
+
+// resultCursorStore parks the remainder of large tool results between
+// the paginated tools/call responses that drain them.
+type resultCursorStore struct {
+	mu      sync.Mutex
+	entries map[string]*chunkedResult
+}
+
+func newResultCursorStore() *resultCursorStore {
+	return &resultCursorStore{entries: map[string]*chunkedResult{}}
+}
+
+// chunk splits content into the part to return now and, when a
+// remainder exists, a cursor under which it is parked.
+func (s *resultCursorStore) chunk(userId, content string, isError bool) (string, string) {
+	if len(content) <= maxResultChunkBytes {
+		return content, ""
+	}
+	cursor := uuid.NewString()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+	s.entries[cursor] = &chunkedResult{
+		userID:    userId,
+		remaining: content[maxResultChunkBytes:],
+		isError:   isError,
+		createdAt: time.Now(),
+	}
+	return content[:maxResultChunkBytes], cursor
+}
+
+// next returns the next chunk for the cursor, a follow-up cursor when
+// more remains, and whether the cursor was valid for this user.
+func (s *resultCursorStore) next(userId, cursor string) (string, string, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[cursor]
+	if !ok || entry.userID != userId || time.Since(entry.createdAt) > resultCursorTTL {
+		delete(s.entries, cursor)
+		return "", "", false, false
+	}
+	delete(s.entries, cursor)
+	if len(entry.remaining) <= maxResultChunkBytes {
+		return entry.remaining, "", entry.isError, true
+	}
+	nextCursor := uuid.NewString()
+	s.prune()
+	s.entries[nextCursor] = &chunkedResult{
+		userID:    userId,
+		remaining: entry.remaining[maxResultChunkBytes:],
+		isError:   entry.isError,
+		createdAt: entry.createdAt,
+	}
+	return entry.remaining[:maxResultChunkBytes], nextCursor, entry.isError, true
+}
+
+// prune drops expired continuations and, if the store is still full, the
+// oldest one. Callers must hold s.mu.
+func (s *resultCursorStore) prune() {
+	now := time.Now()
+	for cursor, entry := range s.entries {
+		if now.Sub(entry.createdAt) > resultCursorTTL {
+			delete(s.entries, cursor)
+		}
+	}
+	if len(s.entries) < maxResultCursors {
+		return
+	}
+	oldestCursor := ""
+	oldestAt := now
+	for cursor, entry := range s.entries {
+		if entry.createdAt.Before(oldestAt) {
+			oldestCursor, oldestAt = cursor, entry.createdAt
+		}
+	}
+	delete(s.entries, oldestCursor)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCursorStore_SmallResultIsNotChunked(t *testing.T) {
+	store := newResultCursorStore()
+	chunk, cursor := store.chunk("user@example.com", "small result", false)
+	assert.Equal(t, "small result", chunk)
+	assert.Empty(t, cursor)
+	assert.Empty(t, store.entries)
+}
+
+func TestResultCursorStore_LargeResultRoundTrip(t *testing.T) {
+	store := newResultCursorStore()
+	content := strings.Repeat("a", maxResultChunkBytes) +
+		strings.Repeat("b", maxResultChunkBytes) +
+		"tail"
+
+	chunk, cursor := store.chunk("user@example.com", content, true)
+	require.NotEmpty(t, cursor)
+	assert.Equal(t, strings.Repeat("a", maxResultChunkBytes), chunk)
+
+	chunk, cursor, isError, ok := store.next("user@example.com", cursor)
+	require.True(t, ok)
+	require.NotEmpty(t, cursor)
+	assert.True(t, isError)
+	assert.Equal(t, strings.Repeat("b", maxResultChunkBytes), chunk)
+
+	chunk, cursor, isError, ok = store.next("user@example.com", cursor)
+	require.True(t, ok)
+	assert.Empty(t, cursor)
+	assert.True(t, isError)
+	assert.Equal(t, "tail", chunk)
+	assert.Empty(t, store.entries)
+}
+
+func TestResultCursorStore_CursorIsSingleUse(t *testing.T) {
+	store := newResultCursorStore()
+	content := strings.Repeat("a", maxResultChunkBytes+1)
+	_, cursor := store.chunk("user@example.com", content, false)
+	require.NotEmpty(t, cursor)
+
+	_, _, _, ok := store.next("user@example.com", cursor)
+	require.True(t, ok)
+	_, _, _, ok = store.next("user@example.com", cursor)
+	assert.False(t, ok)
+}
+
+func TestResultCursorStore_RejectsOtherUsersCursor(t *testing.T) {
+	store := newResultCursorStore()
+	content := strings.Repeat("a", maxResultChunkBytes+1)
+	_, cursor := store.chunk("owner@example.com", content, false)
+	require.NotEmpty(t, cursor)
+
+	_, _, _, ok := store.next("other@example.com", cursor)
+	assert.False(t, ok)
+	// A rejected lookup burns the cursor.
+	_, _, _, ok = store.next("owner@example.com", cursor)
+	assert.False(t, ok)
+}
+
+func TestResultCursorStore_ExpiredCursorIsRejected(t *testing.T) {
+	store := newResultCursorStore()
+	content := strings.Repeat("a", maxResultChunkBytes+1)
+	_, cursor := store.chunk("user@example.com", content, false)
+	require.NotEmpty(t, cursor)
+	store.entries[cursor].createdAt = time.Now().Add(-resultCursorTTL - time.Minute)
+
+	_, _, _, ok := store.next("user@example.com", cursor)
+	assert.False(t, ok)
+}
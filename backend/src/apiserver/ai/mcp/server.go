@@ -0,0 +1,393 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcp exposes the assistant's tools to Model Context Protocol
+// clients over JSON-RPC. Large tool results are paginated with a cursor
+// convention instead of being returned as one giant response.
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/audit"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/ratelimit"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Path is the HTTP route of the MCP endpoint.
+	Path = "/apis/v2beta1/ai/mcp"
+
+	protocolVersion = "2024-11-05"
+	serverName      = "kubeflow-pipelines"
+
+	// toolListPageSize bounds a single tools/list page.
+	toolListPageSize = 50
+
+	maxRequestBytes = 256 * 1024
+
+	// JSON-RPC 2.0 error codes.
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// AuthFunc resolves the calling user from the HTTP request.
+type AuthFunc func(r *http.Request) (string, error)
+
+// Server serves the MCP endpoint against the shared tool registry. MCP
+// clients always operate in agent mode semantics minus confirmations:
+// mutating tools are exposed, and the caller's RBAC is the guard.
+type Server struct {
+	resourceManager *resource.ResourceManager
+	registry        *tool.Registry
+	auth            AuthFunc
+	cursors         *resultCursorStore
+	limiter         ratelimit.Limiter
+	audit           *audit.Logger
+
+	// mu guards toolTimeout, which a config reload can change while
+	// calls are in flight.
+	mu          sync.Mutex
+	toolTimeout time.Duration
+}
+
+// NewServer wires the MCP endpoint. A nil limiter constructs the
+// backend the configuration asks for.
+func NewServer(resourceManager *resource.ResourceManager, registry *tool.Registry, auth AuthFunc, cfg *config.Config, limiter ratelimit.Limiter) *Server {
+	if limiter == nil {
+		limiter = ratelimit.FromConfig(cfg, ratelimit.ScopeMCP)
+	}
+	return &Server{
+		resourceManager: resourceManager,
+		registry:        registry,
+		auth:            auth,
+		cursors:         newResultCursorStore(),
+		limiter:         limiter,
+		toolTimeout:     cfg.Limits.ToolTimeout,
+	}
+}
+
+// UseAuditLog attaches the shared tool invocation audit log, so MCP
+// calls are recorded alongside chat-driven ones. Call before serving
+// requests. A nil logger leaves auditing off.
+func (s *Server) UseAuditLog(logger *audit.Logger) {
+	s.audit = logger
+}
+
+// ApplyConfig applies reloaded settings that are safe to change at
+// runtime; for MCP that is the per-client rate limit and window and the
+// tool timeout.
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.limiter.Configure(cfg.Limits.MCPRateLimit, cfg.Limits.MCPRateWindow)
+	s.mu.Lock()
+	s.toolTimeout = cfg.Limits.ToolTimeout
+	s.mu.Unlock()
+}
+
+// currentToolTimeout returns the tool timeout under the lock.
+func (s *Server) currentToolTimeout() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.toolTimeout
+}
+
+type rpcRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcNotification is a server-to-client JSON-RPC notification, batched
+// in front of the response on this request/response transport.
+type rpcNotification struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// logNotification shapes an MCP notifications/message carrying a tool
+// execution warning.
+func logNotification(toolName string, message string) rpcNotification {
+	return rpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "notifications/message",
+		Params: map[string]interface{}{
+			"level":  "warning",
+			"logger": toolName,
+			"data":   message,
+		},
+	}
+}
+
+// Handle serves a single JSON-RPC request on POST Path.
+func (s *Server) Handle(w http.ResponseWriter, r *http.Request) {
+	userId, err := s.auth(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if ok, retryAfter := s.limiter.Allow(userId); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		http.Error(w, "too many MCP requests, retry later", http.StatusTooManyRequests)
+		return
+	}
+
+	var request rpcRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&request); err != nil {
+		s.writeResponse(w, &rpcResponse{Jsonrpc: "2.0", Error: &rpcError{Code: codeParseError, Message: "invalid JSON-RPC request"}})
+		return
+	}
+	if request.ID == nil {
+		// Notifications (e.g. notifications/initialized) get no response.
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	response := &rpcResponse{Jsonrpc: "2.0", ID: request.ID}
+	var notifications []rpcNotification
+	switch request.Method {
+	case "initialize":
+		response.Result = s.initializeResult()
+	case "ping":
+		response.Result = map[string]interface{}{}
+	case "tools/list":
+		response.Result, response.Error = s.listTools(request.Params)
+	case "tools/call":
+		response.Result, notifications, response.Error = s.callTool(r, userId, request.Params)
+	default:
+		response.Error = &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q is not supported", request.Method)}
+	}
+	if len(notifications) > 0 {
+		// Warnings ride along as a JSON-RPC batch in front of the response.
+		batch := make([]interface{}, 0, len(notifications)+1)
+		for _, n := range notifications {
+			batch = append(batch, n)
+		}
+		s.writeResponse(w, append(batch, response))
+		return
+	}
+	s.writeResponse(w, response)
+}
+
+func (s *Server) initializeResult() map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": protocolVersion,
+		"capabilities": map[string]interface{}{
+			"tools":   map[string]interface{}{},
+			"logging": map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    serverName,
+			"version": common.GetStringConfigWithDefault("TAG_NAME", "unknown"),
+		},
+	}
+}
+
+// listTools returns one page of tool definitions. The cursor is the
+// stringified offset of the next page.
+func (s *Server) listTools(params json.RawMessage) (interface{}, *rpcError) {
+	var p struct {
+		Cursor string `json:"cursor"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/list params"}
+		}
+	}
+	offset := 0
+	if p.Cursor != "" {
+		var err error
+		offset, err = strconv.Atoi(p.Cursor)
+		if err != nil || offset < 0 {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/list cursor"}
+		}
+	}
+
+	tools := s.registry.ListForMode(tool.ModeAgent)
+	if offset > len(tools) {
+		offset = len(tools)
+	}
+	end := offset + toolListPageSize
+	nextCursor := ""
+	if end < len(tools) {
+		nextCursor = strconv.Itoa(end)
+	} else {
+		end = len(tools)
+	}
+
+	defs := make([]map[string]interface{}, 0, end-offset)
+	for _, t := range tools[offset:end] {
+		annotations := tool.AnnotationsFor(t)
+		defs = append(defs, map[string]interface{}{
+			"name":        t.Name(),
+			"description": t.Description(),
+			"inputSchema": t.InputSchema(),
+			"annotations": map[string]interface{}{
+				"readOnlyHint":    annotations.ReadOnly,
+				"destructiveHint": annotations.Destructive,
+				"idempotentHint":  annotations.Idempotent,
+			},
+		})
+	}
+	result := map[string]interface{}{"tools": defs}
+	if nextCursor != "" {
+		result["nextCursor"] = nextCursor
+	}
+	return result, nil
+}
+
+// callTool executes a tool, or drains the next chunk of a previously
+// chunked result when a cursor is presented instead of a name. Warnings
+// raised during execution come back as log notifications.
+func (s *Server) callTool(r *http.Request, userId string, params json.RawMessage) (interface{}, []rpcNotification, *rpcError) {
+	var p struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Cursor    string                 `json:"cursor"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params"}
+	}
+
+	if p.Cursor != "" {
+		chunk, nextCursor, isError, ok := s.cursors.next(userId, p.Cursor)
+		if !ok {
+			return nil, nil, &rpcError{Code: codeInvalidParams, Message: "unknown or expired result cursor"}
+		}
+		return toolCallResult(chunk, nextCursor, isError), nil, nil
+	}
+
+	t := s.registry.Get(p.Name)
+	if t == nil {
+		return nil, nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("tool %q is not available", p.Name)}
+	}
+
+	// Tools authorize through the resource manager, which reads the
+	// identity from gRPC metadata; mirror what grpc-gateway would attach.
+	ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs(
+		strings.ToLower(common.GetKubeflowUserIDHeader()), common.GetKubeflowUserIDPrefix()+userId,
+	))
+	var notifications []rpcNotification
+	ec := &tool.ExecContext{
+		ResourceManager: s.resourceManager,
+		UserID:          userId,
+		Timeout:         s.currentToolTimeout(),
+		Warn: func(message string) {
+			notifications = append(notifications, logNotification(p.Name, message))
+		},
+		// MCP has no mid-call channel over plain HTTP, so batch progress
+		// rides along with the response like warnings do.
+		Progress: func(update tool.ProgressUpdate) {
+			if update.Err != "" {
+				notifications = append(notifications, logNotification(p.Name,
+					fmt.Sprintf("progress %v/%v: %v failed: %v", update.Completed, update.Total, update.Item, update.Err)))
+				return
+			}
+			notifications = append(notifications, logNotification(p.Name,
+				fmt.Sprintf("progress %v/%v: %v", update.Completed, update.Total, update.Item)))
+		},
+	}
+	started := time.Now()
+	record := func(outcome, detail string) {
+		if s.audit == nil {
+			return
+		}
+		arguments, _ := json.Marshal(p.Arguments)
+		s.audit.Record(&model.AIAuditEvent{
+			UserID:     userId,
+			Tool:       p.Name,
+			Arguments:  model.LargeText(arguments),
+			Mutating:   t.Mutating(),
+			Outcome:    outcome,
+			Detail:     detail,
+			DurationMs: time.Since(started).Milliseconds(),
+		})
+	}
+	execCtx, cancel := ec.BoundContext(ctx)
+	defer cancel()
+	result, err := t.Execute(execCtx, ec, p.Arguments)
+	if err != nil {
+		glog.Errorf("MCP tool %v failed for user %v: %+v", p.Name, userId, err)
+		record("failed", externalMessage(err))
+		return toolCallResult(externalMessage(err), "", true), notifications, nil
+	}
+	if result.IsError {
+		record("failed", "")
+	} else {
+		record("succeeded", "")
+	}
+	chunk, nextCursor := s.cursors.chunk(userId, result.Content, result.IsError)
+	return toolCallResult(chunk, nextCursor, result.IsError), notifications, nil
+}
+
+// toolCallResult shapes an MCP tools/call result. A non-empty
+// nextCursor signals that the content continues; clients fetch the rest
+// by calling tools/call again with {"cursor": nextCursor}.
+func toolCallResult(content string, nextCursor string, isError bool) map[string]interface{} {
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": content},
+		},
+		"isError": isError,
+	}
+	if nextCursor != "" {
+		result["_meta"] = map[string]interface{}{"nextCursor": nextCursor}
+	}
+	return result
+}
+
+// externalMessage extracts the user-safe message from an error.
+func externalMessage(err error) string {
+	if userErr, ok := err.(*util.UserError); ok {
+		return userErr.ExternalMessage()
+	}
+	return err.Error()
+}
+
+// writeResponse writes a single response or a notification batch.
+func (s *Server) writeResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		glog.Errorf("Failed to write the MCP response: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// maxTemplateRecommendations bounds one recommendation response.
+const maxTemplateRecommendations = 5
+
+// templateCatalog holds the pipeline templates the assistant recommends
+// as starting points. Builtin entries describe the bundled examples;
+// config entries extend the gallery with pipelines shared in the
+// cluster or, by matching ID, override a builtin.
+type templateCatalog struct {
+	mu        sync.RWMutex
+	templates []config.PipelineTemplate
+}
+
+func newTemplateCatalog(cfg *config.Config) *templateCatalog {
+	c := &templateCatalog{}
+	c.applyConfig(cfg)
+	return c
+}
+
+// applyConfig rebuilds the gallery from the builtin templates plus the
+// configured ones.
+func (c *templateCatalog) applyConfig(cfg *config.Config) {
+	merged := builtinPipelineTemplates()
+	index := map[string]int{}
+	for i, template := range merged {
+		index[template.ID] = i
+	}
+	for _, template := range cfg.PipelineTemplates {
+		if i, ok := index[template.ID]; ok {
+			merged[i] = template
+			continue
+		}
+		merged = append(merged, template)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates = merged
+}
+
+// search ranks templates by how many query terms their title,
+// description and tags mention, best matches first. An empty result
+// means nothing in the gallery relates to the query.
+func (c *templateCatalog) search(query string) []config.PipelineTemplate {
+	terms := strings.Fields(strings.ToLower(query))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	type scored struct {
+		template config.PipelineTemplate
+		score    int
+	}
+	matches := []scored{}
+	for _, template := range c.templates {
+		haystack := strings.ToLower(template.Title + " " + template.Description + " " + strings.Join(template.Tags, " "))
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(haystack, term) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{template: template, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if len(matches) > maxTemplateRecommendations {
+		matches = matches[:maxTemplateRecommendations]
+	}
+	out := make([]config.PipelineTemplate, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.template)
+	}
+	return out
+}
+
+// pipelineTemplatesTool recommends gallery templates. It lives in this
+// package rather than with the builtin tools because the gallery is
+// server state fed by the configuration; it joins the shared registry
+// at startup.
+type pipelineTemplatesTool struct {
+	catalog *templateCatalog
+}
+
+type pipelineTemplatesArgs struct {
+	Query string `json:"query" required:"true" description:"Keywords describing what the user wants to build, e.g. 'training pipeline for tabular data'."`
+}
+
+func (t *pipelineTemplatesTool) Name() string { return "recommend_pipeline_templates" }
+
+func (t *pipelineTemplatesTool) Description() string {
+	return "Recommend starting templates from the pipeline gallery for what the user wants to build, " +
+		"including their parameters. Templates naming an uploaded pipeline can be inspected with list_pipelines/get_pipeline."
+}
+
+func (t *pipelineTemplatesTool) InputSchema() map[string]interface{} {
+	return tool.SchemaFor(&pipelineTemplatesArgs{})
+}
+
+func (t *pipelineTemplatesTool) Mutating() bool { return false }
+
+func (t *pipelineTemplatesTool) Execute(ctx context.Context, ec *tool.ExecContext, args map[string]interface{}) (*tool.Result, error) {
+	var a pipelineTemplatesArgs
+	if err := tool.DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(a.Query) == "" {
+		return nil, util.NewInvalidInputError("The query must not be empty")
+	}
+	return tool.JSONResult(map[string]interface{}{"templates": t.catalog.search(a.Query)})
+}
+
+// builtinPipelineTemplates returns the gallery entries every deployment
+// offers: the standard Kubeflow Pipelines samples.
+func builtinPipelineTemplates() []config.PipelineTemplate {
+	return []config.PipelineTemplate{
+		{
+			ID:          "train-and-evaluate",
+			Title:       "Train and evaluate a model",
+			Description: "A training pipeline that loads a dataset, trains a model, evaluates it against a holdout split and registers the resulting artifacts.",
+			Tags:        []string{"training", "evaluation", "model"},
+			SourceURL:   "https://github.com/kubeflow/pipelines/tree/master/samples",
+			Parameters: []config.PipelineTemplateParameter{
+				{Name: "dataset_uri", Description: "Location of the training dataset.", Required: true},
+				{Name: "epochs", Description: "Number of training epochs.", Default: "10"},
+			},
+		},
+		{
+			ID:          "data-preprocessing",
+			Title:       "Preprocess a dataset",
+			Description: "A data preparation pipeline that validates, cleans and splits a raw dataset into train and test sets.",
+			Tags:        []string{"data", "preprocessing", "etl", "validation"},
+			SourceURL:   "https://github.com/kubeflow/pipelines/tree/master/samples",
+			Parameters: []config.PipelineTemplateParameter{
+				{Name: "input_uri", Description: "Location of the raw dataset.", Required: true},
+				{Name: "train_fraction", Description: "Fraction of rows assigned to the training split.", Default: "0.8"},
+			},
+		},
+		{
+			ID:          "batch-inference",
+			Title:       "Run batch inference",
+			Description: "A batch scoring pipeline that loads a trained model and writes predictions for an input dataset.",
+			Tags:        []string{"inference", "prediction", "scoring", "batch"},
+			SourceURL:   "https://github.com/kubeflow/pipelines/tree/master/samples",
+			Parameters: []config.PipelineTemplateParameter{
+				{Name: "model_uri", Description: "Location of the trained model.", Required: true},
+				{Name: "input_uri", Description: "Dataset to score.", Required: true},
+			},
+		},
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateCatalog_SearchRanksByMatchCount(t *testing.T) {
+	catalog := newTemplateCatalog(&config.Config{})
+
+	results := catalog.search("training pipeline for tabular data")
+	require.NotEmpty(t, results)
+	assert.Equal(t, "train-and-evaluate", results[0].ID)
+
+	// Unrelated queries recommend nothing rather than everything.
+	assert.Empty(t, catalog.search("quantum teleportation"))
+}
+
+func TestTemplateCatalog_ConfigExtendsAndOverrides(t *testing.T) {
+	catalog := newTemplateCatalog(&config.Config{PipelineTemplates: []config.PipelineTemplate{
+		{ID: "train-and-evaluate", Title: "Team training pipeline", Description: "Our blessed training setup.",
+			Tags: []string{"training"}, PipelineName: "team-training"},
+		{ID: "fraud-detection", Title: "Fraud detection", Description: "Scores transactions for fraud.",
+			Tags: []string{"fraud", "scoring"}},
+	}})
+
+	results := catalog.search("training")
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Team training pipeline", results[0].Title)
+	assert.Equal(t, "team-training", results[0].PipelineName)
+
+	results = catalog.search("fraud")
+	require.Len(t, results, 1)
+	assert.Equal(t, "fraud-detection", results[0].ID)
+
+	// Builtins not overridden are still present.
+	assert.NotEmpty(t, catalog.search("batch inference"))
+}
+
+func TestPipelineTemplatesTool_ReturnsParameters(t *testing.T) {
+	tl := &pipelineTemplatesTool{catalog: newTemplateCatalog(&config.Config{})}
+
+	result, err := tl.Execute(context.Background(), nil, map[string]interface{}{"query": "train a model"})
+	require.NoError(t, err)
+	var payload struct {
+		Templates []config.PipelineTemplate `json:"templates"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content), &payload))
+	require.NotEmpty(t, payload.Templates)
+	assert.Equal(t, "train-and-evaluate", payload.Templates[0].ID)
+	require.NotEmpty(t, payload.Templates[0].Parameters)
+	assert.Equal(t, "dataset_uri", payload.Templates[0].Parameters[0].Name)
+
+	_, err = tl.Execute(context.Background(), nil, map[string]interface{}{"query": "  "})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+const systemPromptBase = `You are the Kubeflow Pipelines assistant, embedded in the Kubeflow
+Pipelines API server. You help users inspect, debug and operate their ML
+pipelines, experiments and runs.
+
+Guidelines:
+- Use the provided tools to answer questions about pipelines, runs and
+  experiments instead of guessing. Never invent resource IDs or states.
+- Keep answers concise and formatted as markdown.
+- When a tool fails, relay the failure honestly and suggest what the user
+  could check; do not retry the same call more than once.
+- Mutating operations always require explicit user approval; describe
+  clearly what a proposed change will do.`
+
+// buildSystemPrompt assembles the system prompt for a session turn.
+func buildSystemPrompt(session *Session) string {
+	var b strings.Builder
+	b.WriteString(systemPromptBase)
+	b.WriteString("\n\n")
+	if session.Mode == tool.ModeAgent {
+		b.WriteString("The session is in Agent mode: you may propose mutating operations, which the user must approve.")
+	} else {
+		b.WriteString("The session is in Ask mode: only read-only tools are available.")
+	}
+	if session.Namespace != "" {
+		fmt.Fprintf(&b, "\nThe user's namespace is %q; scope queries to it unless told otherwise.", session.Namespace)
+	}
+	timezone := session.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	fmt.Fprintf(&b, "\nThe current time is %v in the user's timezone (%v). Fields ending in \"_iso\" in tool results are already rendered in that zone; quote them instead of converting epoch values yourself.",
+		time.Now().In(session.Location()).Format(time.RFC3339), timezone)
+	return b.String()
+}
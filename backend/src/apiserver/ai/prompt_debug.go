@@ -0,0 +1,104 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+// PromptDebug is the exact model input the next turn of a session would
+// use, for diagnosing prompt issues without temporary logging. It is
+// assembled with the same code path as a live turn: the same system
+// prompt builder, tool adaptation and history windowing.
+type PromptDebug struct {
+	SessionID string `json:"session_id"`
+	// Model is the provider's model identifier.
+	Model string `json:"model"`
+	Mode  string `json:"mode"`
+	// SystemPrompt is the full rendered system prompt.
+	SystemPrompt string `json:"system_prompt"`
+	// Tools are the definitions the provider would receive, after
+	// capability adaptation.
+	Tools []PromptDebugTool `json:"tools"`
+	// HistoryMessages counts the session's stored messages;
+	// WindowMessages counts how many of them the model would see after
+	// windowing.
+	HistoryMessages int `json:"history_messages"`
+	WindowMessages  int `json:"window_messages"`
+	// ContextTokenBudget is the configured per-invocation token cap;
+	// zero means unlimited.
+	ContextTokenBudget int `json:"context_token_budget"`
+	// EstimatedPromptTokens is the tokenizer's estimate for the window;
+	// zero when no tokenizer is configured.
+	EstimatedPromptTokens int `json:"estimated_prompt_tokens,omitempty"`
+}
+
+// PromptDebugTool is one tool definition as the provider would see it.
+type PromptDebugTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// DebugPrompt reports the model input the next turn of a session would
+// use. The session is left untouched: nothing is appended, emitted or
+// persisted.
+func (s *AIServer) DebugPrompt(sessionId, userId string) (*PromptDebug, error) {
+	session, err := s.sessionManager.Get(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mirror runModelTurn: mode-filtered tools plus the per-session
+	// explain tool, adapted to the provider's capabilities.
+	tools := s.registry.ListForMode(session.Mode)
+	tools = append(tools, &explainActionsTool{session: session})
+	definitions := provider.AdaptTools(tool.Definitions(tools), s.capabilities)
+	debugTools := make([]PromptDebugTool, 0, len(definitions))
+	for _, def := range definitions {
+		debugTools = append(debugTools, PromptDebugTool{
+			Name:        def.Name,
+			Description: def.Description,
+			InputSchema: def.InputSchema,
+		})
+	}
+
+	history := session.Messages()
+	messages := providerWindow(history)
+	budget := s.currentContextTokens()
+	if budget > 0 {
+		if window, dropped := tokenWindow(messages, s.tokenizer, budget); dropped > 0 {
+			messages = window
+		}
+	}
+	estimated := 0
+	if s.tokenizer != nil {
+		estimated = tokenizer.CountMessages(s.tokenizer, messages) + s.tokenizer.CountText(buildSystemPrompt(session))
+	}
+
+	return &PromptDebug{
+		SessionID:             session.ID,
+		Model:                 s.model.Name(),
+		Mode:                  string(session.Mode),
+		SystemPrompt:          buildSystemPrompt(session),
+		Tools:                 debugTools,
+		HistoryMessages:       len(history),
+		WindowMessages:        len(messages),
+		ContextTokenBudget:    budget,
+		EstimatedPromptTokens: estimated,
+	}, nil
+}
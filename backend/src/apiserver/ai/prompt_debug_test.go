@@ -0,0 +1,82 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func debugPromptFixture(t *testing.T) (*AIServer, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	registry := tool.NewRegistry()
+	require.NoError(t, registry.Register(&stubValidatedTool{}))
+	server := &AIServer{
+		sessionManager: m,
+		registry:       registry,
+		model:          &summaryStubModel{},
+		tokenizer:      tokenizer.ForProvider(""),
+		contextTokens:  5000,
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAgent, "")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "why did run r1 fail?"})
+	return server, session
+}
+
+func TestDebugPrompt_ReportsTheNextTurnInput(t *testing.T) {
+	server, session := debugPromptFixture(t)
+
+	debug, err := server.DebugPrompt(session.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, debug.SessionID)
+	assert.Equal(t, "stub", debug.Model)
+	assert.Equal(t, string(tool.ModeAgent), debug.Mode)
+	assert.Equal(t, buildSystemPrompt(session), debug.SystemPrompt)
+	assert.Equal(t, 1, debug.HistoryMessages)
+	assert.Equal(t, 1, debug.WindowMessages)
+	assert.Equal(t, 5000, debug.ContextTokenBudget)
+	assert.True(t, debug.EstimatedPromptTokens > 0)
+
+	names := make([]string, 0, len(debug.Tools))
+	for _, def := range debug.Tools {
+		names = append(names, def.Name)
+		assert.NotNil(t, def.InputSchema)
+	}
+	// The mode-filtered registry plus the per-session explain tool.
+	assert.Contains(t, names, "stub_mutate")
+	assert.Contains(t, names, "explain_last_actions")
+}
+
+func TestDebugPrompt_LeavesTheSessionUntouched(t *testing.T) {
+	server, session := debugPromptFixture(t)
+
+	_, err := server.DebugPrompt(session.ID, "alice")
+	require.NoError(t, err)
+	assert.Len(t, session.Messages(), 1)
+}
+
+func TestDebugPrompt_RejectsOtherUsers(t *testing.T) {
+	server, session := debugPromptFixture(t)
+
+	_, err := server.DebugPrompt(session.ID, "mallory")
+	assert.Error(t, err)
+}
@@ -0,0 +1,442 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	anthropicAPIVersion     = "2023-06-01"
+	defaultAnthropicModel   = "claude-sonnet-4-20250514"
+	defaultMaxTokens        = 4096
+	// defaultMaxRetries bounds the retries of a request rejected before
+	// its stream started, e.g. with 429 or 529.
+	defaultMaxRetries = 3
+	// retryBaseDelay and retryMaxDelay shape the exponential backoff used
+	// when the response carries no Retry-After header.
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// AnthropicProvider implements ChatModel against the Anthropic Messages
+// API using server-sent-event streaming. It is implemented with plain
+// net/http to avoid pulling a vendor SDK into the apiserver.
+type AnthropicProvider struct {
+	// keys rotates requests across the configured API keys; a single-key
+	// configuration is a ring of one.
+	keys    *keyRing
+	baseURL string
+	// maxRetries bounds pre-stream retries of rate-limited or overloaded
+	// requests.
+	maxRetries int
+	// mu guards model, which a configuration reload may swap at runtime.
+	mu         sync.RWMutex
+	model      string
+	httpClient *http.Client
+}
+
+// NewAnthropicProvider creates a provider from the given options. At
+// least one API key is required; model and base URL fall back to
+// defaults.
+func NewAnthropicProvider(opts *Options) (*AnthropicProvider, error) {
+	if opts == nil {
+		return nil, util.NewInvalidInputError("an API key is required for the anthropic provider")
+	}
+	keys := newKeyRing(opts.Keys)
+	if keys == nil {
+		keys = singleKeyRing(opts.APIKey)
+	}
+	if keys == nil {
+		return nil, util.NewInvalidInputError("an API key is required for the anthropic provider")
+	}
+	model := opts.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	} else if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	return &AnthropicProvider{
+		keys:       keys,
+		model:      model,
+		baseURL:    baseURL,
+		maxRetries: maxRetries,
+		// Streaming responses stay open for the whole model turn, so rely
+		// on the request context rather than a client-level timeout.
+		httpClient: &http.Client{Timeout: 0},
+	}, nil
+}
+
+func (p *AnthropicProvider) Name() string {
+	return "anthropic"
+}
+
+// Wire types for the Anthropic Messages API. Only the fields the
+// assistant needs are modeled.
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+	Stream    bool               `json:"stream"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type      string                 `json:"type"`
+	Text      string                 `json:"text,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name,omitempty"`
+	Input     json.RawMessage        `json:"input,omitempty"`
+	ToolUseID string                 `json:"tool_use_id,omitempty"`
+	Content   string                 `json:"content,omitempty"`
+	IsError   bool                   `json:"is_error,omitempty"`
+	Extra     map[string]interface{} `json:"-"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Index int    `json:"index"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// StreamChat sends the conversation to the Messages API and relays
+// streamed events to cb. Requests rejected before the stream starts with
+// a retryable status (429 rate limit, 529 overloaded, 5xx) are retried
+// with exponential backoff, honoring a Retry-After header when present;
+// each scheduled retry is surfaced to cb as a retrying event.
+func (p *AnthropicProvider) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	body, err := json.Marshal(p.buildRequest(req))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to marshal the anthropic request")
+	}
+
+	start := time.Now()
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		apiKey := p.keys.pick(time.Now())
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+		if err != nil {
+			return util.NewInternalServerError(err, "Failed to create the anthropic request")
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("X-Api-Key", apiKey)
+		httpReq.Header.Set("Anthropic-Version", anthropicAPIVersion)
+		httpReq.Header.Set("Accept", "text/event-stream")
+
+		resp, err = p.httpClient.Do(httpReq)
+		if err != nil {
+			return util.NewUnavailableServerError(err, "Failed to reach the anthropic API")
+		}
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		retryAfter := resp.Header.Get("Retry-After")
+		resp.Body.Close()
+		if attempt >= p.maxRetries || !retryableStatus(resp.StatusCode) {
+			return util.NewUnavailableServerError(
+				fmt.Errorf("anthropic API returned status %v: %s", resp.StatusCode, errBody),
+				"The model provider rejected the request (HTTP %v)", resp.StatusCode)
+		}
+		delay := retryDelay(attempt, retryAfter)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			// The limit is per key (typically an org-level limit), so the
+			// key cools down for the delay while the rotation moves on. With
+			// another key free the retry goes out immediately.
+			p.keys.markLimited(apiKey, time.Now().Add(delay))
+			if p.keys.available(time.Now()) {
+				glog.Warningf("Anthropic API rate limited key %v, rotating to another configured key",
+					maskKey(apiKey))
+				delay = 0
+			}
+		}
+		glog.Warningf("Anthropic API returned status %v, retrying in %v (attempt %v of %v)",
+			resp.StatusCode, delay, attempt+1, p.maxRetries)
+		if err := cb(StreamEvent{Type: StreamEventRetrying, Retry: &Retry{
+			Attempt:     attempt + 1,
+			MaxAttempts: p.maxRetries,
+			Delay:       delay,
+		}}); err != nil {
+			return err
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return util.Wrap(ctx.Err(), "The chat request ended while waiting to retry")
+		}
+	}
+	defer resp.Body.Close()
+
+	usage, err := p.consumeStream(resp.Body, cb)
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("Anthropic call finished in %v, input tokens: %v, output tokens: %v",
+		time.Since(start), usage.InputTokens, usage.OutputTokens)
+	if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+		return err
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
+
+// retryableStatus reports whether a rejection may clear up on its own:
+// the request has not streamed anything yet, so repeating it is safe.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryDelay computes the wait before the given 0-based attempt is
+// retried: the server-provided Retry-After when parseable, otherwise
+// capped exponential backoff.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			if until := time.Until(at); until > 0 {
+				return until
+			}
+			return 0
+		}
+	}
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
+
+// SetModel switches the served model, e.g. after a configuration
+// reload. In-flight requests keep the model they started with.
+func (p *AnthropicProvider) SetModel(model string) {
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.model = model
+}
+
+func (p *AnthropicProvider) currentModel() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model
+}
+
+func (p *AnthropicProvider) buildRequest(req *ChatRequest) *anthropicRequest {
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+	out := &anthropicRequest{
+		Model:     requestModel(req, p.currentModel()),
+		MaxTokens: maxTokens,
+		System:    req.System,
+		Stream:    true,
+	}
+	for _, t := range req.Tools {
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		out.Tools = append(out.Tools, anthropicTool{
+			Name: t.Name,
+			// The Anthropic tool format has no structured hint fields, so
+			// the behavior hints ride along in the description.
+			Description: t.DescriptionWithHints(),
+			InputSchema: schema,
+		})
+	}
+	for _, m := range req.Messages {
+		out.Messages = append(out.Messages, toAnthropicMessage(m))
+	}
+	return out
+}
+
+func toAnthropicMessage(m Message) anthropicMessage {
+	switch m.Role {
+	case RoleAssistant:
+		var blocks []anthropicContentBlock
+		if m.Content != "" {
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
+		}
+		for _, tc := range m.ToolCalls {
+			input := json.RawMessage(tc.ArgumentsJSON)
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+			blocks = append(blocks, anthropicContentBlock{
+				Type:  "tool_use",
+				ID:    tc.ID,
+				Name:  tc.Name,
+				Input: input,
+			})
+		}
+		return anthropicMessage{Role: "assistant", Content: blocks}
+	case RoleTool:
+		return anthropicMessage{Role: "user", Content: []anthropicContentBlock{{
+			Type:      "tool_result",
+			ToolUseID: m.ToolCallID,
+			Content:   m.Content,
+		}}}
+	default:
+		return anthropicMessage{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: m.Content}}}
+	}
+}
+
+// consumeStream reads the SSE body, invoking cb for text deltas and
+// completed tool calls, and returns the reported token usage.
+func (p *AnthropicProvider) consumeStream(body io.Reader, cb StreamCallback) (*Usage, error) {
+	state := newAnthropicStreamState()
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+		if err := state.handlePayload([]byte(payload), cb); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, util.NewUnavailableServerError(err, "The model provider stream ended unexpectedly")
+	}
+	return &state.usage, nil
+}
+
+// anthropicStreamState assembles Messages API stream events into stream
+// callbacks. It is shared with the bedrock provider, which delivers the
+// same events through AWS event-stream framing instead of SSE.
+type anthropicStreamState struct {
+	usage Usage
+	// Tool call arguments arrive as partial JSON fragments per content
+	// block; they are assembled here and emitted on content_block_stop.
+	pendingCalls map[int]*ToolCall
+	pendingArgs  map[int]*strings.Builder
+}
+
+func newAnthropicStreamState() *anthropicStreamState {
+	return &anthropicStreamState{
+		pendingCalls: map[int]*ToolCall{},
+		pendingArgs:  map[int]*strings.Builder{},
+	}
+}
+
+// handlePayload decodes one JSON stream event and relays it to cb.
+func (st *anthropicStreamState) handlePayload(payload []byte, cb StreamCallback) error {
+	var event anthropicStreamEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return util.NewInternalServerError(err, "Failed to decode an anthropic stream event")
+	}
+	switch event.Type {
+	case "message_start":
+		st.usage.InputTokens += event.Message.Usage.InputTokens
+		st.usage.OutputTokens += event.Message.Usage.OutputTokens
+	case "content_block_start":
+		if event.ContentBlock.Type == "tool_use" {
+			st.pendingCalls[event.Index] = &ToolCall{ID: event.ContentBlock.ID, Name: event.ContentBlock.Name}
+			st.pendingArgs[event.Index] = &strings.Builder{}
+		}
+	case "content_block_delta":
+		switch event.Delta.Type {
+		case "text_delta":
+			return cb(StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: event.Delta.Text}})
+		case "input_json_delta":
+			if b, ok := st.pendingArgs[event.Index]; ok {
+				b.WriteString(event.Delta.PartialJSON)
+			}
+		}
+	case "content_block_stop":
+		if call, ok := st.pendingCalls[event.Index]; ok {
+			call.ArgumentsJSON = st.pendingArgs[event.Index].String()
+			if call.ArgumentsJSON == "" {
+				call.ArgumentsJSON = "{}"
+			}
+			delete(st.pendingCalls, event.Index)
+			delete(st.pendingArgs, event.Index)
+			return cb(StreamEvent{Type: StreamEventToolCall, ToolCall: call})
+		}
+	case "message_delta":
+		st.usage.OutputTokens += event.Usage.OutputTokens
+	case "error":
+		return util.NewUnavailableServerError(
+			fmt.Errorf("anthropic stream error %v: %v", event.Error.Type, event.Error.Message),
+			"The model provider reported a streaming error")
+	}
+	return nil
+}
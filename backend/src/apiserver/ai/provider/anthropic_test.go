@@ -0,0 +1,195 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// anthropicTestStream writes a minimal successful Messages API stream.
+func anthropicTestStream(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprint(w, "data: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":10,\"output_tokens\":1}}}\n\n")
+	fmt.Fprint(w, "data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello\"}}\n\n")
+	fmt.Fprint(w, "data: {\"type\":\"message_delta\",\"usage\":{\"output_tokens\":5}}\n\n")
+}
+
+func TestAnthropicStreamChat_RetriesRejectedRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "key-a", r.Header.Get("X-Api-Key"))
+		assert.Equal(t, anthropicAPIVersion, r.Header.Get("Anthropic-Version"))
+		if requests <= 2 {
+			w.Header().Set("Retry-After", "0")
+			status := http.StatusTooManyRequests
+			if requests == 2 {
+				status = 529 // overloaded
+			}
+			w.WriteHeader(status)
+			return
+		}
+		anthropicTestStream(w)
+	}))
+	defer server.Close()
+
+	p, err := NewAnthropicProvider(&Options{APIKey: "key-a", BaseURL: server.URL, MaxRetries: 3})
+	require.NoError(t, err)
+
+	var events []StreamEvent
+	err = p.StreamChat(context.Background(), &ChatRequest{Messages: []Message{{Role: RoleUser, Content: "hi"}}},
+		func(event StreamEvent) error {
+			events = append(events, event)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	// Two retrying events, then the streamed turn.
+	require.Len(t, events, 5)
+	assert.Equal(t, StreamEventRetrying, events[0].Type)
+	assert.Equal(t, &Retry{Attempt: 1, MaxAttempts: 3, Delay: 0}, events[0].Retry)
+	assert.Equal(t, StreamEventRetrying, events[1].Type)
+	assert.Equal(t, StreamEventTextDelta, events[2].Type)
+	assert.Equal(t, "Hello", events[2].Delta.Text)
+	assert.Equal(t, StreamEventUsage, events[3].Type)
+	assert.Equal(t, &Usage{InputTokens: 10, OutputTokens: 6}, events[3].Usage)
+	assert.Equal(t, StreamEventDone, events[4].Type)
+}
+
+func TestAnthropicStreamChat_RotatesKeysOnARateLimit(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("X-Api-Key"))
+		if len(keys) == 1 {
+			// A long Retry-After must not delay the retry: another key is
+			// free, so the rotation moves on immediately.
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		anthropicTestStream(w)
+	}))
+	defer server.Close()
+
+	p, err := NewAnthropicProvider(&Options{
+		Keys:    []WeightedKey{{Key: "key-a"}, {Key: "key-b"}},
+		BaseURL: server.URL,
+	})
+	require.NoError(t, err)
+
+	var retries []*Retry
+	start := time.Now()
+	err = p.StreamChat(context.Background(), &ChatRequest{Messages: []Message{{Role: RoleUser, Content: "hi"}}},
+		func(event StreamEvent) error {
+			if event.Type == StreamEventRetrying {
+				retries = append(retries, event.Retry)
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-a", "key-b"}, keys)
+	require.Len(t, retries, 1)
+	assert.Equal(t, time.Duration(0), retries[0].Delay)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestAnthropicStreamChat_GivesUpAfterMaxRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(529)
+	}))
+	defer server.Close()
+
+	p, err := NewAnthropicProvider(&Options{APIKey: "key-a", BaseURL: server.URL, MaxRetries: 2})
+	require.NoError(t, err)
+
+	err = p.StreamChat(context.Background(), &ChatRequest{}, func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "HTTP 529")
+	assert.Equal(t, 3, requests) // the initial attempt plus two retries
+}
+
+func TestAnthropicStreamChat_DoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p, err := NewAnthropicProvider(&Options{APIKey: "key-a", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	err = p.StreamChat(context.Background(), &ChatRequest{}, func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "HTTP 400")
+	assert.Equal(t, 1, requests)
+}
+
+func TestRetryDelay(t *testing.T) {
+	// Retry-After in seconds wins over the backoff.
+	assert.Equal(t, 7*time.Second, retryDelay(0, "7"))
+	// An HTTP-date in the past means retry now.
+	assert.Equal(t, time.Duration(0), retryDelay(0, time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)))
+	// Without a header the backoff doubles per attempt and caps out.
+	assert.Equal(t, retryBaseDelay, retryDelay(0, ""))
+	assert.Equal(t, 4*retryBaseDelay, retryDelay(2, ""))
+	assert.Equal(t, retryMaxDelay, retryDelay(10, "garbage"))
+}
+
+func TestAnthropicStreamState_AssemblesInputJSONDeltas(t *testing.T) {
+	state := newAnthropicStreamState()
+	var events []StreamEvent
+	cb := func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	}
+
+	payloads := []string{
+		`{"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":2}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"list_runs"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"page_"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"size\":5}"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_2","name":"list_experiments"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","usage":{"output_tokens":9}}`,
+	}
+	for _, payload := range payloads {
+		require.NoError(t, state.handlePayload([]byte(payload), cb))
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, &ToolCall{ID: "toolu_1", Name: "list_runs", ArgumentsJSON: `{"page_size":5}`}, events[0].ToolCall)
+	// A call without input deltas gets an empty-object argument.
+	assert.Equal(t, &ToolCall{ID: "toolu_2", Name: "list_experiments", ArgumentsJSON: "{}"}, events[1].ToolCall)
+	assert.Equal(t, Usage{InputTokens: 20, OutputTokens: 11}, state.usage)
+}
+
+func TestAnthropicStreamState_SurfacesStreamErrors(t *testing.T) {
+	state := newAnthropicStreamState()
+	err := state.handlePayload([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"busy"}}`),
+		func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "overloaded_error")
+}
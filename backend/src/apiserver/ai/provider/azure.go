@@ -0,0 +1,161 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// azureOpenAIDefaultAPIVersion is the api-version sent when the
+// endpoint URL does not pin one. Azure retires old versions on a
+// schedule, so deployments needing a specific one append
+// ?api-version=... to the configured base URL.
+const azureOpenAIDefaultAPIVersion = "2024-10-21"
+
+// AzureOpenAIProvider implements ChatModel against Azure OpenAI
+// deployments, for enterprises contractually limited to Azure-hosted
+// models. The wire format is the OpenAI chat completions API shared
+// with the local provider; what differs is the addressing — requests
+// target a named deployment rather than a model, authenticated with an
+// api-key header and versioned with an api-version query parameter.
+type AzureOpenAIProvider struct {
+	apiKey     string
+	endpoint   string
+	apiVersion string
+	// mu guards deployment, which a configuration reload may swap at
+	// runtime.
+	mu         sync.RWMutex
+	deployment string
+	httpClient *http.Client
+}
+
+// NewAzureOpenAIProvider creates a provider from the given options. The
+// base URL is the Azure resource endpoint, e.g.
+// https://my-resource.openai.azure.com, optionally carrying an
+// api-version query parameter; the model option names the deployment.
+func NewAzureOpenAIProvider(opts *Options) (*AzureOpenAIProvider, error) {
+	if opts == nil || opts.BaseURL == "" {
+		return nil, util.NewInvalidInputError("a base URL is required for the azure-openai provider")
+	}
+	if opts.Model == "" {
+		return nil, util.NewInvalidInputError("a deployment name is required for the azure-openai provider; set it as the model")
+	}
+	if opts.APIKey == "" {
+		return nil, util.NewInvalidInputError("an API key is required for the azure-openai provider")
+	}
+	endpoint, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, util.NewInvalidInputError("Invalid azure-openai base URL %q: %v", opts.BaseURL, err)
+	}
+	apiVersion := endpoint.Query().Get("api-version")
+	if apiVersion == "" {
+		apiVersion = azureOpenAIDefaultAPIVersion
+	}
+	endpoint.RawQuery = ""
+	return &AzureOpenAIProvider{
+		apiKey:     opts.APIKey,
+		endpoint:   strings.TrimSuffix(endpoint.String(), "/"),
+		apiVersion: apiVersion,
+		deployment: opts.Model,
+		// Streaming responses stay open for the whole model turn, so rely
+		// on the request context rather than a client-level timeout.
+		httpClient: &http.Client{Timeout: 0},
+	}, nil
+}
+
+func (p *AzureOpenAIProvider) Name() string {
+	return "azure-openai"
+}
+
+// SetModel switches the target deployment, e.g. after a configuration
+// reload. In-flight requests keep the deployment they started with.
+func (p *AzureOpenAIProvider) SetModel(model string) {
+	if model == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deployment = model
+}
+
+func (p *AzureOpenAIProvider) currentDeployment() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.deployment
+}
+
+// chatCompletionsURL builds the deployment-scoped endpoint URL.
+func (p *AzureOpenAIProvider) chatCompletionsURL(deployment string) string {
+	return fmt.Sprintf("%v/openai/deployments/%v/chat/completions?api-version=%v",
+		p.endpoint, url.PathEscape(deployment), url.QueryEscape(p.apiVersion))
+}
+
+// StreamChat sends the conversation to the deployment's chat
+// completions endpoint and relays streamed events to cb.
+func (p *AzureOpenAIProvider) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	deployment := requestModel(req, p.currentDeployment())
+	// The deployment in the URL decides what serves the request; the
+	// model field in the body is ignored by Azure, so it is left to the
+	// shared request builder for consistency.
+	body, err := json.Marshal(buildOpenAIRequest(req, p.currentDeployment()))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to marshal the azure-openai request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.chatCompletionsURL(deployment), bytes.NewReader(body))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create the azure-openai request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("api-key", p.apiKey)
+
+	start := time.Now()
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return util.NewUnavailableServerError(err, "Failed to reach the Azure OpenAI endpoint")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return util.NewUnavailableServerError(
+			fmt.Errorf("azure-openai returned status %v: %s", resp.StatusCode, errBody),
+			"The model provider rejected the request (HTTP %v)", resp.StatusCode)
+	}
+
+	usage, err := consumeOpenAIStream(resp.Body, cb)
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("Azure OpenAI call to deployment %v finished in %v, input tokens: %v, output tokens: %v",
+		deployment, time.Since(start), usage.InputTokens, usage.OutputTokens)
+	if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+		return err
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAzureOpenAIProvider_RequiresEndpointDeploymentAndKey(t *testing.T) {
+	_, err := NewAzureOpenAIProvider(&Options{Model: "gpt-4o", APIKey: "k"})
+	assert.ErrorContains(t, err, "base URL")
+	_, err = NewAzureOpenAIProvider(&Options{BaseURL: "https://r.openai.azure.com", APIKey: "k"})
+	assert.ErrorContains(t, err, "deployment name")
+	_, err = NewAzureOpenAIProvider(&Options{BaseURL: "https://r.openai.azure.com", Model: "gpt-4o"})
+	assert.ErrorContains(t, err, "API key")
+}
+
+func TestNewAzureOpenAIProvider_ParsesTheEndpointAndAPIVersion(t *testing.T) {
+	// Without an api-version in the URL the default applies; a trailing
+	// slash is trimmed.
+	p, err := NewAzureOpenAIProvider(&Options{BaseURL: "https://r.openai.azure.com/", Model: "gpt-4o", APIKey: "k"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://r.openai.azure.com", p.endpoint)
+	assert.Equal(t, azureOpenAIDefaultAPIVersion, p.apiVersion)
+
+	// An api-version query parameter pins the version and is stripped
+	// from the endpoint.
+	p, err = NewAzureOpenAIProvider(&Options{
+		BaseURL: "https://r.openai.azure.com?api-version=2024-06-01",
+		Model:   "gpt-4o",
+		APIKey:  "k",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://r.openai.azure.com", p.endpoint)
+	assert.Equal(t, "2024-06-01", p.apiVersion)
+	assert.Equal(t,
+		"https://r.openai.azure.com/openai/deployments/gpt-4o/chat/completions?api-version=2024-06-01",
+		p.chatCompletionsURL("gpt-4o"))
+
+	_, err = NewAzureOpenAIProvider(&Options{BaseURL: "http://bad url", Model: "gpt-4o", APIKey: "k"})
+	assert.ErrorContains(t, err, "Invalid azure-openai base URL")
+}
+
+func TestAzureStreamChat_EndToEnd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openai/deployments/my-gpt-4o/chat/completions", r.URL.Path)
+		assert.Equal(t, "2024-06-01", r.URL.Query().Get("api-version"))
+		// Azure authenticates with an api-key header, not a bearer token.
+		assert.Equal(t, "secret", r.Header.Get("api-key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"name\":\"list_runs\",\"arguments\":\"{}\"}}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":9,\"completion_tokens\":2}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p, err := NewAzureOpenAIProvider(&Options{
+		BaseURL: server.URL + "?api-version=2024-06-01",
+		Model:   "my-gpt-4o",
+		APIKey:  "secret",
+	})
+	require.NoError(t, err)
+
+	var events []StreamEvent
+	err = p.StreamChat(context.Background(), &ChatRequest{Messages: []Message{{Role: RoleUser, Content: "hi"}}},
+		func(event StreamEvent) error {
+			events = append(events, event)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.Equal(t, "Hi", events[0].Delta.Text)
+	assert.Equal(t, &ToolCall{ID: "call-1", Name: "list_runs", ArgumentsJSON: "{}"}, events[1].ToolCall)
+	assert.Equal(t, &Usage{InputTokens: 9, OutputTokens: 2}, events[2].Usage)
+	assert.Equal(t, StreamEventDone, events[3].Type)
+}
+
+func TestAzureStreamChat_RejectionsCarryTheStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p, err := NewAzureOpenAIProvider(&Options{BaseURL: server.URL, Model: "my-gpt-4o", APIKey: "wrong"})
+	require.NoError(t, err)
+
+	err = p.StreamChat(context.Background(), &ChatRequest{}, func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "HTTP 401")
+}
@@ -0,0 +1,471 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	defaultBedrockModel = "anthropic.claude-sonnet-4-20250514-v1:0"
+	// bedrockAnthropicVersion is the fixed version tag Bedrock expects in
+	// Anthropic-family request bodies instead of an API version header.
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+	bedrockService          = "bedrock"
+	// bedrockMaxEventBytes bounds a single event-stream frame; larger
+	// frames indicate a corrupt stream.
+	bedrockMaxEventBytes = 16 * 1024 * 1024
+)
+
+// BedrockProvider implements ChatModel against the AWS Bedrock
+// InvokeModelWithResponseStream API using plain net/http: it signs
+// requests with SigV4 and decodes the binary event-stream framing
+// itself, so no AWS SDK is pulled into the apiserver. Anthropic-family
+// models get full tool calling; Meta Llama models stream text only.
+type BedrockProvider struct {
+	credentials awsCredentials
+	baseURL     string
+	// mu guards model, which a configuration reload may swap at runtime.
+	mu         sync.RWMutex
+	model      string
+	httpClient *http.Client
+}
+
+// awsCredentials is the credential document the bedrock provider reads
+// from CredentialsJSON. Region is required because it is part of the
+// SigV4 signing scope, not just the endpoint.
+type awsCredentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	// SessionToken is set for temporary credentials, e.g. from STS.
+	SessionToken string `json:"session_token,omitempty"`
+	Region       string `json:"region"`
+}
+
+// NewBedrockProvider creates a provider from the given options. The
+// credentials JSON (access key, secret and region) is required; model
+// and base URL fall back to defaults.
+func NewBedrockProvider(opts *Options) (*BedrockProvider, error) {
+	if opts == nil || opts.CredentialsJSON == "" {
+		return nil, util.NewInvalidInputError("AWS credentials are required for the bedrock provider")
+	}
+	credentials := awsCredentials{}
+	if err := json.Unmarshal([]byte(opts.CredentialsJSON), &credentials); err != nil {
+		return nil, util.NewInvalidInputError("Invalid AWS credentials: %v", err)
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" || credentials.Region == "" {
+		return nil, util.NewInvalidInputError("The AWS credentials need access_key_id, secret_access_key and region")
+	}
+	model := opts.Model
+	if model == "" {
+		model = defaultBedrockModel
+	}
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://bedrock-runtime.%v.amazonaws.com", credentials.Region)
+	}
+	return &BedrockProvider{
+		credentials: credentials,
+		model:       model,
+		baseURL:     baseURL,
+		// Streaming responses stay open for the whole model turn, so rely
+		// on the request context rather than a client-level timeout.
+		httpClient: &http.Client{Timeout: 0},
+	}, nil
+}
+
+func (p *BedrockProvider) Name() string {
+	return "bedrock"
+}
+
+// SetModel switches the served model, e.g. after a configuration
+// reload. In-flight requests keep the model they started with.
+func (p *BedrockProvider) SetModel(model string) {
+	if model == "" {
+		model = defaultBedrockModel
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.model = model
+}
+
+func (p *BedrockProvider) currentModel() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model
+}
+
+// bedrockAnthropicRequest is the Anthropic-family request body. It
+// reuses the Messages API wire types; Bedrock carries the model in the
+// URL and the API version in the body instead of headers.
+type bedrockAnthropicRequest struct {
+	AnthropicVersion string             `json:"anthropic_version"`
+	MaxTokens        int                `json:"max_tokens"`
+	System           string             `json:"system,omitempty"`
+	Messages         []anthropicMessage `json:"messages"`
+	Tools            []anthropicTool    `json:"tools,omitempty"`
+}
+
+// bedrockLlamaRequest is the Meta-family request body.
+type bedrockLlamaRequest struct {
+	Prompt    string `json:"prompt"`
+	MaxGenLen int    `json:"max_gen_len,omitempty"`
+}
+
+// bedrockLlamaChunk is one Meta-family stream chunk.
+type bedrockLlamaChunk struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+}
+
+// StreamChat sends the conversation to InvokeModelWithResponseStream and
+// relays decoded chunks to cb.
+func (p *BedrockProvider) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	model := requestModel(req, p.currentModel())
+	body, err := p.buildBody(model, req)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%v/model/%v/invoke-with-response-stream", p.baseURL, url.PathEscape(model))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create the bedrock request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	p.sign(httpReq, body, time.Now().UTC())
+
+	start := time.Now()
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return util.NewUnavailableServerError(err, "Failed to reach the bedrock API")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return util.NewUnavailableServerError(
+			fmt.Errorf("bedrock API returned status %v: %s", resp.StatusCode, errBody),
+			"The model provider rejected the request (HTTP %v)", resp.StatusCode)
+	}
+
+	usage, err := p.consumeEventStream(resp.Body, model, cb)
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("Bedrock call finished in %v, input tokens: %v, output tokens: %v",
+		time.Since(start), usage.InputTokens, usage.OutputTokens)
+	if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+		return err
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
+
+// buildBody renders the model-family-specific request body.
+func (p *BedrockProvider) buildBody(model string, req *ChatRequest) ([]byte, error) {
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+	var payload interface{}
+	if isLlamaModel(model) {
+		// The Meta invoke API takes a rendered prompt and has no tool
+		// support; tool definitions are not forwarded.
+		if len(req.Tools) > 0 {
+			glog.V(2).Infof("Dropping %v tool definition(s): bedrock model %v does not support tool calling", len(req.Tools), model)
+		}
+		payload = &bedrockLlamaRequest{Prompt: llamaPrompt(req), MaxGenLen: maxTokens}
+	} else {
+		out := &bedrockAnthropicRequest{
+			AnthropicVersion: bedrockAnthropicVersion,
+			MaxTokens:        maxTokens,
+			System:           req.System,
+		}
+		for _, t := range req.Tools {
+			schema := t.InputSchema
+			if schema == nil {
+				schema = map[string]interface{}{"type": "object"}
+			}
+			out.Tools = append(out.Tools, anthropicTool{
+				Name:        t.Name,
+				Description: t.DescriptionWithHints(),
+				InputSchema: schema,
+			})
+		}
+		for _, m := range req.Messages {
+			out.Messages = append(out.Messages, toAnthropicMessage(m))
+		}
+		payload = out
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to marshal the bedrock request")
+	}
+	return body, nil
+}
+
+func isLlamaModel(model string) bool {
+	// Model IDs may carry a region prefix, e.g. "us.meta.llama3-70b-...".
+	return strings.Contains(model, "meta.llama")
+}
+
+// llamaPrompt renders the conversation in the Llama 3 chat template.
+// Tool results are folded into user turns since the template has no
+// native role for them.
+func llamaPrompt(req *ChatRequest) string {
+	var b strings.Builder
+	b.WriteString("<|begin_of_text|>")
+	writeTurn := func(role, content string) {
+		fmt.Fprintf(&b, "<|start_header_id|>%v<|end_header_id|>\n\n%v<|eot_id|>", role, content)
+	}
+	if req.System != "" {
+		writeTurn("system", req.System)
+	}
+	for _, m := range req.Messages {
+		switch m.Role {
+		case RoleAssistant:
+			writeTurn("assistant", m.Content)
+		case RoleTool:
+			writeTurn("user", "Tool result: "+m.Content)
+		default:
+			writeTurn("user", m.Content)
+		}
+	}
+	b.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+	return b.String()
+}
+
+// consumeEventStream decodes the binary event stream, relaying each
+// chunk to cb according to the model family.
+func (p *BedrockProvider) consumeEventStream(body io.Reader, model string, cb StreamCallback) (*Usage, error) {
+	llama := isLlamaModel(model)
+	usage := &Usage{}
+	state := newAnthropicStreamState()
+	reader := bufio.NewReader(body)
+	for {
+		eventType, messageType, payload, err := readEventStreamMessage(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, util.NewUnavailableServerError(err, "The model provider stream ended unexpectedly")
+		}
+		if messageType == "exception" {
+			return nil, util.NewUnavailableServerError(
+				fmt.Errorf("bedrock stream exception %v: %s", eventType, payload),
+				"The model provider reported a streaming error")
+		}
+		if eventType != "chunk" {
+			continue
+		}
+		// Chunk payloads wrap the model's own JSON in a base64 bytes field.
+		var chunk struct {
+			Bytes []byte `json:"bytes"`
+		}
+		if err := json.Unmarshal(payload, &chunk); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to decode a bedrock stream chunk")
+		}
+		if llama {
+			var piece bedrockLlamaChunk
+			if err := json.Unmarshal(chunk.Bytes, &piece); err != nil {
+				return nil, util.NewInternalServerError(err, "Failed to decode a bedrock stream chunk")
+			}
+			if piece.PromptTokenCount > 0 {
+				usage.InputTokens = piece.PromptTokenCount
+			}
+			if piece.GenerationTokenCount > 0 {
+				usage.OutputTokens = piece.GenerationTokenCount
+			}
+			if piece.Generation != "" {
+				if err := cb(StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: piece.Generation}}); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := state.handlePayload(chunk.Bytes, cb); err != nil {
+			return nil, err
+		}
+	}
+	if !llama {
+		usage = &state.usage
+	}
+	return usage, nil
+}
+
+// readEventStreamMessage reads one message of the
+// application/vnd.amazon.eventstream framing and returns its event type,
+// message type and payload.
+func readEventStreamMessage(r *bufio.Reader) (string, string, []byte, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return "", "", nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	preludeCRC := binary.BigEndian.Uint32(prelude[8:12])
+	if crc32.ChecksumIEEE(prelude[0:8]) != preludeCRC {
+		return "", "", nil, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+	if totalLen > bedrockMaxEventBytes || totalLen < 16 || headersLen > totalLen-16 {
+		return "", "", nil, fmt.Errorf("invalid event stream frame of %v bytes", totalLen)
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return "", "", nil, fmt.Errorf("truncated event stream frame: %w", err)
+	}
+	messageCRC := binary.BigEndian.Uint32(rest[len(rest)-4:])
+	if crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, rest[:len(rest)-4]) != messageCRC {
+		return "", "", nil, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(rest[:headersLen])
+	if err != nil {
+		return "", "", nil, err
+	}
+	payload := rest[headersLen : len(rest)-4]
+	return headers[":event-type"], headers[":message-type"], payload, nil
+}
+
+// parseEventStreamHeaders decodes the header block of an event-stream
+// message into a name-to-value map. Non-string values are skipped over
+// but not returned; the stream only uses string headers.
+func parseEventStreamHeaders(block []byte) (map[string]string, error) {
+	headers := map[string]string{}
+	for len(block) > 0 {
+		nameLen := int(block[0])
+		if len(block) < 1+nameLen+1 {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		name := string(block[1 : 1+nameLen])
+		valueType := block[1+nameLen]
+		block = block[1+nameLen+1:]
+
+		// Value sizes by type, per the event stream encoding.
+		var size int
+		switch valueType {
+		case 0, 1: // bool true / false
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // short
+			size = 2
+		case 4: // integer
+			size = 4
+		case 5, 8: // long, timestamp
+			size = 8
+		case 6, 7: // byte buffer, string
+			if len(block) < 2 {
+				return nil, fmt.Errorf("truncated event stream header")
+			}
+			size = 2 + int(binary.BigEndian.Uint16(block[0:2]))
+		case 9: // uuid
+			size = 16
+		default:
+			return nil, fmt.Errorf("unknown event stream header type %v", valueType)
+		}
+		if len(block) < size {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		if valueType == 7 {
+			headers[name] = string(block[2:size])
+		}
+		block = block[size:]
+	}
+	return headers, nil
+}
+
+// sign adds the SigV4 authorization header and its inputs to the
+// request.
+func (p *BedrockProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.credentials.SessionToken)
+	}
+
+	signedHeaderNames := []string{"content-type", "host", "x-amz-date"}
+	if p.credentials.SessionToken != "" {
+		signedHeaderNames = append(signedHeaderNames, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaderNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%v:%v\n", name, value)
+	}
+	signedHeaders := strings.Join(signedHeaderNames, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{dateStamp, p.credentials.Region, bedrockService, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+p.credentials.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, p.credentials.Region)
+	signingKey = hmacSHA256(signingKey, bedrockService)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		p.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
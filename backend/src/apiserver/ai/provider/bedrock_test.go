@@ -0,0 +1,252 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACSHA256_MatchesTheAWSKeyDerivationVector(t *testing.T) {
+	// The signing key derivation example from the AWS SigV4 documentation:
+	// secret wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY, date 20120215,
+	// region us-east-1, service iam.
+	key := hmacSHA256([]byte("AWS4"+"wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"), "20120215")
+	key = hmacSHA256(key, "us-east-1")
+	key = hmacSHA256(key, "iam")
+	key = hmacSHA256(key, "aws4_request")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func testBedrockProvider(t *testing.T, sessionToken string) *BedrockProvider {
+	t.Helper()
+	credentials := fmt.Sprintf(`{
+		"access_key_id": "AKIDEXAMPLE",
+		"secret_access_key": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		"session_token": %q,
+		"region": "us-east-1"
+	}`, sessionToken)
+	p, err := NewBedrockProvider(&Options{CredentialsJSON: credentials})
+	require.NoError(t, err)
+	return p
+}
+
+func TestBedrockSign_PinsTheSigV4Signature(t *testing.T) {
+	p := testBedrockProvider(t, "")
+	body := []byte(`{"prompt": "hello"}`)
+	req, err := http.NewRequest(http.MethodPost,
+		"https://bedrock-runtime.us-east-1.amazonaws.com/model/anthropic.claude-sonnet-4-20250514-v1:0/invoke-with-response-stream",
+		bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	p.sign(req, body, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Empty(t, req.Header.Get("X-Amz-Security-Token"))
+	// Pinned against a known-good signing of these exact inputs; any
+	// change to the canonical request or key derivation shifts it.
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/bedrock/aws4_request, "+
+			"SignedHeaders=content-type;host;x-amz-date, "+
+			"Signature=5e233531a28bc91a0f27447899e304570d4b5e64a307d826e9ff9555d3851e23",
+		req.Header.Get("Authorization"))
+}
+
+func TestBedrockSign_SignsTheSessionToken(t *testing.T) {
+	p := testBedrockProvider(t, "SESSIONTOKENEXAMPLE")
+	body := []byte(`{}`)
+	req, err := http.NewRequest(http.MethodPost,
+		"https://bedrock-runtime.us-east-1.amazonaws.com/model/m/invoke-with-response-stream", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	p.sign(req, body, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "SESSIONTOKENEXAMPLE", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(t, req.Header.Get("Authorization"),
+		"SignedHeaders=content-type;host;x-amz-date;x-amz-security-token")
+}
+
+// encodeEventStreamMessage frames headers and a payload in the
+// application/vnd.amazon.eventstream encoding, string-typed headers
+// only.
+func encodeEventStreamMessage(headers [][2]string, payload []byte) []byte {
+	var headerBlock bytes.Buffer
+	for _, header := range headers {
+		headerBlock.WriteByte(byte(len(header[0])))
+		headerBlock.WriteString(header[0])
+		headerBlock.WriteByte(7) // string
+		binary.Write(&headerBlock, binary.BigEndian, uint16(len(header[1])))
+		headerBlock.WriteString(header[1])
+	}
+
+	totalLen := 12 + headerBlock.Len() + len(payload) + 4
+	var frame bytes.Buffer
+	binary.Write(&frame, binary.BigEndian, uint32(totalLen))
+	binary.Write(&frame, binary.BigEndian, uint32(headerBlock.Len()))
+	binary.Write(&frame, binary.BigEndian, crc32.ChecksumIEEE(frame.Bytes()))
+	frame.Write(headerBlock.Bytes())
+	frame.Write(payload)
+	binary.Write(&frame, binary.BigEndian, crc32.ChecksumIEEE(frame.Bytes()))
+	return frame.Bytes()
+}
+
+func testChunkFrame(eventType string, payload []byte) []byte {
+	return encodeEventStreamMessage([][2]string{
+		{":event-type", eventType},
+		{":message-type", "event"},
+	}, payload)
+}
+
+func TestReadEventStreamMessage_DecodesAValidFrame(t *testing.T) {
+	frame := testChunkFrame("chunk", []byte(`{"bytes":"e30="}`))
+	reader := bufio.NewReader(bytes.NewReader(frame))
+
+	eventType, messageType, payload, err := readEventStreamMessage(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "chunk", eventType)
+	assert.Equal(t, "event", messageType)
+	assert.Equal(t, `{"bytes":"e30="}`, string(payload))
+
+	// A cleanly ended stream reports EOF.
+	_, _, _, err = readEventStreamMessage(reader)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadEventStreamMessage_RejectsACorruptedPrelude(t *testing.T) {
+	frame := testChunkFrame("chunk", []byte(`{}`))
+	frame[9] ^= 0xff // flip a prelude CRC byte
+
+	_, _, _, err := readEventStreamMessage(bufio.NewReader(bytes.NewReader(frame)))
+	assert.ErrorContains(t, err, "prelude checksum")
+}
+
+func TestReadEventStreamMessage_RejectsACorruptedMessage(t *testing.T) {
+	frame := testChunkFrame("chunk", []byte(`{"bytes":"e30="}`))
+	frame[len(frame)-5] ^= 0xff // flip a payload byte, leaving the CRCs intact
+
+	_, _, _, err := readEventStreamMessage(bufio.NewReader(bytes.NewReader(frame)))
+	assert.ErrorContains(t, err, "message checksum")
+}
+
+func TestReadEventStreamMessage_RejectsATruncatedFrame(t *testing.T) {
+	frame := testChunkFrame("chunk", []byte(`{"bytes":"e30="}`))
+
+	_, _, _, err := readEventStreamMessage(bufio.NewReader(bytes.NewReader(frame[:len(frame)-6])))
+	assert.ErrorContains(t, err, "truncated")
+}
+
+func TestReadEventStreamMessage_RejectsAnOversizedFrame(t *testing.T) {
+	frame := testChunkFrame("chunk", []byte(`{}`))
+	// Rewrite the total length beyond the frame bound and fix the prelude
+	// CRC so only the size check can object.
+	binary.BigEndian.PutUint32(frame[0:4], bedrockMaxEventBytes+1)
+	binary.BigEndian.PutUint32(frame[8:12], crc32.ChecksumIEEE(frame[0:8]))
+
+	_, _, _, err := readEventStreamMessage(bufio.NewReader(bytes.NewReader(frame)))
+	assert.ErrorContains(t, err, "invalid event stream frame")
+}
+
+func TestParseEventStreamHeaders_SkipsNonStringValues(t *testing.T) {
+	var block bytes.Buffer
+	// A bool-typed header (type 0, no value bytes)...
+	block.WriteByte(byte(len(":chunked")))
+	block.WriteString(":chunked")
+	block.WriteByte(0)
+	// ...followed by an integer header (type 4, four value bytes)...
+	block.WriteByte(byte(len(":count")))
+	block.WriteString(":count")
+	block.WriteByte(4)
+	binary.Write(&block, binary.BigEndian, uint32(7))
+	// ...must not throw off the string header after them.
+	block.WriteByte(byte(len(":event-type")))
+	block.WriteString(":event-type")
+	block.WriteByte(7)
+	binary.Write(&block, binary.BigEndian, uint16(len("chunk")))
+	block.WriteString("chunk")
+
+	headers, err := parseEventStreamHeaders(block.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{":event-type": "chunk"}, headers)
+}
+
+func TestParseEventStreamHeaders_RejectsTruncatedBlocks(t *testing.T) {
+	block := []byte{5, 'a'} // claims a five-byte name with one byte present
+	_, err := parseEventStreamHeaders(block)
+	assert.ErrorContains(t, err, "truncated")
+}
+
+func TestBedrockConsumeEventStream_DecodesAnthropicChunks(t *testing.T) {
+	wrap := func(event string) []byte {
+		payload, _ := json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString([]byte(event))})
+		// The marshaled form matches the wire shape {"bytes":"..."}.
+		return testChunkFrame("chunk", payload)
+	}
+	var stream bytes.Buffer
+	stream.Write(wrap(`{"type":"message_start","message":{"usage":{"input_tokens":10,"output_tokens":1}}}`))
+	stream.Write(wrap(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}`))
+	stream.Write(wrap(`{"type":"message_delta","usage":{"output_tokens":5}}`))
+
+	p := testBedrockProvider(t, "")
+	var events []StreamEvent
+	usage, err := p.consumeEventStream(&stream, defaultBedrockModel, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Hello", events[0].Delta.Text)
+	assert.Equal(t, &Usage{InputTokens: 10, OutputTokens: 6}, usage)
+}
+
+func TestBedrockConsumeEventStream_SurfacesExceptions(t *testing.T) {
+	frame := encodeEventStreamMessage([][2]string{
+		{":event-type", "throttlingException"},
+		{":message-type", "exception"},
+	}, []byte(`{"message":"slow down"}`))
+
+	p := testBedrockProvider(t, "")
+	_, err := p.consumeEventStream(bytes.NewReader(frame), defaultBedrockModel, func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "throttlingException")
+}
+
+func TestLlamaPrompt_RendersTheChatTemplate(t *testing.T) {
+	prompt := llamaPrompt(&ChatRequest{
+		System: "be brief",
+		Messages: []Message{
+			{Role: RoleUser, Content: "hi"},
+			{Role: RoleAssistant, Content: "hello"},
+			{Role: RoleTool, Content: "42"},
+		},
+	})
+	assert.True(t, strings.HasPrefix(prompt, "<|begin_of_text|><|start_header_id|>system<|end_header_id|>\n\nbe brief<|eot_id|>"))
+	assert.Contains(t, prompt, "<|start_header_id|>user<|end_header_id|>\n\nTool result: 42<|eot_id|>")
+	assert.True(t, strings.HasSuffix(prompt, "<|start_header_id|>assistant<|end_header_id|>\n\n"))
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"github.com/golang/glog"
+)
+
+// Capabilities models the limits of a provider's tool-calling
+// interface. A zero value means the dimension is unlimited. Requests
+// are adapted to these limits up front, instead of surfacing as
+// hard-to-debug 400 responses once the tool set grows.
+type Capabilities struct {
+	// MaxTools caps how many tool definitions one request may carry.
+	MaxTools int
+	// MaxSchemaDepth caps the nesting depth of a tool's input schema.
+	MaxSchemaDepth int
+}
+
+// CapabilityReporter is optionally implemented by chat models that know
+// their backend's limits better than the published per-provider
+// defaults, e.g. a fallback chain reporting its weakest member.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// CapabilitiesFor returns the published limits of a named provider.
+func CapabilitiesFor(name string) Capabilities {
+	switch name {
+	case "local":
+		// Local OpenAI-compatible servers commonly reject large tool
+		// sets and deeply nested schemas outright.
+		return Capabilities{MaxTools: 32, MaxSchemaDepth: 5}
+	case "azure-openai":
+		// Azure OpenAI caps requests at 128 tool definitions.
+		return Capabilities{MaxTools: 128, MaxSchemaDepth: 8}
+	case "vertex":
+		return Capabilities{MaxTools: 64, MaxSchemaDepth: 6}
+	default:
+		// "", "anthropic" and "bedrock" serve Claude models, which accept
+		// large tool sets and deep schemas.
+		return Capabilities{}
+	}
+}
+
+// AdaptTools fits tool definitions to a provider's capabilities.
+// Schemas nested beyond the supported depth are simplified to a
+// permissive object at the cutoff, and tools beyond the count limit are
+// dropped, later-registered ones first. Every adaptation is logged so
+// operators can see which tools degraded.
+func AdaptTools(defs []ToolDefinition, caps Capabilities) []ToolDefinition {
+	if caps == (Capabilities{}) {
+		return defs
+	}
+	adapted := make([]ToolDefinition, len(defs))
+	copy(adapted, defs)
+	if caps.MaxSchemaDepth > 0 {
+		for i := range adapted {
+			if depth := schemaDepth(adapted[i].InputSchema); depth > caps.MaxSchemaDepth {
+				glog.Warningf("Simplifying the input schema of tool %v: depth %v exceeds the provider limit of %v",
+					adapted[i].Name, depth, caps.MaxSchemaDepth)
+				adapted[i].InputSchema = pruneSchema(adapted[i].InputSchema, caps.MaxSchemaDepth)
+			}
+		}
+	}
+	if caps.MaxTools > 0 && len(adapted) > caps.MaxTools {
+		dropped := make([]string, 0, len(adapted)-caps.MaxTools)
+		for _, def := range adapted[caps.MaxTools:] {
+			dropped = append(dropped, def.Name)
+		}
+		glog.Warningf("Dropping %v tool(s) beyond the provider limit of %v: %v", len(dropped), caps.MaxTools, dropped)
+		adapted = adapted[:caps.MaxTools]
+	}
+	return adapted
+}
+
+// schemaDepth returns how deeply a JSON schema nests sub-schemas, i.e.
+// how many levels of "properties" and "items" it descends. A flat
+// object schema has depth 1.
+func schemaDepth(schema map[string]interface{}) int {
+	deepest := 0
+	for _, child := range childSchemas(schema) {
+		if d := schemaDepth(child); d > deepest {
+			deepest = d
+		}
+	}
+	return 1 + deepest
+}
+
+// childSchemas returns a schema's direct sub-schemas: its properties'
+// values and its array item schema.
+func childSchemas(schema map[string]interface{}) []map[string]interface{} {
+	var children []map[string]interface{}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, value := range properties {
+			if child, ok := value.(map[string]interface{}); ok {
+				children = append(children, child)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		children = append(children, items)
+	}
+	return children
+}
+
+// pruneSchema truncates a schema to the depth budget. Sub-schemas below
+// the cutoff are replaced by a permissive object that keeps its
+// description, so the tool stays callable — the model just gets less
+// guidance on the deepest arguments.
+func pruneSchema(schema map[string]interface{}, budget int) map[string]interface{} {
+	if budget <= 1 {
+		pruned := map[string]interface{}{"type": "object"}
+		if description, ok := schema["description"]; ok {
+			pruned["description"] = description
+		}
+		return pruned
+	}
+	out := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		out[key] = value
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		prunedProperties := make(map[string]interface{}, len(properties))
+		for name, value := range properties {
+			if child, ok := value.(map[string]interface{}); ok {
+				prunedProperties[name] = pruneSchema(child, budget-1)
+			} else {
+				prunedProperties[name] = value
+			}
+		}
+		out["properties"] = prunedProperties
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		out["items"] = pruneSchema(items, budget-1)
+	}
+	return out
+}
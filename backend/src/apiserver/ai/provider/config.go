@@ -0,0 +1,93 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Options carries the provider-agnostic settings used to construct a
+// ChatModel.
+type Options struct {
+	// Model is the provider-specific model identifier. Empty selects the
+	// provider's default.
+	Model string
+	// APIKey authenticates against the provider API.
+	APIKey string
+	// Keys optionally spreads requests over several API keys with
+	// weighted rotation, e.g. across multiple orgs; when set it takes
+	// precedence over APIKey. Providers that authenticate with a plain
+	// API key consult it; others ignore it.
+	Keys []WeightedKey
+	// BaseURL overrides the provider endpoint, e.g. for proxies.
+	BaseURL string
+	// CredentialsJSON holds provider credentials that are not a plain API
+	// key, e.g. a GCP service-account key for the vertex provider.
+	CredentialsJSON string
+	// MaxRetries bounds the retries of requests rejected transiently
+	// before their stream starts. Zero selects the provider default; a
+	// negative value disables retries.
+	MaxRetries int
+}
+
+// NewChatModelChain constructs the ChatModel for the named provider,
+// wrapped in a fallback chain over the listed fallback providers when
+// any are configured. All chained providers share the same options;
+// each reads the fields it needs.
+func NewChatModelChain(name string, fallbacks []string, opts *Options) (ChatModel, error) {
+	primary, err := NewChatModel(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(fallbacks) == 0 {
+		return primary, nil
+	}
+	models := []ChatModel{primary}
+	for _, fallback := range fallbacks {
+		model, err := NewChatModel(fallback, opts)
+		if err != nil {
+			return nil, util.Wrapf(err, "Failed to initialize fallback chat model provider %q", fallback)
+		}
+		models = append(models, model)
+	}
+	return NewFallbackChatModel(models)
+}
+
+// NewChatModel constructs the ChatModel for the named provider. An empty
+// name selects the anthropic provider.
+func NewChatModel(name string, opts *Options) (ChatModel, error) {
+	switch name {
+	case "", "anthropic":
+		return NewAnthropicProvider(opts)
+	case "vertex":
+		return NewVertexProvider(opts)
+	case "bedrock":
+		return NewBedrockProvider(opts)
+	case "azure-openai":
+		return NewAzureOpenAIProvider(opts)
+	case "local":
+		return NewLocalProvider(opts)
+	case "local-emulated":
+		// Self-hosted models without native function calling: the local
+		// provider does the wire protocol, the emulator does the tools.
+		inner, err := NewLocalProvider(opts)
+		if err != nil {
+			return nil, err
+		}
+		return NewToolCallEmulator(inner), nil
+	default:
+		return nil, util.NewInvalidInputError("Unknown chat model provider %q", name)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// ToolCallEmulator wraps a ChatModel and emulates tool calling for
+// models without native function calling, so the agent loop still works
+// against open-weight models on vLLM or TGI. Tool definitions are
+// rendered into the system prompt with instructions to emit a JSON
+// tool_call object, tool results are folded back into user messages,
+// and the assistant's output is scanned for tool_call JSON which is
+// re-emitted as regular tool call events. The assistant text is
+// buffered for the scan, so emulated turns do not stream token by
+// token.
+type ToolCallEmulator struct {
+	inner ChatModel
+}
+
+// NewToolCallEmulator wraps the given model with tool-call emulation.
+func NewToolCallEmulator(inner ChatModel) *ToolCallEmulator {
+	return &ToolCallEmulator{inner: inner}
+}
+
+func (p *ToolCallEmulator) Name() string {
+	return p.inner.Name() + "-emulated"
+}
+
+// SetModel forwards runtime model switches to the wrapped provider.
+func (p *ToolCallEmulator) SetModel(model string) {
+	if selector, ok := p.inner.(ModelSelector); ok {
+		selector.SetModel(model)
+	}
+}
+
+// Capabilities caps the tool set tightly: every definition is rendered
+// into the prompt verbatim, and small models follow a short menu of
+// flat schemas far more reliably than a long nested one.
+func (p *ToolCallEmulator) Capabilities() Capabilities {
+	return Capabilities{MaxTools: 16, MaxSchemaDepth: 3}
+}
+
+// StreamChat rewrites the request into plain text, runs the wrapped
+// model and lifts emitted tool_call JSON back into tool call events.
+// Requests without tools pass through untouched.
+func (p *ToolCallEmulator) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	if len(req.Tools) == 0 {
+		return p.inner.StreamChat(ctx, req, cb)
+	}
+
+	var text strings.Builder
+	var usage *Usage
+	err := p.inner.StreamChat(ctx, p.emulatedRequest(req), func(event StreamEvent) error {
+		switch event.Type {
+		case StreamEventTextDelta:
+			// Buffered: the tool_call scan needs the complete output.
+			text.WriteString(event.Delta.Text)
+			return nil
+		case StreamEventToolCall:
+			// The backend turned out to support native calls after all;
+			// pass them through alongside the emulated ones.
+			return cb(event)
+		case StreamEventUsage:
+			usage = event.Usage
+			return nil
+		case StreamEventDone:
+			// Deferred until the buffered text is post-processed below.
+			return nil
+		default:
+			return cb(event)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	prose, calls := extractEmulatedToolCalls(text.String())
+	if prose != "" {
+		if err := cb(StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: prose}}); err != nil {
+			return err
+		}
+	}
+	for _, call := range calls {
+		call := call
+		if err := cb(StreamEvent{Type: StreamEventToolCall, ToolCall: &call}); err != nil {
+			return err
+		}
+	}
+	if len(calls) > 0 {
+		glog.V(4).Infof("Tool call emulation extracted %v call(s) from the model output", len(calls))
+	}
+	if usage != nil {
+		if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+			return err
+		}
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
+
+// emulatedRequest rewrites the request into one a plain text model can
+// serve: tools move into the system prompt, assistant tool calls are
+// re-rendered as the JSON the model originally emitted, and tool
+// results become user messages.
+func (p *ToolCallEmulator) emulatedRequest(req *ChatRequest) *ChatRequest {
+	out := &ChatRequest{
+		System:    req.System + "\n\n" + renderToolPrompt(req.Tools),
+		MaxTokens: req.MaxTokens,
+		Model:     req.Model,
+	}
+	for _, m := range req.Messages {
+		switch m.Role {
+		case RoleAssistant:
+			content := m.Content
+			for _, call := range m.ToolCalls {
+				content += "\n" + renderEmulatedCall(call)
+			}
+			out.Messages = append(out.Messages, Message{Role: RoleAssistant, Content: strings.TrimSpace(content)})
+		case RoleTool:
+			out.Messages = append(out.Messages, Message{
+				Role:    RoleUser,
+				Content: fmt.Sprintf("Tool result for call %v:\n%v", m.ToolCallID, m.Content),
+			})
+		default:
+			out.Messages = append(out.Messages, m)
+		}
+	}
+	return out
+}
+
+// renderToolPrompt renders the tool definitions and calling protocol
+// into system prompt text.
+func renderToolPrompt(tools []ToolDefinition) string {
+	var b strings.Builder
+	b.WriteString("You can call the following tools. To call one, output a JSON object of the form\n")
+	b.WriteString(`{"tool_call": {"name": "<tool name>", "arguments": {<arguments>}}}` + "\n")
+	b.WriteString("on its own line, one object per call, and nothing after it. ")
+	b.WriteString("You will receive the results as follow-up messages; then either call more tools or answer the user.\n\nTools:\n")
+	for _, t := range tools {
+		schema, _ := json.Marshal(t.InputSchema)
+		fmt.Fprintf(&b, "- %v: %v Arguments schema: %s\n", t.Name, t.DescriptionWithHints(), schema)
+	}
+	return b.String()
+}
+
+// renderEmulatedCall renders a recorded tool call back into the JSON
+// protocol form, keeping the replayed conversation consistent with what
+// the model is instructed to produce.
+func renderEmulatedCall(call ToolCall) string {
+	arguments := call.ArgumentsJSON
+	if arguments == "" {
+		arguments = "{}"
+	}
+	return fmt.Sprintf(`{"tool_call": {"name": %q, "arguments": %v}}`, call.Name, arguments)
+}
+
+// emulatedCall is the JSON protocol shape the model is instructed to
+// emit.
+type emulatedCall struct {
+	ToolCall struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"tool_call"`
+}
+
+// extractEmulatedToolCalls splits the model output into prose and
+// tool_call objects. Every '{' is tried as the start of a protocol
+// object; anything that does not decode to one — including arbitrary
+// JSON the model quoted in prose — stays text. Leftover code fence
+// markers around extracted objects are dropped.
+func extractEmulatedToolCalls(text string) (string, []ToolCall) {
+	var calls []ToolCall
+	var prose strings.Builder
+	rest := text
+	for {
+		idx := strings.Index(rest, "{")
+		if idx < 0 {
+			prose.WriteString(rest)
+			break
+		}
+		var call emulatedCall
+		decoder := json.NewDecoder(strings.NewReader(rest[idx:]))
+		if err := decoder.Decode(&call); err != nil || call.ToolCall.Name == "" {
+			prose.WriteString(rest[:idx+1])
+			rest = rest[idx+1:]
+			continue
+		}
+		prose.WriteString(rest[:idx])
+		arguments := string(call.ToolCall.Arguments)
+		if arguments == "" || arguments == "null" {
+			arguments = "{}"
+		}
+		calls = append(calls, ToolCall{
+			ID:            fmt.Sprintf("call-%v", len(calls)+1),
+			Name:          call.ToolCall.Name,
+			ArgumentsJSON: arguments,
+		})
+		rest = rest[idx+int(decoder.InputOffset()):]
+	}
+	if len(calls) == 0 {
+		return strings.TrimSpace(text), nil
+	}
+	return strings.TrimSpace(stripFenceMarkers(prose.String())), calls
+}
+
+// stripFenceMarkers drops lines that are nothing but a code fence,
+// which extraction leaves behind when the model wrapped its tool_call
+// in a fenced block.
+func stripFenceMarkers(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "```" || trimmed == "```json" {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEmulatedToolCalls(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		prose string
+		calls []ToolCall
+	}{
+		{
+			name:  "call embedded in prose",
+			text:  "Let me check.\n{\"tool_call\": {\"name\": \"list_runs\", \"arguments\": {\"page_size\": 5}}}",
+			prose: "Let me check.",
+			calls: []ToolCall{{ID: "call-1", Name: "list_runs", ArgumentsJSON: `{"page_size": 5}`}},
+		},
+		{
+			name: "multiple calls",
+			text: "{\"tool_call\": {\"name\": \"list_runs\", \"arguments\": {}}}\n" +
+				"{\"tool_call\": {\"name\": \"list_experiments\", \"arguments\": {}}}",
+			calls: []ToolCall{
+				{ID: "call-1", Name: "list_runs", ArgumentsJSON: "{}"},
+				{ID: "call-2", Name: "list_experiments", ArgumentsJSON: "{}"},
+			},
+		},
+		{
+			name:  "fenced call",
+			text:  "```json\n{\"tool_call\": {\"name\": \"get_run\", \"arguments\": {\"run_id\": \"r1\"}}}\n```",
+			calls: []ToolCall{{ID: "call-1", Name: "get_run", ArgumentsJSON: `{"run_id": "r1"}`}},
+		},
+		{
+			name:  "quoted JSON that is not a protocol object stays prose",
+			text:  `The run spec sets {"replicas": 3} by default.`,
+			prose: `The run spec sets {"replicas": 3} by default.`,
+		},
+		{
+			name:  "null arguments default to an empty object",
+			text:  `{"tool_call": {"name": "list_runs", "arguments": null}}`,
+			calls: []ToolCall{{ID: "call-1", Name: "list_runs", ArgumentsJSON: "{}"}},
+		},
+		{
+			name:  "missing arguments default to an empty object",
+			text:  `{"tool_call": {"name": "list_runs"}}`,
+			calls: []ToolCall{{ID: "call-1", Name: "list_runs", ArgumentsJSON: "{}"}},
+		},
+		{
+			name:  "truncated JSON stays prose",
+			text:  `{"tool_call": {"name": "list_runs", "arguments": {"page`,
+			prose: `{"tool_call": {"name": "list_runs", "arguments": {"page`,
+		},
+		{
+			name:  "protocol object without a name stays prose",
+			text:  `{"tool_call": {"arguments": {}}}`,
+			prose: `{"tool_call": {"arguments": {}}}`,
+		},
+		{
+			name:  "plain text passes through",
+			text:  "All runs look healthy.",
+			prose: "All runs look healthy.",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prose, calls := extractEmulatedToolCalls(tc.text)
+			assert.Equal(t, tc.prose, prose)
+			assert.Equal(t, tc.calls, calls)
+		})
+	}
+}
+
+// scriptedModel is a ChatModel stub replaying a fixed event sequence and
+// recording the request it received.
+type scriptedModel struct {
+	events []StreamEvent
+	req    *ChatRequest
+}
+
+func (m *scriptedModel) Name() string { return "local" }
+
+func (m *scriptedModel) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	m.req = req
+	for _, event := range m.events {
+		if err := cb(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func textDeltas(pieces ...string) []StreamEvent {
+	var events []StreamEvent
+	for _, piece := range pieces {
+		events = append(events, StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: piece}})
+	}
+	return append(events,
+		StreamEvent{Type: StreamEventUsage, Usage: &Usage{InputTokens: 30, OutputTokens: 12}},
+		StreamEvent{Type: StreamEventDone})
+}
+
+func TestToolCallEmulator_LiftsToolCallsOutOfBufferedText(t *testing.T) {
+	// The call streams split across deltas; only the completed text can
+	// be parsed.
+	inner := &scriptedModel{events: textDeltas("Checking.\n", "{\"tool_call\": {\"name\": \"list_runs\", ", "\"arguments\": {\"page_size\": 5}}}")}
+	emulator := NewToolCallEmulator(inner)
+
+	req := &ChatRequest{
+		System: "be helpful",
+		Messages: []Message{
+			{Role: RoleUser, Content: "list my runs"},
+			{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "call-1", Name: "get_run", ArgumentsJSON: `{"run_id":"r1"}`}}},
+			{Role: RoleTool, ToolCallID: "call-1", Content: `{"state":"FAILED"}`},
+		},
+		Tools: []ToolDefinition{{Name: "list_runs", Description: "List runs.", InputSchema: map[string]interface{}{"type": "object"}}},
+	}
+	var events []StreamEvent
+	require.NoError(t, emulator.StreamChat(context.Background(), req, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	// The forwarded request is plain text: tools in the prompt, the
+	// recorded call re-rendered, the tool result folded into a user turn.
+	require.NotNil(t, inner.req)
+	assert.Empty(t, inner.req.Tools)
+	assert.Contains(t, inner.req.System, "list_runs")
+	assert.Contains(t, inner.req.System, `"tool_call"`)
+	require.Len(t, inner.req.Messages, 3)
+	assert.Contains(t, inner.req.Messages[1].Content, `{"tool_call": {"name": "get_run", "arguments": {"run_id":"r1"}}}`)
+	assert.Empty(t, inner.req.Messages[1].ToolCalls)
+	assert.Equal(t, RoleUser, inner.req.Messages[2].Role)
+	assert.Contains(t, inner.req.Messages[2].Content, "Tool result for call call-1")
+
+	require.Len(t, events, 4)
+	assert.Equal(t, StreamEventTextDelta, events[0].Type)
+	assert.Equal(t, "Checking.", events[0].Delta.Text)
+	assert.Equal(t, &ToolCall{ID: "call-1", Name: "list_runs", ArgumentsJSON: `{"page_size": 5}`}, events[1].ToolCall)
+	assert.Equal(t, &Usage{InputTokens: 30, OutputTokens: 12}, events[2].Usage)
+	assert.Equal(t, StreamEventDone, events[3].Type)
+}
+
+func TestToolCallEmulator_PassesThroughRequestsWithoutTools(t *testing.T) {
+	inner := &scriptedModel{events: textDeltas("hello")}
+	emulator := NewToolCallEmulator(inner)
+
+	req := &ChatRequest{Messages: []Message{{Role: RoleUser, Content: "hi"}}}
+	var events []StreamEvent
+	require.NoError(t, emulator.StreamChat(context.Background(), req, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	// The request reaches the wrapped model untouched and the deltas
+	// stream through unbuffered.
+	assert.Same(t, req, inner.req)
+	require.Len(t, events, 3)
+	assert.Equal(t, "hello", events[0].Delta.Text)
+}
+
+func TestToolCallEmulator_ReportsTightCapabilities(t *testing.T) {
+	emulator := NewToolCallEmulator(&scriptedModel{})
+	assert.Equal(t, "local-emulated", emulator.Name())
+	capabilities := emulator.Capabilities()
+	assert.Greater(t, capabilities.MaxTools, 0)
+	assert.Greater(t, capabilities.MaxSchemaDepth, 0)
+}
+
+func TestStripFenceMarkers(t *testing.T) {
+	text := "before\n```json\n```\nafter"
+	assert.Equal(t, "before\nafter", stripFenceMarkers(text))
+	assert.False(t, strings.Contains(stripFenceMarkers("```"), "`"))
+}
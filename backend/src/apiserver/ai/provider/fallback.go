@@ -0,0 +1,115 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"google.golang.org/grpc/codes"
+)
+
+// FallbackChatModel chains providers in priority order: a request is
+// served by the first one, and retried against the next when the
+// provider fails transiently (outage, timeout, rate limit) before any
+// output reached the caller. A stream_event of type provider_switched is
+// emitted before each failover so the caller knows which provider ended
+// up answering. Failures after output has streamed are not retried here;
+// replaying a partially delivered answer against another provider would
+// duplicate text, and the server's partial-response salvage already
+// covers that case.
+type FallbackChatModel struct {
+	models []ChatModel
+}
+
+// NewFallbackChatModel chains the given models in order. At least one is
+// required; a single model is wrapped transparently.
+func NewFallbackChatModel(models []ChatModel) (*FallbackChatModel, error) {
+	if len(models) == 0 {
+		return nil, util.NewInvalidInputError("at least one chat model is required for a fallback chain")
+	}
+	return &FallbackChatModel{models: models}, nil
+}
+
+// Name joins the chained provider names in priority order.
+func (f *FallbackChatModel) Name() string {
+	names := make([]string, 0, len(f.models))
+	for _, m := range f.models {
+		names = append(names, m.Name())
+	}
+	return strings.Join(names, "+")
+}
+
+// SetModel forwards the model switch to every chained provider that
+// supports selection. Providers that do not are left unchanged.
+func (f *FallbackChatModel) SetModel(model string) {
+	for _, m := range f.models {
+		if selector, ok := m.(ModelSelector); ok {
+			selector.SetModel(model)
+		}
+	}
+}
+
+// StreamChat tries each provider in order until one completes the turn.
+func (f *FallbackChatModel) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	var lastErr error
+	for i, model := range f.models {
+		if i > 0 {
+			glog.Warningf("Chat model %v failed, failing over to %v: %v", f.models[i-1].Name(), model.Name(), lastErr)
+			if err := cb(StreamEvent{Type: StreamEventProviderSwitched, Provider: model.Name()}); err != nil {
+				return err
+			}
+		}
+		delivered := false
+		err := model.StreamChat(ctx, req, func(event StreamEvent) error {
+			// Only actual model output blocks a failover; status events
+			// like retrying do not.
+			if event.Type == StreamEventTextDelta || event.Type == StreamEventToolCall {
+				delivered = true
+			}
+			return cb(event)
+		})
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if delivered || ctx.Err() != nil || !retryableProviderError(err) {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// retryableProviderError reports whether another provider is worth
+// trying: transient upstream conditions qualify, caller-side problems
+// (bad input, canceled context, marshaling bugs) do not.
+func retryableProviderError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	userErr, ok := err.(*util.UserError)
+	if !ok {
+		return false
+	}
+	switch userErr.ExternalStatusCode() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"sync"
+	"time"
+)
+
+// WeightedKey is one provider API key with its rotation weight. A higher
+// weight receives a proportionally larger share of requests, so a large
+// org key can carry more load than a small one. A non-positive weight
+// counts as one.
+type WeightedKey struct {
+	Key    string
+	Weight int
+}
+
+// keyRing rotates requests across several API keys using smooth weighted
+// round-robin and tracks per-key rate limiting: a key whose org-level
+// limit was hit is put on cooldown and skipped until it expires, so a
+// single exhausted key does not take the assistant down.
+type keyRing struct {
+	mu      sync.Mutex
+	entries []*ringEntry
+	total   int
+}
+
+type ringEntry struct {
+	key    string
+	weight int
+	// current is the smooth weighted round-robin balance; the entry with
+	// the highest balance serves the next request.
+	current int
+	// limitedUntil marks the key as rate limited until that instant;
+	// picks skip it while the cooldown runs.
+	limitedUntil time.Time
+	// served counts requests handed to this key, for observability.
+	served int64
+}
+
+// newKeyRing builds a ring from the given keys, dropping empty entries
+// and defaulting non-positive weights to one. It returns nil when no
+// usable key remains, so callers can fall back to a single-key setup.
+func newKeyRing(keys []WeightedKey) *keyRing {
+	r := &keyRing{}
+	for _, k := range keys {
+		if k.Key == "" {
+			continue
+		}
+		weight := k.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		r.entries = append(r.entries, &ringEntry{key: k.Key, weight: weight})
+		r.total += weight
+	}
+	if len(r.entries) == 0 {
+		return nil
+	}
+	return r
+}
+
+// singleKeyRing wraps one plain API key in a ring, so providers handle
+// the single- and multi-key configurations uniformly.
+func singleKeyRing(key string) *keyRing {
+	return newKeyRing([]WeightedKey{{Key: key}})
+}
+
+// pick returns the key to use for the next request. Keys on cooldown are
+// skipped; when every key is cooling down the rotation runs over all of
+// them anyway — sending the request and letting the provider judge beats
+// failing it locally on possibly stale bookkeeping.
+func (r *keyRing) pick(now time.Time) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	best := r.pickFrom(now, false)
+	if best == nil {
+		best = r.pickFrom(now, true)
+	}
+	best.served++
+	return best.key
+}
+
+// pickFrom runs one smooth weighted round-robin step over the usable
+// entries; includeLimited widens it to keys on cooldown. It returns nil
+// when no entry qualifies.
+func (r *keyRing) pickFrom(now time.Time, includeLimited bool) *ringEntry {
+	var best *ringEntry
+	for _, e := range r.entries {
+		if !includeLimited && now.Before(e.limitedUntil) {
+			continue
+		}
+		e.current += e.weight
+		if best == nil || e.current > best.current {
+			best = e
+		}
+	}
+	if best != nil {
+		best.current -= r.total
+	}
+	return best
+}
+
+// markLimited puts the key on cooldown until the given instant, e.g. the
+// provider's Retry-After. An earlier existing cooldown is extended, not
+// shortened.
+func (r *keyRing) markLimited(key string, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if e.key == key && e.limitedUntil.Before(until) {
+			e.limitedUntil = until
+		}
+	}
+}
+
+// available reports whether any key is currently off cooldown, so a
+// caller holding a limited key can rotate instead of waiting.
+func (r *keyRing) available(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if !now.Before(e.limitedUntil) {
+			return true
+		}
+	}
+	return false
+}
+
+// size returns the number of keys in the ring.
+func (r *keyRing) size() int {
+	return len(r.entries)
+}
+
+// maskKey renders an API key safely for logs: only the last four
+// characters survive.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
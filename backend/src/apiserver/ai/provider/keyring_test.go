@@ -0,0 +1,103 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyRing_DropsEmptyKeysAndDefaultsWeights(t *testing.T) {
+	assert.Nil(t, newKeyRing(nil))
+	assert.Nil(t, newKeyRing([]WeightedKey{{Key: ""}}))
+	assert.Nil(t, singleKeyRing(""))
+
+	r := newKeyRing([]WeightedKey{{Key: "a", Weight: -3}, {Key: ""}, {Key: "b", Weight: 2}})
+	require.NotNil(t, r)
+	assert.Equal(t, 2, r.size())
+	// The non-positive weight counts as one.
+	assert.Equal(t, 3, r.total)
+}
+
+func TestKeyRing_SpreadsPicksByWeight(t *testing.T) {
+	r := newKeyRing([]WeightedKey{{Key: "a", Weight: 3}, {Key: "b", Weight: 1}})
+	now := time.Now()
+
+	// Smooth weighted round-robin interleaves rather than bursting: the
+	// first cycle over the total weight is a, a, b, a.
+	assert.Equal(t, []string{"a", "a", "b", "a"},
+		[]string{r.pick(now), r.pick(now), r.pick(now), r.pick(now)})
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[r.pick(now)]++
+	}
+	assert.Equal(t, 300, counts["a"])
+	assert.Equal(t, 100, counts["b"])
+}
+
+func TestKeyRing_SkipsKeysOnCooldown(t *testing.T) {
+	r := newKeyRing([]WeightedKey{{Key: "a", Weight: 3}, {Key: "b", Weight: 1}})
+	now := time.Now()
+	r.markLimited("a", now.Add(time.Minute))
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, "b", r.pick(now))
+	}
+	assert.True(t, r.available(now))
+
+	// After the cooldown the key rejoins the rotation.
+	later := now.Add(2 * time.Minute)
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		counts[r.pick(later)]++
+	}
+	assert.Greater(t, counts["a"], 0)
+}
+
+func TestKeyRing_FallsBackWhenEveryKeyIsLimited(t *testing.T) {
+	r := newKeyRing([]WeightedKey{{Key: "a"}, {Key: "b"}})
+	now := time.Now()
+	r.markLimited("a", now.Add(time.Minute))
+	r.markLimited("b", now.Add(time.Minute))
+
+	assert.False(t, r.available(now))
+	// Picks still hand out keys: sending the request and letting the
+	// provider judge beats failing locally on possibly stale bookkeeping.
+	picked := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		picked[r.pick(now)] = true
+	}
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, picked)
+}
+
+func TestKeyRing_MarkLimitedNeverShortensACooldown(t *testing.T) {
+	r := singleKeyRing("a")
+	now := time.Now()
+	r.markLimited("a", now.Add(time.Minute))
+	r.markLimited("a", now.Add(time.Second))
+
+	assert.False(t, r.available(now.Add(30*time.Second)))
+	assert.True(t, r.available(now.Add(2*time.Minute)))
+}
+
+func TestMaskKey(t *testing.T) {
+	assert.Equal(t, "****3456", maskKey("sk-123456"))
+	assert.Equal(t, "****", maskKey("abcd"))
+	assert.Equal(t, "****", maskKey(""))
+}
@@ -0,0 +1,320 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// LocalProvider implements ChatModel against OpenAI-compatible chat
+// completion servers such as Ollama, vLLM and llama.cpp, so air-gapped
+// clusters can run the assistant without sending data to an external
+// API. Streaming always works; tool calling works where the serving
+// backend and model support it, and degrades to text-only otherwise.
+type LocalProvider struct {
+	apiKey  string
+	baseURL string
+	// mu guards model, which a configuration reload may swap at runtime.
+	mu         sync.RWMutex
+	model      string
+	httpClient *http.Client
+}
+
+// NewLocalProvider creates a provider from the given options. The base
+// URL and a model name are required: a self-hosted server has no
+// meaningful defaults for either. The API key is optional since most
+// local servers do not authenticate.
+func NewLocalProvider(opts *Options) (*LocalProvider, error) {
+	if opts == nil || opts.BaseURL == "" {
+		return nil, util.NewInvalidInputError("a base URL is required for the local provider")
+	}
+	if opts.Model == "" {
+		return nil, util.NewInvalidInputError("a model name is required for the local provider")
+	}
+	return &LocalProvider{
+		apiKey:  opts.APIKey,
+		model:   opts.Model,
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		// Streaming responses stay open for the whole model turn, so rely
+		// on the request context rather than a client-level timeout.
+		httpClient: &http.Client{Timeout: 0},
+	}, nil
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+// SetModel switches the served model, e.g. after a configuration
+// reload. In-flight requests keep the model they started with.
+func (p *LocalProvider) SetModel(model string) {
+	if model == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.model = model
+}
+
+func (p *LocalProvider) currentModel() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model
+}
+
+// Wire types for the OpenAI chat completions API. Only the fields the
+// assistant needs are modeled.
+type openAIRequest struct {
+	Model         string               `json:"model"`
+	MaxTokens     int                  `json:"max_tokens,omitempty"`
+	Messages      []openAIMessage      `json:"messages"`
+	Tools         []openAITool         `json:"tools,omitempty"`
+	Stream        bool                 `json:"stream"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content,omitempty"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	Index    int    `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description,omitempty"`
+		Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// StreamChat sends the conversation to the chat completions endpoint and
+// relays streamed events to cb.
+func (p *LocalProvider) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	body, err := json.Marshal(buildOpenAIRequest(req, p.currentModel()))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to marshal the local provider request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create the local provider request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return util.NewUnavailableServerError(err, "Failed to reach the local model server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return util.NewUnavailableServerError(
+			fmt.Errorf("local model server returned status %v: %s", resp.StatusCode, errBody),
+			"The model provider rejected the request (HTTP %v)", resp.StatusCode)
+	}
+
+	usage, err := consumeOpenAIStream(resp.Body, cb)
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("Local model call finished in %v, input tokens: %v, output tokens: %v",
+		time.Since(start), usage.InputTokens, usage.OutputTokens)
+	if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+		return err
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
+
+// buildOpenAIRequest translates a chat request into the OpenAI wire
+// form, shared by every OpenAI-compatible provider.
+func buildOpenAIRequest(req *ChatRequest, model string) *openAIRequest {
+	out := &openAIRequest{
+		Model:     requestModel(req, model),
+		MaxTokens: req.MaxTokens,
+		Stream:    true,
+		// Servers that support it report token usage on the final chunk;
+		// the rest silently ignore the option.
+		StreamOptions: &openAIStreamOptions{IncludeUsage: true},
+	}
+	if req.System != "" {
+		out.Messages = append(out.Messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, m := range req.Messages {
+		out.Messages = append(out.Messages, toOpenAIMessage(m))
+	}
+	for _, t := range req.Tools {
+		entry := openAITool{Type: "function"}
+		entry.Function.Name = t.Name
+		// The OpenAI tool format has no structured hint fields, so the
+		// behavior hints ride along in the description.
+		entry.Function.Description = t.DescriptionWithHints()
+		entry.Function.Parameters = t.InputSchema
+		out.Tools = append(out.Tools, entry)
+	}
+	return out
+}
+
+func toOpenAIMessage(m Message) openAIMessage {
+	switch m.Role {
+	case RoleAssistant:
+		out := openAIMessage{Role: "assistant", Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			call := openAIToolCall{ID: tc.ID, Type: "function"}
+			call.Function.Name = tc.Name
+			call.Function.Arguments = tc.ArgumentsJSON
+			out.ToolCalls = append(out.ToolCalls, call)
+		}
+		return out
+	case RoleTool:
+		return openAIMessage{Role: "tool", ToolCallID: m.ToolCallID, Content: m.Content}
+	default:
+		return openAIMessage{Role: "user", Content: m.Content}
+	}
+}
+
+// consumeOpenAIStream reads the SSE body, invoking cb for text deltas
+// and assembled tool calls, and returns the reported token usage. Tool
+// call arguments arrive as fragments keyed by choice index and are
+// emitted once the stream finishes.
+func consumeOpenAIStream(body io.Reader, cb StreamCallback) (*Usage, error) {
+	usage := &Usage{}
+	pending := map[int]*ToolCall{}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" || payload == "[DONE]" {
+			continue
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to decode a chat completions stream event")
+		}
+		if chunk.Error != nil {
+			return nil, util.NewUnavailableServerError(
+				fmt.Errorf("chat completions stream error %v: %v", chunk.Error.Type, chunk.Error.Message),
+				"The model provider reported a streaming error")
+		}
+		if chunk.Usage != nil {
+			usage.InputTokens = chunk.Usage.PromptTokens
+			usage.OutputTokens = chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				if err := cb(StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: choice.Delta.Content}}); err != nil {
+					return nil, err
+				}
+			}
+			for _, fragment := range choice.Delta.ToolCalls {
+				call := pending[fragment.Index]
+				if call == nil {
+					call = &ToolCall{}
+					pending[fragment.Index] = call
+				}
+				if fragment.ID != "" {
+					call.ID = fragment.ID
+				}
+				if fragment.Function.Name != "" {
+					call.Name = fragment.Function.Name
+				}
+				call.ArgumentsJSON += fragment.Function.Arguments
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, util.NewUnavailableServerError(err, "The model provider stream ended unexpectedly")
+	}
+
+	// Emit the assembled calls in index order so multi-call turns replay
+	// deterministically.
+	indexes := make([]int, 0, len(pending))
+	for index := range pending {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for n, index := range indexes {
+		call := pending[index]
+		if call.ArgumentsJSON == "" {
+			call.ArgumentsJSON = "{}"
+		}
+		// Some servers omit call IDs; generate stable ones so tool results
+		// can reference them.
+		if call.ID == "" {
+			call.ID = fmt.Sprintf("call-%v", n+1)
+		}
+		if err := cb(StreamEvent{Type: StreamEventToolCall, ToolCall: call}); err != nil {
+			return nil, err
+		}
+	}
+	return usage, nil
+}
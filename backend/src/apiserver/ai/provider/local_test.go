@@ -0,0 +1,170 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocalProvider_RequiresABaseURLAndModel(t *testing.T) {
+	_, err := NewLocalProvider(&Options{Model: "llama3"})
+	assert.ErrorContains(t, err, "base URL")
+	_, err = NewLocalProvider(&Options{BaseURL: "http://ollama:11434"})
+	assert.ErrorContains(t, err, "model name")
+}
+
+func TestBuildOpenAIRequest_TranslatesTheConversation(t *testing.T) {
+	out := buildOpenAIRequest(&ChatRequest{
+		System:    "be helpful",
+		MaxTokens: 512,
+		Messages: []Message{
+			{Role: RoleUser, Content: "list my runs"},
+			{Role: RoleAssistant, Content: "On it.", ToolCalls: []ToolCall{{ID: "call-1", Name: "list_runs", ArgumentsJSON: `{"page_size":5}`}}},
+			{Role: RoleTool, ToolCallID: "call-1", Content: `{"runs":[]}`},
+		},
+		Tools: []ToolDefinition{{
+			Name:        "list_runs",
+			Description: "List runs.",
+			InputSchema: map[string]interface{}{"type": "object"},
+		}},
+	}, "llama3")
+
+	assert.Equal(t, "llama3", out.Model)
+	assert.Equal(t, 512, out.MaxTokens)
+	assert.True(t, out.Stream)
+	require.NotNil(t, out.StreamOptions)
+	assert.True(t, out.StreamOptions.IncludeUsage)
+
+	require.Len(t, out.Messages, 4)
+	assert.Equal(t, openAIMessage{Role: "system", Content: "be helpful"}, out.Messages[0])
+	assert.Equal(t, openAIMessage{Role: "user", Content: "list my runs"}, out.Messages[1])
+	assistant := out.Messages[2]
+	assert.Equal(t, "assistant", assistant.Role)
+	require.Len(t, assistant.ToolCalls, 1)
+	assert.Equal(t, "call-1", assistant.ToolCalls[0].ID)
+	assert.Equal(t, "function", assistant.ToolCalls[0].Type)
+	assert.Equal(t, "list_runs", assistant.ToolCalls[0].Function.Name)
+	assert.Equal(t, `{"page_size":5}`, assistant.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, openAIMessage{Role: "tool", ToolCallID: "call-1", Content: `{"runs":[]}`}, out.Messages[3])
+
+	require.Len(t, out.Tools, 1)
+	assert.Equal(t, "function", out.Tools[0].Type)
+	assert.Equal(t, "list_runs", out.Tools[0].Function.Name)
+	// The behavior hints ride along in the description.
+	assert.Equal(t, "List runs. This tool changes cluster state; repeating the same call creates or changes more resources.",
+		out.Tools[0].Function.Description)
+}
+
+func TestBuildOpenAIRequest_HonorsThePerRequestModelOverride(t *testing.T) {
+	out := buildOpenAIRequest(&ChatRequest{Model: "llama3:70b"}, "llama3")
+	assert.Equal(t, "llama3:70b", out.Model)
+}
+
+func TestConsumeOpenAIStream_EmitsTextAndUsage(t *testing.T) {
+	body := strings.Join([]string{
+		`data: {"choices":[{"delta":{"content":"Hello"}}]}`,
+		`data: {"choices":[{"delta":{"content":" world"}}]}`,
+		`data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3}}`,
+		`data: [DONE]`,
+		``,
+	}, "\n\n")
+
+	var events []StreamEvent
+	usage, err := consumeOpenAIStream(strings.NewReader(body), func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Hello", events[0].Delta.Text)
+	assert.Equal(t, " world", events[1].Delta.Text)
+	assert.Equal(t, &Usage{InputTokens: 12, OutputTokens: 3}, usage)
+}
+
+func TestConsumeOpenAIStream_AssemblesToolCallFragments(t *testing.T) {
+	// Two calls stream interleaved as fragments keyed by index; the first
+	// carries an ID and split arguments, the second neither.
+	body := strings.Join([]string{
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call-abc","function":{"name":"list_runs"}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"page_"}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":1,"function":{"name":"list_experiments"}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"size\":5}"}}]}}]}`,
+		`data: [DONE]`,
+		``,
+	}, "\n\n")
+
+	var events []StreamEvent
+	_, err := consumeOpenAIStream(strings.NewReader(body), func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	first := events[0].ToolCall
+	require.NotNil(t, first)
+	assert.Equal(t, "call-abc", first.ID)
+	assert.Equal(t, "list_runs", first.Name)
+	assert.Equal(t, `{"page_size":5}`, first.ArgumentsJSON)
+
+	// Missing IDs and arguments get stable defaults.
+	second := events[1].ToolCall
+	require.NotNil(t, second)
+	assert.Equal(t, "call-2", second.ID)
+	assert.Equal(t, "list_experiments", second.Name)
+	assert.Equal(t, "{}", second.ArgumentsJSON)
+}
+
+func TestConsumeOpenAIStream_SurfacesStreamErrors(t *testing.T) {
+	body := `data: {"error":{"type":"server_error","message":"model crashed"}}` + "\n"
+	_, err := consumeOpenAIStream(strings.NewReader(body), func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "model crashed")
+}
+
+func TestLocalStreamChat_EndToEnd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":4,\"completion_tokens\":1}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p, err := NewLocalProvider(&Options{BaseURL: server.URL, Model: "llama3", APIKey: "secret"})
+	require.NoError(t, err)
+
+	var events []StreamEvent
+	err = p.StreamChat(context.Background(), &ChatRequest{Messages: []Message{{Role: RoleUser, Content: "hi"}}},
+		func(event StreamEvent) error {
+			events = append(events, event)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, StreamEventTextDelta, events[0].Type)
+	assert.Equal(t, StreamEventUsage, events[1].Type)
+	assert.Equal(t, &Usage{InputTokens: 4, OutputTokens: 1}, events[1].Usage)
+	assert.Equal(t, StreamEventDone, events[2].Type)
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package provider contains the chat model abstraction used by the AI
+// assistant and its concrete implementations. A ChatModel turns a
+// conversation plus a set of tool definitions into a stream of events
+// (text deltas and tool calls) without knowing anything about Kubeflow
+// Pipelines resources.
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// Role identifies the author of a conversation message.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	// RoleTool marks a message carrying the result of a tool call back to
+	// the model. ToolCallID must reference the originating call.
+	RoleTool Role = "tool"
+)
+
+// Message is one entry in a conversation history.
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content,omitempty"`
+	// ToolCalls is set on assistant messages that requested tool execution.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is set on RoleTool messages and references the call the
+	// content responds to.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// ToolCall is a request from the model to execute a named tool.
+type ToolCall struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ArgumentsJSON holds the raw JSON object the model supplied as input.
+	ArgumentsJSON string `json:"arguments_json"`
+}
+
+// ModelSelector is implemented by chat models that can switch the
+// served model identifier at runtime, e.g. after a configuration
+// reload.
+type ModelSelector interface {
+	SetModel(model string)
+}
+
+// Summarizer is optionally implemented by chat models that can condense
+// a conversation prefix natively, e.g. through a cheaper model. Models
+// without it are driven through StreamChat with a summarization prompt
+// instead.
+type Summarizer interface {
+	Summarize(ctx context.Context, messages []Message) (string, error)
+}
+
+// ToolAnnotations are behavior hints that calibrate the model about a
+// tool's blast radius before it proposes a call.
+type ToolAnnotations struct {
+	// ReadOnly marks tools that do not change cluster state.
+	ReadOnly bool
+	// Destructive marks tools that delete or irreversibly alter resources.
+	Destructive bool
+	// Idempotent marks tools where repeating a call with the same
+	// arguments has no additional effect.
+	Idempotent bool
+}
+
+// ToolDefinition describes a tool to the model. InputSchema is a JSON
+// schema object in the generic map form so providers can serialize it
+// into their native tool-definition format.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	InputSchema map[string]interface{}
+	Annotations ToolAnnotations
+}
+
+// DescriptionWithHints appends the annotation hints to the description,
+// for providers whose tool format has no structured metadata fields.
+func (d *ToolDefinition) DescriptionWithHints() string {
+	hint := "This tool is read-only."
+	switch {
+	case d.Annotations.Destructive:
+		hint = "This tool is destructive: it deletes or irreversibly alters resources."
+	case !d.Annotations.ReadOnly && d.Annotations.Idempotent:
+		hint = "This tool changes cluster state; repeating the same call has no additional effect."
+	case !d.Annotations.ReadOnly:
+		hint = "This tool changes cluster state; repeating the same call creates or changes more resources."
+	}
+	if d.Description == "" {
+		return hint
+	}
+	return d.Description + " " + hint
+}
+
+// ChatRequest is a single model invocation: the system prompt, the
+// conversation so far and the tools the model may call.
+type ChatRequest struct {
+	System    string
+	Messages  []Message
+	Tools     []ToolDefinition
+	MaxTokens int
+	// Model overrides the provider's configured model for this request
+	// when non-empty, e.g. when an overloaded server degrades a turn to
+	// a cheaper model.
+	Model string
+}
+
+// requestModel resolves the model for one request: a per-request
+// override wins over the provider's configured model.
+func requestModel(req *ChatRequest, configured string) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return configured
+}
+
+// StreamEventType enumerates the events a ChatModel emits while streaming.
+type StreamEventType string
+
+const (
+	// StreamEventTextDelta carries an incremental chunk of assistant text.
+	StreamEventTextDelta StreamEventType = "text_delta"
+	// StreamEventToolCall carries a complete tool call once its arguments
+	// have fully streamed.
+	StreamEventToolCall StreamEventType = "tool_call"
+	// StreamEventDone signals the end of the model turn.
+	StreamEventDone StreamEventType = "done"
+	// StreamEventProviderSwitched reports that a fallback chain moved on
+	// to another provider after a transient failure; Provider names it.
+	StreamEventProviderSwitched StreamEventType = "provider_switched"
+	// StreamEventRetrying reports that the provider request was rejected
+	// transiently (rate limit, overload) before the stream started and is
+	// about to be retried; Retry describes the attempt.
+	StreamEventRetrying StreamEventType = "retrying"
+	// StreamEventUsage reports the token consumption of the completed
+	// model invocation, emitted once just before done.
+	StreamEventUsage StreamEventType = "usage"
+)
+
+// Delta is an incremental piece of assistant output.
+type Delta struct {
+	Text string
+}
+
+// Retry describes one scheduled retry of a rejected provider request.
+type Retry struct {
+	// Attempt is the upcoming attempt, 1-based over MaxAttempts retries.
+	Attempt     int
+	MaxAttempts int
+	// Delay is how long the provider waits before the attempt.
+	Delay time.Duration
+}
+
+// Usage reports token consumption for a model invocation.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// StreamEvent is a single event emitted during a streaming model call.
+// Exactly one of the pointer fields is set, according to Type.
+type StreamEvent struct {
+	Type     StreamEventType
+	Delta    *Delta
+	ToolCall *ToolCall
+	// Provider is set on provider_switched events and names the provider
+	// that handles the turn from that point on.
+	Provider string
+	// Retry is set on retrying events.
+	Retry *Retry
+	// Usage is set on usage events.
+	Usage *Usage
+}
+
+// StreamCallback receives stream events in order. Returning an error
+// aborts the stream and fails the StreamChat call with that error.
+type StreamCallback func(StreamEvent) error
+
+// ChatModel is implemented by every model provider. StreamChat blocks
+// until the model turn completes, invoking cb for each event.
+type ChatModel interface {
+	// Name returns the provider identifier, e.g. "anthropic".
+	Name() string
+	StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error
+}
@@ -0,0 +1,497 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	defaultGeminiBaseURL = "https://generativelanguage.googleapis.com"
+	defaultVertexBaseURL = "https://aiplatform.googleapis.com"
+	defaultVertexModel   = "gemini-2.0-flash"
+	// vertexTokenScope is the OAuth scope requested for service-account
+	// access tokens.
+	vertexTokenScope = "https://www.googleapis.com/auth/cloud-platform"
+	// vertexTokenSlack renews a cached access token this long before it
+	// actually expires, so in-flight requests never race the expiry.
+	vertexTokenSlack = time.Minute
+)
+
+// VertexProvider implements ChatModel against the Gemini streaming API.
+// With an API key it talks to the Gemini API directly; with a GCP
+// service-account key it talks to the Vertex AI endpoint of the key's
+// project, minting OAuth access tokens itself so no vendor SDK is
+// needed.
+type VertexProvider struct {
+	apiKey  string
+	baseURL string
+	// account is set in service-account mode and nil in API-key mode.
+	account *gcpServiceAccount
+	signKey *rsa.PrivateKey
+	// mu guards model, which a configuration reload may swap at runtime.
+	mu         sync.RWMutex
+	model      string
+	httpClient *http.Client
+
+	// tokenMu guards the cached service-account access token.
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// gcpServiceAccount is the subset of a GCP service-account key file the
+// provider needs.
+type gcpServiceAccount struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewVertexProvider creates a provider from the given options. Either an
+// API key or a service-account key (in CredentialsJSON) is required;
+// model and base URL fall back to defaults.
+func NewVertexProvider(opts *Options) (*VertexProvider, error) {
+	if opts == nil || (opts.APIKey == "" && opts.CredentialsJSON == "") {
+		return nil, util.NewInvalidInputError("an API key or a GCP service-account key is required for the vertex provider")
+	}
+	model := opts.Model
+	if model == "" {
+		model = defaultVertexModel
+	}
+	p := &VertexProvider{
+		apiKey:  opts.APIKey,
+		model:   model,
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		// Streaming responses stay open for the whole model turn, so rely
+		// on the request context rather than a client-level timeout.
+		httpClient: &http.Client{Timeout: 0},
+	}
+	if opts.CredentialsJSON != "" {
+		account := &gcpServiceAccount{}
+		if err := json.Unmarshal([]byte(opts.CredentialsJSON), account); err != nil {
+			return nil, util.NewInvalidInputError("Invalid GCP service-account key: %v", err)
+		}
+		if account.ClientEmail == "" || account.PrivateKey == "" || account.ProjectID == "" {
+			return nil, util.NewInvalidInputError("The GCP service-account key needs client_email, private_key and project_id")
+		}
+		if account.TokenURI == "" {
+			account.TokenURI = "https://oauth2.googleapis.com/token"
+		}
+		key, err := parseRSAPrivateKey(account.PrivateKey)
+		if err != nil {
+			return nil, util.NewInvalidInputError("Invalid private_key in the GCP service-account key: %v", err)
+		}
+		p.account = account
+		p.signKey = key
+	}
+	if p.baseURL == "" {
+		if p.account != nil {
+			p.baseURL = defaultVertexBaseURL
+		} else {
+			p.baseURL = defaultGeminiBaseURL
+		}
+	}
+	return p, nil
+}
+
+func (p *VertexProvider) Name() string {
+	return "vertex"
+}
+
+// Wire types for the Gemini generateContent API. Only the fields the
+// assistant needs are modeled.
+type vertexRequest struct {
+	SystemInstruction *vertexContent         `json:"systemInstruction,omitempty"`
+	Contents          []vertexContent        `json:"contents"`
+	Tools             []vertexTool           `json:"tools,omitempty"`
+	GenerationConfig  vertexGenerationConfig `json:"generationConfig"`
+}
+
+type vertexContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []vertexPart `json:"parts"`
+}
+
+type vertexPart struct {
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *vertexFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *vertexFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type vertexFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type vertexFunctionResponse struct {
+	Name     string                 `json:"name"`
+	Response map[string]interface{} `json:"response"`
+}
+
+type vertexTool struct {
+	FunctionDeclarations []vertexFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type vertexFunctionDeclaration struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type vertexGenerationConfig struct {
+	MaxOutputTokens int `json:"maxOutputTokens,omitempty"`
+}
+
+type vertexStreamResponse struct {
+	Candidates []struct {
+		Content vertexContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StreamChat sends the conversation to the streamGenerateContent
+// endpoint and relays streamed events to cb.
+func (p *VertexProvider) StreamChat(ctx context.Context, req *ChatRequest, cb StreamCallback) error {
+	body, err := json.Marshal(p.buildRequest(req))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to marshal the vertex request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint(requestModel(req, p.currentModel())), bytes.NewReader(body))
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create the vertex request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if p.account != nil {
+		token, err := p.bearerToken(ctx)
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		httpReq.Header.Set("X-Goog-Api-Key", p.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return util.NewUnavailableServerError(err, "Failed to reach the vertex API")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return util.NewUnavailableServerError(
+			fmt.Errorf("vertex API returned status %v: %s", resp.StatusCode, errBody),
+			"The model provider rejected the request (HTTP %v)", resp.StatusCode)
+	}
+
+	usage, err := p.consumeStream(resp.Body, cb)
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("Vertex call finished in %v, input tokens: %v, output tokens: %v",
+		time.Since(start), usage.InputTokens, usage.OutputTokens)
+	if err := cb(StreamEvent{Type: StreamEventUsage, Usage: usage}); err != nil {
+		return err
+	}
+	return cb(StreamEvent{Type: StreamEventDone})
+}
+
+// SetModel switches the served model, e.g. after a configuration
+// reload. In-flight requests keep the model they started with.
+func (p *VertexProvider) SetModel(model string) {
+	if model == "" {
+		model = defaultVertexModel
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.model = model
+}
+
+func (p *VertexProvider) currentModel() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model
+}
+
+// endpoint builds the streaming URL. The Gemini API addresses models
+// directly; Vertex AI scopes them under the service account's project on
+// the global endpoint (set BaseURL to a regional endpoint to pin a
+// region).
+func (p *VertexProvider) endpoint(model string) string {
+	model = url.PathEscape(model)
+	if p.account != nil {
+		return fmt.Sprintf("%v/v1/projects/%v/locations/global/publishers/google/models/%v:streamGenerateContent?alt=sse",
+			p.baseURL, url.PathEscape(p.account.ProjectID), model)
+	}
+	return fmt.Sprintf("%v/v1beta/models/%v:streamGenerateContent?alt=sse", p.baseURL, model)
+}
+
+func (p *VertexProvider) buildRequest(req *ChatRequest) *vertexRequest {
+	out := &vertexRequest{
+		GenerationConfig: vertexGenerationConfig{MaxOutputTokens: req.MaxTokens},
+	}
+	if req.System != "" {
+		out.SystemInstruction = &vertexContent{Parts: []vertexPart{{Text: req.System}}}
+	}
+	if len(req.Tools) > 0 {
+		decls := make([]vertexFunctionDeclaration, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			decls = append(decls, vertexFunctionDeclaration{
+				Name: t.Name,
+				// The Gemini tool format has no structured hint fields, so
+				// the behavior hints ride along in the description.
+				Description: t.DescriptionWithHints(),
+				Parameters:  t.InputSchema,
+			})
+		}
+		out.Tools = []vertexTool{{FunctionDeclarations: decls}}
+	}
+
+	// Gemini matches function responses to calls by name, not ID, so track
+	// which name each of our tool call IDs belongs to.
+	callNames := map[string]string{}
+	for _, m := range req.Messages {
+		out.Contents = append(out.Contents, toVertexContent(m, callNames))
+	}
+	return out
+}
+
+func toVertexContent(m Message, callNames map[string]string) vertexContent {
+	switch m.Role {
+	case RoleAssistant:
+		var parts []vertexPart
+		if m.Content != "" {
+			parts = append(parts, vertexPart{Text: m.Content})
+		}
+		for _, tc := range m.ToolCalls {
+			callNames[tc.ID] = tc.Name
+			args := json.RawMessage(tc.ArgumentsJSON)
+			if len(args) == 0 {
+				args = json.RawMessage("{}")
+			}
+			parts = append(parts, vertexPart{FunctionCall: &vertexFunctionCall{Name: tc.Name, Args: args}})
+		}
+		return vertexContent{Role: "model", Parts: parts}
+	case RoleTool:
+		// The response must be a JSON object; tool results that are not
+		// already objects are wrapped.
+		response := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(m.Content), &response); err != nil {
+			response = map[string]interface{}{"output": m.Content}
+		}
+		return vertexContent{Role: "user", Parts: []vertexPart{{FunctionResponse: &vertexFunctionResponse{
+			Name:     callNames[m.ToolCallID],
+			Response: response,
+		}}}}
+	default:
+		return vertexContent{Role: "user", Parts: []vertexPart{{Text: m.Content}}}
+	}
+}
+
+// consumeStream reads the SSE body, invoking cb for text deltas and
+// function calls, and returns the reported token usage. Gemini streams
+// whole parts rather than block deltas and does not assign call IDs, so
+// IDs are generated per stream.
+func (p *VertexProvider) consumeStream(body io.Reader, cb StreamCallback) (*Usage, error) {
+	usage := &Usage{}
+	calls := 0
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+		var chunk vertexStreamResponse
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to decode a vertex stream event")
+		}
+		if chunk.Error != nil {
+			return nil, util.NewUnavailableServerError(
+				fmt.Errorf("vertex stream error %v: %v", chunk.Error.Status, chunk.Error.Message),
+				"The model provider reported a streaming error")
+		}
+		// Usage metadata is cumulative; keep the latest report.
+		if chunk.UsageMetadata.PromptTokenCount > 0 {
+			usage.InputTokens = chunk.UsageMetadata.PromptTokenCount
+		}
+		if chunk.UsageMetadata.CandidatesTokenCount > 0 {
+			usage.OutputTokens = chunk.UsageMetadata.CandidatesTokenCount
+		}
+		for _, candidate := range chunk.Candidates {
+			for _, part := range candidate.Content.Parts {
+				if part.Text != "" {
+					if err := cb(StreamEvent{Type: StreamEventTextDelta, Delta: &Delta{Text: part.Text}}); err != nil {
+						return nil, err
+					}
+				}
+				if part.FunctionCall != nil {
+					calls++
+					args := string(part.FunctionCall.Args)
+					if args == "" {
+						args = "{}"
+					}
+					call := &ToolCall{
+						ID:            fmt.Sprintf("%v-%v", part.FunctionCall.Name, calls),
+						Name:          part.FunctionCall.Name,
+						ArgumentsJSON: args,
+					}
+					if err := cb(StreamEvent{Type: StreamEventToolCall, ToolCall: call}); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, util.NewUnavailableServerError(err, "The model provider stream ended unexpectedly")
+	}
+	return usage, nil
+}
+
+// bearerToken returns a valid service-account access token, minting a
+// new one through the OAuth JWT-bearer flow when the cached token is
+// missing or about to expire.
+func (p *VertexProvider) bearerToken(ctx context.Context) (string, error) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry.Add(-vertexTokenSlack)) {
+		return p.token, nil
+	}
+
+	assertion, err := p.signAssertion()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to create the token request")
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return "", util.NewUnavailableServerError(err, "Failed to reach the GCP token endpoint")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", util.NewUnavailableServerError(
+			fmt.Errorf("token endpoint returned status %v: %s", resp.StatusCode, errBody),
+			"Failed to authenticate the service account with GCP (HTTP %v)", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", util.NewInternalServerError(err, "Failed to decode the GCP token response")
+	}
+	if token.AccessToken == "" {
+		return "", util.NewUnavailableServerError(fmt.Errorf("token endpoint returned no access token"),
+			"Failed to authenticate the service account with GCP")
+	}
+	p.token = token.AccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return p.token, nil
+}
+
+// signAssertion builds the RS256-signed JWT the token endpoint exchanges
+// for an access token.
+func (p *VertexProvider) signAssertion() (string, error) {
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   p.account.ClientEmail,
+		"scope": vertexTokenScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the token claims")
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.signKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to sign the token assertion")
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseRSAPrivateKey parses the PEM private key of a service-account key
+// file, which GCP issues in PKCS#8 form.
+func parseRSAPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		// Older keys may be PKCS#1.
+		if key, err1 := x509.ParsePKCS1PrivateKey(block.Bytes); err1 == nil {
+			return key, nil
+		}
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServiceAccountJSON builds a GCP service-account key document with
+// a freshly generated RSA key, returning the JSON and the key so tests
+// can verify signatures.
+func testServiceAccountJSON(t *testing.T, tokenURI string) (string, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	raw, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "test-project",
+		"private_key":  string(pemKey),
+		"client_email": "assistant@test-project.iam.gserviceaccount.com",
+		"token_uri":    tokenURI,
+	})
+	require.NoError(t, err)
+	return string(raw), key
+}
+
+func TestVertexBearerToken_MintsVerifiableAssertionsAndCachesTokens(t *testing.T) {
+	requests := 0
+	var key *rsa.PrivateKey
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+
+		// The assertion must be a JWT signed with the service account's key
+		// and carry the documented claim set.
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		claims := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+		assert.Equal(t, "assistant@test-project.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, vertexTokenScope, claims["scope"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%v", "expires_in": 3600}`, requests)
+	}))
+	defer server.Close()
+
+	credentials, signKey := testServiceAccountJSON(t, server.URL)
+	key = signKey
+	p, err := NewVertexProvider(&Options{CredentialsJSON: credentials})
+	require.NoError(t, err)
+
+	// The first call mints a token; the second reuses it.
+	token, err := p.bearerToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	token, err = p.bearerToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, requests)
+
+	// A token inside the renewal slack is refreshed, not reused.
+	p.tokenExpiry = time.Now().Add(vertexTokenSlack / 2)
+	token, err = p.bearerToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, 2, requests)
+}
+
+func TestVertexBearerToken_RejectsAnEmptyTokenResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"expires_in": 3600}`)
+	}))
+	defer server.Close()
+
+	credentials, _ := testServiceAccountJSON(t, server.URL)
+	p, err := NewVertexProvider(&Options{CredentialsJSON: credentials})
+	require.NoError(t, err)
+
+	_, err = p.bearerToken(context.Background())
+	assert.ErrorContains(t, err, "no access token")
+}
+
+func TestParseRSAPrivateKey_AcceptsPKCS8AndPKCS1(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsed, err := parseRSAPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	pkcs1 := x509.MarshalPKCS1PrivateKey(key)
+	parsed, err = parseRSAPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1})))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = parseRSAPrivateKey("not a key")
+	assert.ErrorContains(t, err, "no PEM block")
+}
+
+func TestVertexConsumeStream_RelaysTextFunctionCallsAndUsage(t *testing.T) {
+	body := strings.Join([]string{
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"Checking. "}]}}],"usageMetadata":{"promptTokenCount":10}}`,
+		``,
+		`data: {"candidates":[{"content":{"parts":[{"functionCall":{"name":"list_runs","args":{"page_size":5}}}]}}],"usageMetadata":{"promptTokenCount":12,"candidatesTokenCount":7}}`,
+		``,
+	}, "\n")
+
+	p := &VertexProvider{}
+	var events []StreamEvent
+	usage, err := p.consumeStream(strings.NewReader(body), func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, StreamEventTextDelta, events[0].Type)
+	assert.Equal(t, "Checking. ", events[0].Delta.Text)
+	assert.Equal(t, StreamEventToolCall, events[1].Type)
+	assert.Equal(t, "list_runs-1", events[1].ToolCall.ID)
+	assert.Equal(t, "list_runs", events[1].ToolCall.Name)
+	assert.JSONEq(t, `{"page_size":5}`, events[1].ToolCall.ArgumentsJSON)
+	// Usage metadata is cumulative; the latest report wins.
+	assert.Equal(t, &Usage{InputTokens: 12, OutputTokens: 7}, usage)
+}
+
+func TestVertexConsumeStream_SurfacesStreamErrors(t *testing.T) {
+	body := `data: {"error":{"code":429,"status":"RESOURCE_EXHAUSTED","message":"quota"}}` + "\n"
+	p := &VertexProvider{}
+	_, err := p.consumeStream(strings.NewReader(body), func(StreamEvent) error { return nil })
+	assert.ErrorContains(t, err, "RESOURCE_EXHAUSTED")
+}
+
+func TestVertexBuildRequest_RoundTripsFunctionCallsByName(t *testing.T) {
+	p, err := NewVertexProvider(&Options{APIKey: "test-key"})
+	require.NoError(t, err)
+
+	// Replay a turn the way the agent loop does: the assistant's call is
+	// recorded under our generated ID, and the tool result references it.
+	// Gemini matches responses by function name, so the translation must
+	// recover the name from the ID.
+	out := p.buildRequest(&ChatRequest{
+		System: "be helpful",
+		Messages: []Message{
+			{Role: RoleUser, Content: "list my runs"},
+			{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "list_runs-1", Name: "list_runs", ArgumentsJSON: `{"page_size":5}`}}},
+			{Role: RoleTool, ToolCallID: "list_runs-1", Content: `{"runs":[]}`},
+			{Role: RoleTool, ToolCallID: "list_runs-1", Content: "plain text"},
+		},
+		Tools: []ToolDefinition{{Name: "list_runs", Description: "List runs.", InputSchema: map[string]interface{}{"type": "object"}}},
+	})
+
+	require.NotNil(t, out.SystemInstruction)
+	assert.Equal(t, "be helpful", out.SystemInstruction.Parts[0].Text)
+	require.Len(t, out.Tools, 1)
+	require.Len(t, out.Tools[0].FunctionDeclarations, 1)
+	assert.Equal(t, "list_runs", out.Tools[0].FunctionDeclarations[0].Name)
+
+	require.Len(t, out.Contents, 4)
+	assert.Equal(t, "model", out.Contents[1].Role)
+	require.NotNil(t, out.Contents[1].Parts[0].FunctionCall)
+	assert.Equal(t, "list_runs", out.Contents[1].Parts[0].FunctionCall.Name)
+
+	response := out.Contents[2].Parts[0].FunctionResponse
+	require.NotNil(t, response)
+	assert.Equal(t, "list_runs", response.Name)
+	assert.Equal(t, map[string]interface{}{"runs": []interface{}{}}, response.Response)
+	// Results that are not JSON objects are wrapped.
+	assert.Equal(t, map[string]interface{}{"output": "plain text"}, out.Contents[3].Parts[0].FunctionResponse.Response)
+}
+
+func TestVertexEndpoint_DependsOnTheCredentialMode(t *testing.T) {
+	keyed, err := NewVertexProvider(&Options{APIKey: "test-key"})
+	require.NoError(t, err)
+	assert.Equal(t,
+		defaultGeminiBaseURL+"/v1beta/models/gemini-2.0-flash:streamGenerateContent?alt=sse",
+		keyed.endpoint("gemini-2.0-flash"))
+
+	credentials, _ := testServiceAccountJSON(t, "https://oauth2.googleapis.com/token")
+	scoped, err := NewVertexProvider(&Options{CredentialsJSON: credentials})
+	require.NoError(t, err)
+	assert.Equal(t,
+		defaultVertexBaseURL+"/v1/projects/test-project/locations/global/publishers/google/models/gemini-2.0-flash:streamGenerateContent?alt=sse",
+		scoped.endpoint("gemini-2.0-flash"))
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// quickActionCatalog holds the parameterized quick actions clients can
+// invoke as a single chat request. Builtin actions come first; config
+// entries extend the catalog or, by matching ID, override a builtin.
+type quickActionCatalog struct {
+	mu      sync.RWMutex
+	actions []config.QuickAction
+}
+
+func newQuickActionCatalog(cfg *config.Config) *quickActionCatalog {
+	c := &quickActionCatalog{}
+	c.applyConfig(cfg)
+	return c
+}
+
+// applyConfig rebuilds the catalog from the builtin actions plus the
+// configured ones.
+func (c *quickActionCatalog) applyConfig(cfg *config.Config) {
+	merged := builtinQuickActions()
+	index := map[string]int{}
+	for i, action := range merged {
+		index[action.ID] = i
+	}
+	for _, action := range cfg.QuickActions {
+		if i, ok := index[action.ID]; ok {
+			merged[i] = action
+			continue
+		}
+		merged = append(merged, action)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = merged
+}
+
+// List returns the catalog in stable order.
+func (c *quickActionCatalog) List() []config.QuickAction {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]config.QuickAction, len(c.actions))
+	copy(out, c.actions)
+	return out
+}
+
+// Expand renders the named action's message template with the given
+// parameters. Missing required parameters, undeclared parameters and
+// unresolved placeholders are rejected.
+func (c *quickActionCatalog) Expand(id string, params map[string]string) (string, error) {
+	c.mu.RLock()
+	var action *config.QuickAction
+	for i := range c.actions {
+		if c.actions[i].ID == id {
+			action = &c.actions[i]
+			break
+		}
+	}
+	c.mu.RUnlock()
+	if action == nil {
+		return "", util.NewInvalidInputError("Quick action %q does not exist", id)
+	}
+
+	declared := map[string]bool{}
+	message := action.MessageTemplate
+	for _, p := range action.Parameters {
+		declared[p.Name] = true
+		value, ok := params[p.Name]
+		if !ok || value == "" {
+			if p.Required {
+				return "", util.NewInvalidInputError("Quick action %q requires parameter %q", id, p.Name)
+			}
+			continue
+		}
+		message = strings.ReplaceAll(message, fmt.Sprintf("{{%v}}", p.Name), value)
+	}
+	for name := range params {
+		if !declared[name] {
+			return "", util.NewInvalidInputError("Quick action %q has no parameter %q", id, name)
+		}
+	}
+	if strings.Contains(message, "{{") {
+		return "", util.NewInvalidInputError("Quick action %q left unresolved placeholders; provide all parameters", id)
+	}
+	return message, nil
+}
+
+// builtinQuickActions returns the actions every deployment offers.
+func builtinQuickActions() []config.QuickAction {
+	return []config.QuickAction{
+		{
+			ID:          "rerun_latest_failed",
+			Title:       "Rerun latest failed run",
+			Description: "Find the most recent failed run of a pipeline, summarize why it failed and submit a fresh run with the same version and parameters.",
+			MessageTemplate: "Find the most recent failed run of the pipeline named {{pipeline_name}}, briefly explain why it failed, " +
+				"and then create a new run of the same pipeline version with the same parameters.",
+			Parameters: []config.QuickActionParameter{
+				{Name: "pipeline_name", Description: "Display name of the pipeline.", Required: true},
+			},
+		},
+		{
+			ID:          "cleanup_old_runs",
+			Title:       "Clean up old runs",
+			Description: "Review runs older than a cutoff and propose which ones to delete.",
+			MessageTemplate: "List the completed runs in this namespace that finished more than {{days}} days ago and propose which of them " +
+				"can be deleted. Only delete runs after I approve.",
+			Parameters: []config.QuickActionParameter{
+				{Name: "days", Description: "Age cutoff in days.", Required: true},
+			},
+		},
+		{
+			ID:              "namespace_health",
+			Title:           "Namespace health check",
+			Description:     "Summarize recent run health in the current namespace.",
+			MessageTemplate: "Give me a health summary of this namespace: recent run failures, flaky pipelines and anything that needs attention.",
+		},
+	}
+}
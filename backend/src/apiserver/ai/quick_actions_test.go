@@ -0,0 +1,71 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickActionCatalog_ExpandFillsTemplate(t *testing.T) {
+	catalog := newQuickActionCatalog(&config.Config{})
+
+	message, err := catalog.Expand("rerun_latest_failed", map[string]string{"pipeline_name": "training"})
+	require.NoError(t, err)
+	assert.Contains(t, message, "pipeline named training")
+	assert.NotContains(t, message, "{{")
+}
+
+func TestQuickActionCatalog_ExpandRejectsBadInvocations(t *testing.T) {
+	catalog := newQuickActionCatalog(&config.Config{})
+
+	_, err := catalog.Expand("no_such_action", nil)
+	assert.Error(t, err)
+
+	// Missing required parameter.
+	_, err = catalog.Expand("rerun_latest_failed", nil)
+	assert.Error(t, err)
+
+	// Undeclared parameter.
+	_, err = catalog.Expand("namespace_health", map[string]string{"bogus": "x"})
+	assert.Error(t, err)
+}
+
+func TestQuickActionCatalog_ConfigExtendsAndOverrides(t *testing.T) {
+	catalog := newQuickActionCatalog(&config.Config{QuickActions: []config.QuickAction{
+		{ID: "namespace_health", Title: "Custom health", MessageTemplate: "Custom health check."},
+		{ID: "team_report", Title: "Team report", MessageTemplate: "Summarize runs for team {{team}}.",
+			Parameters: []config.QuickActionParameter{{Name: "team", Required: true}}},
+	}})
+
+	message, err := catalog.Expand("namespace_health", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Custom health check.", message)
+
+	message, err = catalog.Expand("team_report", map[string]string{"team": "ml-infra"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize runs for team ml-infra.", message)
+
+	// Builtins not overridden are still present.
+	ids := []string{}
+	for _, action := range catalog.List() {
+		ids = append(ids, action.ID)
+	}
+	assert.Contains(t, ids, "rerun_latest_failed")
+	assert.Contains(t, ids, "cleanup_old_runs")
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quota enforces token budgets for the AI assistant. Without a
+// budget one user (or one namespace full of users) can burn the entire
+// provider bill; the tracker accumulates token consumption per user and
+// per namespace over daily and monthly windows and reports when a
+// configured budget is exhausted. Like the session store, the counters
+// are in-memory and reset with the apiserver.
+package quota
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget window and scope identifiers, as reported in Exceeded.
+const (
+	ScopeUser      = "user"
+	ScopeNamespace = "namespace"
+	WindowDaily    = "daily"
+	WindowMonthly  = "monthly"
+)
+
+// Limits are the configured token budgets. Zero disables a budget.
+type Limits struct {
+	// UserDailyTokens and UserMonthlyTokens cap one user's consumption
+	// per calendar day and month (UTC).
+	UserDailyTokens   int
+	UserMonthlyTokens int
+	// NamespaceDailyTokens and NamespaceMonthlyTokens cap the combined
+	// consumption of all sessions scoped to one namespace.
+	NamespaceDailyTokens   int
+	NamespaceMonthlyTokens int
+}
+
+// Exceeded describes which budget a chat turn would violate.
+type Exceeded struct {
+	// Scope is ScopeUser or ScopeNamespace; Subject is the user or
+	// namespace the budget applies to.
+	Scope   string
+	Subject string
+	// Window is WindowDaily or WindowMonthly.
+	Window string
+	// LimitTokens is the configured budget; SpentTokens what the subject
+	// already consumed in the window.
+	LimitTokens int
+	SpentTokens int
+	// ResetsAt is when the window rolls over and the budget refills.
+	ResetsAt time.Time
+}
+
+// bucket accumulates one subject's consumption in the current windows.
+// Stale windows are rolled over lazily on access instead of by a sweeper.
+type bucket struct {
+	day         string
+	dayTokens   int
+	month       string
+	monthTokens int
+}
+
+// Tracker accumulates token consumption and answers budget checks. The
+// zero value is not usable; construct it with NewTracker.
+type Tracker struct {
+	mu     sync.Mutex
+	limits Limits
+	spend  map[string]*bucket
+	// now is the clock, overridable in tests.
+	now func() time.Time
+}
+
+// NewTracker creates a tracker enforcing the given limits.
+func NewTracker(limits Limits) *Tracker {
+	return &Tracker{limits: limits, spend: map[string]*bucket{}, now: time.Now}
+}
+
+// ApplyLimits swaps the enforced limits, e.g. after a config reload.
+// Accumulated consumption is kept.
+func (t *Tracker) ApplyLimits(limits Limits) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limits = limits
+}
+
+// Record adds one model invocation's token consumption to the user's and
+// namespace's windows. An empty namespace only charges the user.
+func (t *Tracker) Record(userId, namespace string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now().UTC()
+	t.bucketFor(ScopeUser, userId, now).add(tokens)
+	if namespace != "" {
+		t.bucketFor(ScopeNamespace, namespace, now).add(tokens)
+	}
+}
+
+// Exceeded reports the first budget the user or namespace has exhausted,
+// or nil if the chat may proceed. The user's budgets are checked first
+// so the report blames the caller before their namespace.
+func (t *Tracker) Exceeded(userId, namespace string) *Exceeded {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now().UTC()
+	user := t.bucketFor(ScopeUser, userId, now)
+	if e := exceeded(ScopeUser, userId, WindowDaily, user.dayTokens, t.limits.UserDailyTokens, nextDay(now)); e != nil {
+		return e
+	}
+	if e := exceeded(ScopeUser, userId, WindowMonthly, user.monthTokens, t.limits.UserMonthlyTokens, nextMonth(now)); e != nil {
+		return e
+	}
+	if namespace == "" {
+		return nil
+	}
+	ns := t.bucketFor(ScopeNamespace, namespace, now)
+	if e := exceeded(ScopeNamespace, namespace, WindowDaily, ns.dayTokens, t.limits.NamespaceDailyTokens, nextDay(now)); e != nil {
+		return e
+	}
+	return exceeded(ScopeNamespace, namespace, WindowMonthly, ns.monthTokens, t.limits.NamespaceMonthlyTokens, nextMonth(now))
+}
+
+// bucketFor returns the subject's bucket with stale windows rolled over.
+// Callers must hold t.mu.
+func (t *Tracker) bucketFor(scope, subject string, now time.Time) *bucket {
+	key := scope + "\x00" + subject
+	b, ok := t.spend[key]
+	if !ok {
+		b = &bucket{}
+		t.spend[key] = b
+	}
+	if day := now.Format("2006-01-02"); b.day != day {
+		b.day = day
+		b.dayTokens = 0
+	}
+	if month := now.Format("2006-01"); b.month != month {
+		b.month = month
+		b.monthTokens = 0
+	}
+	return b
+}
+
+func (b *bucket) add(tokens int) {
+	b.dayTokens += tokens
+	b.monthTokens += tokens
+}
+
+// exceeded builds the violation report when a limit is set and spent.
+func exceeded(scope, subject, window string, spent, limit int, resetsAt time.Time) *Exceeded {
+	if limit <= 0 || spent < limit {
+		return nil
+	}
+	return &Exceeded{
+		Scope:       scope,
+		Subject:     subject,
+		Window:      window,
+		LimitTokens: limit,
+		SpentTokens: spent,
+		ResetsAt:    resetsAt,
+	}
+}
+
+func nextDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+func nextMonth(now time.Time) time.Time {
+	year, month, _ := now.Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trackerAt(t *Tracker, instant time.Time) *Tracker {
+	t.now = func() time.Time { return instant }
+	return t
+}
+
+func TestTracker_UserDailyBudget(t *testing.T) {
+	now := time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC)
+	tracker := trackerAt(NewTracker(Limits{UserDailyTokens: 100}), now)
+
+	tracker.Record("alice", "ns", 60)
+	assert.Nil(t, tracker.Exceeded("alice", "ns"))
+
+	tracker.Record("alice", "ns", 60)
+	e := tracker.Exceeded("alice", "ns")
+	require.NotNil(t, e)
+	assert.Equal(t, ScopeUser, e.Scope)
+	assert.Equal(t, "alice", e.Subject)
+	assert.Equal(t, WindowDaily, e.Window)
+	assert.Equal(t, 120, e.SpentTokens)
+	assert.Equal(t, 100, e.LimitTokens)
+	assert.Equal(t, time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), e.ResetsAt)
+
+	// Other users are unaffected.
+	assert.Nil(t, tracker.Exceeded("bob", "ns"))
+}
+
+func TestTracker_NamespaceBudgetPoolsUsers(t *testing.T) {
+	now := time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC)
+	tracker := trackerAt(NewTracker(Limits{NamespaceMonthlyTokens: 100}), now)
+
+	tracker.Record("alice", "team-a", 70)
+	tracker.Record("bob", "team-a", 40)
+
+	e := tracker.Exceeded("carol", "team-a")
+	require.NotNil(t, e)
+	assert.Equal(t, ScopeNamespace, e.Scope)
+	assert.Equal(t, "team-a", e.Subject)
+	assert.Equal(t, WindowMonthly, e.Window)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), e.ResetsAt)
+
+	// A session with no namespace is only held to the user budgets.
+	assert.Nil(t, tracker.Exceeded("alice", ""))
+	assert.Nil(t, tracker.Exceeded("alice", "team-b"))
+}
+
+func TestTracker_WindowsRollOver(t *testing.T) {
+	day1 := time.Date(2026, 8, 30, 23, 0, 0, 0, time.UTC)
+	tracker := trackerAt(NewTracker(Limits{UserDailyTokens: 100, UserMonthlyTokens: 150}), day1)
+	tracker.Record("alice", "", 120)
+	require.NotNil(t, tracker.Exceeded("alice", ""))
+
+	// The next day refills the daily budget but keeps the monthly count.
+	tracker.now = func() time.Time { return day1.AddDate(0, 0, 1) }
+	assert.Nil(t, tracker.Exceeded("alice", ""))
+	tracker.Record("alice", "", 40)
+	e := tracker.Exceeded("alice", "")
+	require.NotNil(t, e)
+	assert.Equal(t, WindowMonthly, e.Window)
+
+	// The next month refills everything.
+	tracker.now = func() time.Time { return day1.AddDate(0, 1, 0) }
+	assert.Nil(t, tracker.Exceeded("alice", ""))
+}
+
+func TestTracker_ApplyLimitsKeepsSpend(t *testing.T) {
+	now := time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC)
+	tracker := trackerAt(NewTracker(Limits{}), now)
+	tracker.Record("alice", "", 500)
+	assert.Nil(t, tracker.Exceeded("alice", ""))
+
+	tracker.ApplyLimits(Limits{UserDailyTokens: 100})
+	require.NotNil(t, tracker.Exceeded("alice", ""))
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit enforces the assistant's per-caller request
+// budgets. Serving surfaces construct a Limiter per endpoint and inject
+// it, so deployments can choose between per-replica in-memory counters
+// and Redis-shared ones without the handlers knowing the difference.
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+)
+
+// Limiter answers whether a caller may make another request. A
+// non-positive budget disables limiting.
+type Limiter interface {
+	// Allow reports whether key may make another request now and, when
+	// it may not, how long until it is worth retrying.
+	Allow(key string) (bool, time.Duration)
+	// Configure swaps the budget and window, e.g. after a config
+	// reload. A non-positive window keeps the previous one.
+	Configure(limit int, window time.Duration)
+}
+
+// Scope names the endpoint a limiter guards; it selects the configured
+// budget and keeps backends' counters apart.
+type Scope string
+
+const (
+	// ScopeChat is the per-user budget of the chat endpoints.
+	ScopeChat Scope = "chat"
+	// ScopeMCP is the per-client budget of the MCP endpoint.
+	ScopeMCP Scope = "mcp"
+)
+
+// FromConfig constructs the configured backend for one endpoint's
+// budget. The backend choice is fixed for the limiter's lifetime;
+// budget and window changes arrive through Configure.
+func FromConfig(cfg *config.Config, scope Scope) Limiter {
+	limit, window := cfg.Limits.ChatRateLimit, cfg.Limits.ChatRateWindow
+	if scope == ScopeMCP {
+		limit, window = cfg.Limits.MCPRateLimit, cfg.Limits.MCPRateWindow
+	}
+	if cfg.RateLimiter.Backend == "redis" {
+		return NewRedis(cfg.RateLimiter.RedisAddress, cfg.RateLimiter.RedisPassword, scope, limit, window)
+	}
+	return NewSlidingWindow(limit, window)
+}
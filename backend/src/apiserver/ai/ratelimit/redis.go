@@ -0,0 +1,243 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// redisTimeout bounds every dial, read and write against Redis; rate
+// limiting sits in front of each request and must not stall them.
+const redisTimeout = 2 * time.Second
+
+// Redis counts requests in a shared Redis server, so the budget holds
+// across apiserver replicas. It uses fixed windows — one INCR-ed key
+// per caller and window index — which needs no Lua and stays a single
+// round trip per request. When Redis is unreachable the limiter fails
+// open: availability of the assistant wins over strict limiting, and
+// the per-replica admission controls still apply.
+//
+// Only the handful of commands needed here are spoken, over a single
+// pooled connection; that keeps the backend dependency-free.
+type Redis struct {
+	address  string
+	password string
+	scope    Scope
+
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	conn   *redisConn
+	// now is the clock, overridable in tests.
+	now func() time.Time
+}
+
+// NewRedis creates a limiter backed by the Redis server at address.
+// The connection is established lazily on first use.
+func NewRedis(address, password string, scope Scope, limit int, window time.Duration) *Redis {
+	if window <= 0 {
+		window = defaultWindow
+	}
+	return &Redis{
+		address:  address,
+		password: password,
+		scope:    scope,
+		limit:    limit,
+		window:   window,
+		now:      time.Now,
+	}
+}
+
+// Configure swaps the budget and window.
+func (l *Redis) Configure(limit int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	if window > 0 {
+		l.window = window
+	}
+}
+
+// Allow reports whether key may make another request now.
+func (l *Redis) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit <= 0 {
+		return true, 0
+	}
+	now := l.now()
+	windowIndex := now.UnixMilli() / l.window.Milliseconds()
+	counter := fmt.Sprintf("kfp:ai:ratelimit:%v:%v:%v", l.scope, key, windowIndex)
+	count, err := l.increment(counter)
+	if err != nil {
+		glog.Warningf("The Redis rate limiter failed open for %v: %v", key, err)
+		l.close()
+		return true, 0
+	}
+	if count > int64(l.limit) {
+		// The fixed window frees up at its boundary.
+		windowEnd := time.UnixMilli((windowIndex + 1) * l.window.Milliseconds())
+		return false, windowEnd.Sub(now)
+	}
+	return true, 0
+}
+
+// increment bumps the window's counter, arranging for it to expire.
+// Callers must hold l.mu.
+func (l *Redis) increment(counter string) (int64, error) {
+	conn, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	count, err := conn.commandInt("INCR", counter)
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		// First hit in the window: bound the key's lifetime. Twice the
+		// window leaves slack for clock skew between replicas.
+		if _, err := conn.commandInt("PEXPIRE", counter, strconv.FormatInt(2*l.window.Milliseconds(), 10)); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// connect returns the pooled connection, dialing and authenticating on
+// first use. Callers must hold l.mu.
+func (l *Redis) connect() (*redisConn, error) {
+	if l.conn != nil {
+		return l.conn, nil
+	}
+	netConn, err := net.DialTimeout("tcp", l.address, redisTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to Redis at %v", l.address)
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if l.password != "" {
+		if err := conn.commandOK("AUTH", l.password); err != nil {
+			conn.close()
+			return nil, errors.Wrap(err, "failed to authenticate against Redis")
+		}
+	}
+	l.conn = conn
+	return conn, nil
+}
+
+// close drops the pooled connection so the next request redials.
+// Callers must hold l.mu.
+func (l *Redis) close() {
+	if l.conn != nil {
+		l.conn.close()
+		l.conn = nil
+	}
+}
+
+// redisConn speaks the Redis serialization protocol over one TCP
+// connection.
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (c *redisConn) close() {
+	_ = c.conn.Close()
+}
+
+// commandInt runs a command expecting an integer reply.
+func (c *redisConn) commandInt(args ...string) (int64, error) {
+	reply, err := c.command(args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.ParseInt(reply, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("unexpected Redis reply %q to %v", reply, args[0])
+	}
+	return count, nil
+}
+
+// commandOK runs a command expecting a simple +OK reply.
+func (c *redisConn) commandOK(args ...string) error {
+	reply, err := c.command(args...)
+	if err != nil {
+		return err
+	}
+	if reply != "OK" {
+		return errors.Errorf("unexpected Redis reply %q to %v", reply, args[0])
+	}
+	return nil
+}
+
+// command writes one command as a RESP array of bulk strings and reads
+// its reply.
+func (c *redisConn) command(args ...string) (string, error) {
+	deadline := time.Now().Add(redisTimeout)
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return "", errors.Wrap(err, "failed to set the Redis connection deadline")
+	}
+	var request strings.Builder
+	fmt.Fprintf(&request, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&request, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(request.String())); err != nil {
+		return "", errors.Wrap(err, "failed to write to Redis")
+	}
+	return c.readReply()
+}
+
+// readReply parses the reply types the limiter's commands produce:
+// simple strings, errors, integers and bulk strings.
+func (c *redisConn) readReply() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read from Redis")
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("empty Redis reply")
+	}
+	body := line[1:]
+	switch line[0] {
+	case '+', ':':
+		return body, nil
+	case '-':
+		return "", errors.Errorf("Redis error: %v", body)
+	case '$':
+		length, err := strconv.Atoi(body)
+		if err != nil || length < 0 {
+			return "", errors.Errorf("unexpected Redis bulk length %q", body)
+		}
+		// The body plus its trailing CRLF may span several reads.
+		buf := make([]byte, length+2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return "", errors.Wrap(err, "failed to read a Redis bulk reply")
+		}
+		return string(buf[:length]), nil
+	default:
+		return "", errors.Errorf("unexpected Redis reply %q", line)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is a minimal in-process Redis that understands just the
+// commands the limiter issues.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	counters map[string]int64
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := &fakeRedis{listener: listener, password: password, counters: map[string]int64{}}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeRedis) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) commandLog() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.commands...)
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		var reply string
+		switch args[0] {
+		case "AUTH":
+			if args[1] == s.password {
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "INCR":
+			s.counters[args[1]]++
+			reply = ":" + strconv.FormatInt(s.counters[args[1]], 10) + "\r\n"
+		case "PEXPIRE":
+			reply = ":1\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand parses one RESP array of bulk strings.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimRight(header[1:], "\r\n"))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		length, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimRight(length[1:], "\r\n"))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := reader.Read(buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+func TestReadReply_ReadsFragmentedBulkStrings(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// The bulk body arrives split across writes; the reply must still
+	// come out whole.
+	go func() {
+		server.Write([]byte("$11\r\nhello"))
+		time.Sleep(10 * time.Millisecond)
+		server.Write([]byte(" world\r\n"))
+	}()
+
+	c := &redisConn{conn: client, reader: bufio.NewReader(client)}
+	reply, err := c.readReply()
+	require.Nil(t, err)
+	assert.Equal(t, "hello world", reply)
+}
+
+func TestRedis_DeniesOverBudget(t *testing.T) {
+	server := newFakeRedis(t, "")
+	limiter := NewRedis(server.address(), "", ScopeChat, 2, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, retryAfter := limiter.Allow("a@example.com")
+	assert.False(t, ok)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, retryAfter, time.Minute)
+
+	// Other keys have their own counter.
+	ok, _ = limiter.Allow("b@example.com")
+	assert.True(t, ok)
+}
+
+func TestRedis_NewWindowResetsTheBudget(t *testing.T) {
+	server := newFakeRedis(t, "")
+	now := time.Now()
+	limiter := NewRedis(server.address(), "", ScopeChat, 1, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+}
+
+func TestRedis_ExpiresCounters(t *testing.T) {
+	server := newFakeRedis(t, "")
+	limiter := NewRedis(server.address(), "", ScopeMCP, 5, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+
+	// Only the window's first hit sets the expiry.
+	var expires []string
+	for _, command := range server.commandLog() {
+		if strings.HasPrefix(command, "PEXPIRE ") {
+			expires = append(expires, command)
+		}
+	}
+	require.Len(t, expires, 1)
+	assert.Contains(t, expires[0], "kfp:ai:ratelimit:mcp:a@example.com:")
+}
+
+func TestRedis_Authenticates(t *testing.T) {
+	server := newFakeRedis(t, "sesame")
+	limiter := NewRedis(server.address(), "sesame", ScopeChat, 1, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.False(t, ok)
+	require.NotEmpty(t, server.commandLog())
+	assert.Equal(t, "AUTH sesame", server.commandLog()[0])
+}
+
+func TestRedis_FailsOpenWhenUnreachable(t *testing.T) {
+	server := newFakeRedis(t, "")
+	address := server.address()
+	server.listener.Close()
+
+	limiter := NewRedis(address, "", ScopeChat, 1, time.Minute)
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.Allow("a@example.com")
+		assert.True(t, ok)
+	}
+}
+
+func TestRedis_FailsOpenOnBadCredentials(t *testing.T) {
+	server := newFakeRedis(t, "sesame")
+	limiter := NewRedis(server.address(), "wrong", ScopeChat, 1, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+}
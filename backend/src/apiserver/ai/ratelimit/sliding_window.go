@@ -0,0 +1,84 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultWindow applies when a limiter is constructed or configured
+// with a non-positive window, e.g. from a zero-valued test config.
+const defaultWindow = time.Minute
+
+// SlidingWindow counts requests per key over a sliding window, in
+// memory. Each replica counts for itself, so with N replicas a caller
+// effectively gets N times the budget; deployments that need the budget
+// to hold globally use the Redis backend.
+type SlidingWindow struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	// requestTimes tracks recent request timestamps per key.
+	requestTimes map[string][]time.Time
+	// now is the clock, overridable in tests.
+	now func() time.Time
+}
+
+// NewSlidingWindow creates an in-memory limiter.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	if window <= 0 {
+		window = defaultWindow
+	}
+	return &SlidingWindow{
+		limit:        limit,
+		window:       window,
+		requestTimes: map[string][]time.Time{},
+		now:          time.Now,
+	}
+}
+
+// Configure swaps the budget and window.
+func (l *SlidingWindow) Configure(limit int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	if window > 0 {
+		l.window = window
+	}
+}
+
+// Allow reports whether key may make another request now.
+func (l *SlidingWindow) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit <= 0 {
+		return true, 0
+	}
+	now := l.now()
+	cutoff := now.Add(-l.window)
+	recent := l.requestTimes[key][:0]
+	for _, t := range l.requestTimes[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.limit {
+		l.requestTimes[key] = recent
+		return false, recent[0].Sub(cutoff)
+	}
+	l.requestTimes[key] = append(recent, now)
+	return true, 0
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlidingWindow_DeniesOverBudget(t *testing.T) {
+	limiter := NewSlidingWindow(2, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, retryAfter := limiter.Allow("a@example.com")
+	assert.False(t, ok)
+	assert.Greater(t, retryAfter, time.Duration(0))
+
+	// Other keys have their own budget.
+	ok, _ = limiter.Allow("b@example.com")
+	assert.True(t, ok)
+}
+
+func TestSlidingWindow_WindowSlides(t *testing.T) {
+	now := time.Now()
+	limiter := NewSlidingWindow(1, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute + time.Second)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+}
+
+func TestSlidingWindow_NonPositiveLimitDisables(t *testing.T) {
+	limiter := NewSlidingWindow(0, time.Minute)
+	for i := 0; i < 100; i++ {
+		ok, _ := limiter.Allow("a@example.com")
+		assert.True(t, ok)
+	}
+}
+
+func TestSlidingWindow_Configure(t *testing.T) {
+	limiter := NewSlidingWindow(1, time.Minute)
+
+	ok, _ := limiter.Allow("a@example.com")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.False(t, ok)
+
+	limiter.Configure(2, time.Minute)
+	ok, _ = limiter.Allow("a@example.com")
+	assert.True(t, ok)
+
+	// A non-positive window keeps the previous one.
+	limiter.Configure(2, 0)
+	assert.Equal(t, time.Minute, limiter.window)
+}
+
+func TestFromConfig_PicksBackendAndScope(t *testing.T) {
+	cfg := &config.Config{Limits: config.Limits{
+		ChatRateLimit:  10,
+		ChatRateWindow: time.Minute,
+		MCPRateLimit:   20,
+		MCPRateWindow:  time.Hour,
+	}}
+
+	chat := FromConfig(cfg, ScopeChat)
+	assert.IsType(t, &SlidingWindow{}, chat)
+	assert.Equal(t, 10, chat.(*SlidingWindow).limit)
+	assert.Equal(t, time.Minute, chat.(*SlidingWindow).window)
+
+	mcp := FromConfig(cfg, ScopeMCP)
+	assert.Equal(t, 20, mcp.(*SlidingWindow).limit)
+	assert.Equal(t, time.Hour, mcp.(*SlidingWindow).window)
+
+	cfg.RateLimiter = config.RateLimiter{Backend: "redis", RedisAddress: "redis:6379"}
+	assert.IsType(t, &Redis{}, FromConfig(cfg, ScopeChat))
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readOnlyFixture() (*AIServer, *SessionManager) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, readOnly: true}
+	registry := tool.NewRegistry()
+	registry.StripMutating()
+	return &AIServer{sessionManager: m, registry: registry, readOnly: true}, m
+}
+
+func TestRegistry_StripMutatingLeavesOnlyReadOnlyTools(t *testing.T) {
+	registry := tool.NewRegistry()
+	require.NotEmpty(t, registry.ListForMode(tool.ModeAsk))
+	registry.StripMutating()
+
+	// Even Agent mode resolves no mutating tool anymore.
+	for _, tl := range registry.ListForMode(tool.ModeAgent) {
+		assert.False(t, tl.Mutating(), "tool %v survived StripMutating", tl.Name())
+	}
+	assert.Nil(t, registry.Get("delete_run"))
+	assert.NotNil(t, registry.Get("list_runs"))
+}
+
+func TestReadOnly_SessionsAreForcedIntoAskMode(t *testing.T) {
+	_, m := readOnlyFixture()
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAgent, "")
+	require.NoError(t, err)
+	assert.Equal(t, tool.ModeAsk, session.Mode)
+}
+
+func TestReadOnly_SwitchToAgentModeIsRejected(t *testing.T) {
+	server, m := readOnlyFixture()
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	_, err = server.SwitchMode(session.ID, "alice", "agent")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+	assert.Equal(t, tool.ModeAsk, session.Mode)
+
+	// Switching to the mode the session is already in stays allowed.
+	metadata, err := server.SwitchMode(session.ID, "alice", "ask")
+	require.NoError(t, err)
+	assert.Equal(t, "ask", metadata.Mode)
+}
+
+func TestReadOnly_ApprovalsAreRejected(t *testing.T) {
+	server, m := readOnlyFixture()
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	err = server.ApproveToolCall(session.ID, "alice", "tc1", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+)
+
+// runWatchInterval is how often referenced runs are polled for state
+// transitions. Run state lives in the database, so polling is cheap and
+// avoids a Kubernetes informer per session.
+const runWatchInterval = 15 * time.Second
+
+// runWatcher pushes resource_update events to open sessions when a run
+// the conversation referenced changes state (e.g. Running to Failed),
+// letting the assistant comment on completions proactively.
+type runWatcher struct {
+	resourceManager *resource.ResourceManager
+	sessionManager  *SessionManager
+}
+
+func newRunWatcher(resourceManager *resource.ResourceManager, sessionManager *SessionManager) *runWatcher {
+	return &runWatcher{
+		resourceManager: resourceManager,
+		sessionManager:  sessionManager,
+	}
+}
+
+func (w *runWatcher) watchLoop() {
+	ticker := time.NewTicker(runWatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		w.poll()
+	}
+}
+
+// poll checks every streaming session's referenced runs for state
+// transitions. RBAC was already enforced when the run entered the
+// conversation through a tool call, so the lookup itself runs with
+// server identity.
+func (w *runWatcher) poll() {
+	for _, session := range w.sessionManager.streamingSessions() {
+		for runId, lastState := range session.referencedRunsSnapshot() {
+			run, err := w.resourceManager.GetRun(runId)
+			if err != nil {
+				glog.V(4).Infof("AI run watcher failed to fetch run %v: %v", runId, err)
+				session.untrackRun(runId)
+				continue
+			}
+			state := string(run.State)
+			if state == lastState {
+				continue
+			}
+			session.updateRunState(runId, state)
+			if lastState == "" {
+				// First observation establishes the baseline silently.
+				continue
+			}
+			session.emitAsync(Event{Type: EventTypeResourceUpdate, Payload: &ResourceUpdatePayload{
+				ResourceType: "run",
+				ResourceID:   runId,
+				DisplayName:  run.DisplayName,
+				OldState:     lastState,
+				NewState:     state,
+			}})
+			if isTerminalRunState(run.State) {
+				session.untrackRun(runId)
+			}
+		}
+	}
+}
+
+func isTerminalRunState(state model.RuntimeState) bool {
+	switch state {
+	case model.RuntimeStateSucceeded, model.RuntimeStateFailed, model.RuntimeStateCanceled, model.RuntimeStateSkipped,
+		model.RuntimeStateSucceededV1, model.RuntimeStateFailedV1, model.RuntimeStateErrorV1, model.RuntimeStateSkippedV1:
+		return true
+	default:
+		return false
+	}
+}
+
+// trackFromToolCall registers the runs a tool call touched so the
+// watcher can report their state transitions. Run IDs are taken from the
+// call arguments and from run_id fields in the result payload (which
+// covers runs returned by listings and newly created runs).
+func trackFromToolCall(session *Session, args map[string]interface{}, result *tool.Result) {
+	if runId, ok := args["run_id"].(string); ok && runId != "" {
+		session.trackRun(runId)
+	}
+	if rawIds, ok := args["run_ids"].([]interface{}); ok {
+		for _, raw := range rawIds {
+			if runId, ok := raw.(string); ok && runId != "" {
+				session.trackRun(runId)
+			}
+		}
+	}
+	if result == nil || result.IsError {
+		return
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(result.Content), &decoded); err != nil {
+		return
+	}
+	for _, runId := range collectRunIds(decoded, nil) {
+		session.trackRun(runId)
+	}
+}
+
+// collectRunIds walks a decoded JSON value and gathers all string values
+// keyed "run_id".
+func collectRunIds(v interface{}, acc []string) []string {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		if runId, ok := typed["run_id"].(string); ok && runId != "" {
+			acc = append(acc, runId)
+		}
+		for _, child := range typed {
+			acc = collectRunIds(child, acc)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			acc = collectRunIds(child, acc)
+		}
+	}
+	return acc
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackFromToolCall_ArgsAndResult(t *testing.T) {
+	session := &Session{}
+	args := map[string]interface{}{
+		"run_id":  "run-1",
+		"run_ids": []interface{}{"run-2", "run-3"},
+	}
+	result := &tool.Result{Content: `{"runs":[{"run_id":"run-4"},{"run_id":"run-5"}],"total_size":2}`}
+	trackFromToolCall(session, args, result)
+
+	tracked := session.referencedRunsSnapshot()
+	assert.Len(t, tracked, 5)
+	for _, id := range []string{"run-1", "run-2", "run-3", "run-4", "run-5"} {
+		_, ok := tracked[id]
+		assert.True(t, ok, "expected %v to be tracked", id)
+	}
+}
+
+func TestTrackFromToolCall_ErrorResultIgnored(t *testing.T) {
+	session := &Session{}
+	result := &tool.Result{Content: `{"runs":[{"run_id":"run-1"}]}`, IsError: true}
+	trackFromToolCall(session, map[string]interface{}{}, result)
+	assert.Empty(t, session.referencedRunsSnapshot())
+}
+
+func TestTrackRun_PreservesObservedState(t *testing.T) {
+	session := &Session{}
+	session.trackRun("run-1")
+	session.updateRunState("run-1", "RUNNING")
+	// Re-tracking the same run must not reset the baseline state.
+	session.trackRun("run-1")
+	assert.Equal(t, "RUNNING", session.referencedRunsSnapshot()["run-1"])
+}
+
+func TestIsTerminalRunState(t *testing.T) {
+	assert.True(t, isTerminalRunState("SUCCEEDED"))
+	assert.True(t, isTerminalRunState("FAILED"))
+	assert.False(t, isTerminalRunState("RUNNING"))
+	assert.False(t, isTerminalRunState("PENDING"))
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// sandboxPipelineSpec is the sample pipeline the sandbox is seeded
+// with; it only needs to look plausible in listings and detail views.
+const sandboxPipelineSpec = `pipelineInfo:
+  name: demo-training
+components: {}
+deploymentSpec: {}
+root:
+  dag:
+    tasks: {}
+`
+
+// NewSandboxResourceManager builds the resource manager that sandboxed
+// deployments serve every tool against: an in-memory fake backed by no
+// real cluster, seeded with a sample pipeline, experiment and runs.
+// Mutating tools operate on the fake data only, so Agent mode can be
+// tried — and demoed by admins — without touching real workloads.
+// Nothing written to it survives a restart; the seed is rebuilt.
+func NewSandboxResourceManager() *resource.ResourceManager {
+	clients, err := resource.NewFakeClientManager(util.NewRealTime(), util.NewUUIDGenerator())
+	if err != nil {
+		// The fake is memory-backed; this cannot fail outside of a bug.
+		glog.Fatalf("Failed to build the AI sandbox client manager: %v", err)
+	}
+	seedSandbox(clients)
+	return resource.NewResourceManager(clients, &resource.ResourceManagerOptions{CollectMetrics: false})
+}
+
+// seedSandbox populates the sandbox with sample data: one pipeline with
+// a version, one experiment, and runs in the states users typically ask
+// about. Seeding failures are logged and skipped — an emptier sandbox
+// is still a working sandbox.
+func seedSandbox(clients *resource.FakeClientManager) {
+	now := clients.Time().Now().Unix()
+	pipeline, version, err := clients.PipelineStore().CreatePipelineAndPipelineVersion(
+		&model.Pipeline{
+			Name:        "demo-training",
+			DisplayName: "Demo training pipeline",
+			Description: model.LargeText("A sample pipeline seeded by the assistant sandbox."),
+		},
+		&model.PipelineVersion{
+			Name:         "demo-training-v1",
+			DisplayName:  "Demo training pipeline v1",
+			PipelineSpec: model.LargeText(sandboxPipelineSpec),
+		})
+	if err != nil {
+		glog.Errorf("Failed to seed the AI sandbox pipeline: %v", err)
+	} else {
+		// Creation leaves both rows in the transient creating status; the
+		// seed has no upload step, so they are marked ready directly.
+		if err := clients.PipelineStore().UpdatePipelineStatus(pipeline.UUID, model.PipelineReady); err != nil {
+			glog.Errorf("Failed to mark the AI sandbox pipeline ready: %v", err)
+		}
+		if err := clients.PipelineStore().UpdatePipelineVersionStatus(version.UUID, model.PipelineVersionReady); err != nil {
+			glog.Errorf("Failed to mark the AI sandbox pipeline version ready: %v", err)
+		}
+	}
+	experiment, err := clients.ExperimentStore().CreateExperiment(&model.Experiment{
+		Name:         "sandbox-experiments",
+		Description:  "Sample experiment seeded by the assistant sandbox.",
+		StorageState: model.StorageStateAvailable,
+	})
+	if err != nil {
+		glog.Errorf("Failed to seed the AI sandbox experiment: %v", err)
+		return
+	}
+	runs := []struct {
+		name       string
+		state      model.RuntimeState
+		conditions string
+		ageInSec   int64
+	}{
+		{"demo-training-succeeded", model.RuntimeStateSucceeded, "Succeeded", 3600},
+		{"demo-training-failed", model.RuntimeStateFailed, "Failed", 1800},
+		{"demo-training-running", model.RuntimeStateRunning, "Running", 300},
+	}
+	for _, run := range runs {
+		id, err := clients.UUID().NewRandom()
+		if err != nil {
+			glog.Errorf("Failed to seed AI sandbox run %v: %v", run.name, err)
+			continue
+		}
+		seeded := &model.Run{
+			UUID:         id.String(),
+			ExperimentId: experiment.UUID,
+			K8SName:      run.name,
+			DisplayName:  run.name,
+			StorageState: model.StorageStateAvailable,
+			RunDetails: model.RunDetails{
+				CreatedAtInSec:   now - run.ageInSec,
+				ScheduledAtInSec: now - run.ageInSec,
+				Conditions:       run.conditions,
+				State:            run.state,
+			},
+		}
+		if pipeline != nil && version != nil {
+			seeded.PipelineSpec = model.PipelineSpec{
+				PipelineId:        pipeline.UUID,
+				PipelineVersionId: version.UUID,
+				PipelineName:      pipeline.Name,
+			}
+		}
+		if _, err := clients.RunStore().CreateRun(seeded); err != nil {
+			glog.Errorf("Failed to seed AI sandbox run %v: %v", run.name, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sandboxFixture(t *testing.T) (*AIServer, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		sandbox:        NewSandboxResourceManager(),
+	}
+	session, err := m.GetOrCreate("", "alice", "", tool.ModeAsk, "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestSandbox_ToolsRunAgainstTheSeededData(t *testing.T) {
+	server, session := sandboxFixture(t)
+	emit := func(Event) error { return nil }
+
+	result := server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc1", Name: "list_runs", ArgumentsJSON: "{}"}, emit)
+
+	require.False(t, result.IsError, result.Content)
+	// The seed covers the states users typically ask about.
+	assert.Contains(t, result.Content, "demo-training-succeeded")
+	assert.Contains(t, result.Content, "demo-training-failed")
+	assert.Contains(t, result.Content, "demo-training-running")
+
+	result = server.executeToolCall(context.Background(), session,
+		provider.ToolCall{ID: "tc2", Name: "list_pipelines", ArgumentsJSON: "{}"}, emit)
+	require.False(t, result.IsError, result.Content)
+	assert.Contains(t, result.Content, "demo-training")
+}
+
+func TestSandbox_SessionMetadataIsLabeled(t *testing.T) {
+	server, session := sandboxFixture(t)
+
+	metadata, err := server.SwitchMode(session.ID, "alice", "agent")
+	require.NoError(t, err)
+	assert.True(t, metadata.Sandbox)
+
+	// A deployment without the sandbox wired stays unlabeled.
+	plain, plainSession := modeSwitchFixture(t)
+	metadata, err = plain.SwitchMode(plainSession.ID, "alice", "agent")
+	require.NoError(t, err)
+	assert.False(t, metadata.Sandbox)
+}
@@ -0,0 +1,1197 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metric variables. Please prefix the metric names with ai_session_manager_.
+var (
+	sessionLruEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_lru_evictions",
+		Help: "The total number of sessions evicted because the session cap was exceeded",
+	})
+
+	sessionExpiredCleanups = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_expired_cleanups",
+		Help: "The total number of sessions removed after idling past the session timeout",
+	})
+
+	sessionMessageTrims = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_message_trims",
+		Help: "The total number of messages dropped from session histories over the per-session limit",
+	})
+
+	sessionCleanupDeniedConfirmations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_cleanup_denied_confirmations",
+		Help: "The total number of pending confirmations denied because their session was cleaned up",
+	})
+
+	sessionPerUserEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_per_user_evictions",
+		Help: "The total number of sessions evicted because their user exceeded the per-user session cap",
+	})
+
+	sessionUserBudgetTrims = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ai_session_manager_user_budget_trims",
+		Help: "The total number of messages dropped because their user exceeded the per-user message budget",
+	})
+)
+
+const (
+	sessionCleanupInterval = time.Minute
+	// maxLostApprovalsPerUser caps the undelivered lost-approval notices
+	// kept per user; older ones are dropped beyond that.
+	maxLostApprovalsPerUser = 20
+	// sessionExpiryWarningLead is how far ahead of the absolute lifetime
+	// a session_expiring warning is pushed to an open stream.
+	sessionExpiryWarningLead = 5 * time.Minute
+)
+
+// pendingConfirmation tracks a mutating tool call waiting for the user's
+// decision. The payload is kept so a reconnecting client can re-render
+// the approval card while the turn is still blocked on the decision.
+type pendingConfirmation struct {
+	toolCall provider.ToolCall
+	payload  *ConfirmationRequestPayload
+	decision chan bool
+}
+
+// Session is one chat conversation. All mutable state is guarded by mu.
+type Session struct {
+	ID        string
+	UserID    string
+	Namespace string
+	Mode      tool.Mode
+	CreatedAt time.Time
+	// Timezone is the IANA timezone name the user requested; location is
+	// its resolved form. Both default to UTC.
+	Timezone string
+	location *time.Location
+
+	mu           sync.Mutex
+	updatedAt    time.Time
+	messages     []provider.Message
+	confirmation *pendingConfirmation
+	// active serializes turns: only one StreamChat may run per session.
+	active bool
+	// emitFn writes to the client stream while a turn is running.
+	emitFn func(Event) error
+	// cancelTurn aborts the running turn's context when the cancel
+	// endpoint is hit; nil between turns.
+	cancelTurn context.CancelFunc
+	// events numbers and buffers the session's stream events so a
+	// briefly disconnected client can replay what it missed; created on
+	// first use by eventLog.
+	events *eventBuffer
+	// referencedRuns maps runs the conversation touched to their last
+	// observed state, for the run watcher.
+	referencedRuns map[string]string
+	// actions is the bounded audit trace of tool calls this session
+	// executed, rendered by the explain_last_actions tool.
+	actions []ActionRecord
+	// usage accumulates the token consumption reported by the provider
+	// over the session's lifetime.
+	usage provider.Usage
+	// maxMessages is the history cap inherited from the session manager.
+	maxMessages int
+	// expiryWarned dedups the session_expiring warning.
+	expiryWarned bool
+	// turnCheckpointAt is when the running turn last checkpointed its
+	// state to the store; zero between turns.
+	turnCheckpointAt time.Time
+	// interruptedTurnAt is the checkpoint time of a turn that died with
+	// a previous process, carried over from the restored snapshot so the
+	// client can offer to continue; zero when no turn was interrupted.
+	interruptedTurnAt time.Time
+	// telemetryOptOut disables usage analytics and transcript persistence
+	// for this session; operational metrics and budget enforcement still
+	// apply. Once set it sticks for the session's lifetime.
+	telemetryOptOut bool
+}
+
+// telemetryDisabled reports whether the session opted out of telemetry.
+func (s *Session) telemetryDisabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.telemetryOptOut
+}
+
+// setEmitter installs the stream writer for the duration of a turn.
+func (s *Session) setEmitter(emit func(Event) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitFn = emit
+}
+
+func (s *Session) clearEmitter() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitFn = nil
+}
+
+// setCancel installs the running turn's cancel function.
+func (s *Session) setCancel(cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelTurn = cancel
+}
+
+func (s *Session) clearCancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelTurn = nil
+}
+
+// cancelActiveTurn aborts the in-flight turn server-side, cancelling the
+// provider request and any running tool executions through the turn's
+// context. It reports whether a turn was actually running.
+func (s *Session) cancelActiveTurn() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelTurn == nil {
+		return false
+	}
+	s.cancelTurn()
+	s.cancelTurn = nil
+	return true
+}
+
+// emitAsync writes an event to the client stream if one is open. Events
+// arriving between turns are dropped.
+func (s *Session) emitAsync(event Event) {
+	s.mu.Lock()
+	emit := s.emitFn
+	s.mu.Unlock()
+	if emit == nil {
+		return
+	}
+	if err := emit(event); err != nil {
+		glog.V(4).Infof("Failed to push %v event to session %v: %v", event.Type, s.ID, err)
+	}
+}
+
+// trackRun registers a run for state change notifications. Tracking is
+// idempotent and preserves the last observed state.
+func (s *Session) trackRun(runId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.referencedRuns == nil {
+		s.referencedRuns = map[string]string{}
+	}
+	if _, ok := s.referencedRuns[runId]; !ok {
+		s.referencedRuns[runId] = ""
+	}
+}
+
+func (s *Session) untrackRun(runId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.referencedRuns, runId)
+}
+
+func (s *Session) updateRunState(runId, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.referencedRuns == nil {
+		return
+	}
+	s.referencedRuns[runId] = state
+}
+
+func (s *Session) referencedRunsSnapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string, len(s.referencedRuns))
+	for id, state := range s.referencedRuns {
+		out[id] = state
+	}
+	return out
+}
+
+// Messages returns a snapshot of the conversation history.
+func (s *Session) Messages() []provider.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]provider.Message, len(s.messages))
+	copy(out, s.messages)
+	return out
+}
+
+// AppendMessage adds a message to the history, trimming the oldest
+// entries beyond the session's message limit.
+func (s *Session) AppendMessage(m provider.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, m)
+	limit := s.maxMessages
+	if limit <= 0 {
+		limit = config.DefaultMaxMessagesPerSession
+	}
+	if trimmed := len(s.messages) - limit; trimmed > 0 {
+		// A leading rolling-summary note survives the trim, so compacted
+		// context is not lost to the count cap mid-turn.
+		if strings.HasPrefix(s.messages[0].Content, summaryNotePrefix) && 1+trimmed < len(s.messages) {
+			s.messages = append(s.messages[:1], s.messages[1+trimmed:]...)
+		} else {
+			s.messages = s.messages[trimmed:]
+		}
+		sessionMessageTrims.Add(float64(trimmed))
+		glog.V(2).Infof("Trimmed %v message(s) from AI session %v of user %v", trimmed, s.ID, s.UserID)
+	}
+	s.updatedAt = time.Now()
+}
+
+// addUsage accumulates one model invocation's token consumption.
+func (s *Session) addUsage(u provider.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage.InputTokens += u.InputTokens
+	s.usage.OutputTokens += u.OutputTokens
+}
+
+// usageSnapshot returns the session's accumulated token consumption.
+func (s *Session) usageSnapshot() provider.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage
+}
+
+// messageCount returns the stored history length.
+func (s *Session) messageCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.messages)
+}
+
+// trimOldest drops up to n of the oldest messages and returns how many
+// were dropped. Sessions blocked in a turn are skipped so an in-flight
+// model call never loses context mid-conversation.
+func (s *Session) trimOldest(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active || n <= 0 {
+		return 0
+	}
+	if n > len(s.messages) {
+		n = len(s.messages)
+	}
+	s.messages = s.messages[n:]
+	return n
+}
+
+// switchMode changes the session mode between turns. It returns the
+// previous mode and whether it actually changed; a session with a turn
+// in progress cannot switch.
+func (s *Session) switchMode(mode tool.Mode) (tool.Mode, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.Mode
+	if s.active {
+		return previous, false, util.NewFailedPreconditionError(
+			errors.New("session is busy"), "The mode cannot change while a chat turn is in progress for session %v", s.ID)
+	}
+	if previous == mode {
+		return previous, false, nil
+	}
+	s.Mode = mode
+	s.updatedAt = time.Now()
+	return previous, true, nil
+}
+
+// beginTurn marks the session busy. It fails if another turn is running.
+func (s *Session) beginTurn() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active {
+		return util.NewFailedPreconditionError(
+			errors.New("session is busy"), "Another chat turn is already in progress for session %v", s.ID)
+	}
+	s.active = true
+	s.updatedAt = time.Now()
+	return nil
+}
+
+func (s *Session) endTurn() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = false
+	s.confirmation = nil
+	// The turn ran to an end, so neither marker applies anymore: the
+	// next snapshot is a clean turn boundary, and any earlier
+	// interruption notice is moot once the conversation moved on.
+	s.turnCheckpointAt = time.Time{}
+	s.interruptedTurnAt = time.Time{}
+	s.updatedAt = time.Now()
+}
+
+// markTurnCheckpoint stamps the running turn's checkpoint time, so the
+// next snapshot records that it was written mid-turn.
+func (s *Session) markTurnCheckpoint() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.turnCheckpointAt = time.Now()
+}
+
+// interruptedTurn returns when a previous process's turn last
+// checkpointed before dying, or zero when none did.
+func (s *Session) interruptedTurn() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interruptedTurnAt
+}
+
+// setConfirmation installs a pending confirmation and returns the channel
+// the decision will arrive on.
+func (s *Session) setConfirmation(call provider.ToolCall, payload *ConfirmationRequestPayload) chan bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confirmation = &pendingConfirmation{toolCall: call, payload: payload, decision: make(chan bool, 1)}
+	return s.confirmation.decision
+}
+
+// PendingConfirmation returns the confirmation currently awaiting the
+// user's decision, or nil. Clients that reconnect mid-turn use this to
+// restore the approval card that was delivered on the dropped stream.
+func (s *Session) PendingConfirmation() *ConfirmationRequestPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.confirmation == nil {
+		return nil
+	}
+	payload := *s.confirmation.payload
+	return &payload
+}
+
+// pendingArguments returns the full arguments of the pending
+// confirmation with the given tool call ID. Clients use this to inspect
+// a confirmation whose event carried only a summarized preview.
+func (s *Session) pendingArguments(toolCallId string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.confirmation == nil || s.confirmation.toolCall.ID != toolCallId {
+		return "", util.NewResourceNotFoundError("Pending confirmation", toolCallId)
+	}
+	return s.confirmation.toolCall.ArgumentsJSON, nil
+}
+
+// warnBeforeExpiry pushes a single session_expiring event to an open
+// stream once the session enters the warning window before its absolute
+// expiry.
+func (s *Session) warnBeforeExpiry(expiresAt time.Time) {
+	if time.Until(expiresAt) > sessionExpiryWarningLead {
+		return
+	}
+	s.mu.Lock()
+	warned := s.expiryWarned
+	s.expiryWarned = true
+	s.mu.Unlock()
+	if warned {
+		return
+	}
+	s.emitAsync(Event{Type: EventTypeSessionExpiring, Payload: &SessionExpiringPayload{
+		SessionID: s.ID,
+		ExpiresAt: expiresAt.Unix(),
+	}})
+}
+
+// denyPendingOnCleanup denies any pending confirmation so a turn blocked
+// on user approval is unblocked when its session is removed. It returns
+// a record of the lost approval, or nil if none was pending.
+func (s *Session) denyPendingOnCleanup(reason string) *LostApproval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.confirmation == nil {
+		return nil
+	}
+	glog.Warningf("Denying pending confirmation for tool call %v: session %v of user %v was cleaned up (%v)",
+		s.confirmation.toolCall.ID, s.ID, s.UserID, reason)
+	select {
+	case s.confirmation.decision <- false:
+	default:
+	}
+	lost := &LostApproval{
+		SessionID:  s.ID,
+		ToolCallID: s.confirmation.toolCall.ID,
+		Tool:       s.confirmation.toolCall.Name,
+		Reason:     reason,
+		LostAt:     time.Now().Unix(),
+	}
+	s.confirmation = nil
+	sessionCleanupDeniedConfirmations.Inc()
+	return lost
+}
+
+// resolveConfirmation delivers the user's decision for the given tool
+// call. It fails if no matching confirmation is pending.
+func (s *Session) resolveConfirmation(toolCallId string, approved bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.confirmation == nil || s.confirmation.toolCall.ID != toolCallId {
+		return util.NewInvalidInputError("No pending confirmation for tool call %v in session %v", toolCallId, s.ID)
+	}
+	s.confirmation.decision <- approved
+	s.confirmation = nil
+	return nil
+}
+
+// SessionManager owns the in-memory session store. Sessions are evicted
+// when idle past the configured timeout or when the session cap is
+// exceeded.
+type SessionManager struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	// lostApprovals records, per user, confirmations that were resolved as
+	// denied because their session was reclaimed, until the user's next
+	// stream delivers the notice. Like the sessions themselves the record
+	// is in-memory, so approvals pending at an apiserver crash are lost
+	// with the store rather than reported.
+	lostApprovals map[string][]LostApproval
+	// userUsage accumulates token consumption per user. Unlike the
+	// per-session counters it survives session eviction, so the usage
+	// report reflects everything the user spent since the last restart.
+	userUsage map[string]provider.Usage
+	// store optionally persists session snapshots; nil keeps sessions
+	// purely in memory.
+	store SessionStore
+	// readOnly forces every session into Ask mode, whatever mode the
+	// request (or a restored snapshot) carries.
+	readOnly bool
+	// optOutNamespaces lists namespaces whose sessions are opted out of
+	// telemetry by configuration.
+	optOutNamespaces map[string]bool
+	// identities resolves raw user IDs to display names for listing,
+	// export and audit output; nil leaves identities raw.
+	identities *IdentityResolver
+	// attachments holds files produced or consumed during chat; removing
+	// a session releases its references. Attached at startup like the
+	// session store; nil disables attachments.
+	attachments *AttachmentStore
+
+	// Limits resolved from configuration at construction time.
+	timeout            time.Duration
+	maxAge             time.Duration
+	maxSessions        int
+	maxMessages        int
+	maxSessionsPerUser int
+	maxMessagesPerUser int
+	// deletedRetention keeps soft-deleted persisted sessions restorable
+	// for this long; zero hard-deletes immediately.
+	deletedRetention time.Duration
+}
+
+// NewSessionManager creates a manager with the configured limits and
+// starts its cleanup loop. The limits are validated by config.Load.
+func NewSessionManager(cfg *config.Config) *SessionManager {
+	m := &SessionManager{
+		sessions:           map[string]*Session{},
+		lostApprovals:      map[string][]LostApproval{},
+		userUsage:          map[string]provider.Usage{},
+		readOnly:           cfg.ReadOnly,
+		optOutNamespaces:   namespaceSet(cfg.TelemetryOptOutNamespaces),
+		identities:         newIdentityResolver(cfg),
+		timeout:            cfg.Sessions.Timeout,
+		maxAge:             cfg.Sessions.MaxAge,
+		maxSessions:        cfg.Sessions.MaxSessions,
+		maxMessages:        cfg.Sessions.MaxMessagesPerSession,
+		maxSessionsPerUser: cfg.Sessions.MaxSessionsPerUser,
+		maxMessagesPerUser: cfg.Sessions.MaxMessagesPerUser,
+		deletedRetention:   cfg.Sessions.DeletedRetention,
+	}
+	go m.cleanupLoop()
+	return m
+}
+
+// ApplyConfig applies reloaded session limits. New limits take effect
+// for subsequent operations; sessions created before a message-limit
+// change keep the limit they were created with.
+func (m *SessionManager) ApplyConfig(cfg *config.Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeout = cfg.Sessions.Timeout
+	m.maxAge = cfg.Sessions.MaxAge
+	m.maxSessions = cfg.Sessions.MaxSessions
+	m.maxMessages = cfg.Sessions.MaxMessagesPerSession
+	m.maxSessionsPerUser = cfg.Sessions.MaxSessionsPerUser
+	m.maxMessagesPerUser = cfg.Sessions.MaxMessagesPerUser
+	m.deletedRetention = cfg.Sessions.DeletedRetention
+	m.optOutNamespaces = namespaceSet(cfg.TelemetryOptOutNamespaces)
+	if m.identities != nil {
+		m.identities.applyConfig(cfg)
+	}
+}
+
+// Identities exposes the identity resolver, e.g. for installing a
+// Profile or directory lookup hook at startup.
+func (m *SessionManager) Identities() *IdentityResolver {
+	return m.identities
+}
+
+// displayName resolves an identity for user-facing and audit output.
+func (m *SessionManager) displayName(userId string) string {
+	if m.identities == nil {
+		return userId
+	}
+	return m.identities.DisplayName(userId)
+}
+
+// namespaceSet indexes a namespace list for membership checks.
+func namespaceSet(namespaces []string) map[string]bool {
+	set := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		set[ns] = true
+	}
+	return set
+}
+
+// Location returns the session's resolved timezone, defaulting to UTC.
+func (s *Session) Location() *time.Location {
+	if s.location == nil {
+		return time.UTC
+	}
+	return s.location
+}
+
+// GetOrCreate returns the session with the given ID for the user, or
+// creates a new one when the ID is empty. Users can only access their own
+// sessions.
+func (m *SessionManager) GetOrCreate(sessionId, userId, namespace string, mode tool.Mode, timezone string) (*Session, error) {
+	if sessionId != "" {
+		return m.Get(sessionId, userId)
+	}
+	if m.readOnly {
+		// A read-only deployment ignores the requested mode rather than
+		// rejecting it, so clients that default to Agent mode still work.
+		mode = tool.ModeAsk
+	}
+	location := time.UTC
+	if timezone != "" {
+		var err error
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, util.NewInvalidInputError("Invalid timezone %q: %s", timezone, err.Error())
+		}
+	}
+	session := &Session{
+		ID:          uuid.NewString(),
+		UserID:      userId,
+		Namespace:   namespace,
+		Mode:        mode,
+		Timezone:    timezone,
+		location:    location,
+		CreatedAt:   time.Now(),
+		updatedAt:   time.Now(),
+		maxMessages: m.maxMessages,
+	}
+	m.mu.Lock()
+	session.telemetryOptOut = m.optOutNamespaces[namespace]
+	m.sessions[session.ID] = session
+	// The per-user cap applies first, so a heavy user evicts their own
+	// oldest session instead of pushing other users out through the
+	// global LRU.
+	m.evictUserOverCapacity(userId)
+	m.evictOverCapacity()
+	m.mu.Unlock()
+	m.persist(session)
+	return session, nil
+}
+
+// Get returns the user's session or a not-found error. Sessions missing
+// from memory are revived from the persistent store when one is
+// attached.
+func (m *SessionManager) Get(sessionId, userId string) (*Session, error) {
+	m.mu.Lock()
+	session, ok := m.sessions[sessionId]
+	store := m.store
+	m.mu.Unlock()
+	if ok {
+		if session.UserID != userId {
+			// A session owned by someone else is reported identically to a
+			// missing one to avoid leaking session IDs across users.
+			return nil, util.NewResourceNotFoundError("Session", sessionId)
+		}
+		return session, nil
+	}
+	if store == nil {
+		return nil, util.NewResourceNotFoundError("Session", sessionId)
+	}
+	return m.restore(sessionId, userId, store)
+}
+
+// evictOverCapacity removes least-recently-used sessions beyond the
+// configured maximum. Callers must hold m.mu.
+func (m *SessionManager) evictOverCapacity() {
+	if len(m.sessions) <= m.maxSessions {
+		return
+	}
+	type entry struct {
+		id        string
+		updatedAt time.Time
+	}
+	entries := make([]entry, 0, len(m.sessions))
+	for id, s := range m.sessions {
+		s.mu.Lock()
+		entries = append(entries, entry{id: id, updatedAt: s.updatedAt})
+		s.mu.Unlock()
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].updatedAt.Before(entries[j].updatedAt) })
+	for _, e := range entries[:len(m.sessions)-m.maxSessions] {
+		s := m.sessions[e.id]
+		glog.Infof("Evicting least recently used AI session %v of user %v", e.id, s.UserID)
+		delete(m.sessions, e.id)
+		sessionLruEvictions.Inc()
+		m.recordLostApproval(s.UserID, s.denyPendingOnCleanup("session_evicted"))
+		m.releaseAttachments(e.id)
+	}
+}
+
+// evictUserOverCapacity removes the user's least-recently-used sessions
+// beyond the per-user cap. Callers must hold m.mu.
+func (m *SessionManager) evictUserOverCapacity(userId string) {
+	if m.maxSessionsPerUser <= 0 {
+		return
+	}
+	owned := m.sessionsByAge(userId)
+	if len(owned) <= m.maxSessionsPerUser {
+		return
+	}
+	for _, s := range owned[:len(owned)-m.maxSessionsPerUser] {
+		glog.Infof("Evicting AI session %v: user %v exceeded the per-user session cap of %v", s.ID, userId, m.maxSessionsPerUser)
+		delete(m.sessions, s.ID)
+		sessionPerUserEvictions.Inc()
+		m.recordLostApproval(userId, s.denyPendingOnCleanup("session_evicted"))
+		m.releaseAttachments(s.ID)
+	}
+}
+
+// recordLostApproval queues a lost-approval notice for delivery on the
+// user's next stream. A nil record is ignored. Callers must hold m.mu.
+func (m *SessionManager) recordLostApproval(userId string, lost *LostApproval) {
+	if lost == nil {
+		return
+	}
+	queued := append(m.lostApprovals[userId], *lost)
+	if over := len(queued) - maxLostApprovalsPerUser; over > 0 {
+		queued = queued[over:]
+	}
+	m.lostApprovals[userId] = queued
+}
+
+// UseAttachments attaches the attachment store, so removed sessions
+// release their attachments. Wired once at startup, like the session
+// store.
+func (m *SessionManager) UseAttachments(store *AttachmentStore) {
+	m.attachments = store
+}
+
+// Attachments exposes the attachment store to the HTTP handlers; nil
+// when none is attached.
+func (m *SessionManager) Attachments() *AttachmentStore {
+	return m.attachments
+}
+
+// releaseAttachments drops a removed session's attachment references.
+// Callers may hold m.mu; the store does its object deletions in the
+// background.
+func (m *SessionManager) releaseAttachments(sessionId string) {
+	if m.attachments != nil {
+		m.attachments.ReleaseSession(sessionId)
+	}
+}
+
+// recordUsage accumulates one model invocation's token consumption into
+// the user's running total.
+func (m *SessionManager) recordUsage(userId string, u provider.Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := m.userUsage[userId]
+	total.InputTokens += u.InputTokens
+	total.OutputTokens += u.OutputTokens
+	m.userUsage[userId] = total
+}
+
+// maxSessionTitleChars bounds the derived conversation title shown in
+// the session listing.
+const maxSessionTitleChars = 80
+
+// SessionSummary is one entry of the session listing, enough for a
+// "previous conversations" sidebar.
+type SessionSummary struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	// User is the owner's resolved display name, not the raw identity.
+	User         string `json:"user,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	Mode         string `json:"mode"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	MessageCount int    `json:"message_count"`
+	// DeletedAt marks a soft-deleted session in the deleted listing;
+	// zero for live sessions.
+	DeletedAt int64 `json:"deleted_at,omitempty"`
+}
+
+// SessionDetail is the full session view used to resume a conversation
+// after a page reload: the summary plus the message history and any
+// confirmation the session is blocked on.
+type SessionDetail struct {
+	SessionSummary
+	Messages            []provider.Message          `json:"messages"`
+	PendingConfirmation *ConfirmationRequestPayload `json:"pending_confirmation,omitempty"`
+	// InterruptedTurnAt is set when a turn died with a previous process;
+	// the history ends at its last checkpoint and the client should
+	// offer to continue.
+	InterruptedTurnAt int64 `json:"interrupted_turn_at,omitempty"`
+}
+
+// sessionTitle derives a listing title from the conversation: the first
+// real user message, truncated. Injected system notes are skipped.
+func sessionTitle(messages []provider.Message) string {
+	for _, m := range messages {
+		if m.Role != provider.RoleUser || strings.HasPrefix(m.Content, "[System note]") {
+			continue
+		}
+		title := strings.TrimSpace(m.Content)
+		if runes := []rune(title); len(runes) > maxSessionTitleChars {
+			return string(runes[:maxSessionTitleChars]) + "..."
+		}
+		return title
+	}
+	return "New conversation"
+}
+
+// summary renders the session's listing entry.
+func (s *Session) summary() SessionSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SessionSummary{
+		SessionID:    s.ID,
+		Title:        sessionTitle(s.messages),
+		Namespace:    s.Namespace,
+		Mode:         string(s.Mode),
+		CreatedAt:    s.CreatedAt.Unix(),
+		UpdatedAt:    s.updatedAt.Unix(),
+		MessageCount: len(s.messages),
+	}
+}
+
+// detail renders the full resume view.
+func (s *Session) detail() *SessionDetail {
+	summary := s.summary()
+	detail := &SessionDetail{
+		SessionSummary:      summary,
+		Messages:            s.Messages(),
+		PendingConfirmation: s.PendingConfirmation(),
+	}
+	if interrupted := s.interruptedTurn(); !interrupted.IsZero() {
+		detail.InterruptedTurnAt = interrupted.Unix()
+	}
+	return detail
+}
+
+// ListForUser returns the user's sessions, most recently updated first.
+// When a persistent store is attached, stored sessions that are no
+// longer in memory (evicted, or created by another replica) are
+// included too.
+func (m *SessionManager) ListForUser(userId string) ([]SessionSummary, error) {
+	m.mu.Lock()
+	store := m.store
+	owned := make([]*Session, 0)
+	for _, s := range m.sessions {
+		if s.UserID == userId {
+			owned = append(owned, s)
+		}
+	}
+	m.mu.Unlock()
+
+	summaries := []SessionSummary{}
+	seen := map[string]bool{}
+	for _, s := range owned {
+		summary := s.summary()
+		summary.User = m.displayName(s.UserID)
+		summaries = append(summaries, summary)
+		seen[summary.SessionID] = true
+	}
+	if store != nil {
+		stored, err := store.ListSessions(userId)
+		if err != nil {
+			return nil, util.Wrapf(err, "Failed to list the stored AI sessions of user %v", userId)
+		}
+		for _, row := range stored {
+			if seen[row.UUID] {
+				// The in-memory session is newer than its last snapshot.
+				continue
+			}
+			summary := storedSessionSummary(row)
+			summary.User = m.displayName(row.UserID)
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].UpdatedAt > summaries[j].UpdatedAt })
+	return summaries, nil
+}
+
+// storedSessionSummary renders a listing entry from a stored snapshot.
+// A corrupt history still yields an entry, just without a title.
+func storedSessionSummary(row *model.AISession) SessionSummary {
+	var messages []provider.Message
+	if row.Messages != "" {
+		if err := json.Unmarshal([]byte(row.Messages), &messages); err != nil {
+			glog.Warningf("Failed to decode the stored history of AI session %v for listing: %v", row.UUID, err)
+		}
+	}
+	return SessionSummary{
+		SessionID:    row.UUID,
+		Title:        sessionTitle(messages),
+		Namespace:    row.Namespace,
+		Mode:         row.Mode,
+		CreatedAt:    row.CreatedAtInSec,
+		UpdatedAt:    row.UpdatedAtInSec,
+		MessageCount: len(messages),
+		DeletedAt:    row.DeletedAtInSec,
+	}
+}
+
+// ListDeletedForUser returns the user's soft-deleted sessions that are
+// still within their restore window, most recently updated first.
+func (m *SessionManager) ListDeletedForUser(userId string) ([]SessionSummary, error) {
+	store := m.currentStore()
+	if store == nil {
+		return []SessionSummary{}, nil
+	}
+	stored, err := store.ListDeletedSessions(userId)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to list the deleted AI sessions of user %v", userId)
+	}
+	summaries := []SessionSummary{}
+	for _, row := range stored {
+		summary := storedSessionSummary(row)
+		summary.User = m.displayName(row.UserID)
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Delete removes a session. With a persistent store attached the row is
+// only marked deleted and stays restorable by the owner until the
+// configured retention elapses; without one the session is gone for
+// good.
+func (m *SessionManager) Delete(sessionId, userId string) error {
+	m.mu.Lock()
+	session, inMemory := m.sessions[sessionId]
+	if inMemory && session.UserID != userId {
+		m.mu.Unlock()
+		// Report foreign sessions like missing ones, matching Get.
+		return util.NewResourceNotFoundError("Session", sessionId)
+	}
+	if inMemory {
+		delete(m.sessions, sessionId)
+		m.recordLostApproval(session.UserID, session.denyPendingOnCleanup("session_deleted"))
+		m.releaseAttachments(sessionId)
+	}
+	store := m.store
+	retention := m.deletedRetention
+	m.mu.Unlock()
+
+	if store == nil {
+		if !inMemory {
+			return util.NewResourceNotFoundError("Session", sessionId)
+		}
+		return nil
+	}
+	if !inMemory {
+		stored, err := store.GetSession(sessionId)
+		if err != nil {
+			return err
+		}
+		if stored.UserID != userId || stored.DeletedAtInSec != 0 {
+			return util.NewResourceNotFoundError("Session", sessionId)
+		}
+	}
+	glog.Infof("Deleting AI session %v of user %v (restorable: %v)", sessionId, userId, retention > 0)
+	return m.storeDelete(store, sessionId, retention)
+}
+
+// Restore clears the soft-delete marker of one of the user's sessions,
+// making it resumable again. Only possible while the deleted row is
+// still within its retention window.
+func (m *SessionManager) Restore(sessionId, userId string) error {
+	store := m.currentStore()
+	if store == nil {
+		return util.NewFailedPreconditionError(errors.New("no session store"),
+			"Deleted sessions can only be restored when session persistence is enabled")
+	}
+	stored, err := store.GetSession(sessionId)
+	if err != nil {
+		return err
+	}
+	if stored.UserID != userId {
+		return util.NewResourceNotFoundError("Session", sessionId)
+	}
+	if stored.DeletedAtInSec == 0 {
+		return util.NewFailedPreconditionError(errors.New("session is live"),
+			"Session %v is not deleted", sessionId)
+	}
+	if err := store.RestoreSession(sessionId); err != nil {
+		return err
+	}
+	glog.Infof("Restored AI session %v of user %v", sessionId, userId)
+	return nil
+}
+
+// DetailForUser returns the full resume view of one session. Sessions
+// only present in the persistent store are revived first.
+func (m *SessionManager) DetailForUser(sessionId, userId string) (*SessionDetail, error) {
+	session, err := m.Get(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	detail := session.detail()
+	detail.User = m.displayName(session.UserID)
+	return detail, nil
+}
+
+// SessionUsage is the per-session entry of a usage report.
+type SessionUsage struct {
+	SessionID    string `json:"session_id"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+}
+
+// UsageReport summarizes a user's token consumption: the running total
+// since the last apiserver restart plus a breakdown over the sessions
+// still in the store.
+type UsageReport struct {
+	UserID       string         `json:"user_id"`
+	InputTokens  int            `json:"input_tokens"`
+	OutputTokens int            `json:"output_tokens"`
+	Sessions     []SessionUsage `json:"sessions"`
+}
+
+// UsageForUser renders the user's usage report.
+func (m *SessionManager) UsageForUser(userId string) *UsageReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := m.userUsage[userId]
+	report := &UsageReport{
+		UserID:       userId,
+		InputTokens:  total.InputTokens,
+		OutputTokens: total.OutputTokens,
+		Sessions:     []SessionUsage{},
+	}
+	for _, s := range m.sessions {
+		if s.UserID != userId {
+			continue
+		}
+		// Opted-out sessions track usage for their own stream and for
+		// budget enforcement, but stay out of the analytics report.
+		if s.telemetryDisabled() {
+			continue
+		}
+		usage := s.usageSnapshot()
+		report.Sessions = append(report.Sessions, SessionUsage{
+			SessionID:    s.ID,
+			InputTokens:  usage.InputTokens,
+			OutputTokens: usage.OutputTokens,
+		})
+	}
+	sort.Slice(report.Sessions, func(i, j int) bool { return report.Sessions[i].SessionID < report.Sessions[j].SessionID })
+	return report
+}
+
+// broadcast records one event on every live session's replay buffer.
+// eventFor builds the event per session, since payloads can depend on
+// the session's mode.
+func (m *SessionManager) broadcast(eventFor func(*Session) Event) {
+	m.mu.Lock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+	// Recording happens outside m.mu: the buffers take their own
+	// session locks and may write to attached connections.
+	for _, s := range sessions {
+		_ = s.eventLog().Record(eventFor(s))
+	}
+}
+
+// takeLostApprovals drains the user's queued lost-approval notices.
+func (m *SessionManager) takeLostApprovals(userId string) []LostApproval {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lost := m.lostApprovals[userId]
+	delete(m.lostApprovals, userId)
+	return lost
+}
+
+// sessionsByAge returns the user's sessions ordered least recently used
+// first. Callers must hold m.mu.
+func (m *SessionManager) sessionsByAge(userId string) []*Session {
+	var owned []*Session
+	for _, s := range m.sessions {
+		if s.UserID == userId {
+			owned = append(owned, s)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		owned[i].mu.Lock()
+		ti := owned[i].updatedAt
+		owned[i].mu.Unlock()
+		owned[j].mu.Lock()
+		tj := owned[j].updatedAt
+		owned[j].mu.Unlock()
+		return ti.Before(tj)
+	})
+	return owned
+}
+
+// enforceMessageBudgets trims history oldest-first from each over-budget
+// user's least-recently-used sessions until the user's total stored
+// messages fit the per-user budget. Callers must hold m.mu.
+func (m *SessionManager) enforceMessageBudgets() {
+	if m.maxMessagesPerUser <= 0 {
+		return
+	}
+	totals := map[string]int{}
+	for _, s := range m.sessions {
+		totals[s.UserID] += s.messageCount()
+	}
+	for userId, total := range totals {
+		over := total - m.maxMessagesPerUser
+		if over <= 0 {
+			continue
+		}
+		for _, s := range m.sessionsByAge(userId) {
+			if over <= 0 {
+				break
+			}
+			trimmed := s.trimOldest(over)
+			over -= trimmed
+			if trimmed > 0 {
+				sessionUserBudgetTrims.Add(float64(trimmed))
+				glog.V(2).Infof("Trimmed %v message(s) from AI session %v: user %v exceeded the per-user message budget of %v",
+					trimmed, s.ID, userId, m.maxMessagesPerUser)
+			}
+		}
+	}
+}
+
+// streamingSessions returns the sessions that currently have an open
+// client stream.
+func (m *SessionManager) streamingSessions() []*Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*Session
+	for _, s := range m.sessions {
+		s.mu.Lock()
+		streaming := s.emitFn != nil
+		s.mu.Unlock()
+		if streaming {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (m *SessionManager) cleanupLoop() {
+	ticker := time.NewTicker(sessionCleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.cleanupExpired()
+	}
+}
+
+func (m *SessionManager) cleanupExpired() {
+	m.mu.Lock()
+	now := time.Now()
+	var expired []string
+	var nearingExpiry []*Session
+	for id, s := range m.sessions {
+		s.mu.Lock()
+		idle := !s.active && now.Sub(s.updatedAt) > m.timeout
+		s.mu.Unlock()
+		// The absolute lifetime applies even to active sessions: it exists
+		// so compliance-driven deployments can force turnover.
+		aged := m.maxAge > 0 && now.Sub(s.CreatedAt) > m.maxAge
+		if idle || aged {
+			glog.Infof("Cleaning up expired AI session %v of user %v (idle: %v, over max age: %v)", id, s.UserID, idle, aged)
+			delete(m.sessions, id)
+			expired = append(expired, id)
+			sessionExpiredCleanups.Inc()
+			m.recordLostApproval(s.UserID, s.denyPendingOnCleanup("session_expired"))
+			m.releaseAttachments(id)
+			continue
+		}
+		if m.maxAge > 0 {
+			nearingExpiry = append(nearingExpiry, s)
+		}
+	}
+	m.enforceMessageBudgets()
+	store := m.store
+	retention := m.deletedRetention
+	maxAge := m.maxAge
+	m.mu.Unlock()
+	// Warning happens outside m.mu, like broadcast: the emit path may
+	// write to an attached connection, and a stalled client must not
+	// block every session operation on the server.
+	for _, s := range nearingExpiry {
+		s.warnBeforeExpiry(s.CreatedAt.Add(maxAge))
+	}
+	if store == nil {
+		return
+	}
+	// Expired sessions are dropped from the store too, so they cannot be
+	// revived past their lifetime — but only softly while the retention
+	// runs, in case an important conversation expired under the owner.
+	// Capacity evictions keep their rows: being restorable later is the
+	// point of persisting them.
+	for _, id := range expired {
+		if err := m.storeDelete(store, id, retention); err != nil {
+			glog.Errorf("Failed to delete expired AI session %v from the session store: %v", id, err)
+		}
+	}
+	if retention > 0 {
+		if err := store.PurgeDeletedSessions(now.Add(-retention).Unix()); err != nil {
+			glog.Errorf("Failed to purge soft-deleted AI sessions: %v", err)
+		}
+	}
+}
+
+// storeDelete removes a session from the store: softly when a retention
+// keeps it restorable, outright otherwise.
+func (m *SessionManager) storeDelete(store SessionStore, sessionId string, retention time.Duration) error {
+	if retention > 0 {
+		return store.SoftDeleteSession(sessionId, time.Now().Unix())
+	}
+	return store.DeleteSession(sessionId)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deleteFixture(t *testing.T, retention time.Duration) (*SessionManager, *fakeSessionStore, *Session) {
+	t.Helper()
+	m := &SessionManager{
+		sessions:         map[string]*Session{},
+		userUsage:        map[string]provider.Usage{},
+		lostApprovals:    map[string][]LostApproval{},
+		maxSessions:      10,
+		maxMessages:      50,
+		deletedRetention: retention,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "UTC")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "debug run r1 with me"})
+	m.persist(session)
+	return m, store, session
+}
+
+func TestDelete_SoftDeletesWithinTheRetention(t *testing.T) {
+	m, store, session := deleteFixture(t, 72*time.Hour)
+
+	require.NoError(t, m.Delete(session.ID, "alice"))
+	// Gone from the user's point of view...
+	_, err := m.Get(session.ID, "alice")
+	assert.Error(t, err)
+	live, err := m.ListForUser("alice")
+	require.NoError(t, err)
+	assert.Empty(t, live)
+	// ...but the row is kept, marked, and listed as restorable.
+	require.NotNil(t, store.rows[session.ID])
+	assert.NotZero(t, store.rows[session.ID].DeletedAtInSec)
+	deleted, err := m.ListDeletedForUser("alice")
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, session.ID, deleted[0].SessionID)
+	assert.NotZero(t, deleted[0].DeletedAt)
+}
+
+func TestRestore_RevivesASoftDeletedSession(t *testing.T) {
+	m, _, session := deleteFixture(t, 72*time.Hour)
+	require.NoError(t, m.Delete(session.ID, "alice"))
+
+	require.NoError(t, m.Restore(session.ID, "alice"))
+	restored, err := m.Get(session.ID, "alice")
+	require.NoError(t, err)
+	require.Len(t, restored.Messages(), 1)
+	assert.Equal(t, "debug run r1 with me", restored.Messages()[0].Content)
+}
+
+func TestDelete_WithoutRetentionRemovesTheRow(t *testing.T) {
+	m, store, session := deleteFixture(t, 0)
+
+	require.NoError(t, m.Delete(session.ID, "alice"))
+	assert.Nil(t, store.rows[session.ID])
+	assert.Error(t, m.Restore(session.ID, "alice"))
+}
+
+func TestDelete_RejectsOtherUsers(t *testing.T) {
+	m, store, session := deleteFixture(t, 72*time.Hour)
+
+	assert.Error(t, m.Delete(session.ID, "mallory"))
+	assert.Zero(t, store.rows[session.ID].DeletedAtInSec)
+	assert.Error(t, m.Restore(session.ID, "mallory"))
+}
+
+func TestRestore_RequiresADeletedSession(t *testing.T) {
+	m, _, session := deleteFixture(t, 72*time.Hour)
+	assert.Error(t, m.Restore(session.ID, "alice"))
+}
+
+func TestCleanupExpired_SoftDeletesAndPurgesPastRetention(t *testing.T) {
+	m, store, session := deleteFixture(t, time.Hour)
+	m.timeout = time.Minute
+	session.mu.Lock()
+	session.updatedAt = time.Now().Add(-time.Hour)
+	session.mu.Unlock()
+
+	m.cleanupExpired()
+	require.NotNil(t, store.rows[session.ID])
+	assert.NotZero(t, store.rows[session.ID].DeletedAtInSec)
+
+	// Once the marker ages past the retention, the next sweep purges it.
+	store.rows[session.ID].DeletedAtInSec = time.Now().Add(-2 * time.Hour).Unix()
+	m.cleanupExpired()
+	assert.Nil(t, store.rows[session.ID])
+}
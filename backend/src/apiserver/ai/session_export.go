@@ -0,0 +1,110 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Export formats accepted by SessionManager.Export.
+const (
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "markdown"
+)
+
+// ExportedSession is a downloadable transcript rendering.
+type ExportedSession struct {
+	// Filename is a suggested name for the download.
+	Filename string
+	// ContentType is the MIME type of Content.
+	ContentType string
+	Content     []byte
+}
+
+// Export renders a session the user owns as a downloadable transcript,
+// including tool calls and their results, for audit and for sharing
+// debugging sessions. Format is "json" or "markdown"; empty defaults to
+// JSON. Sessions only present in the persistent store are revived
+// first.
+func (m *SessionManager) Export(sessionId, userId, format string) (*ExportedSession, error) {
+	detail, err := m.DetailForUser(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", ExportFormatJSON:
+		content, err := json.MarshalIndent(detail, "", "  ")
+		if err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to marshal the session transcript")
+		}
+		return &ExportedSession{
+			Filename:    fmt.Sprintf("session-%v.json", sessionId),
+			ContentType: "application/json",
+			Content:     content,
+		}, nil
+	case ExportFormatMarkdown:
+		return &ExportedSession{
+			Filename:    fmt.Sprintf("session-%v.md", sessionId),
+			ContentType: "text/markdown",
+			Content:     []byte(markdownTranscript(detail)),
+		}, nil
+	default:
+		return nil, util.NewInvalidInputError("Unsupported export format %q; use %q or %q", format, ExportFormatJSON, ExportFormatMarkdown)
+	}
+}
+
+// markdownTranscript renders a session as a human-readable document.
+func markdownTranscript(detail *SessionDetail) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", detail.Title)
+	fmt.Fprintf(&b, "- Session: `%s`\n", detail.SessionID)
+	if detail.User != "" {
+		fmt.Fprintf(&b, "- User: %s\n", detail.User)
+	}
+	if detail.Namespace != "" {
+		fmt.Fprintf(&b, "- Namespace: `%s`\n", detail.Namespace)
+	}
+	fmt.Fprintf(&b, "- Mode: %s\n", detail.Mode)
+	fmt.Fprintf(&b, "- Started: %s\n", time.Unix(detail.CreatedAt, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Last activity: %s\n", time.Unix(detail.UpdatedAt, 0).UTC().Format(time.RFC3339))
+
+	for _, message := range detail.Messages {
+		switch message.Role {
+		case provider.RoleUser:
+			fmt.Fprintf(&b, "\n## User\n\n%s\n", message.Content)
+		case provider.RoleAssistant:
+			fmt.Fprintf(&b, "\n## Assistant\n")
+			if message.Content != "" {
+				fmt.Fprintf(&b, "\n%s\n", message.Content)
+			}
+			for _, call := range message.ToolCalls {
+				fmt.Fprintf(&b, "\nCalled `%s`:\n\n```json\n%s\n```\n", call.Name, call.ArgumentsJSON)
+			}
+		case provider.RoleTool:
+			fmt.Fprintf(&b, "\n### Tool result\n\n```json\n%s\n```\n", message.Content)
+		default:
+			// System prompts and future roles are exported verbatim so the
+			// transcript stays a faithful audit record.
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", message.Role, message.Content)
+		}
+	}
+	return b.String()
+}
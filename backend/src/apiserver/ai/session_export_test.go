@@ -0,0 +1,86 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportFixture(t *testing.T) (*SessionManager, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "why did run 42 fail?"})
+	session.AppendMessage(provider.Message{
+		Role:      provider.RoleAssistant,
+		Content:   "Let me look at the run.",
+		ToolCalls: []provider.ToolCall{{ID: "call-1", Name: "get_run", ArgumentsJSON: `{"run_id":"42"}`}},
+	})
+	session.AppendMessage(provider.Message{Role: provider.RoleTool, ToolCallID: "call-1", Content: `{"state":"FAILED"}`})
+	session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: "The run failed."})
+	return m, session
+}
+
+func TestExport_MarkdownIncludesToolCallsAndResults(t *testing.T) {
+	m, session := exportFixture(t)
+
+	export, err := m.Export(session.ID, "alice", ExportFormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown", export.ContentType)
+	assert.Equal(t, "session-"+session.ID+".md", export.Filename)
+
+	transcript := string(export.Content)
+	assert.Contains(t, transcript, "# why did run 42 fail?")
+	assert.Contains(t, transcript, "## User")
+	assert.Contains(t, transcript, "Called `get_run`:")
+	assert.Contains(t, transcript, `{"run_id":"42"}`)
+	assert.Contains(t, transcript, "### Tool result")
+	assert.Contains(t, transcript, `{"state":"FAILED"}`)
+	assert.Contains(t, transcript, "The run failed.")
+}
+
+func TestExport_JSONIsTheFullDetail(t *testing.T) {
+	m, session := exportFixture(t)
+
+	// JSON is the default format.
+	export, err := m.Export(session.ID, "alice", "")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", export.ContentType)
+	assert.Equal(t, "session-"+session.ID+".json", export.Filename)
+
+	var detail SessionDetail
+	require.NoError(t, json.Unmarshal(export.Content, &detail))
+	assert.Equal(t, session.ID, detail.SessionID)
+	require.Len(t, detail.Messages, 4)
+	require.Len(t, detail.Messages[1].ToolCalls, 1)
+	assert.Equal(t, "get_run", detail.Messages[1].ToolCalls[0].Name)
+}
+
+func TestExport_RejectsBadRequests(t *testing.T) {
+	m, session := exportFixture(t)
+
+	_, err := m.Export(session.ID, "alice", "pdf")
+	assert.Error(t, err)
+
+	// Another user's request looks like a missing session.
+	_, err = m.Export(session.ID, "mallory", ExportFormatMarkdown)
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTitle(t *testing.T) {
+	assert.Equal(t, "New conversation", sessionTitle(nil))
+	assert.Equal(t, "why did my run fail?", sessionTitle([]provider.Message{
+		{Role: provider.RoleUser, Content: "[System note] read_after_write: ignore me"},
+		{Role: provider.RoleUser, Content: "why did my run fail?"},
+		{Role: provider.RoleAssistant, Content: "let me check"},
+	}))
+
+	long := strings.Repeat("a", maxSessionTitleChars+10)
+	title := sessionTitle([]provider.Message{{Role: provider.RoleUser, Content: long}})
+	assert.Equal(t, maxSessionTitleChars+3, len(title))
+	assert.True(t, strings.HasSuffix(title, "..."))
+}
+
+func TestListForUser_ReturnsOwnSessionsNewestFirst(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+
+	first, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	first.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "first conversation"})
+	second, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	second.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "second conversation"})
+	_, err = m.GetOrCreate("", "bob", "team-b", "ask", "")
+	require.NoError(t, err)
+
+	sessions, err := m.ListForUser("alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	ids := []string{sessions[0].SessionID, sessions[1].SessionID}
+	assert.Contains(t, ids, first.ID)
+	assert.Contains(t, ids, second.ID)
+	for _, s := range sessions {
+		assert.Equal(t, "team-a", s.Namespace)
+		assert.Equal(t, 1, s.MessageCount)
+	}
+}
+
+func TestListForUser_IncludesStoredSessionsNotInMemory(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	evicted, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	evicted.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "older conversation"})
+	m.persist(evicted)
+	delete(m.sessions, evicted.ID)
+
+	live, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	live.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "current conversation"})
+
+	sessions, err := m.ListForUser("alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	byId := map[string]SessionSummary{}
+	for _, s := range sessions {
+		byId[s.SessionID] = s
+	}
+	assert.Equal(t, "older conversation", byId[evicted.ID].Title)
+	assert.Equal(t, "current conversation", byId[live.ID].Title)
+}
+
+func TestDetailForUser_ReturnsTheFullHistory(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "list my runs"})
+	session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: "here they are"})
+
+	detail, err := m.DetailForUser(session.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "list my runs", detail.Title)
+	require.Len(t, detail.Messages, 2)
+	assert.Nil(t, detail.PendingConfirmation)
+
+	// Another user's request looks like a missing session.
+	_, err = m.DetailForUser(session.ID, "mallory")
+	assert.Error(t, err)
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// SessionStore persists chat session snapshots, so conversations survive
+// an apiserver restart and can be resumed on another replica. The
+// database-backed implementation lives in the storage package; the
+// in-memory map remains the default when no store is attached.
+type SessionStore interface {
+	SaveSession(session *model.AISession) error
+	GetSession(sessionId string) (*model.AISession, error)
+	ListSessions(userId string) ([]*model.AISession, error)
+	ListDeletedSessions(userId string) ([]*model.AISession, error)
+	DeleteSession(sessionId string) error
+	SoftDeleteSession(sessionId string, deletedAtInSec int64) error
+	RestoreSession(sessionId string) error
+	PurgeDeletedSessions(deletedBeforeInSec int64) error
+}
+
+// UseStore attaches a persistent backend. Sessions are written as whole
+// snapshots at turn boundaries and revived on demand when a requested
+// session is not in memory, so an LRU-evicted session stays restorable
+// until it expires.
+func (m *SessionManager) UseStore(store SessionStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
+// currentStore returns the attached store, or nil.
+func (m *SessionManager) currentStore() SessionStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.store
+}
+
+// checkpoint persists the session mid-turn, at a tool loop iteration
+// boundary. The snapshot is internally consistent — every appended tool
+// call has its result — and is marked as mid-turn, so a restart before
+// the turn completes can tell the user the investigation survived up to
+// this point instead of losing it entirely.
+func (m *SessionManager) checkpoint(s *Session) {
+	s.markTurnCheckpoint()
+	m.persist(s)
+}
+
+// persist writes the session snapshot to the attached store. Persistence
+// failures are logged rather than surfaced: the in-memory session keeps
+// serving the conversation, it just will not survive a restart.
+func (m *SessionManager) persist(s *Session) {
+	store := m.currentStore()
+	if store == nil || s.telemetryDisabled() {
+		return
+	}
+	snapshot, err := s.storeSnapshot()
+	if err != nil {
+		glog.Errorf("Failed to snapshot AI session %v for persistence: %v", s.ID, err)
+		return
+	}
+	if err := store.SaveSession(snapshot); err != nil {
+		glog.Errorf("Failed to persist AI session %v: %v", s.ID, err)
+	}
+}
+
+// OptOutOfTelemetry excludes the session from usage analytics and
+// transcript persistence, and removes any snapshot already written.
+// Operational metrics and budget enforcement are unaffected. The opt-out
+// cannot be reversed for the session's lifetime.
+func (m *SessionManager) OptOutOfTelemetry(s *Session) {
+	s.mu.Lock()
+	alreadyOut := s.telemetryOptOut
+	s.telemetryOptOut = true
+	s.mu.Unlock()
+	if alreadyOut {
+		return
+	}
+	glog.V(2).Infof("AI session %v opted out of telemetry", s.ID)
+	store := m.currentStore()
+	if store == nil {
+		return
+	}
+	// The creation-time snapshot may predate the opt-out.
+	if err := store.DeleteSession(s.ID); err != nil {
+		glog.Errorf("Failed to delete the persisted snapshot of opted-out AI session %v: %v", s.ID, err)
+	}
+}
+
+// restore revives a stored session, e.g. after a restart or on a replica
+// that did not create it. A session owned by another user is reported
+// identically to a missing one, matching Get.
+func (m *SessionManager) restore(sessionId, userId string, store SessionStore) (*Session, error) {
+	stored, err := store.GetSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	// A soft-deleted session is gone from the user's point of view until
+	// it is explicitly restored; ownership mismatches look the same.
+	if stored.UserID != userId || stored.DeletedAtInSec != 0 {
+		return nil, util.NewResourceNotFoundError("Session", sessionId)
+	}
+	mode, err := tool.ParseMode(stored.Mode)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to restore AI session %v", sessionId)
+	}
+	if m.readOnly {
+		// Snapshots written before the deployment went read-only may carry
+		// Agent mode; the flag wins.
+		mode = tool.ModeAsk
+	}
+	location := time.UTC
+	if stored.Timezone != "" {
+		if parsed, err := time.LoadLocation(stored.Timezone); err == nil {
+			location = parsed
+		}
+	}
+	var messages []provider.Message
+	if stored.Messages != "" {
+		if err := json.Unmarshal([]byte(stored.Messages), &messages); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to restore the history of AI session %v", sessionId)
+		}
+	}
+	if stored.PendingConfirmation != "" {
+		// The decision channel of a pending confirmation cannot outlive
+		// the process that created it; the turn it blocked is gone.
+		glog.Warningf("Restored AI session %v had a pending confirmation; it is treated as denied", sessionId)
+	}
+	interruptedAt := time.Time{}
+	if stored.TurnCheckpointAtInSec != 0 {
+		// The last snapshot was a mid-turn checkpoint, so the process died
+		// before the turn completed. The history is usable as-is — it ends
+		// at the last completed iteration — and the client is offered a
+		// continuation.
+		glog.Warningf("Restored AI session %v from a mid-turn checkpoint; the interrupted turn can be continued", sessionId)
+		interruptedAt = time.Unix(stored.TurnCheckpointAtInSec, 0)
+	}
+	session := &Session{
+		ID:                stored.UUID,
+		UserID:            stored.UserID,
+		Namespace:         stored.Namespace,
+		Mode:              mode,
+		Timezone:          stored.Timezone,
+		location:          location,
+		CreatedAt:         time.Unix(stored.CreatedAtInSec, 0),
+		updatedAt:         time.Now(),
+		messages:          messages,
+		maxMessages:       m.maxMessages,
+		interruptedTurnAt: interruptedAt,
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session.telemetryOptOut = m.optOutNamespaces[session.Namespace]
+	// A concurrent request may have restored the session first.
+	if existing, ok := m.sessions[sessionId]; ok {
+		return existing, nil
+	}
+	m.sessions[sessionId] = session
+	m.evictUserOverCapacity(userId)
+	m.evictOverCapacity()
+	return session, nil
+}
+
+// storeSnapshot renders the session as its persistence row.
+func (s *Session) storeSnapshot() (*model.AISession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages, err := json.Marshal(s.messages)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to serialize the history of AI session %v", s.ID)
+	}
+	pending := ""
+	if s.confirmation != nil {
+		payload, err := json.Marshal(s.confirmation.payload)
+		if err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to serialize the pending confirmation of AI session %v", s.ID)
+		}
+		pending = string(payload)
+	}
+	checkpointAt := int64(0)
+	if !s.turnCheckpointAt.IsZero() {
+		checkpointAt = s.turnCheckpointAt.Unix()
+	}
+	return &model.AISession{
+		UUID:                  s.ID,
+		UserID:                s.UserID,
+		Namespace:             s.Namespace,
+		Mode:                  string(s.Mode),
+		Timezone:              s.Timezone,
+		CreatedAtInSec:        s.CreatedAt.Unix(),
+		UpdatedAtInSec:        s.updatedAt.Unix(),
+		Messages:              model.LargeText(messages),
+		PendingConfirmation:   model.LargeText(pending),
+		TurnCheckpointAtInSec: checkpointAt,
+	}, nil
+}
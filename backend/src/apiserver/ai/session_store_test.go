@@ -0,0 +1,179 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionStore is a map-backed SessionStore standing in for the
+// database-backed implementation in the storage package.
+type fakeSessionStore struct {
+	rows map[string]*model.AISession
+}
+
+func newFakeSessionStore() *fakeSessionStore {
+	return &fakeSessionStore{rows: map[string]*model.AISession{}}
+}
+
+func (f *fakeSessionStore) SaveSession(session *model.AISession) error {
+	copied := *session
+	f.rows[session.UUID] = &copied
+	return nil
+}
+
+func (f *fakeSessionStore) GetSession(sessionId string) (*model.AISession, error) {
+	row, ok := f.rows[sessionId]
+	if !ok {
+		return nil, util.NewResourceNotFoundError("Session", sessionId)
+	}
+	copied := *row
+	return &copied, nil
+}
+
+func (f *fakeSessionStore) ListSessions(userId string) ([]*model.AISession, error) {
+	out := []*model.AISession{}
+	for _, row := range f.rows {
+		if row.UserID == userId && row.DeletedAtInSec == 0 {
+			copied := *row
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessionStore) ListDeletedSessions(userId string) ([]*model.AISession, error) {
+	out := []*model.AISession{}
+	for _, row := range f.rows {
+		if row.UserID == userId && row.DeletedAtInSec > 0 {
+			copied := *row
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessionStore) DeleteSession(sessionId string) error {
+	delete(f.rows, sessionId)
+	return nil
+}
+
+func (f *fakeSessionStore) SoftDeleteSession(sessionId string, deletedAtInSec int64) error {
+	if row, ok := f.rows[sessionId]; ok && row.DeletedAtInSec == 0 {
+		row.DeletedAtInSec = deletedAtInSec
+	}
+	return nil
+}
+
+func (f *fakeSessionStore) RestoreSession(sessionId string) error {
+	if row, ok := f.rows[sessionId]; ok {
+		row.DeletedAtInSec = 0
+	}
+	return nil
+}
+
+func (f *fakeSessionStore) PurgeDeletedSessions(deletedBeforeInSec int64) error {
+	for id, row := range f.rows {
+		if row.DeletedAtInSec > 0 && row.DeletedAtInSec <= deletedBeforeInSec {
+			delete(f.rows, id)
+		}
+	}
+	return nil
+}
+
+func TestSessionManager_PersistedSessionIsRestoredAfterEviction(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+		maxMessages: 50,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "UTC")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "list my runs"})
+	session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: "here they are"})
+	m.persist(session)
+
+	// Drop the session from memory, as a restart or LRU eviction would.
+	delete(m.sessions, session.ID)
+
+	restored, err := m.Get(session.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, restored.ID)
+	assert.Equal(t, "team-a", restored.Namespace)
+	require.Len(t, restored.Messages(), 2)
+	assert.Equal(t, "list my runs", restored.Messages()[0].Content)
+}
+
+func TestSessionManager_RestoreRejectsAnotherUsersSession(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	m.persist(session)
+	delete(m.sessions, session.ID)
+
+	// Another user's stored session looks exactly like a missing one.
+	_, err = m.Get(session.ID, "mallory")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestSessionManager_ExpiredSessionsAreDeletedFromTheStore(t *testing.T) {
+	m := &SessionManager{
+		sessions:      map[string]*Session{},
+		userUsage:     map[string]provider.Usage{},
+		lostApprovals: map[string][]LostApproval{},
+		maxSessions:   10,
+		timeout:       1, // Nanoseconds: everything is immediately expired.
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	m.persist(session)
+	require.Contains(t, store.rows, session.ID)
+
+	m.cleanupExpired()
+	assert.NotContains(t, store.rows, session.ID)
+	assert.NotContains(t, m.sessions, session.ID)
+}
+
+func TestSessionManager_PersistWithoutStoreIsANoOp(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		maxSessions: 10,
+	}
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	// Must not panic or error with no store attached.
+	m.persist(session)
+}
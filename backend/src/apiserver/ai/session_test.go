@@ -0,0 +1,143 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_PendingConfirmationSurvivesUntilResolved(t *testing.T) {
+	session := &Session{ID: "s1", UserID: "alice"}
+	assert.Nil(t, session.PendingConfirmation())
+
+	call := provider.ToolCall{ID: "tc1", Name: "delete_run", ArgumentsJSON: `{"run_id":"r1"}`}
+	decision := session.setConfirmation(call, &ConfirmationRequestPayload{
+		ToolCallID:    call.ID,
+		Tool:          call.Name,
+		ArgumentsJSON: call.ArgumentsJSON,
+	})
+
+	pending := session.PendingConfirmation()
+	require.NotNil(t, pending)
+	assert.Equal(t, "tc1", pending.ToolCallID)
+	assert.Equal(t, "delete_run", pending.Tool)
+
+	require.NoError(t, session.resolveConfirmation("tc1", true))
+	assert.True(t, <-decision)
+	assert.Nil(t, session.PendingConfirmation())
+}
+
+func TestSession_ResolveConfirmationRejectsUnknownToolCall(t *testing.T) {
+	session := &Session{ID: "s1", UserID: "alice"}
+	assert.Error(t, session.resolveConfirmation("tc1", true))
+}
+
+func TestSessionManager_PerUserCapEvictsOwnSessionsOnly(t *testing.T) {
+	m := &SessionManager{
+		sessions:           map[string]*Session{},
+		maxSessions:        100,
+		maxSessionsPerUser: 2,
+	}
+	bobSession, err := m.GetOrCreate("", "bob", "ns", "ask", "")
+	require.NoError(t, err)
+	var aliceLatest *Session
+	for i := 0; i < 4; i++ {
+		aliceLatest, err = m.GetOrCreate("", "alice", "ns", "ask", "")
+		require.NoError(t, err)
+	}
+
+	// Alice is capped at two sessions; Bob's session is untouched.
+	assert.Len(t, m.sessions, 3)
+	_, err = m.Get(bobSession.ID, "bob")
+	assert.NoError(t, err)
+	_, err = m.Get(aliceLatest.ID, "alice")
+	assert.NoError(t, err)
+}
+
+func TestSessionManager_EvictionQueuesLostApprovalNotice(t *testing.T) {
+	m := &SessionManager{
+		sessions:           map[string]*Session{},
+		lostApprovals:      map[string][]LostApproval{},
+		maxSessions:        100,
+		maxSessionsPerUser: 1,
+	}
+	blocked, err := m.GetOrCreate("", "alice", "ns", "ask", "")
+	require.NoError(t, err)
+	call := provider.ToolCall{ID: "tc1", Name: "delete_run"}
+	decision := blocked.setConfirmation(call, &ConfirmationRequestPayload{ToolCallID: call.ID, Tool: call.Name})
+
+	// A second session pushes the blocked one out through the per-user cap.
+	_, err = m.GetOrCreate("", "alice", "ns", "ask", "")
+	require.NoError(t, err)
+
+	assert.False(t, <-decision)
+	lost := m.takeLostApprovals("alice")
+	require.Len(t, lost, 1)
+	assert.Equal(t, blocked.ID, lost[0].SessionID)
+	assert.Equal(t, "tc1", lost[0].ToolCallID)
+	assert.Equal(t, "delete_run", lost[0].Tool)
+	assert.Equal(t, "session_evicted", lost[0].Reason)
+	// The notice is delivered once.
+	assert.Empty(t, m.takeLostApprovals("alice"))
+}
+
+func TestSessionManager_MessageBudgetTrimsOldestSessionsFirst(t *testing.T) {
+	m := &SessionManager{
+		sessions:           map[string]*Session{},
+		maxSessions:        100,
+		maxMessagesPerUser: 3,
+	}
+	older, err := m.GetOrCreate("", "alice", "ns", "ask", "")
+	require.NoError(t, err)
+	newer, err := m.GetOrCreate("", "alice", "ns", "ask", "")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		older.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "old"})
+	}
+	// Appending last makes this the most recently used session.
+	for i := 0; i < 2; i++ {
+		newer.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "new"})
+	}
+
+	m.mu.Lock()
+	m.enforceMessageBudgets()
+	m.mu.Unlock()
+
+	assert.Equal(t, 1, older.messageCount())
+	assert.Equal(t, 2, newer.messageCount())
+}
+
+func TestSessionManager_MessageBudgetSkipsActiveSessions(t *testing.T) {
+	m := &SessionManager{
+		sessions:           map[string]*Session{},
+		maxSessions:        100,
+		maxMessagesPerUser: 1,
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", "ask", "")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "a"})
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "b"})
+	require.NoError(t, session.beginTurn())
+
+	m.mu.Lock()
+	m.enforceMessageBudgets()
+	m.mu.Unlock()
+
+	assert.Equal(t, 2, session.messageCount())
+}
@@ -0,0 +1,890 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/ratelimit"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	maxChatRequestBytes = 64 * 1024
+
+	// anonymousUser identifies callers in single-user deployments where
+	// no identity header is configured.
+	anonymousUser = "anonymous"
+)
+
+// SSEHandler serves the AI assistant HTTP endpoints. Chat responses are
+// streamed as server-sent events; grpc-gateway cannot stream, so these
+// are native HTTP handlers like the run log endpoint.
+type SSEHandler struct {
+	aiServer *AIServer
+	// limiter enforces the per-user chat request budget.
+	limiter ratelimit.Limiter
+
+	mu sync.Mutex
+	// idempotency replays recorded responses for retried POSTs that carry
+	// an idempotency key.
+	idempotency *idempotencyCache
+	// writeTimeout bounds writes on the non-stream endpoints.
+	writeTimeout time.Duration
+	// heartbeatInterval spaces keepalive comments on idle SSE streams;
+	// zero disables them.
+	heartbeatInterval time.Duration
+	// apiKeys authenticates non-browser clients by bearer token.
+	apiKeys *apiKeyStore
+	// auditViewers may query the audit log across all users; everyone
+	// else is pinned to their own events.
+	auditViewers map[string]bool
+}
+
+// NewSSEHandler creates the handler for the given AI server. A nil
+// limiter constructs the backend the configuration asks for.
+func NewSSEHandler(aiServer *AIServer, cfg *config.Config, limiter ratelimit.Limiter) *SSEHandler {
+	if limiter == nil {
+		limiter = ratelimit.FromConfig(cfg, ratelimit.ScopeChat)
+	}
+	return &SSEHandler{
+		aiServer:          aiServer,
+		limiter:           limiter,
+		idempotency:       newIdempotencyCache(),
+		writeTimeout:      cfg.Limits.WriteTimeout,
+		heartbeatInterval: cfg.Limits.HeartbeatInterval,
+		apiKeys:           newAPIKeyStore(cfg),
+		auditViewers:      viewerSet(cfg.AuditViewers),
+	}
+}
+
+// ApplyConfig applies reloaded settings that are safe to change at
+// runtime: the write timeout, the chat rate limit and window, the
+// heartbeat interval, the API key set and the audit viewer list.
+func (h *SSEHandler) ApplyConfig(cfg *config.Config) {
+	h.limiter.Configure(cfg.Limits.ChatRateLimit, cfg.Limits.ChatRateWindow)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeTimeout = cfg.Limits.WriteTimeout
+	h.heartbeatInterval = cfg.Limits.HeartbeatInterval
+	h.apiKeys = newAPIKeyStore(cfg)
+	h.auditViewers = viewerSet(cfg.AuditViewers)
+}
+
+// currentWriteTimeout returns the write timeout under the lock, since a
+// configuration reload may swap it concurrently.
+func (h *SSEHandler) currentWriteTimeout() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.writeTimeout
+}
+
+// apiKeyStore returns the API key store under the lock.
+func (h *SSEHandler) apiKeyStore() *apiKeyStore {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.apiKeys
+}
+
+// Chat handles POST /apis/v2beta1/ai/chat and streams the turn as SSE.
+func (h *SSEHandler) Chat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", r.Method))
+		return
+	}
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if !h.allowRequest(userId) {
+		h.writeError(w, http.StatusTooManyRequests, fmt.Errorf("too many chat requests, retry later"))
+		return
+	}
+	if shed, retryAfter := h.aiServer.Overloaded(); shed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		h.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("the assistant is overloaded, retry later"))
+		return
+	}
+
+	var request ChatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatRequestBytes)).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid chat request body: %w", err))
+		return
+	}
+	filter, err := newEventFilter(request.EventTypes)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// A client that lost its stream mid-turn retries with the ID of the
+	// last event it processed; the session's buffer replays the rest
+	// instead of starting a new turn.
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		h.resumeChat(w, r, userId, request.SessionID, lastEventID, filter)
+		return
+	}
+	mode, err := tool.ParseMode(request.Mode)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if request.QuickAction != "" {
+		message, err := h.aiServer.QuickActions().Expand(request.QuickAction, request.QuickActionParameters)
+		if err != nil {
+			h.writeError(w, httpStatus(err), err)
+			return
+		}
+		request.Message = message
+	}
+	if request.Namespace == "" {
+		// API-key identities can carry a configured default namespace.
+		request.Namespace = h.apiKeyStore().namespaceFor(userId)
+	}
+	session, err := h.aiServer.SessionManager().GetOrCreate(request.SessionID, userId, request.Namespace, mode, request.Timezone)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	if request.DisableTelemetry {
+		h.aiServer.SessionManager().OptOutOfTelemetry(session)
+	}
+
+	// The response controller gives working flush and deadline control on
+	// both HTTP/1.1 and HTTP/2. The stream clears its write deadline so a
+	// server-wide write timeout cannot cut off long turns.
+	rc := http.NewResponseController(w)
+	setWriteDeadline(w, 0)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache, private")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		glog.Errorf("AI chat stream unsupported by the connection: %v", err)
+		return
+	}
+
+	// Tools authorize through the resource manager, which reads the
+	// identity from gRPC metadata; mirror what grpc-gateway would attach.
+	// The resolved identity also covers API-key callers, which present no
+	// identity header of their own.
+	//
+	// The turn's context is detached from the connection: a dropped
+	// client no longer aborts the turn. It keeps running against the
+	// session's replay buffer — so a reconnect can resume the stream —
+	// and the cancel endpoint aborts it explicitly.
+	ctx := metadata.NewIncomingContext(context.WithoutCancel(r.Context()), metadata.Pairs(
+		strings.ToLower(common.GetKubeflowUserIDHeader()), common.GetKubeflowUserIDPrefix()+userId,
+	))
+
+	var writeMu sync.Mutex
+	stopHeartbeat := h.startHeartbeat(w, rc, &writeMu)
+	defer stopHeartbeat()
+	eventLog := session.eventLog()
+	detach := eventLog.Subscribe(func(ev numberedEvent) error {
+		if !filter.allows(ev.Event.Type) {
+			return nil
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return writeSSEEvent(w, rc, ev)
+	})
+	defer detach()
+	if err := h.aiServer.StreamChat(ctx, session, request.Message, eventLog.Record); err != nil {
+		glog.Errorf("AI chat turn failed for session %v: %+v", session.ID, err)
+		// The stream is already open; report the failure in-band.
+		_ = eventLog.Record(Event{Type: EventTypeError, Payload: &ErrorPayload{Message: externalMessage(err)}})
+		_ = eventLog.Record(Event{Type: EventTypeDone})
+	}
+}
+
+// resumeChat replays the events a briefly disconnected client missed
+// and, while the turn is still running, carries the rest of its stream
+// on the new connection.
+func (h *SSEHandler) resumeChat(w http.ResponseWriter, r *http.Request, userId, sessionId, lastEventID string, filter eventFilter) {
+	lastID, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || lastID < 0 {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid Last-Event-ID header %q", lastEventID))
+		return
+	}
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("session_id is required to resume a stream"))
+		return
+	}
+	session, err := h.aiServer.SessionManager().Get(sessionId, userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	setWriteDeadline(w, 0)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache, private")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		glog.Errorf("AI chat stream unsupported by the connection: %v", err)
+		return
+	}
+
+	var writeMu sync.Mutex
+	stopHeartbeat := h.startHeartbeat(w, rc, &writeMu)
+	defer stopHeartbeat()
+	var once sync.Once
+	done := make(chan struct{})
+	// Replayed done events belong to finished turns; only a done event
+	// streamed live ends the wait below.
+	replaying := true
+	sink := func(ev numberedEvent) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if filter.allows(ev.Event.Type) {
+			if err := writeSSEEvent(w, rc, ev); err != nil {
+				return err
+			}
+		}
+		if !replaying && ev.Event.Type == EventTypeDone {
+			once.Do(func() { close(done) })
+		}
+		return nil
+	}
+	detach, err := session.eventLog().Resume(lastID, sink)
+	if err != nil {
+		glog.V(2).Infof("AI session %v: resumed stream dropped during replay: %v", session.ID, err)
+		return
+	}
+	defer detach()
+	writeMu.Lock()
+	replaying = false
+	writeMu.Unlock()
+	// The turn may have finished while its done event counted as replay;
+	// the check runs after the flag flip so that case returns instead of
+	// waiting forever.
+	if !session.turnInFlight() {
+		return
+	}
+	select {
+	case <-done:
+	case <-r.Context().Done():
+	}
+}
+
+// ApproveRequest is the body of the approve endpoint.
+type ApproveRequest struct {
+	SessionID  string `json:"session_id"`
+	ToolCallID string `json:"tool_call_id"`
+	Approved   bool   `json:"approved"`
+	// IdempotencyKey deduplicates network-level retries: the response to
+	// the first submission carrying a key is replayed for later ones,
+	// instead of failing with "no pending confirmation" after the first
+	// already resolved the call.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// Approve handles POST /apis/v2beta1/ai/chat/approve, resolving a
+// pending mutating tool call.
+func (h *SSEHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", r.Method))
+		return
+	}
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	var request ApproveRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatRequestBytes)).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid approve request body: %w", err))
+		return
+	}
+	if request.SessionID == "" || request.ToolCallID == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("session_id and tool_call_id are required"))
+		return
+	}
+	// Keys are scoped per user so one caller cannot replay another's
+	// responses.
+	idempotencyKey := ""
+	if request.IdempotencyKey != "" {
+		idempotencyKey = userId + "\x00" + request.IdempotencyKey
+		if cached, ok := h.idempotency.Lookup(idempotencyKey); ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(cached.status)
+			w.Write(cached.body)
+			return
+		}
+	}
+	status, body := http.StatusOK, []byte(`{}`)
+	if err := h.aiServer.ApproveToolCall(request.SessionID, userId, request.ToolCallID, request.Approved); err != nil {
+		glog.Errorf("AI approve failed for session %v: %+v", request.SessionID, err)
+		status = httpStatus(err)
+		body, _ = json.Marshal(map[string]string{"error": externalMessage(err)})
+	}
+	if idempotencyKey != "" {
+		h.idempotency.Store(idempotencyKey, status, body)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// CancelRequest is the body of the cancel endpoint.
+type CancelRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// Cancel handles POST /apis/v2beta1/ai/chat/cancel, aborting the
+// session's in-flight chat turn server-side instead of relying on the
+// client dropping the SSE connection.
+func (h *SSEHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	var request CancelRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatRequestBytes)).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid cancel request body: %w", err))
+		return
+	}
+	if request.SessionID == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("session_id is required"))
+		return
+	}
+	if err := h.aiServer.CancelTurn(request.SessionID, userId); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+// PendingConfirmation handles GET /apis/v2beta1/ai/sessions/{session_id}/pending.
+// It returns the confirmation the session is currently blocked on, if
+// any, so a client that lost its SSE stream can restore the approval
+// card after reconnecting.
+func (h *SSEHandler) PendingConfirmation(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	session, err := h.aiServer.SessionManager().Get(sessionId, userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"pending": session.PendingConfirmation()})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// ConfirmationArguments handles GET
+// /apis/v2beta1/ai/sessions/{session_id}/confirmations/{tool_call_id}/arguments.
+// It serves the full arguments of a pending confirmation whose
+// confirmation_request event was summarized because the payload was too
+// large to embed inline.
+func (h *SSEHandler) ConfirmationArguments(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	toolCallId := mux.Vars(r)["tool_call_id"]
+	if sessionId == "" || toolCallId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("session_id and tool_call_id path parameters are required"))
+		return
+	}
+	session, err := h.aiServer.SessionManager().Get(sessionId, userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	arguments, err := session.pendingArguments(toolCallId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]string{"arguments_json": arguments})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// Usage handles GET /apis/v2beta1/ai/usage. It reports the caller's
+// token consumption: the running total since the last apiserver restart
+// and a per-session breakdown over the sessions still in the store.
+func (h *SSEHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(h.aiServer.SessionManager().UsageForUser(userId))
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// ListSessions handles GET /apis/v2beta1/ai/sessions. It returns the
+// authenticated user's sessions as listing summaries, so the client can
+// render a "previous conversations" sidebar. With ?deleted=true it
+// lists the user's soft-deleted sessions awaiting purge instead.
+func (h *SSEHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	list := h.aiServer.SessionManager().ListForUser
+	if r.URL.Query().Get("deleted") == "true" {
+		// Soft-deleted sessions still within their restore window.
+		list = h.aiServer.SessionManager().ListDeletedForUser
+	}
+	sessions, err := list(userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"sessions": sessions})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// GetSession handles GET /apis/v2beta1/ai/sessions/{session_id}. It
+// returns the full message history plus any pending confirmation, so a
+// client can resume a conversation after a page reload.
+func (h *SSEHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	detail, err := h.aiServer.SessionManager().DetailForUser(sessionId, userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(detail)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// DeleteSession handles DELETE /apis/v2beta1/ai/sessions/{session_id}.
+// With session persistence enabled the session is only soft-deleted and
+// can be restored until the configured retention elapses.
+func (h *SSEHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	if err := h.aiServer.SessionManager().Delete(sessionId, userId); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+// RestoreSession handles POST /apis/v2beta1/ai/sessions/{session_id}/restore,
+// undoing a soft delete while the session is still within its retention
+// window.
+func (h *SSEHandler) RestoreSession(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	if err := h.aiServer.SessionManager().Restore(sessionId, userId); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+// ExportSession handles GET /apis/v2beta1/ai/sessions/{session_id}/export.
+// It returns the transcript, including tool calls and results, as a
+// downloadable markdown or JSON document (format query parameter).
+func (h *SSEHandler) ExportSession(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	export, err := h.aiServer.SessionManager().Export(sessionId, userId, r.URL.Query().Get("format"))
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Cache-Control", "no-cache, private")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	w.Write(export.Content)
+}
+
+// DebugPrompt handles GET /apis/v2beta1/ai/sessions/{session_id}/debug/prompt.
+// It returns the exact system prompt, tool list and model parameters the
+// next turn of the session would use, so prompt issues can be diagnosed
+// without temporary logging.
+func (h *SSEHandler) DebugPrompt(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	debug, err := h.aiServer.DebugPrompt(sessionId, userId)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(debug)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// QuickActions handles GET /apis/v2beta1/ai/quick_actions and returns
+// the quick action catalog for the client to render.
+func (h *SSEHandler) QuickActions(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	if _, err := h.userIdentity(r); err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(map[string]interface{}{"quick_actions": h.aiServer.QuickActions().List()})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// SwitchModeRequest is the body of a mode switch request.
+type SwitchModeRequest struct {
+	// Mode is the target mode, "ask" or "agent".
+	Mode string `json:"mode"`
+}
+
+// SwitchMode handles POST /apis/v2beta1/ai/sessions/{session_id}/mode.
+// It switches the session between Ask and Agent mode in place and
+// returns the refreshed session metadata, mirroring the event pushed on
+// an open stream.
+func (h *SSEHandler) SwitchMode(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	sessionId := mux.Vars(r)["session_id"]
+	if sessionId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("a session_id path parameter is required"))
+		return
+	}
+	var request SwitchModeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChatRequestBytes)).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid mode switch request body: %w", err))
+		return
+	}
+	metadata, err := h.aiServer.SwitchMode(sessionId, userId, request.Mode)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(body)
+}
+
+// DownloadLog handles GET on LogDownloadPath. The request is authorized
+// by the pre-signed token minted by the get_log_download_link tool, not
+// by the caller's identity, so links can be opened outside the chat UI.
+func (h *SSEHandler) DownloadLog(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	query := r.URL.Query()
+	runId := query.Get("run_id")
+	nodeId := query.Get("node_id")
+	if runId == "" || nodeId == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("run_id and node_id are required"))
+		return
+	}
+	if err := h.aiServer.logLinks.Verify(runId, nodeId, query.Get("expires"), query.Get("token")); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Cache-Control", "no-cache, private")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nodeId+".log"))
+	if err := h.aiServer.resourceManager.ReadLog(r.Context(), runId, nodeId, false, w); err != nil {
+		glog.Errorf("AI log download failed for run %v pod %v: %+v", runId, nodeId, err)
+	}
+}
+
+// UserIdentity resolves the caller for surfaces that reuse the AI
+// authentication scheme, such as the MCP endpoint.
+func (h *SSEHandler) UserIdentity(r *http.Request) (string, error) {
+	return h.userIdentity(r)
+}
+
+// userIdentity resolves the caller, either from a configured API key
+// presented as a bearer token (non-browser clients) or from the identity
+// header. Outside multi-user mode an anonymous identity is used,
+// matching the behavior of the rest of the API surface.
+func (h *SSEHandler) userIdentity(r *http.Request) (string, error) {
+	if token := bearerToken(r); token != "" {
+		identity, ok := h.apiKeyStore().identityFor(token)
+		if !ok {
+			return "", fmt.Errorf("the presented API key is not recognized")
+		}
+		return identity, nil
+	}
+	header := r.Header.Get(common.GetKubeflowUserIDHeader())
+	if header == "" {
+		if common.IsMultiUserMode() {
+			return "", fmt.Errorf("a user identity header is required in multi-user mode")
+		}
+		return anonymousUser, nil
+	}
+	return strings.TrimPrefix(header, common.GetKubeflowUserIDPrefix()), nil
+}
+
+// startHeartbeat writes SSE comment pings on the stream until the
+// returned stop function is called. Long tool executions and pending
+// confirmations produce no bytes for minutes, and many ingress
+// controllers kill connections they consider idle; the pings keep the
+// stream alive without appearing in the client's event stream. Writes
+// share writeMu with the event writer. A failing ping stops the loop;
+// the event writer discovers the dead connection on its own.
+func (h *SSEHandler) startHeartbeat(w http.ResponseWriter, rc *http.ResponseController, writeMu *sync.Mutex) func() {
+	h.mu.Lock()
+	interval := h.heartbeatInterval
+	h.mu.Unlock()
+	if interval <= 0 {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				writeMu.Lock()
+				_, err := fmt.Fprint(w, ": ping\n\n")
+				if err == nil {
+					err = rc.Flush()
+				}
+				writeMu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		// Wait for the goroutine: the ResponseWriter must not be touched
+		// after the handler returns, and a ping may be mid-write.
+		<-exited
+	}
+}
+
+// userIdentityFromContext resolves the caller of a gRPC request from the
+// incoming metadata, accepting the same credentials as userIdentity: a
+// configured API key presented as a bearer token in the authorization
+// metadata, or the identity header.
+func (h *SSEHandler) userIdentityFromContext(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) == 1 && strings.HasPrefix(auth[0], "Bearer ") {
+		token := strings.TrimSpace(strings.TrimPrefix(auth[0], "Bearer "))
+		identity, ok := h.apiKeyStore().identityFor(token)
+		if !ok {
+			return "", fmt.Errorf("the presented API key is not recognized")
+		}
+		return identity, nil
+	}
+	headers := md.Get(strings.ToLower(common.GetKubeflowUserIDHeader()))
+	if len(headers) == 0 {
+		if common.IsMultiUserMode() {
+			return "", fmt.Errorf("a user identity header is required in multi-user mode")
+		}
+		return anonymousUser, nil
+	}
+	if len(headers) != 1 {
+		return "", fmt.Errorf("expected exactly one user identity header, got %d", len(headers))
+	}
+	return strings.TrimPrefix(headers[0], common.GetKubeflowUserIDPrefix()), nil
+}
+
+// allowRequest applies the per-user chat rate limit.
+func (h *SSEHandler) allowRequest(userId string) bool {
+	ok, _ := h.limiter.Allow(userId)
+	return ok
+}
+
+func writeSSEEvent(w http.ResponseWriter, rc *http.ResponseController, ev numberedEvent) error {
+	event := ev.Event
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to marshal an SSE event payload")
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, event.Type, payload); err != nil {
+		return util.Wrap(err, "Failed to write to the SSE stream")
+	}
+	if err := rc.Flush(); err != nil {
+		return util.Wrap(err, "Failed to flush the SSE stream")
+	}
+	return nil
+}
+
+func (h *SSEHandler) writeError(w http.ResponseWriter, code int, err error) {
+	writeJSONError(w, code, err)
+}
+
+func writeJSONError(w http.ResponseWriter, code int, err error) {
+	glog.Errorf("AI endpoint error (HTTP %v): %+v", code, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	body, _ := json.Marshal(map[string]string{"error": externalMessage(err)})
+	w.Write(body)
+}
+
+// externalMessage extracts the user-safe message from an error.
+func externalMessage(err error) string {
+	if errors.Is(err, context.Canceled) {
+		// Reached when the cancel endpoint aborted the turn.
+		return "The chat turn was cancelled."
+	}
+	if userErr, ok := err.(*util.UserError); ok {
+		return userErr.ExternalMessage()
+	}
+	return err.Error()
+}
+
+// httpStatus maps an error onto an HTTP status code.
+func httpStatus(err error) int {
+	userErr, ok := err.(*util.UserError)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	switch userErr.ExternalStatusCode() {
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sync"
+)
+
+// maxBufferedEvents bounds the per-session replay buffer. A turn rarely
+// produces more than a few dozen events; the cap only matters for turns
+// with very chatty tool progress, which lose their oldest events first.
+const maxBufferedEvents = 256
+
+// numberedEvent is a stream event with its session-scoped sequence ID,
+// written as the SSE id field.
+type numberedEvent struct {
+	ID    int64
+	Event Event
+}
+
+// eventBuffer assigns each of a session's stream events a monotonically
+// increasing ID, keeps the recent ones for replay, and forwards live
+// events to the attached connection. A client that briefly disconnects
+// resumes by sending the last ID it processed: the buffer replays what
+// was missed and the new connection takes over the live stream.
+//
+// Losing the connection no longer fails the turn; a write error just
+// detaches the dead connection while the turn keeps running against the
+// buffer.
+type eventBuffer struct {
+	mu     sync.Mutex
+	nextID int64
+	events []numberedEvent
+	// sink is the currently attached connection; sinkGen invalidates
+	// stale detach calls after a newer connection attached.
+	sink    func(numberedEvent) error
+	sinkGen int64
+}
+
+func newEventBuffer() *eventBuffer {
+	return &eventBuffer{nextID: 1}
+}
+
+// Record assigns the next ID, buffers the event and forwards it to the
+// attached connection, if any. It never fails: a connection that cannot
+// be written is detached and the caller's turn continues.
+func (b *eventBuffer) Record(event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ev := numberedEvent{ID: b.nextID, Event: event}
+	b.nextID++
+	b.events = append(b.events, ev)
+	if over := len(b.events) - maxBufferedEvents; over > 0 {
+		b.events = b.events[over:]
+	}
+	if b.sink != nil {
+		if err := b.sink(ev); err != nil {
+			b.sink = nil
+		}
+	}
+	return nil
+}
+
+// Subscribe attaches a connection for events recorded from now on and
+// returns its detach function. Any previously attached connection is
+// replaced.
+func (b *eventBuffer) Subscribe(sink func(numberedEvent) error) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.attachLocked(sink)
+}
+
+// Resume replays the buffered events after lastID through sink and then
+// attaches it like Subscribe. A replay write failure leaves the buffer
+// unattached and is returned.
+func (b *eventBuffer) Resume(lastID int64, sink func(numberedEvent) error) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ev := range b.events {
+		if ev.ID <= lastID {
+			continue
+		}
+		if err := sink(ev); err != nil {
+			return nil, err
+		}
+	}
+	return b.attachLocked(sink), nil
+}
+
+func (b *eventBuffer) attachLocked(sink func(numberedEvent) error) func() {
+	b.sinkGen++
+	gen := b.sinkGen
+	b.sink = sink
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.sinkGen == gen {
+			b.sink = nil
+		}
+	}
+}
+
+// eventLog returns the session's replay buffer, creating it on first
+// use so restored sessions behave like freshly created ones.
+func (s *Session) eventLog() *eventBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events == nil {
+		s.events = newEventBuffer()
+	}
+	return s.events
+}
+
+// turnInFlight reports whether a chat turn is currently running.
+func (s *Session) turnInFlight() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBuffer_NumbersEventsAndReplaysAfterTheLastSeenID(t *testing.T) {
+	b := newEventBuffer()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, b.Record(Event{Type: EventTypeMarkdown}))
+	}
+
+	var replayed []int64
+	detach, err := b.Resume(2, func(ev numberedEvent) error {
+		replayed = append(replayed, ev.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 5}, replayed)
+
+	// The sink stays attached for live events until detached.
+	require.NoError(t, b.Record(Event{Type: EventTypeDone}))
+	assert.Equal(t, []int64{3, 4, 5, 6}, replayed)
+	detach()
+	require.NoError(t, b.Record(Event{Type: EventTypeMarkdown}))
+	assert.Equal(t, []int64{3, 4, 5, 6}, replayed)
+}
+
+func TestEventBuffer_DetachesAFailingSinkWithoutFailingTheTurn(t *testing.T) {
+	b := newEventBuffer()
+	writes := 0
+	b.Subscribe(func(numberedEvent) error {
+		writes++
+		return fmt.Errorf("connection reset")
+	})
+
+	// The recording side never sees the dead connection.
+	require.NoError(t, b.Record(Event{Type: EventTypeMarkdown}))
+	require.NoError(t, b.Record(Event{Type: EventTypeMarkdown}))
+	assert.Equal(t, 1, writes)
+
+	// The events are still buffered for a later resume.
+	var replayed []int64
+	_, err := b.Resume(0, func(ev numberedEvent) error {
+		replayed = append(replayed, ev.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, replayed)
+}
+
+func TestEventBuffer_CapsTheBufferAtTheOldestEvents(t *testing.T) {
+	b := newEventBuffer()
+	for i := 0; i < maxBufferedEvents+10; i++ {
+		require.NoError(t, b.Record(Event{Type: EventTypeMarkdown}))
+	}
+	var first int64
+	_, err := b.Resume(0, func(ev numberedEvent) error {
+		if first == 0 {
+			first = ev.ID
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), first)
+}
+
+func replayFixture() *SSEHandler {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &summaryStubModel{reply: "Run 42 failed because its main container was OOM killed."},
+	}
+	return NewSSEHandler(server, &config.Config{Limits: config.Limits{ChatRateLimit: 10}}, nil)
+}
+
+func TestChat_EventsCarrySequentialIDs(t *testing.T) {
+	h := replayFixture()
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"message":"why did my run fail?"}`))
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "id: 1\nevent: session_metadata")
+	assert.Contains(t, body, "event: markdown")
+	assert.Contains(t, body, "event: done")
+}
+
+func TestChat_ResumeReplaysTheMissedEvents(t *testing.T) {
+	h := replayFixture()
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"message":"why did my run fail?"}`))
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+	sessions, err := h.aiServer.SessionManager().ListForUser(anonymousUser)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// Reconnect claiming to have processed only the first event.
+	r = httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat",
+		strings.NewReader(fmt.Sprintf(`{"session_id":%q}`, sessions[0].SessionID)))
+	r.Header.Set("Last-Event-ID", "1")
+	w = httptest.NewRecorder()
+	h.Chat(w, r)
+
+	body := w.Body.String()
+	assert.NotContains(t, body, "id: 1\n")
+	assert.Contains(t, body, "event: markdown")
+	assert.Contains(t, body, "OOM killed")
+	assert.Contains(t, body, "event: done")
+}
+
+func TestChat_ResumeRejectsAnInvalidLastEventID(t *testing.T) {
+	h := replayFixture()
+	r := httptest.NewRequest(http.MethodPost, "/apis/v2beta1/ai/chat", strings.NewReader(`{"session_id":"s1"}`))
+	r.Header.Set("Last-Event-ID", "not-a-number")
+	w := httptest.NewRecorder()
+	h.Chat(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStreamChat_KeepsRunningWhenTheConnectionDies(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &summaryStubModel{reply: "Run 42 failed because its main container was OOM killed."},
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	// A connection that dies on the first write: the turn must finish
+	// against the buffer anyway.
+	log := session.eventLog()
+	log.Subscribe(func(numberedEvent) error { return fmt.Errorf("broken pipe") })
+	require.NoError(t, server.StreamChat(t.Context(), session, "why did my run fail?", log.Record))
+
+	messages := session.Messages()
+	require.NotEmpty(t, messages)
+	last := messages[len(messages)-1]
+	assert.Equal(t, provider.RoleAssistant, last.Role)
+	assert.Contains(t, last.Content, "OOM killed")
+}
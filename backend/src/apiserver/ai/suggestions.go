@@ -0,0 +1,117 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+)
+
+const (
+	// maxSuggestions caps how many follow-up chips a turn produces.
+	maxSuggestions = 3
+	// suggestionsMaxTokens bounds the constrained second pass; the JSON
+	// array of three short suggestions fits comfortably within it.
+	suggestionsMaxTokens = 400
+)
+
+// suggestionsSystemPrompt constrains the second model pass to structured
+// output the handler can parse without guesswork.
+const suggestionsSystemPrompt = `You generate quick-reply suggestions for a Kubeflow Pipelines assistant UI.
+Given the conversation so far, reply with ONLY a JSON array of at most 3 objects, no prose or code fences.
+Each object has a short "label" (under 6 words) and either a "message" with the full chat message the
+suggestion would send, or a "tool" with the tool name and "arguments_json" with its JSON arguments.
+Suggest concrete next steps grounded in the conversation, e.g. drilling into a failing run that was
+mentioned. Reply with [] if no follow-up is useful.`
+
+// emitSuggestions runs a constrained second model pass over the finished
+// turn and emits the resulting follow-up actions as a suggestions event.
+// Suggestions are best-effort decoration: any failure is logged and the
+// turn still completes normally.
+func (s *AIServer) emitSuggestions(ctx context.Context, session *Session, emit func(Event) error) {
+	suggestions, err := s.generateSuggestions(ctx, session)
+	if err != nil {
+		glog.V(2).Infof("Skipping suggestions for AI session %v: %v", session.ID, err)
+		return
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+	if err := emit(Event{Type: EventTypeSuggestions, Payload: &SuggestionsPayload{Suggestions: suggestions}}); err != nil {
+		glog.V(2).Infof("Failed to emit suggestions for AI session %v: %v", session.ID, err)
+	}
+}
+
+// generateSuggestions asks the model for follow-up actions without tools
+// and with a tight token budget, then parses the structured reply.
+func (s *AIServer) generateSuggestions(ctx context.Context, session *Session) ([]Suggestion, error) {
+	var text strings.Builder
+	req := &provider.ChatRequest{
+		System:    suggestionsSystemPrompt,
+		Messages:  session.Messages(),
+		MaxTokens: suggestionsMaxTokens,
+	}
+	err := s.model.StreamChat(ctx, req, func(event provider.StreamEvent) error {
+		if event.Type == provider.StreamEventTextDelta {
+			text.WriteString(event.Delta.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.parseSuggestions(text.String(), session.Mode), nil
+}
+
+// parseSuggestions extracts the suggestion array from the model reply,
+// dropping malformed entries and tool suggestions that do not resolve to
+// a tool available in the session's mode. A reply that is not valid JSON
+// yields no suggestions rather than an error.
+func (s *AIServer) parseSuggestions(text string, mode tool.Mode) []Suggestion {
+	// Tolerate prose or code fences around the array by slicing from the
+	// first '[' to the last ']'.
+	start := strings.Index(text, "[")
+	end := strings.LastIndex(text, "]")
+	if start < 0 || end <= start {
+		return nil
+	}
+	var parsed []Suggestion
+	if err := json.Unmarshal([]byte(text[start:end+1]), &parsed); err != nil {
+		glog.V(2).Infof("Discarding unparseable suggestions reply: %v", err)
+		return nil
+	}
+	suggestions := make([]Suggestion, 0, maxSuggestions)
+	for _, suggestion := range parsed {
+		if suggestion.Label == "" || (suggestion.Message == "" && suggestion.Tool == "") {
+			continue
+		}
+		if suggestion.Tool != "" {
+			t := s.registry.Get(suggestion.Tool)
+			if t == nil || (t.Mutating() && mode != tool.ModeAgent) {
+				continue
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+	}
+	return suggestions
+}
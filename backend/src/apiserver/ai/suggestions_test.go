@@ -0,0 +1,70 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func suggestionsServer() *AIServer {
+	return &AIServer{registry: tool.NewRegistry()}
+}
+
+func TestParseSuggestions_ExtractsArrayFromFencedReply(t *testing.T) {
+	reply := "Here are some ideas:\n```json\n[" +
+		`{"label":"Inspect the failure","message":"Show me the logs of the failed run"},` +
+		`{"label":"Compare runs","message":"Compare this run with the last green one"}` +
+		"]\n```"
+	suggestions := suggestionsServer().parseSuggestions(reply, tool.ModeAsk)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "Inspect the failure", suggestions[0].Label)
+	assert.Equal(t, "Show me the logs of the failed run", suggestions[0].Message)
+}
+
+func TestParseSuggestions_ToleratesGarbage(t *testing.T) {
+	s := suggestionsServer()
+	assert.Empty(t, s.parseSuggestions("no array here", tool.ModeAsk))
+	assert.Empty(t, s.parseSuggestions("[not json", tool.ModeAsk))
+	assert.Empty(t, s.parseSuggestions(`[{"label":"","message":"dropped: empty label"}]`, tool.ModeAsk))
+	assert.Empty(t, s.parseSuggestions(`[{"label":"dropped: no action"}]`, tool.ModeAsk))
+}
+
+func TestParseSuggestions_FiltersToolsByMode(t *testing.T) {
+	s := suggestionsServer()
+	reply := `[
+		{"label":"Stop it","tool":"stop_run","arguments_json":"{\"run_id\":\"r1\"}"},
+		{"label":"Unknown","tool":"no_such_tool","arguments_json":"{}"}
+	]`
+
+	assert.Empty(t, s.parseSuggestions(reply, tool.ModeAsk))
+
+	suggestions := s.parseSuggestions(reply, tool.ModeAgent)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "stop_run", suggestions[0].Tool)
+}
+
+func TestParseSuggestions_CapsAtThree(t *testing.T) {
+	reply := `[
+		{"label":"a","message":"a"},
+		{"label":"b","message":"b"},
+		{"label":"c","message":"c"},
+		{"label":"d","message":"d"}
+	]`
+	assert.Len(t, suggestionsServer().parseSuggestions(reply, tool.ModeAsk), maxSuggestions)
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sessionHistorySummarizations = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "ai_session_manager_history_summarizations",
+	Help: "The total number of times a session history was compacted into a rolling summary",
+})
+
+// summaryNotePrefix marks the synthetic message carrying the rolling
+// summary. It reuses the system-note convention so the message is
+// skipped by title derivation and recognizable to the model.
+const summaryNotePrefix = "[System note] Summary of the earlier conversation: "
+
+// summaryInstruction is the prompt driving the default summarizer.
+const summaryInstruction = "Summarize the following assistant conversation for your own future reference. " +
+	"Keep every finding, decision, resource name and ID (runs, pipelines, experiments) that later turns may need; " +
+	"drop pleasantries and raw tool output that was already interpreted. Reply with the summary only.\n\n"
+
+// summaryMaxTokens bounds the summarizer's reply.
+const summaryMaxTokens = 1024
+
+// compactHistory condenses the oldest messages of a full session into a
+// rolling summary note, so long debugging sessions keep their earlier
+// findings instead of silently losing them to the message cap. The most
+// recent half of the allowance stays verbatim; everything older —
+// including a previous summary note, which makes the summary rolling —
+// is replaced. On failure the history is left alone and the hard trim
+// in AppendMessage remains the backstop.
+func (s *AIServer) compactHistory(ctx context.Context, session *Session) {
+	limit := session.historyLimit()
+	snapshot := session.Messages()
+	if len(snapshot) < limit {
+		return
+	}
+	keep := limit / 2
+	overflow := snapshot[:len(snapshot)-keep]
+	summary, err := s.summarize(ctx, overflow)
+	if err != nil {
+		glog.Warningf("Failed to summarize the history of AI session %v; keeping it unchanged: %v", session.ID, err)
+		return
+	}
+	session.compactTo(len(overflow), provider.Message{
+		Role:    provider.RoleUser,
+		Content: summaryNotePrefix + strings.TrimSpace(summary),
+	})
+	sessionHistorySummarizations.Inc()
+	glog.V(2).Infof("Compacted %v message(s) of AI session %v into a rolling summary", len(overflow), session.ID)
+}
+
+// summarize produces a summary of the given conversation prefix. Models
+// implementing provider.Summarizer condense it natively; every other
+// model is driven through a plain chat call with a summarization prompt.
+func (s *AIServer) summarize(ctx context.Context, messages []provider.Message) (string, error) {
+	if summarizer, ok := s.model.(provider.Summarizer); ok {
+		return summarizer.Summarize(ctx, messages)
+	}
+	var prompt strings.Builder
+	prompt.WriteString(summaryInstruction)
+	for _, m := range messages {
+		fmt.Fprintf(&prompt, "%s: %s\n", m.Role, m.Content)
+		for _, call := range m.ToolCalls {
+			fmt.Fprintf(&prompt, "%s called %s(%s)\n", m.Role, call.Name, call.ArgumentsJSON)
+		}
+	}
+	var summary strings.Builder
+	err := s.model.StreamChat(ctx, &provider.ChatRequest{
+		Messages:  []provider.Message{{Role: provider.RoleUser, Content: prompt.String()}},
+		MaxTokens: summaryMaxTokens,
+	}, func(event provider.StreamEvent) error {
+		if event.Type == provider.StreamEventTextDelta {
+			summary.WriteString(event.Delta.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", util.Wrap(err, "The summarization call failed")
+	}
+	if strings.TrimSpace(summary.String()) == "" {
+		return "", util.NewInternalServerError(fmt.Errorf("the model returned no text"), "The summarization call produced an empty summary")
+	}
+	return summary.String(), nil
+}
+
+// historyLimit returns the session's message cap.
+func (s *Session) historyLimit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxMessages > 0 {
+		return s.maxMessages
+	}
+	return config.DefaultMaxMessagesPerSession
+}
+
+// compactTo replaces the session's oldest n messages with the rolling
+// summary note.
+func (s *Session) compactTo(n int, note provider.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.messages) {
+		n = len(s.messages)
+	}
+	s.messages = append([]provider.Message{note}, s.messages[n:]...)
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summaryStubModel answers every chat call with a fixed summary text.
+type summaryStubModel struct {
+	reply      string
+	err        error
+	lastPrompt string
+}
+
+func (m *summaryStubModel) Name() string { return "stub" }
+
+func (m *summaryStubModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	m.lastPrompt = req.Messages[len(req.Messages)-1].Content
+	if m.err != nil {
+		return m.err
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: m.reply}}); err != nil {
+		return err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
+
+// nativeSummaryModel additionally implements provider.Summarizer.
+type nativeSummaryModel struct {
+	summaryStubModel
+	summarized bool
+}
+
+func (m *nativeSummaryModel) Summarize(ctx context.Context, messages []provider.Message) (string, error) {
+	m.summarized = true
+	return "native summary", nil
+}
+
+func summarizeFixture(t *testing.T, model provider.ChatModel, maxMessages int) (*AIServer, *Session) {
+	t.Helper()
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, maxMessages: maxMessages}
+	server := &AIServer{sessionManager: m, model: model}
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	return server, session
+}
+
+func TestCompactHistory_RollsOldMessagesIntoASummaryNote(t *testing.T) {
+	model := &summaryStubModel{reply: "alice debugged run 42; it failed on OOM"}
+	server, session := summarizeFixture(t, model, 8)
+	for i := 0; i < 8; i++ {
+		session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: fmt.Sprintf("message %v", i)})
+	}
+
+	server.compactHistory(context.Background(), session)
+
+	messages := session.Messages()
+	// The oldest half was condensed; the most recent half stays verbatim.
+	require.Len(t, messages, 5)
+	assert.True(t, strings.HasPrefix(messages[0].Content, summaryNotePrefix))
+	assert.Contains(t, messages[0].Content, "failed on OOM")
+	assert.Equal(t, "message 4", messages[1].Content)
+	assert.Equal(t, "message 7", messages[4].Content)
+	// The summarized prefix, including tool traffic, reached the model.
+	assert.Contains(t, model.lastPrompt, "message 0")
+	assert.Contains(t, model.lastPrompt, "message 3")
+	assert.NotContains(t, model.lastPrompt, "message 4")
+}
+
+func TestCompactHistory_BelowTheLimitIsANoOp(t *testing.T) {
+	model := &summaryStubModel{reply: "unused"}
+	server, session := summarizeFixture(t, model, 8)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "short conversation"})
+
+	server.compactHistory(context.Background(), session)
+
+	require.Len(t, session.Messages(), 1)
+	assert.Empty(t, model.lastPrompt)
+}
+
+func TestCompactHistory_PrefersANativeSummarizer(t *testing.T) {
+	model := &nativeSummaryModel{}
+	server, session := summarizeFixture(t, model, 4)
+	for i := 0; i < 4; i++ {
+		session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: fmt.Sprintf("message %v", i)})
+	}
+
+	server.compactHistory(context.Background(), session)
+
+	assert.True(t, model.summarized)
+	assert.Empty(t, model.lastPrompt)
+	messages := session.Messages()
+	require.NotEmpty(t, messages)
+	assert.Equal(t, summaryNotePrefix+"native summary", messages[0].Content)
+}
+
+func TestCompactHistory_KeepsTheHistoryWhenSummarizationFails(t *testing.T) {
+	model := &summaryStubModel{err: errors.New("provider down")}
+	server, session := summarizeFixture(t, model, 4)
+	for i := 0; i < 4; i++ {
+		session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: fmt.Sprintf("message %v", i)})
+	}
+
+	server.compactHistory(context.Background(), session)
+
+	require.Len(t, session.Messages(), 4)
+}
+
+func TestAppendMessage_TrimKeepsALeadingSummaryNote(t *testing.T) {
+	_, session := summarizeFixture(t, &summaryStubModel{}, 3)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: summaryNotePrefix + "earlier findings"})
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "a"})
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "b"})
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "c"})
+
+	messages := session.Messages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, summaryNotePrefix+"earlier findings", messages[0].Content)
+	assert.Equal(t, "b", messages[1].Content)
+	assert.Equal(t, "c", messages[2].Content)
+}
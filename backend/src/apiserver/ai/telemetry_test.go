@@ -0,0 +1,89 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryOptOut_RequestFlagStopsPersistenceAndDropsTheSnapshot(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	require.Contains(t, store.rows, session.ID, "the creation-time snapshot is written before the opt-out")
+
+	m.OptOutOfTelemetry(session)
+	assert.NotContains(t, store.rows, session.ID)
+
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "sensitive"})
+	m.persist(session)
+	assert.NotContains(t, store.rows, session.ID)
+}
+
+func TestTelemetryOptOut_NamespaceConfigAppliesAtCreation(t *testing.T) {
+	m := &SessionManager{
+		sessions:         map[string]*Session{},
+		userUsage:        map[string]provider.Usage{},
+		maxSessions:      10,
+		optOutNamespaces: map[string]bool{"restricted": true},
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	optedOut, err := m.GetOrCreate("", "alice", "restricted", "ask", "")
+	require.NoError(t, err)
+	assert.True(t, optedOut.telemetryDisabled())
+	assert.NotContains(t, store.rows, optedOut.ID)
+
+	regular, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	assert.False(t, regular.telemetryDisabled())
+	assert.Contains(t, store.rows, regular.ID)
+}
+
+func TestTelemetryOptOut_UsageStaysOutOfTheCrossSessionReport(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+	}
+	server := &AIServer{sessionManager: m}
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	m.OptOutOfTelemetry(session)
+
+	var turnUsage provider.Usage
+	server.recordUsage(session, &turnUsage, provider.Usage{InputTokens: 10, OutputTokens: 5}, "")
+
+	// The user's own stream still sees the turn and session totals.
+	assert.Equal(t, 10, turnUsage.InputTokens)
+	assert.Equal(t, 5, session.usageSnapshot().OutputTokens)
+	// The analytics report does not.
+	report := m.UsageForUser("alice")
+	assert.Empty(t, report.Sessions)
+	assert.Zero(t, report.InputTokens)
+	assert.Zero(t, report.OutputTokens)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Raw epoch fields such as "created_at": 1718822400 routinely lead the
+// model to mis-state times. Tool results therefore get a sibling
+// "<field>_iso" rendering in the user's timezone for every epoch field.
+
+// minPlausibleEpoch filters out small integers that merely look like
+// timestamps; anything before ~1973 is not a KFP resource timestamp.
+const minPlausibleEpoch = 100000000
+
+// annotateTimestamps decodes a JSON tool result and adds ISO renderings
+// for epoch-second fields (keys ending in "_at"). The original fields
+// are preserved. Non-JSON content is returned unchanged.
+func annotateTimestamps(content string, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+		return content
+	}
+	annotated := annotateTimestampValues(decoded, loc)
+	out, err := json.Marshal(annotated)
+	if err != nil {
+		return content
+	}
+	return string(out)
+}
+
+func annotateTimestampValues(v interface{}, loc *time.Location) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if epoch, ok := child.(float64); ok && isTimestampKey(key) && epoch >= minPlausibleEpoch {
+				typed[key+"_iso"] = time.Unix(int64(epoch), 0).In(loc).Format(time.RFC3339)
+				continue
+			}
+			typed[key] = annotateTimestampValues(child, loc)
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = annotateTimestampValues(child, loc)
+		}
+		return typed
+	default:
+		return v
+	}
+}
+
+func isTimestampKey(key string) bool {
+	return strings.HasSuffix(key, "_at") || strings.HasSuffix(key, "_at_in_sec")
+}
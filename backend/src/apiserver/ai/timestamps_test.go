@@ -0,0 +1,69 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateTimestamps_AddsISOFields(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	content := `{"run":{"run_id":"r1","created_at":1718822400,"finished_at":0},"total_size":1}`
+	annotated := annotateTimestamps(content, berlin)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(annotated), &decoded))
+	run := decoded["run"].(map[string]interface{})
+	assert.Equal(t, "2024-06-19T20:40:00+02:00", run["created_at_iso"])
+	// The raw value must be preserved alongside the rendering.
+	assert.Equal(t, float64(1718822400), run["created_at"])
+	// Zero (unset) and small values are not timestamps.
+	_, ok := run["finished_at_iso"]
+	assert.False(t, ok)
+	_, ok = decoded["total_size_iso"]
+	assert.False(t, ok)
+}
+
+func TestAnnotateTimestamps_NestedArrays(t *testing.T) {
+	content := `{"runs":[{"created_at":1718822400},{"created_at":1718822401}]}`
+	annotated := annotateTimestamps(content, time.UTC)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(annotated), &decoded))
+	runs := decoded["runs"].([]interface{})
+	assert.Equal(t, "2024-06-19T18:40:00Z", runs[0].(map[string]interface{})["created_at_iso"])
+	assert.Equal(t, "2024-06-19T18:40:01Z", runs[1].(map[string]interface{})["created_at_iso"])
+}
+
+func TestAnnotateTimestamps_NonJSONUnchanged(t *testing.T) {
+	assert.Equal(t, "plain text", annotateTimestamps("plain text", time.UTC))
+}
+
+func TestSessionLocation_InvalidTimezoneRejected(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}}
+	_, err := m.GetOrCreate("", "user", "ns", "ask", "Not/AZone")
+	assert.Error(t, err)
+
+	session, err := m.GetOrCreate("", "user", "ns", "ask", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, session.Location())
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenizer estimates token counts per model provider behind one
+// interface, so context budgeting, quotas and usage reporting agree on
+// the numbers. The estimates deliberately avoid shipping the providers'
+// real vocabularies: budgeting needs consistency and the right order of
+// magnitude, not exactness, and providers that report authoritative
+// counts on their streams take precedence over any estimate.
+package tokenizer
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+)
+
+// messageOverheadTokens approximates the per-message role framing that
+// providers charge on top of the content.
+const messageOverheadTokens = 4
+
+// Tokenizer counts tokens under one provider's counting scheme.
+type Tokenizer interface {
+	// Name identifies the counting scheme, e.g. "claude-estimate".
+	Name() string
+	// CountText estimates the tokens a text occupies.
+	CountText(text string) int
+}
+
+// ForProvider returns the tokenizer matching the named chat model
+// provider. Unknown names fall back to the Claude estimate, mirroring
+// the provider default.
+func ForProvider(name string) Tokenizer {
+	switch name {
+	case "local", "local-emulated", "azure-openai":
+		// Both speak the OpenAI chat completions API and typically serve
+		// models with tiktoken-style BPE vocabularies.
+		return &wordEstimator{name: "tiktoken-estimate"}
+	case "vertex":
+		return &charEstimator{name: "gemini-estimate", charsPerToken: 4}
+	default:
+		// "", "anthropic" and "bedrock" all serve Claude models.
+		return &charEstimator{name: "claude-estimate", charsPerToken: 3.5}
+	}
+}
+
+// CountMessages estimates the prompt size of a conversation, including
+// the per-message framing overhead and any tool calls riding on
+// assistant messages.
+func CountMessages(t Tokenizer, messages []provider.Message) int {
+	total := 0
+	for _, m := range messages {
+		total += messageOverheadTokens + t.CountText(m.Content)
+		for _, call := range m.ToolCalls {
+			total += messageOverheadTokens + t.CountText(call.Name) + t.CountText(call.ArgumentsJSON)
+		}
+	}
+	return total
+}
+
+// charEstimator approximates schemes that average a fixed number of
+// characters per token, which matches the published guidance for the
+// Claude and Gemini families closely enough for budgeting.
+type charEstimator struct {
+	name          string
+	charsPerToken float64
+}
+
+func (e *charEstimator) Name() string { return e.name }
+
+func (e *charEstimator) CountText(text string) int {
+	if text == "" {
+		return 0
+	}
+	return int(math.Ceil(float64(len(text)) / e.charsPerToken))
+}
+
+// wordEstimator approximates tiktoken-style BPE vocabularies: common
+// words are one token, punctuation and symbols split off on their own,
+// and long or unusual words span multiple tokens.
+type wordEstimator struct {
+	name string
+}
+
+func (e *wordEstimator) Name() string { return e.name }
+
+func (e *wordEstimator) CountText(text string) int {
+	tokens := 0
+	inWord := false
+	wordLen := 0
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				tokens++
+				inWord = true
+				wordLen = 0
+			}
+			wordLen++
+			// Words beyond about six characters tend to split into
+			// additional subword tokens.
+			if wordLen > 6 {
+				tokens++
+				wordLen = 1
+			}
+		case unicode.IsSpace(r):
+			inWord = false
+		default:
+			tokens++
+			inWord = false
+		}
+	}
+	return tokens
+}
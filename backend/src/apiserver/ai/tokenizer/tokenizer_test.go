@@ -0,0 +1,64 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForProvider_MapsProvidersToSchemes(t *testing.T) {
+	assert.Equal(t, "claude-estimate", ForProvider("anthropic").Name())
+	assert.Equal(t, "claude-estimate", ForProvider("bedrock").Name())
+	assert.Equal(t, "claude-estimate", ForProvider("").Name())
+	assert.Equal(t, "gemini-estimate", ForProvider("vertex").Name())
+	assert.Equal(t, "tiktoken-estimate", ForProvider("local").Name())
+	assert.Equal(t, "tiktoken-estimate", ForProvider("local-emulated").Name())
+	assert.Equal(t, "tiktoken-estimate", ForProvider("azure-openai").Name())
+}
+
+func TestCharEstimator_CountText(t *testing.T) {
+	claude := ForProvider("anthropic")
+	assert.Equal(t, 0, claude.CountText(""))
+	// 7 characters at 3.5 characters per token.
+	assert.Equal(t, 2, claude.CountText("abcdefg"))
+	assert.Equal(t, 1, claude.CountText("a"))
+}
+
+func TestWordEstimator_CountText(t *testing.T) {
+	tiktoken := ForProvider("local")
+	assert.Equal(t, 0, tiktoken.CountText(""))
+	assert.Equal(t, 1, tiktoken.CountText("hello"))
+	// Words, punctuation and symbols each count.
+	assert.Equal(t, 5, tiktoken.CountText("list my runs, please"))
+	// Long words split into subword tokens.
+	assert.Greater(t, tiktoken.CountText("internationalization"), 2)
+}
+
+func TestCountMessages_IncludesFramingAndToolCalls(t *testing.T) {
+	claude := ForProvider("anthropic")
+	messages := []provider.Message{
+		{Role: provider.RoleUser, Content: "hello"},
+		{Role: provider.RoleAssistant, ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "list_runs", ArgumentsJSON: `{"page_size":5}`},
+		}},
+	}
+	total := CountMessages(claude, messages)
+	perMessage := messageOverheadTokens
+	content := claude.CountText("hello") + claude.CountText("list_runs") + claude.CountText(`{"page_size":5}`)
+	assert.Equal(t, 3*perMessage+content, total)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// The helpers below mirror the access checks the gRPC servers perform
+// (e.g. BaseRunServer.canAccessRun) so tools enforce the same RBAC rules
+// as the regular API surface.
+
+func checkRunAccess(ctx context.Context, ec *ExecContext, runId string, resourceAttributes *authorizationv1.ResourceAttributes) error {
+	if !common.IsMultiUserMode() {
+		// Skip authz if not multi-user mode.
+		return nil
+	}
+	if runId != "" {
+		run, err := ec.ResourceManager.GetRun(runId)
+		if err != nil {
+			return util.Wrapf(err, "Failed to authorize with the run ID %v", runId)
+		}
+		if ec.ResourceManager.IsEmptyNamespace(run.Namespace) {
+			experiment, err := ec.ResourceManager.GetExperiment(run.ExperimentId)
+			if err != nil {
+				return util.NewInvalidInputError("run %v has an empty namespace and the parent experiment %v could not be fetched: %s", runId, run.ExperimentId, err.Error())
+			}
+			resourceAttributes.Namespace = experiment.Namespace
+		} else {
+			resourceAttributes.Namespace = run.Namespace
+		}
+		if resourceAttributes.Name == "" {
+			resourceAttributes.Name = run.K8SName
+		}
+	}
+	if ec.ResourceManager.IsEmptyNamespace(resourceAttributes.Namespace) {
+		return util.NewInvalidInputError("A run cannot have an empty namespace in multi-user mode")
+	}
+	resourceAttributes.Group = common.RbacPipelinesGroup
+	resourceAttributes.Version = common.RbacPipelinesVersion
+	resourceAttributes.Resource = common.RbacResourceTypeRuns
+	return ec.ResourceManager.IsAuthorized(ctx, resourceAttributes)
+}
+
+func checkExperimentAccess(ctx context.Context, ec *ExecContext, experimentId string, resourceAttributes *authorizationv1.ResourceAttributes) error {
+	if !common.IsMultiUserMode() {
+		return nil
+	}
+	if experimentId != "" {
+		experiment, err := ec.ResourceManager.GetExperiment(experimentId)
+		if err != nil {
+			return util.Wrapf(err, "Failed to authorize with the experiment ID %v", experimentId)
+		}
+		resourceAttributes.Namespace = experiment.Namespace
+		if resourceAttributes.Name == "" {
+			resourceAttributes.Name = experiment.Name
+		}
+	}
+	if ec.ResourceManager.IsEmptyNamespace(resourceAttributes.Namespace) {
+		return util.NewInvalidInputError("An experiment cannot have an empty namespace in multi-user mode")
+	}
+	resourceAttributes.Group = common.RbacPipelinesGroup
+	resourceAttributes.Version = common.RbacPipelinesVersion
+	resourceAttributes.Resource = common.RbacResourceTypeExperiments
+	return ec.ResourceManager.IsAuthorized(ctx, resourceAttributes)
+}
+
+func checkNamespaceAccess(ctx context.Context, ec *ExecContext, namespace string, resourceType string, verb string) error {
+	if !common.IsMultiUserMode() {
+		return nil
+	}
+	if ec.ResourceManager.IsEmptyNamespace(namespace) {
+		return util.NewInvalidInputError("A namespace is required in multi-user mode")
+	}
+	return ec.ResourceManager.IsAuthorized(ctx, &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      verb,
+		Group:     common.RbacPipelinesGroup,
+		Version:   common.RbacPipelinesVersion,
+		Resource:  resourceType,
+	})
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// storageStateArchived reports whether a storage state marks the
+// resource as archived, covering the v1 spelling alongside the v2 one.
+func storageStateArchived(state model.StorageState) bool {
+	switch model.StorageState(strings.ToUpper(string(state))) {
+	case model.StorageStateArchived, model.StorageStateArchivedV1:
+		return true
+	}
+	return false
+}
+
+// runArchiveTool archives or unarchives a run depending on its
+// direction, so both directions share the verification and no-op
+// handling while keeping distinct names, RBAC verbs and audit entries.
+type runArchiveTool struct {
+	archive bool
+}
+
+type runArchiveArgs struct {
+	RunID               string `json:"run_id" required:"true" description:"ID of the run."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the run."`
+}
+
+func (t *runArchiveTool) Name() string {
+	if t.archive {
+		return "archive_run"
+	}
+	return "unarchive_run"
+}
+
+func (t *runArchiveTool) Description() string {
+	if t.archive {
+		return "Archive a run to move it out of the active listings. The run and its details are kept and can be unarchived later."
+	}
+	return "Unarchive a run, returning it to the active listings."
+}
+
+func (t *runArchiveTool) InputSchema() map[string]interface{} { return SchemaFor(&runArchiveArgs{}) }
+
+func (t *runArchiveTool) Mutating() bool { return true }
+
+// Annotations: repeating the call leaves the run in the requested state.
+func (t *runArchiveTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Idempotent: true}
+}
+
+func (t *runArchiveTool) verb() string {
+	if t.archive {
+		return common.RbacResourceVerbArchive
+	}
+	return common.RbacResourceVerbUnarchive
+}
+
+// Validate fetches the run before the user is asked to confirm, and
+// rejects calls that would not change anything so the confirmation card
+// never offers a no-op.
+func (t *runArchiveTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a runArchiveArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return "", util.Wrapf(err, "Failed to authorize the %v call", t.Name())
+	}
+	run, err := verifyRunForMutation(ec, a.RunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if storageStateArchived(run.StorageState) == t.archive {
+		return "", util.NewFailedPreconditionError(errors.New("storage state unchanged"),
+			"Run %v is already in storage state %v; there is nothing to change", a.RunID, run.StorageState)
+	}
+	action := "unarchive"
+	if t.archive {
+		action = "archive"
+	}
+	return marshalVerification(action, run)
+}
+
+func (t *runArchiveTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a runArchiveArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return nil, util.Wrapf(err, "Failed to authorize the %v call", t.Name())
+	}
+	var err error
+	if t.archive {
+		err = ec.ResourceManager.ArchiveRun(a.RunID)
+	} else {
+		err = ec.ResourceManager.UnarchiveRun(a.RunID)
+	}
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to %v run %v", strings.TrimSuffix(t.Name(), "_run"), a.RunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"run_id": a.RunID, "archived": t.archive})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: a.RunID, Action: MutationUpdated}
+	return result, nil
+}
+
+// verifyExperimentForMutation fetches the experiment and matches it
+// against the session scope and the name the user gave, mirroring
+// verifyRunForMutation.
+func verifyExperimentForMutation(ec *ExecContext, experimentId, expectedName string) (*model.Experiment, error) {
+	experiment, err := ec.ResourceManager.GetExperiment(experimentId)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to fetch experiment %v for verification", experimentId)
+	}
+	if ec.Namespace != "" && experiment.Namespace != "" && experiment.Namespace != ec.Namespace {
+		return nil, util.NewPermissionDeniedError(errors.New("namespace mismatch"),
+			"Experiment %v belongs to namespace %v, not the session namespace %v", experiment.UUID, experiment.Namespace, ec.Namespace)
+	}
+	if expectedName != "" && experiment.Name != expectedName {
+		return nil, util.NewInvalidInputError(
+			"Experiment %v is named %q, not %q; refusing to proceed against an experiment that does not match the user's description",
+			experiment.UUID, experiment.Name, expectedName)
+	}
+	return experiment, nil
+}
+
+// marshalExperimentVerification renders the verification summary
+// attached to the confirmation request.
+func marshalExperimentVerification(action string, experiment *model.Experiment) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{"action": action, "verified_experiment": toExperimentSummary(experiment)})
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the experiment verification summary")
+	}
+	return string(b), nil
+}
+
+// experimentArchiveTool archives or unarchives an experiment depending
+// on its direction.
+type experimentArchiveTool struct {
+	archive bool
+}
+
+type experimentArchiveArgs struct {
+	ExperimentID string `json:"experiment_id" required:"true" description:"ID of the experiment."`
+	ExpectedName string `json:"expected_name" description:"Name the user referred to. Verification fails if it does not match the experiment."`
+}
+
+func (t *experimentArchiveTool) Name() string {
+	if t.archive {
+		return "archive_experiment"
+	}
+	return "unarchive_experiment"
+}
+
+func (t *experimentArchiveTool) Description() string {
+	if t.archive {
+		return "Archive an experiment to move it out of the active listings. Its runs are archived with it; unarchiving the experiment later does not unarchive them."
+	}
+	return "Unarchive an experiment, returning it to the active listings. Runs archived with it stay archived until unarchived individually."
+}
+
+func (t *experimentArchiveTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&experimentArchiveArgs{})
+}
+
+func (t *experimentArchiveTool) Mutating() bool { return true }
+
+// Annotations: repeating the call leaves the experiment in the
+// requested state.
+func (t *experimentArchiveTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Idempotent: true}
+}
+
+func (t *experimentArchiveTool) verb() string {
+	if t.archive {
+		return common.RbacResourceVerbArchive
+	}
+	return common.RbacResourceVerbUnarchive
+}
+
+// Validate fetches the experiment before the user is asked to confirm,
+// and rejects no-op calls.
+func (t *experimentArchiveTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a experimentArchiveArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkExperimentAccess(ctx, ec, a.ExperimentID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return "", util.Wrapf(err, "Failed to authorize the %v call", t.Name())
+	}
+	experiment, err := verifyExperimentForMutation(ec, a.ExperimentID, a.ExpectedName)
+	if err != nil {
+		return "", err
+	}
+	if storageStateArchived(experiment.StorageState) == t.archive {
+		return "", util.NewFailedPreconditionError(errors.New("storage state unchanged"),
+			"Experiment %v is already in storage state %v; there is nothing to change", a.ExperimentID, experiment.StorageState)
+	}
+	action := "unarchive"
+	if t.archive {
+		action = "archive"
+	}
+	return marshalExperimentVerification(action, experiment)
+}
+
+func (t *experimentArchiveTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a experimentArchiveArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkExperimentAccess(ctx, ec, a.ExperimentID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return nil, util.Wrapf(err, "Failed to authorize the %v call", t.Name())
+	}
+	var err error
+	if t.archive {
+		err = ec.ResourceManager.ArchiveExperiment(ctx, a.ExperimentID)
+	} else {
+		err = ec.ResourceManager.UnarchiveExperiment(a.ExperimentID)
+	}
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to %v experiment %v", strings.TrimSuffix(t.Name(), "_experiment"), a.ExperimentID)
+	}
+	result, err := JSONResult(map[string]interface{}{"experiment_id": a.ExperimentID, "archived": t.archive})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "experiment", ResourceID: a.ExperimentID, Action: MutationUpdated}
+	return result, nil
+}
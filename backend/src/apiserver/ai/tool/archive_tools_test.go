@@ -0,0 +1,43 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageStateArchived_CoversBothAPIVersions(t *testing.T) {
+	assert.True(t, storageStateArchived(model.StorageStateArchived))
+	assert.True(t, storageStateArchived(model.StorageStateArchivedV1))
+	assert.True(t, storageStateArchived("archived"))
+	assert.False(t, storageStateArchived(model.StorageStateAvailable))
+	assert.False(t, storageStateArchived(model.StorageStateAvailableV1))
+	assert.False(t, storageStateArchived(""))
+}
+
+func TestArchiveTools_NamesAndVerbsFollowTheDirection(t *testing.T) {
+	archive := &runArchiveTool{archive: true}
+	assert.Equal(t, "archive_run", archive.Name())
+	assert.Equal(t, common.RbacResourceVerbArchive, archive.verb())
+
+	unarchive := &experimentArchiveTool{archive: false}
+	assert.Equal(t, "unarchive_experiment", unarchive.Name())
+	assert.Equal(t, common.RbacResourceVerbUnarchive, unarchive.verb())
+	assert.True(t, unarchive.Mutating())
+}
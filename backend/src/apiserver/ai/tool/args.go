@@ -0,0 +1,197 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Tools declare their inputs once, as a struct: DecodeArgs fills the
+// struct from the raw argument map and SchemaFor derives the matching
+// JSON schema from the same struct tags, so the schema shown to the
+// model and the decoding cannot drift apart.
+//
+// Supported tags on exported fields:
+//
+//	json:"name"        argument name; "-" excludes the field
+//	description:"..."  schema description shown to the model
+//	required:"true"    the argument must be present and non-zero
+//	default:"v"        applied when the argument is absent or zero
+//	enum:"a,b"         allowed values of a string argument
+
+// DecodeArgs decodes the raw tool arguments into v, a pointer to an
+// argument struct, then applies defaults and enforces required and enum
+// tags. Unknown arguments are ignored: models routinely invent extras,
+// and rejecting them only burns a turn.
+func DecodeArgs(args map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to re-encode the tool arguments")
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return util.NewInvalidInputError("The tool arguments did not match their declared types: %s", err.Error())
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	var missing []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := argName(field)
+		if name == "" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if fieldValue.IsZero() {
+			if def := field.Tag.Get("default"); def != "" {
+				if err := setDefault(fieldValue, def); err != nil {
+					return util.NewInternalServerError(err, "Invalid default tag on argument %v", name)
+				}
+			}
+		}
+		if field.Tag.Get("required") == "true" && fieldValue.IsZero() {
+			missing = append(missing, name)
+			continue
+		}
+		if enum := field.Tag.Get("enum"); enum != "" && fieldValue.Kind() == reflect.String && fieldValue.String() != "" {
+			if !containsString(strings.Split(enum, ","), fieldValue.String()) {
+				return util.NewInvalidInputError("%v must be one of [%v], got %q", name, enum, fieldValue.String())
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return util.NewInvalidInputError("The required arguments [%v] are missing", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// SchemaFor derives the JSON input schema of an argument struct from its
+// tags. v is a pointer to the (zero) argument struct.
+func SchemaFor(v interface{}) map[string]interface{} {
+	structType := reflect.TypeOf(v)
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := argName(field)
+		if name == "" {
+			continue
+		}
+		property := map[string]interface{}{"type": jsonType(field.Type)}
+		if field.Type.Kind() == reflect.Slice {
+			property["items"] = map[string]interface{}{"type": jsonType(field.Type.Elem())}
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := []interface{}{}
+			for _, v := range strings.Split(enum, ",") {
+				values = append(values, v)
+			}
+			property["enum"] = values
+		}
+		properties[name] = property
+		if field.Tag.Get("required") == "true" {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// argName resolves the argument name of a struct field from its json
+// tag. Empty means the field is not an argument.
+func argName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		// Unexported fields carry tool-internal state, not arguments.
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "" || tag == "-" {
+		return ""
+	}
+	return strings.Split(tag, ",")[0]
+}
+
+// jsonType maps a Go type onto its JSON schema type name.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// setDefault parses a default tag into the field.
+func setDefault(fieldValue reflect.Value, def string) error {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(def)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(def, 10, 64)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(def, 64)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(parsed)
+	default:
+		return util.NewInvalidInputError("default tags are not supported on %v fields", fieldValue.Kind())
+	}
+	return nil
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeFixtureArgs struct {
+	Name     string   `json:"name" required:"true" description:"The name."`
+	Action   string   `json:"action" default:"archive" enum:"archive,delete" description:"What to do."`
+	PageSize int      `json:"page_size" description:"Page size."`
+	Days     float64  `json:"days"`
+	IDs      []string `json:"ids"`
+}
+
+func TestDecodeArgs_FillsDefaultsAndTypes(t *testing.T) {
+	var a decodeFixtureArgs
+	err := DecodeArgs(map[string]interface{}{
+		"name":      "x",
+		"page_size": float64(5),
+		"days":      float64(1.5),
+		"ids":       []interface{}{"a", "b"},
+		"invented":  "ignored",
+	}, &a)
+	require.NoError(t, err)
+	assert.Equal(t, "x", a.Name)
+	assert.Equal(t, "archive", a.Action)
+	assert.Equal(t, 5, a.PageSize)
+	assert.Equal(t, 1.5, a.Days)
+	assert.Equal(t, []string{"a", "b"}, a.IDs)
+}
+
+func TestDecodeArgs_RejectsBadArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing required argument", map[string]interface{}{}},
+		{"value outside the enum", map[string]interface{}{"name": "x", "action": "vaporize"}},
+		{"wrong argument type", map[string]interface{}{"name": float64(3)}},
+	}
+	for _, test := range tests {
+		var a decodeFixtureArgs
+		assert.Error(t, DecodeArgs(test.args, &a), test.name)
+	}
+}
+
+func TestSchemaFor_MatchesTheStructTags(t *testing.T) {
+	schema := SchemaFor(&decodeFixtureArgs{})
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []interface{}{"name"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	name := properties["name"].(map[string]interface{})
+	assert.Equal(t, "string", name["type"])
+	assert.Equal(t, "The name.", name["description"])
+
+	action := properties["action"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"archive", "delete"}, action["enum"])
+
+	assert.Equal(t, "integer", properties["page_size"].(map[string]interface{})["type"])
+	assert.Equal(t, "number", properties["days"].(map[string]interface{})["type"])
+
+	ids := properties["ids"].(map[string]interface{})
+	assert.Equal(t, "array", ids["type"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, ids["items"])
+}
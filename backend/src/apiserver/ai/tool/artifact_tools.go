@@ -0,0 +1,353 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// getRunArtifactsTool surfaces what a run produced — reported metrics
+// and the per-task output artifacts recorded in MLMD — so questions like
+// "what accuracy did this run reach" are answered from the stored
+// values instead of the user pasting them into the chat.
+type getRunArtifactsTool struct{}
+
+type getRunArtifactsArgs struct {
+	RunID    string `json:"run_id" required:"true" description:"Run whose metrics and output artifacts to fetch."`
+	TaskName string `json:"task_name" description:"Restrict the task outputs to this task."`
+}
+
+func (t *getRunArtifactsTool) Name() string { return "get_run_artifacts" }
+
+func (t *getRunArtifactsTool) Description() string {
+	return "Get the metrics a run reported (e.g. accuracy) and the output artifacts its tasks recorded, with their MLMD metadata."
+}
+
+func (t *getRunArtifactsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&getRunArtifactsArgs{})
+}
+
+func (t *getRunArtifactsTool) Mutating() bool { return false }
+
+// runMetricSummary is the model-facing view of one reported run metric.
+type runMetricSummary struct {
+	Name   string  `json:"name"`
+	NodeID string  `json:"node_id,omitempty"`
+	Value  float64 `json:"value"`
+	Format string  `json:"format,omitempty"`
+}
+
+// taskArtifactSummary lists one task's recorded outputs. Outputs carries
+// the task's MLMD output metadata as stored — output names mapped to
+// their artifact records (IDs, and URIs where MLMD recorded them).
+type taskArtifactSummary struct {
+	TaskID     string                 `json:"task_id"`
+	Name       string                 `json:"name,omitempty"`
+	State      string                 `json:"state,omitempty"`
+	FinishedAt int64                  `json:"finished_at,omitempty"`
+	Outputs    map[string]interface{} `json:"outputs,omitempty"`
+	// OutputsError notes outputs that were stored but could not be
+	// decoded, instead of failing the whole listing.
+	OutputsError string `json:"outputs_error,omitempty"`
+}
+
+// toTaskArtifactSummary decodes the task's stored MLMD outputs. Old or
+// partially reported tasks may carry none, or malformed ones; both yield
+// a summary rather than an error so one bad task cannot hide the rest.
+func toTaskArtifactSummary(task *model.Task) taskArtifactSummary {
+	summary := taskArtifactSummary{
+		TaskID:     task.UUID,
+		Name:       task.Name,
+		State:      string(task.State),
+		FinishedAt: task.FinishedTimestamp,
+	}
+	if task.MLMDOutputs == "" {
+		return summary
+	}
+	if err := json.Unmarshal([]byte(task.MLMDOutputs), &summary.Outputs); err != nil {
+		summary.Outputs = nil
+		summary.OutputsError = "the stored output metadata could not be decoded"
+	}
+	return summary
+}
+
+// artifactRecord is the model-facing view of one artifact reference
+// found in a task's stored MLMD metadata.
+type artifactRecord struct {
+	// ArtifactID is the MLMD artifact ID, normalized to a string.
+	ArtifactID string `json:"artifact_id,omitempty"`
+	// OutputName is the task input or output the artifact was bound to.
+	OutputName string `json:"output_name,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Type       string `json:"type,omitempty"`
+	// Properties carries the remaining recorded fields as stored.
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	TaskID     string                 `json:"task_id,omitempty"`
+	TaskName   string                 `json:"task_name,omitempty"`
+}
+
+// collectTaskArtifacts extracts artifact references from one side of a
+// task's stored MLMD metadata. The stored shape varies across driver
+// versions — records may be single objects or lists per IO name — so
+// the walk is permissive and malformed entries are simply skipped.
+func collectTaskArtifacts(task *model.Task, stored model.LargeText) []artifactRecord {
+	if stored == "" {
+		return nil
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(stored), &decoded); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(decoded))
+	for name := range decoded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var records []artifactRecord
+	for _, name := range names {
+		switch value := decoded[name].(type) {
+		case map[string]interface{}:
+			records = append(records, toArtifactRecord(task, name, value))
+		case []interface{}:
+			for _, item := range value {
+				if entry, ok := item.(map[string]interface{}); ok {
+					records = append(records, toArtifactRecord(task, name, entry))
+				}
+			}
+		}
+	}
+	return records
+}
+
+func toArtifactRecord(task *model.Task, outputName string, entry map[string]interface{}) artifactRecord {
+	record := artifactRecord{
+		OutputName: outputName,
+		TaskID:     task.UUID,
+		TaskName:   task.Name,
+		Properties: map[string]interface{}{},
+	}
+	for key, value := range entry {
+		switch key {
+		case "id", "artifact_id", "artifactId":
+			record.ArtifactID = stringifyArtifactID(value)
+		case "uri":
+			record.URI, _ = value.(string)
+		case "type", "type_id", "typeId":
+			record.Type = stringifyArtifactID(value)
+		default:
+			record.Properties[key] = value
+		}
+	}
+	if len(record.Properties) == 0 {
+		record.Properties = nil
+	}
+	return record
+}
+
+// stringifyArtifactID normalizes an ID field that may be stored as a
+// JSON number or string.
+func stringifyArtifactID(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	default:
+		return ""
+	}
+}
+
+// matchesArtifact reports whether a record refers to the artifact the
+// user asked about, by MLMD ID or by URI.
+func matchesArtifact(record artifactRecord, artifactId, uri string) bool {
+	if artifactId != "" && record.ArtifactID == artifactId {
+		return true
+	}
+	return uri != "" && record.URI == uri
+}
+
+// listArtifactsTool lists the output artifacts a run's tasks recorded,
+// flattened into one artifact-centric view.
+type listArtifactsTool struct{}
+
+type listArtifactsArgs struct {
+	RunID    string `json:"run_id" required:"true" description:"Run whose artifacts to list."`
+	TaskName string `json:"task_name" description:"Restrict the listing to artifacts produced by this task."`
+}
+
+func (t *listArtifactsTool) Name() string { return "list_artifacts" }
+
+func (t *listArtifactsTool) Description() string {
+	return "List the output artifacts a run's tasks recorded in MLMD, with their IDs, URIs and producing tasks. " +
+		"Use get_artifact to trace one artifact's lineage."
+}
+
+func (t *listArtifactsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&listArtifactsArgs{})
+}
+
+func (t *listArtifactsTool) Mutating() bool { return false }
+
+func (t *listArtifactsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listArtifactsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize listing the run's artifacts")
+	}
+	tasks, err := listRunTasks(a.RunID, ec)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to list the tasks of run %v", a.RunID)
+	}
+	artifacts := []artifactRecord{}
+	for _, task := range tasks {
+		if a.TaskName != "" && task.Name != a.TaskName {
+			continue
+		}
+		artifacts = append(artifacts, collectTaskArtifacts(task, task.MLMDOutputs)...)
+	}
+	return JSONResult(map[string]interface{}{
+		"run_id":    a.RunID,
+		"artifacts": artifacts,
+	})
+}
+
+// getArtifactTool traces one artifact within a run: its recorded
+// properties, the task that produced it and the tasks that consumed it.
+type getArtifactTool struct{}
+
+type getArtifactArgs struct {
+	RunID      string `json:"run_id" required:"true" description:"Run the artifact belongs to."`
+	ArtifactID string `json:"artifact_id" description:"MLMD ID of the artifact. Either this or uri is required."`
+	URI        string `json:"uri" description:"Storage URI of the artifact, e.g. from list_artifacts."`
+}
+
+func (t *getArtifactTool) Name() string { return "get_artifact" }
+
+func (t *getArtifactTool) Description() string {
+	return "Get one artifact's recorded properties and its lineage within a run: the task that produced it and the tasks that consumed it."
+}
+
+func (t *getArtifactTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&getArtifactArgs{})
+}
+
+func (t *getArtifactTool) Mutating() bool { return false }
+
+func (t *getArtifactTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getArtifactArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.ArtifactID == "" && a.URI == "" {
+		return nil, util.NewInvalidInputError("Either artifact_id or uri is required")
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the artifact")
+	}
+	tasks, err := listRunTasks(a.RunID, ec)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to list the tasks of run %v", a.RunID)
+	}
+	var artifact *artifactRecord
+	producedBy := []artifactRecord{}
+	consumedBy := []artifactRecord{}
+	for _, task := range tasks {
+		for _, record := range collectTaskArtifacts(task, task.MLMDOutputs) {
+			if matchesArtifact(record, a.ArtifactID, a.URI) {
+				record := record
+				if artifact == nil {
+					artifact = &record
+				}
+				producedBy = append(producedBy, record)
+			}
+		}
+		for _, record := range collectTaskArtifacts(task, task.MLMDInputs) {
+			if matchesArtifact(record, a.ArtifactID, a.URI) {
+				consumedBy = append(consumedBy, record)
+			}
+		}
+	}
+	if artifact == nil && len(consumedBy) == 0 {
+		name := a.ArtifactID
+		if name == "" {
+			name = a.URI
+		}
+		return nil, util.NewResourceNotFoundError("Artifact", name)
+	}
+	result := map[string]interface{}{
+		"run_id":      a.RunID,
+		"produced_by": producedBy,
+		"consumed_by": consumedBy,
+	}
+	if artifact != nil {
+		result["artifact"] = artifact
+	}
+	return JSONResult(result)
+}
+
+func (t *getRunArtifactsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getRunArtifactsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the run's artifacts")
+	}
+	run, err := ec.ResourceManager.GetRun(a.RunID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get run %v", a.RunID)
+	}
+
+	metrics := []runMetricSummary{}
+	for _, metric := range run.Metrics {
+		metrics = append(metrics, runMetricSummary{
+			Name:   metric.Name,
+			NodeID: metric.NodeID,
+			Value:  metric.NumberValue,
+			Format: metric.Format,
+		})
+	}
+
+	tasks, err := listRunTasks(a.RunID, ec)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to list the tasks of run %v", a.RunID)
+	}
+	summaries := []taskArtifactSummary{}
+	for _, task := range tasks {
+		if a.TaskName != "" && task.Name != a.TaskName {
+			continue
+		}
+		summaries = append(summaries, toTaskArtifactSummary(task))
+	}
+	if a.TaskName != "" && len(summaries) == 0 {
+		return nil, util.NewResourceNotFoundError("Task", a.TaskName)
+	}
+	return JSONResult(map[string]interface{}{
+		"run_id":  a.RunID,
+		"state":   string(run.State),
+		"metrics": metrics,
+		"tasks":   summaries,
+	})
+}
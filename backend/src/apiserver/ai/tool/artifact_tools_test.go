@@ -0,0 +1,87 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTaskArtifactSummary_DecodesStoredOutputs(t *testing.T) {
+	summary := toTaskArtifactSummary(&model.Task{
+		UUID:        "task-1",
+		Name:        "train",
+		State:       model.RuntimeStateSucceeded,
+		MLMDOutputs: model.LargeText(`{"model":{"artifact_ids":[4],"uri":"s3://bucket/model"}}`),
+	})
+
+	assert.Equal(t, "task-1", summary.TaskID)
+	assert.Equal(t, "train", summary.Name)
+	assert.Empty(t, summary.OutputsError)
+	require.Contains(t, summary.Outputs, "model")
+	output, ok := summary.Outputs["model"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "s3://bucket/model", output["uri"])
+}
+
+func TestToTaskArtifactSummary_ToleratesMissingAndMalformedOutputs(t *testing.T) {
+	summary := toTaskArtifactSummary(&model.Task{UUID: "task-1"})
+	assert.Nil(t, summary.Outputs)
+	assert.Empty(t, summary.OutputsError)
+
+	summary = toTaskArtifactSummary(&model.Task{
+		UUID:        "task-2",
+		MLMDOutputs: model.LargeText(`{"b2":{"artifact_ids":[4,5,6]`),
+	})
+	assert.Nil(t, summary.Outputs)
+	assert.NotEmpty(t, summary.OutputsError)
+}
+
+func TestCollectTaskArtifacts_FlattensObjectsAndLists(t *testing.T) {
+	task := &model.Task{UUID: "task-1", Name: "train"}
+	records := collectTaskArtifacts(task, model.LargeText(
+		`{"model":{"id":4,"uri":"s3://bucket/model","display_name":"model"},`+
+			`"splits":[{"id":"5","uri":"s3://bucket/train"},{"id":6,"uri":"s3://bucket/test"}],`+
+			`"bogus":"not an artifact"}`))
+
+	require.Len(t, records, 3)
+	// IO names are emitted in sorted order.
+	assert.Equal(t, "model", records[0].OutputName)
+	assert.Equal(t, "4", records[0].ArtifactID)
+	assert.Equal(t, "s3://bucket/model", records[0].URI)
+	assert.Equal(t, "train", records[0].TaskName)
+	assert.Equal(t, map[string]interface{}{"display_name": "model"}, records[0].Properties)
+	// List entries keep their IO name, and string IDs pass through.
+	assert.Equal(t, "splits", records[1].OutputName)
+	assert.Equal(t, "5", records[1].ArtifactID)
+	assert.Equal(t, "6", records[2].ArtifactID)
+}
+
+func TestCollectTaskArtifacts_ToleratesMissingAndMalformedMetadata(t *testing.T) {
+	task := &model.Task{UUID: "task-1"}
+	assert.Nil(t, collectTaskArtifacts(task, ""))
+	assert.Nil(t, collectTaskArtifacts(task, model.LargeText(`{"model":{`)))
+}
+
+func TestMatchesArtifact_ByIDOrURI(t *testing.T) {
+	record := artifactRecord{ArtifactID: "4", URI: "s3://bucket/model"}
+	assert.True(t, matchesArtifact(record, "4", ""))
+	assert.True(t, matchesArtifact(record, "", "s3://bucket/model"))
+	assert.False(t, matchesArtifact(record, "5", ""))
+	assert.False(t, matchesArtifact(record, "", ""))
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	// cleanupBatchCap is the hard limit on runs affected per approval. A
+	// larger cleanup needs multiple rounds, each with its own preview and
+	// confirmation.
+	cleanupBatchCap = 20
+	// cleanupScanPages bounds how much history the selection scans.
+	cleanupScanPages = 10
+
+	cleanupActionArchive = "archive"
+	cleanupActionDelete  = "delete"
+)
+
+// cleanupSelection is the resolved set of runs a cleanup_runs call will
+// touch. It doubles as the confirmation preview: the user approves this
+// exact list, not the selection criteria.
+type cleanupSelection struct {
+	Action string       `json:"action"`
+	Runs   []runSummary `json:"runs"`
+	// Truncated reports that more runs matched than the batch cap allows;
+	// the call only affects the listed ones.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type cleanupRunsTool struct{}
+
+type cleanupRunsArgs struct {
+	Action        string   `json:"action" default:"archive" enum:"archive,delete" description:"What to do with the matching runs (default archive)."`
+	OlderThanDays float64  `json:"older_than_days" required:"true" description:"Only touch runs that finished more than this many days ago."`
+	States        []string `json:"states" description:"Run states to match, e.g. [\"FAILED\"]. Defaults to all finished states."`
+	MaxRuns       int      `json:"max_runs" description:"Cap on the number of runs to touch (default and maximum 20)."`
+	Namespace     string   `json:"namespace" description:"Namespace to clean up. Defaults to the session namespace."`
+	ExperimentID  string   `json:"experiment_id" description:"Restrict the cleanup to one experiment."`
+}
+
+func (t *cleanupRunsTool) Name() string { return "cleanup_runs" }
+
+func (t *cleanupRunsTool) Description() string {
+	return fmt.Sprintf("Archive or delete finished runs older than a cutoff. The confirmation shows exactly which runs "+
+		"will be affected, and at most %v runs are touched per approval.", cleanupBatchCap)
+}
+
+func (t *cleanupRunsTool) InputSchema() map[string]interface{} { return SchemaFor(&cleanupRunsArgs{}) }
+
+func (t *cleanupRunsTool) Mutating() bool { return true }
+
+func (t *cleanupRunsTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Destructive: true}
+}
+
+// Validate runs the selection as a dry run so the confirmation request
+// lists exactly which runs the approval covers.
+func (t *cleanupRunsTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	selection, err := t.selectRuns(ctx, ec, args)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(selection)
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the cleanup preview")
+	}
+	return string(b), nil
+}
+
+func (t *cleanupRunsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	selection, err := t.approvedSelection(ctx, ec, args)
+	if err != nil {
+		return nil, err
+	}
+	if selection == nil {
+		// No approved preview to honor (e.g. execution without a
+		// confirmation round trip); resolve the criteria now.
+		if selection, err = t.selectRuns(ctx, ec, args); err != nil {
+			return nil, err
+		}
+	}
+	cleaned := []string{}
+	failed := []map[string]string{}
+	for i, run := range selection.Runs {
+		// ArchiveRun does not honor the context, so the per-tool deadline
+		// is enforced between items; runs already cleaned stay cleaned.
+		if err := ctx.Err(); err != nil {
+			return nil, util.Wrapf(err, "The cleanup was cut off after %v of %v runs", len(cleaned), len(selection.Runs))
+		}
+		var err error
+		if selection.Action == cleanupActionDelete {
+			err = ec.ResourceManager.DeleteRun(ctx, run.RunID)
+		} else {
+			err = ec.ResourceManager.ArchiveRun(run.RunID)
+		}
+		update := ProgressUpdate{Completed: i + 1, Total: len(selection.Runs), Item: run.RunID}
+		if err != nil {
+			update.Err = err.Error()
+			failed = append(failed, map[string]string{"run_id": run.RunID, "error": err.Error()})
+			ec.Warnf("cleanup_runs failed to %v run %v: %v", selection.Action, run.RunID, err)
+		} else {
+			cleaned = append(cleaned, run.RunID)
+		}
+		ec.ReportProgress(update)
+	}
+	return JSONResult(map[string]interface{}{
+		"action":    selection.Action,
+		"cleaned":   cleaned,
+		"failed":    failed,
+		"truncated": selection.Truncated,
+	})
+}
+
+// approvedSelection returns the run set the user approved, decoded from
+// the preview attached to the confirmation. The criteria are not
+// re-resolved: minutes may pass between the preview and the approval,
+// and a fresh cutoff could sweep in runs the user never saw. The
+// authorization check still runs at execution time. Returns nil when no
+// approved preview is present.
+func (t *cleanupRunsTool) approvedSelection(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*cleanupSelection, error) {
+	if ec.ApprovedValidationJSON == "" {
+		return nil, nil
+	}
+	selection := &cleanupSelection{}
+	if err := json.Unmarshal([]byte(ec.ApprovedValidationJSON), selection); err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to decode the approved cleanup preview")
+	}
+	var a cleanupRunsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if _, err := t.authorize(ctx, ec, &a); err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
+
+// authorize resolves the target namespace and checks the caller may
+// archive or delete runs in it, shared by the selection and the
+// execution of an approved preview.
+func (t *cleanupRunsTool) authorize(ctx context.Context, ec *ExecContext, a *cleanupRunsArgs) (string, error) {
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	verb := common.RbacResourceVerbArchive
+	if a.Action == cleanupActionDelete {
+		verb = common.RbacResourceVerbDelete
+	}
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, verb); err != nil {
+		return "", util.Wrapf(err, "Failed to authorize the run cleanup (%v)", a.Action)
+	}
+	return namespace, nil
+}
+
+// selectRuns resolves the cleanup criteria into the concrete, capped
+// list of runs, shared by the dry run and the execution.
+func (t *cleanupRunsTool) selectRuns(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*cleanupSelection, error) {
+	var a cleanupRunsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.OlderThanDays <= 0 {
+		return nil, util.NewInvalidInputError("A positive older_than_days is required")
+	}
+	maxRuns := cleanupBatchCap
+	if a.MaxRuns > 0 && a.MaxRuns < cleanupBatchCap {
+		maxRuns = a.MaxRuns
+	}
+	states := map[string]bool{}
+	for _, state := range a.States {
+		if state != "" {
+			states[strings.ToUpper(state)] = true
+		}
+	}
+	if len(states) == 0 {
+		for _, state := range []model.RuntimeState{model.RuntimeStateSucceeded, model.RuntimeStateFailed, model.RuntimeStateCanceled} {
+			states[string(state)] = true
+		}
+	}
+
+	namespace, err := t.authorize(ctx, ec, &a)
+	if err != nil {
+		return nil, err
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	if a.ExperimentID != "" {
+		if err := ec.ResourceManager.CheckExperimentBelongsToNamespace(a.ExperimentID, namespace); err != nil {
+			return nil, util.Wrap(err, "Failed to clean up runs due to namespace mismatch")
+		}
+		filterContext = &model.FilterContext{
+			ReferenceKey: &model.ReferenceKey{Type: model.ExperimentResourceType, ID: a.ExperimentID},
+		}
+	}
+
+	cutoff := time.Now().Add(-time.Duration(a.OlderThanDays) * 24 * time.Hour).Unix()
+	selection := &cleanupSelection{Action: a.Action, Runs: []runSummary{}}
+	opts, err := list.NewOptions(&model.Run{}, maxListPageSize, "created_at asc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the run listing options")
+	}
+	for page := 0; page < cleanupScanPages; page++ {
+		// ListRuns does not honor the context, so the per-tool deadline is
+		// enforced between pages.
+		if err := ctx.Err(); err != nil {
+			return nil, util.Wrap(err, "The run scan for cleanup was cut off")
+		}
+		runs, _, nextPageToken, err := ec.ResourceManager.ListRuns(filterContext, opts)
+		if err != nil {
+			return nil, util.Wrap(err, "Failed to list runs for cleanup")
+		}
+		for _, run := range runs {
+			if !t.matches(run, states, cutoff, a.Action) {
+				continue
+			}
+			if len(selection.Runs) == maxRuns {
+				selection.Truncated = true
+				return selection, nil
+			}
+			selection.Runs = append(selection.Runs, toRunSummary(run))
+		}
+		if nextPageToken == "" {
+			return selection, nil
+		}
+		if opts, err = list.NewOptionsFromToken(nextPageToken, maxListPageSize); err != nil {
+			return nil, util.Wrap(err, "Failed to continue the run listing")
+		}
+	}
+	return selection, nil
+}
+
+// matches reports whether a run falls under the cleanup criteria.
+func (t *cleanupRunsTool) matches(run *model.Run, states map[string]bool, cutoff int64, action string) bool {
+	if run.FinishedAtInSec <= 0 || run.FinishedAtInSec >= cutoff {
+		return false
+	}
+	if !states[strings.ToUpper(string(run.State))] {
+		return false
+	}
+	// Archiving an already archived run is a no-op; leave it out of the
+	// preview so the user sees only real changes.
+	if action == cleanupActionArchive && run.StorageState == model.StorageStateArchived {
+		return false
+	}
+	return true
+}
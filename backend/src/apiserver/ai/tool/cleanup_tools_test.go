@@ -0,0 +1,94 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupRuns_RejectsBadCriteria(t *testing.T) {
+	tool := &cleanupRunsTool{}
+	ec := &ExecContext{}
+
+	_, err := tool.selectRuns(context.Background(), ec, map[string]interface{}{"older_than_days": float64(30), "action": "vaporize"})
+	assert.Error(t, err)
+
+	_, err = tool.selectRuns(context.Background(), ec, map[string]interface{}{})
+	assert.Error(t, err)
+
+	_, err = tool.selectRuns(context.Background(), ec, map[string]interface{}{"older_than_days": float64(-1)})
+	assert.Error(t, err)
+}
+
+func TestCleanupRuns_ExecutesExactlyTheApprovedSelection(t *testing.T) {
+	world := newFixtureWorld(t)
+	tool := &cleanupRunsTool{}
+	runID := world.placeholders["$RUN_ID"]
+
+	// The criteria resolve to nothing (the seeded run has not finished),
+	// but the approved preview names it: the approved list is what
+	// executes, not a re-resolution of the criteria.
+	approved, err := json.Marshal(&cleanupSelection{
+		Action: cleanupActionArchive,
+		Runs:   []runSummary{{RunID: runID}},
+	})
+	require.Nil(t, err)
+	world.ec.ApprovedValidationJSON = string(approved)
+
+	result, err := tool.Execute(context.Background(), world.ec,
+		map[string]interface{}{"older_than_days": float64(30)})
+	require.Nil(t, err)
+	var out map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(result.Content), &out))
+	assert.Equal(t, []interface{}{runID}, out["cleaned"])
+	assert.Empty(t, out["failed"])
+
+	run, err := world.ec.ResourceManager.GetRun(runID)
+	require.Nil(t, err)
+	assert.Equal(t, model.StorageStateArchived, run.StorageState)
+}
+
+func TestCleanupRuns_MatchesOnlyFinishedRunsPastCutoff(t *testing.T) {
+	tool := &cleanupRunsTool{}
+	states := map[string]bool{"FAILED": true}
+	cutoff := time.Now().Add(-30 * 24 * time.Hour).Unix()
+
+	old := &model.Run{UUID: "r1", RunDetails: model.RunDetails{State: model.RuntimeStateFailed, FinishedAtInSec: cutoff - 100}}
+	assert.True(t, tool.matches(old, states, cutoff, cleanupActionDelete))
+
+	// Still running: no finish timestamp.
+	running := &model.Run{UUID: "r2", RunDetails: model.RunDetails{State: model.RuntimeStateFailed}}
+	assert.False(t, tool.matches(running, states, cutoff, cleanupActionDelete))
+
+	// Finished, but after the cutoff.
+	recent := &model.Run{UUID: "r3", RunDetails: model.RunDetails{State: model.RuntimeStateFailed, FinishedAtInSec: cutoff + 100}}
+	assert.False(t, tool.matches(recent, states, cutoff, cleanupActionDelete))
+
+	// Wrong state.
+	succeeded := &model.Run{UUID: "r4", RunDetails: model.RunDetails{State: model.RuntimeStateSucceeded, FinishedAtInSec: cutoff - 100}}
+	assert.False(t, tool.matches(succeeded, states, cutoff, cleanupActionDelete))
+
+	// Archiving skips runs that are already archived, deleting does not.
+	archived := &model.Run{UUID: "r5", StorageState: model.StorageStateArchived, RunDetails: model.RunDetails{State: model.RuntimeStateFailed, FinishedAtInSec: cutoff - 100}}
+	assert.False(t, tool.matches(archived, states, cutoff, cleanupActionArchive))
+	assert.True(t, tool.matches(archived, states, cutoff, cleanupActionDelete))
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+const (
+	// defaultExperimentName matches the experiment the API server itself
+	// creates for runs submitted without an experiment.
+	defaultExperimentName = "Default"
+	// maxDefaultExperimentPages bounds the scan for an existing Default
+	// experiment in very large namespaces.
+	maxDefaultExperimentPages = 10
+)
+
+type experimentSummary struct {
+	ExperimentID     string `json:"experiment_id"`
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	Namespace        string `json:"namespace,omitempty"`
+	CreatedAt        int64  `json:"created_at"`
+	LastRunCreatedAt int64  `json:"last_run_created_at,omitempty"`
+	StorageState     string `json:"storage_state,omitempty"`
+}
+
+func toExperimentSummary(e *model.Experiment) experimentSummary {
+	return experimentSummary{
+		ExperimentID:     e.UUID,
+		Name:             e.Name,
+		Description:      e.Description,
+		Namespace:        e.Namespace,
+		CreatedAt:        e.CreatedAtInSec,
+		LastRunCreatedAt: e.LastRunCreatedAtInSec,
+		StorageState:     string(e.StorageState),
+	}
+}
+
+type listExperimentsTool struct{}
+
+type listExperimentsArgs struct {
+	Namespace string `json:"namespace" description:"Namespace to list experiments in."`
+	PageSize  int    `json:"page_size" description:"Maximum number of experiments to return (default 20, max 100)."`
+}
+
+func (t *listExperimentsTool) Name() string { return "list_experiments" }
+
+func (t *listExperimentsTool) Description() string {
+	return "List the experiments in a namespace, newest first."
+}
+
+func (t *listExperimentsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&listExperimentsArgs{})
+}
+
+func (t *listExperimentsTool) Mutating() bool { return false }
+
+func (t *listExperimentsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listExperimentsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeExperiments, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize listing experiments")
+	}
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Experiment{}, boundedPageSize(a.PageSize), "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the experiment listing options")
+	}
+	experiments, totalSize, _, err := ec.ResourceManager.ListExperiments(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list experiments")
+	}
+	summaries := []experimentSummary{}
+	for _, e := range experiments {
+		summaries = append(summaries, toExperimentSummary(e))
+	}
+	return JSONResult(map[string]interface{}{
+		"experiments": summaries,
+		"total_size":  totalSize,
+	})
+}
+
+type getExperimentTool struct{}
+
+type getExperimentArgs struct {
+	ExperimentID string `json:"experiment_id" required:"true" description:"ID of the experiment to fetch."`
+}
+
+func (t *getExperimentTool) Name() string { return "get_experiment" }
+
+func (t *getExperimentTool) Description() string {
+	return "Get the details of an experiment by ID. Use list_runs with experiment_id to see its runs."
+}
+
+func (t *getExperimentTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&getExperimentArgs{})
+}
+
+func (t *getExperimentTool) Mutating() bool { return false }
+
+func (t *getExperimentTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getExperimentArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkExperimentAccess(ctx, ec, a.ExperimentID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the experiment")
+	}
+	experiment, err := ec.ResourceManager.GetExperiment(a.ExperimentID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get experiment %v", a.ExperimentID)
+	}
+	return JSONResult(map[string]interface{}{"experiment": toExperimentSummary(experiment)})
+}
+
+type ensureDefaultExperimentTool struct{}
+
+func (t *ensureDefaultExperimentTool) Name() string { return "ensure_default_experiment" }
+
+func (t *ensureDefaultExperimentTool) Description() string {
+	return "Resolve the Default experiment of a namespace, creating it if it does not exist yet. " +
+		"Use this to obtain an experiment_id for create_run when the user has not named an experiment."
+}
+
+type ensureDefaultExperimentArgs struct {
+	Namespace string `json:"namespace" description:"Namespace to resolve the Default experiment in. Defaults to the session namespace."`
+}
+
+func (t *ensureDefaultExperimentTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&ensureDefaultExperimentArgs{})
+}
+
+func (t *ensureDefaultExperimentTool) Mutating() bool { return true }
+
+// Annotations: re-running resolves to the same experiment.
+func (t *ensureDefaultExperimentTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Idempotent: true}
+}
+
+// plan resolves the namespace and looks for an existing Default
+// experiment, shared by Validate and Execute.
+func (t *ensureDefaultExperimentTool) plan(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, *model.Experiment, error) {
+	var a ensureDefaultExperimentArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeExperiments, common.RbacResourceVerbList); err != nil {
+		return "", nil, util.Wrap(err, "Failed to authorize resolving the default experiment")
+	}
+	existing, err := findDefaultExperiment(ctx, ec, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	return namespace, existing, nil
+}
+
+// Validate reports whether the call would reuse an existing Default
+// experiment or create a new one, so the user approves the concrete
+// outcome.
+func (t *ensureDefaultExperimentTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	namespace, existing, err := t.plan(ctx, ec, args)
+	if err != nil {
+		return "", err
+	}
+	summary := map[string]interface{}{"namespace": namespace, "action": "create"}
+	if existing != nil {
+		summary["action"] = "reuse"
+		summary["experiment"] = toExperimentSummary(existing)
+	}
+	b, err := json.Marshal(summary)
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the default experiment plan")
+	}
+	return string(b), nil
+}
+
+func (t *ensureDefaultExperimentTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	namespace, existing, err := t.plan(ctx, ec, args)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return JSONResult(map[string]interface{}{"experiment": toExperimentSummary(existing), "created": false})
+	}
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeExperiments, common.RbacResourceVerbCreate); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize creating the default experiment")
+	}
+	created, err := ec.ResourceManager.CreateExperiment(&model.Experiment{
+		Name:         defaultExperimentName,
+		Description:  "All runs created without specifying an experiment will be grouped here.",
+		Namespace:    namespace,
+		StorageState: model.StorageStateAvailable,
+	})
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to create the default experiment")
+	}
+	result, err := JSONResult(map[string]interface{}{"experiment": toExperimentSummary(created), "created": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "experiment", ResourceID: created.UUID, DisplayName: created.Name, Action: MutationCreated}
+	return result, nil
+}
+
+// findDefaultExperiment scans the namespace for an experiment named
+// Default. The scan is bounded; a namespace pathological enough to bury
+// its Default experiment beyond it simply gets a fresh one.
+func findDefaultExperiment(ctx context.Context, ec *ExecContext, namespace string) (*model.Experiment, error) {
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Experiment{}, maxListPageSize, "created_at asc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the experiment listing options")
+	}
+	for page := 0; page < maxDefaultExperimentPages; page++ {
+		// ListExperiments does not honor the context, so the per-tool
+		// deadline is enforced between pages.
+		if err := ctx.Err(); err != nil {
+			return nil, util.Wrap(err, "The default experiment scan was cut off")
+		}
+		experiments, _, nextPageToken, err := ec.ResourceManager.ListExperiments(filterContext, opts)
+		if err != nil {
+			return nil, util.Wrap(err, "Failed to list experiments while resolving the default experiment")
+		}
+		for _, e := range experiments {
+			if e.Name == defaultExperimentName {
+				return e, nil
+			}
+		}
+		if nextPageToken == "" {
+			return nil, nil
+		}
+		if opts, err = list.NewOptionsFromToken(nextPageToken, maxListPageSize); err != nil {
+			return nil, util.Wrap(err, "Failed to continue the experiment listing")
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Fixture-driven golden tests for the builtin tools. Each YAML case
+// under testdata/fixtures names a tool and its arguments; the case runs
+// against a fresh fake backend seeded with one experiment, pipeline,
+// run and recurring run, and the outcome — the result JSON, the
+// mutation, or the error — is compared against
+// testdata/golden/<case>.json. Any change to a tool's output format
+// shows up as a golden diff and is reviewed explicitly. Regenerate with
+//
+//	go test ./src/apiserver/ai/tool/ -run TestToolFixtures -update
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/config/proxy"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/template"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files under testdata/golden")
+
+// sequentialUUIDGenerator hands out a deterministic UUID sequence, so
+// every seeded resource gets a distinct but stable ID and mutating
+// cases can create resources without colliding with the seeds.
+type sequentialUUIDGenerator struct {
+	next int
+}
+
+func (g *sequentialUUIDGenerator) NewRandom() (uuid.UUID, error) {
+	g.next++
+	return uuid.Parse(fmt.Sprintf("00000000-0000-0000-0000-%012d", g.next))
+}
+
+// fixtureWorkflow is the pipeline spec the fixture world is seeded
+// with. It doubles as the $PIPELINE_SPEC placeholder value for cases
+// that take an inline spec.
+const fixtureWorkflow = `
+apiVersion: argoproj.io/v1alpha1
+kind: Workflow
+metadata:
+  name: fixture-workflow
+spec:
+  entrypoint: trainer
+  arguments:
+    parameters:
+    - name: param1
+  templates:
+  - name: trainer
+    container:
+      image: library/trainer
+      command: [train]
+`
+
+// fixtureCase is one YAML entry under testdata/fixtures.
+type fixtureCase struct {
+	// Name identifies the case and its golden file.
+	Name string `json:"name"`
+	// Tool is the builtin tool to execute.
+	Tool string `json:"tool"`
+	// Args are the tool arguments; string values may use the $*_ID
+	// placeholders, which resolve to the seeded world's resource IDs.
+	Args map[string]interface{} `json:"args"`
+}
+
+// fixtureOutcome is the shape stored in a golden file.
+type fixtureOutcome struct {
+	Error    string          `json:"error,omitempty"`
+	IsError  bool            `json:"is_error,omitempty"`
+	Mutation *Mutation       `json:"mutation,omitempty"`
+	Content  json.RawMessage `json:"content,omitempty"`
+}
+
+// fixtureWorld is the seeded fake backend every case runs against.
+type fixtureWorld struct {
+	ec *ExecContext
+	// placeholders maps $*_ID tokens to the seeded resource IDs.
+	placeholders map[string]string
+}
+
+func newFixtureWorld(t *testing.T) *fixtureWorld {
+	viper.Set(common.PodNamespace, "ns1")
+	proxy.InitializeConfigWithEmptyForTests()
+	store, err := resource.NewFakeClientManager(util.NewFakeTimeForEpoch(), &sequentialUUIDGenerator{})
+	require.Nil(t, err)
+	manager := resource.NewResourceManager(store, &resource.ResourceManagerOptions{CollectMetrics: false})
+
+	experiment, err := manager.CreateExperiment(&model.Experiment{Name: "fixtures"})
+	require.Nil(t, err)
+
+	tmpl, err := template.New([]byte(fixtureWorkflow), template.TemplateOptions{})
+	require.Nil(t, err)
+	pipeline, err := manager.CreatePipeline(&model.Pipeline{Name: "trainer"})
+	require.Nil(t, err)
+	version, err := manager.CreatePipelineVersion(&model.PipelineVersion{
+		Name:         "trainer/v1",
+		PipelineId:   pipeline.UUID,
+		Status:       model.PipelineVersionReady,
+		PipelineSpec: model.LargeText(tmpl.Bytes()),
+	})
+	require.Nil(t, err)
+
+	run, err := manager.CreateRun(context.Background(), &model.Run{
+		DisplayName:  "training-run",
+		ExperimentId: experiment.UUID,
+		PipelineSpec: model.PipelineSpec{
+			PipelineId: pipeline.UUID,
+			Parameters: `[{"name":"param1","value":"world"}]`,
+		},
+	})
+	require.Nil(t, err)
+
+	job, err := manager.CreateJob(context.Background(), &model.Job{
+		DisplayName:  "nightly-training",
+		Enabled:      true,
+		ExperimentId: experiment.UUID,
+		PipelineSpec: model.PipelineSpec{
+			WorkflowSpecManifest: model.LargeText(fixtureWorkflow),
+		},
+	})
+	require.Nil(t, err)
+
+	return &fixtureWorld{
+		ec: &ExecContext{
+			ResourceManager: manager,
+			UserID:          "fixture-user",
+			SignedLogLink: func(runId string, nodeId string) (string, int64) {
+				return fmt.Sprintf("https://example.invalid/logs/%v/%v", runId, nodeId), 2000000000
+			},
+		},
+		placeholders: map[string]string{
+			"$EXPERIMENT_ID":       experiment.UUID,
+			"$PIPELINE_ID":         pipeline.UUID,
+			"$PIPELINE_VERSION_ID": version.UUID,
+			"$RUN_ID":              run.UUID,
+			"$RECURRING_RUN_ID":    job.UUID,
+			"$PIPELINE_SPEC":       fixtureWorkflow,
+		},
+	}
+}
+
+// resolve substitutes the world's placeholders into string values,
+// recursing through nested maps and lists.
+func (w *fixtureWorld) resolve(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if resolved, ok := w.placeholders[v]; ok {
+			return resolved
+		}
+		return v
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for key, item := range v {
+			out[key] = w.resolve(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			out = append(out, w.resolve(item))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func loadFixtureCases(t *testing.T) []fixtureCase {
+	paths, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*.yaml"))
+	require.Nil(t, err)
+	require.NotEmpty(t, paths, "no fixture files under testdata/fixtures")
+	var cases []fixtureCase
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.Nil(t, err)
+		var fileCases []fixtureCase
+		require.Nil(t, yaml.UnmarshalStrict(data, &fileCases), "parsing %v", path)
+		cases = append(cases, fileCases...)
+	}
+	return cases
+}
+
+// outcomeFor normalizes an execution into the golden shape. JSON
+// content is re-indented so golden diffs stay readable.
+func outcomeFor(result *Result, err error) *fixtureOutcome {
+	if err != nil {
+		return &fixtureOutcome{Error: err.Error()}
+	}
+	outcome := &fixtureOutcome{IsError: result.IsError, Mutation: result.Mutation}
+	var decoded interface{}
+	if json.Unmarshal([]byte(result.Content), &decoded) == nil && result.Content != "" {
+		outcome.Content, _ = json.Marshal(decoded)
+	} else {
+		outcome.Content, _ = json.Marshal(result.Content)
+	}
+	return outcome
+}
+
+func goldenCompare(t *testing.T, name string, outcome *fixtureOutcome) {
+	marshaled, err := json.Marshal(outcome)
+	require.Nil(t, err)
+	var indented bytes.Buffer
+	require.Nil(t, json.Indent(&indented, marshaled, "", "  "))
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *updateGolden {
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.Nil(t, os.WriteFile(path, indented.Bytes(), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.Nil(t, err, "missing golden file %v; run the fixture tests with -update to create it", path)
+	assert.Equal(t, string(want), indented.String(), "golden mismatch for %v; rerun with -update if the change is intended", name)
+}
+
+func TestToolFixtures(t *testing.T) {
+	for _, c := range loadFixtureCases(t) {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			world := newFixtureWorld(t)
+			tool := NewRegistry().Get(c.Tool)
+			require.NotNil(t, tool, "case %v names unknown tool %v", c.Name, c.Tool)
+			args, _ := world.resolve(c.Args).(map[string]interface{})
+			result, err := tool.Execute(context.Background(), world.ec, args)
+			goldenCompare(t, c.Name, outcomeFor(result, err))
+		})
+	}
+}
+
+// Every builtin tool must have at least one fixture case, so a new tool
+// cannot land without its output format under golden review.
+func TestToolFixtures_CoverEveryBuiltinTool(t *testing.T) {
+	covered := map[string]bool{}
+	for _, c := range loadFixtureCases(t) {
+		covered[c.Tool] = true
+	}
+	for _, tool := range builtinTools() {
+		assert.True(t, covered[tool.Name()], "builtin tool %v has no fixture case under testdata/fixtures", tool.Name())
+	}
+}
+
+// The schema golden pins every builtin tool's name, description, input
+// schema and behavior annotations, so schema changes the model sees are
+// reviewed like output changes.
+func TestToolSchemas_MatchTheGoldenFile(t *testing.T) {
+	tools := builtinTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name() < tools[j].Name() })
+	schemas := make([]map[string]interface{}, 0, len(tools))
+	for _, tool := range tools {
+		schemas = append(schemas, map[string]interface{}{
+			"name":         tool.Name(),
+			"description":  tool.Description(),
+			"mutating":     tool.Mutating(),
+			"annotations":  AnnotationsFor(tool),
+			"input_schema": tool.InputSchema(),
+		})
+	}
+	marshaled, err := json.Marshal(schemas)
+	require.Nil(t, err)
+	var outcome fixtureOutcome
+	outcome.Content = marshaled
+	goldenCompare(t, "tool_schemas", &outcome)
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	flakinessRunSampleSize = 200
+	// maxFlakyTaskRunScans bounds the per-run task listings used to find
+	// intermittently failing steps.
+	maxFlakyTaskRunScans = 20
+)
+
+// pipelineFlakinessTool analyzes the historical runs of a pipeline for
+// flakiness: alternating pass/fail outcomes on identical parameters and
+// steps that fail only intermittently.
+type pipelineFlakinessTool struct{}
+
+func (t *pipelineFlakinessTool) Name() string { return "detect_pipeline_flakiness" }
+
+func (t *pipelineFlakinessTool) Description() string {
+	return "Analyze a pipeline's recent runs for flakiness: alternating pass/fail on identical parameters and intermittently failing tasks. Returns a flakiness score between 0 and 1."
+}
+
+type pipelineFlakinessArgs struct {
+	PipelineID string `json:"pipeline_id" required:"true" description:"Pipeline to analyze."`
+	Namespace  string `json:"namespace" description:"Namespace the pipeline's runs live in."`
+}
+
+func (t *pipelineFlakinessTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&pipelineFlakinessArgs{})
+}
+
+func (t *pipelineFlakinessTool) Mutating() bool { return false }
+
+func (t *pipelineFlakinessTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a pipelineFlakinessArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	pipelineId := a.PipelineID
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize the flakiness analysis")
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Run{}, flakinessRunSampleSize, "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the run listing options")
+	}
+	allRuns, _, _, err := ec.ResourceManager.ListRuns(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list runs for the flakiness analysis")
+	}
+	var runs []*model.Run
+	for _, r := range allRuns {
+		if r.PipelineId == pipelineId {
+			runs = append(runs, r)
+		}
+	}
+	if len(runs) < 2 {
+		return JSONResult(map[string]interface{}{
+			"pipeline_id":     pipelineId,
+			"analyzed_runs":   len(runs),
+			"flakiness_score": 0.0,
+			"note":            "Not enough finished runs of this pipeline to assess flakiness.",
+		})
+	}
+
+	score, flakyGroups := computeFlakiness(runs)
+	flakyTasks, err := t.findIntermittentTasks(runs, ec)
+	if err != nil {
+		return nil, err
+	}
+	return JSONResult(map[string]interface{}{
+		"pipeline_id":          pipelineId,
+		"analyzed_runs":        len(runs),
+		"flakiness_score":      score,
+		"flaky_parameter_sets": flakyGroups,
+		"intermittent_tasks":   flakyTasks,
+		"assessment_hint":      "Scores above 0.3 indicate significant flakiness; 0 means outcomes were consistent per parameter set.",
+	})
+}
+
+// flakyGroup describes one parameter set with mixed outcomes.
+type flakyGroup struct {
+	Parameters   string   `json:"parameters"`
+	Runs         int      `json:"runs"`
+	Failed       int      `json:"failed"`
+	Succeeded    int      `json:"succeeded"`
+	Alternations int      `json:"alternations"`
+	RunIDs       []string `json:"run_ids"`
+}
+
+// computeFlakiness groups finished runs by their exact parameter string
+// and measures how often the outcome alternates within each group. The
+// score is the weighted average alternation rate across groups, so runs
+// that consistently pass or consistently fail score 0.
+func computeFlakiness(runs []*model.Run) (float64, []flakyGroup) {
+	byParameters := map[string][]*model.Run{}
+	for _, r := range runs {
+		if !isFailedRunState(r.State) && r.State != model.RuntimeStateSucceeded && r.State != model.RuntimeStateSucceededV1 {
+			continue
+		}
+		key := string(r.RuntimeConfig.Parameters)
+		byParameters[key] = append(byParameters[key], r)
+	}
+
+	var weightedAlternations, comparablePairs float64
+	var groups []flakyGroup
+	for params, groupRuns := range byParameters {
+		if len(groupRuns) < 2 {
+			continue
+		}
+		sort.Slice(groupRuns, func(i, j int) bool { return groupRuns[i].CreatedAtInSec < groupRuns[j].CreatedAtInSec })
+		group := flakyGroup{Parameters: params, Runs: len(groupRuns)}
+		for i, r := range groupRuns {
+			group.RunIDs = append(group.RunIDs, r.UUID)
+			if isFailedRunState(r.State) {
+				group.Failed++
+			} else {
+				group.Succeeded++
+			}
+			if i > 0 && isFailedRunState(r.State) != isFailedRunState(groupRuns[i-1].State) {
+				group.Alternations++
+			}
+		}
+		weightedAlternations += float64(group.Alternations)
+		comparablePairs += float64(len(groupRuns) - 1)
+		if group.Failed > 0 && group.Succeeded > 0 {
+			groups = append(groups, group)
+		}
+	}
+	if comparablePairs == 0 {
+		return 0, groups
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Alternations > groups[j].Alternations })
+	score := weightedAlternations / comparablePairs
+	return roundScore(score), groups
+}
+
+// findIntermittentTasks scans the tasks of a bounded number of runs and
+// reports task names that failed in some runs but succeeded in others.
+func (t *pipelineFlakinessTool) findIntermittentTasks(runs []*model.Run, ec *ExecContext) ([]map[string]interface{}, error) {
+	failedIn := map[string]int{}
+	succeededIn := map[string]int{}
+	scanned := 0
+	for _, r := range runs {
+		if scanned >= maxFlakyTaskRunScans {
+			break
+		}
+		filterContext := &model.FilterContext{
+			ReferenceKey: &model.ReferenceKey{Type: model.RunResourceType, ID: r.UUID},
+		}
+		opts, err := list.NewOptions(&model.Task{}, maxListPageSize, "", nil)
+		if err != nil {
+			return nil, util.Wrap(err, "Failed to build the task listing options")
+		}
+		tasks, _, _, err := ec.ResourceManager.ListTasks(filterContext, opts)
+		if err != nil {
+			// Task details may be unavailable for old runs; skip rather
+			// than failing the whole analysis.
+			continue
+		}
+		scanned++
+		for _, task := range tasks {
+			if task.Name == "" {
+				continue
+			}
+			if isFailedRunState(task.State) {
+				failedIn[task.Name]++
+			} else if task.State == model.RuntimeStateSucceeded || task.State == model.RuntimeStateSucceededV1 {
+				succeededIn[task.Name]++
+			}
+		}
+	}
+	flaky := []map[string]interface{}{}
+	for name, failures := range failedIn {
+		if succeededIn[name] == 0 {
+			continue
+		}
+		flaky = append(flaky, map[string]interface{}{
+			"task_name":    name,
+			"failed_in":    failures,
+			"succeeded_in": succeededIn[name],
+		})
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		return flaky[i]["failed_in"].(int) > flaky[j]["failed_in"].(int)
+	})
+	return flaky, nil
+}
+
+func roundScore(score float64) float64 {
+	rounded := fmt.Sprintf("%.2f", score)
+	var out float64
+	fmt.Sscanf(rounded, "%f", &out)
+	return out
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func flakinessRun(id string, createdAt int64, state model.RuntimeState, parameters string) *model.Run {
+	return &model.Run{
+		UUID: id,
+		PipelineSpec: model.PipelineSpec{
+			RuntimeConfig: model.RuntimeConfig{Parameters: model.LargeText(parameters)},
+		},
+		RunDetails: model.RunDetails{
+			CreatedAtInSec: createdAt,
+			State:          state,
+		},
+	}
+}
+
+func TestComputeFlakiness_AlternatingOutcomes(t *testing.T) {
+	runs := []*model.Run{
+		flakinessRun("r1", 1, model.RuntimeStateSucceeded, `{"lr":"0.1"}`),
+		flakinessRun("r2", 2, model.RuntimeStateFailed, `{"lr":"0.1"}`),
+		flakinessRun("r3", 3, model.RuntimeStateSucceeded, `{"lr":"0.1"}`),
+		flakinessRun("r4", 4, model.RuntimeStateFailed, `{"lr":"0.1"}`),
+	}
+	score, groups := computeFlakiness(runs)
+	assert.Equal(t, 1.0, score)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, 2, groups[0].Failed)
+	assert.Equal(t, 2, groups[0].Succeeded)
+	assert.Equal(t, 3, groups[0].Alternations)
+}
+
+func TestComputeFlakiness_ConsistentOutcomesScoreZero(t *testing.T) {
+	runs := []*model.Run{
+		flakinessRun("r1", 1, model.RuntimeStateSucceeded, `{"lr":"0.1"}`),
+		flakinessRun("r2", 2, model.RuntimeStateSucceeded, `{"lr":"0.1"}`),
+		flakinessRun("r3", 3, model.RuntimeStateFailed, `{"lr":"0.2"}`),
+		flakinessRun("r4", 4, model.RuntimeStateFailed, `{"lr":"0.2"}`),
+	}
+	score, groups := computeFlakiness(runs)
+	assert.Equal(t, 0.0, score)
+	assert.Empty(t, groups)
+}
+
+func TestComputeFlakiness_IgnoresUnfinishedRuns(t *testing.T) {
+	runs := []*model.Run{
+		flakinessRun("r1", 1, model.RuntimeStateRunning, `{"lr":"0.1"}`),
+		flakinessRun("r2", 2, model.RuntimeStatePending, `{"lr":"0.1"}`),
+	}
+	score, groups := computeFlakiness(runs)
+	assert.Equal(t, 0.0, score)
+	assert.Empty(t, groups)
+}
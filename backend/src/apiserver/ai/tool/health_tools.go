@@ -0,0 +1,204 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+const (
+	healthSnapshotRunSampleSize = 200
+	maxHealthReportedItems      = 10
+)
+
+// namespaceHealthTool aggregates the data behind a "morning status
+// report": run failure rates, stuck runs, recurring runs that stopped
+// firing and the most recent failures.
+type namespaceHealthTool struct{}
+
+func (t *namespaceHealthTool) Name() string { return "namespace_health" }
+
+func (t *namespaceHealthTool) Description() string {
+	return "Summarize the health of a namespace: run failure rate, stuck runs, quiet recurring runs and recent failures."
+}
+
+type namespaceHealthArgs struct {
+	Namespace           string `json:"namespace" description:"Namespace to report on."`
+	LookbackHours       int    `json:"lookback_hours" default:"24" description:"How far back to look at runs (default 24)."`
+	StuckThresholdHours int    `json:"stuck_threshold_hours" default:"6" description:"Runs running longer than this are considered stuck (default 6)."`
+}
+
+func (t *namespaceHealthTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&namespaceHealthArgs{})
+}
+
+func (t *namespaceHealthTool) Mutating() bool { return false }
+
+func (t *namespaceHealthTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a namespaceHealthArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize the namespace health snapshot")
+	}
+
+	lookbackHours := a.LookbackHours
+	stuckThresholdHours := a.StuckThresholdHours
+	now := time.Now()
+	lookbackCutoff := now.Add(-time.Duration(lookbackHours) * time.Hour).Unix()
+	stuckCutoff := now.Add(-time.Duration(stuckThresholdHours) * time.Hour).Unix()
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Run{}, healthSnapshotRunSampleSize, "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the run listing options")
+	}
+	runs, _, _, err := ec.ResourceManager.ListRuns(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list runs for the health snapshot")
+	}
+
+	var total, failed, succeeded, running int
+	var stuckRuns, recentFailures []runSummary
+	lastRunByRecurringRun := map[string]int64{}
+	for _, r := range runs {
+		if r.RecurringRunId != "" && r.CreatedAtInSec > lastRunByRecurringRun[r.RecurringRunId] {
+			lastRunByRecurringRun[r.RecurringRunId] = r.CreatedAtInSec
+		}
+		if r.CreatedAtInSec < lookbackCutoff {
+			// Runs are sorted newest first; older runs only feed the
+			// recurring-run freshness map above.
+			if isActiveRunState(r.State) && r.CreatedAtInSec < stuckCutoff && len(stuckRuns) < maxHealthReportedItems {
+				stuckRuns = append(stuckRuns, toRunSummary(r))
+			}
+			continue
+		}
+		total++
+		switch {
+		case isFailedRunState(r.State):
+			failed++
+			if len(recentFailures) < maxHealthReportedItems {
+				recentFailures = append(recentFailures, toRunSummary(r))
+			}
+		case r.State == model.RuntimeStateSucceeded || r.State == model.RuntimeStateSucceededV1:
+			succeeded++
+		case isActiveRunState(r.State):
+			running++
+			if r.CreatedAtInSec < stuckCutoff && len(stuckRuns) < maxHealthReportedItems {
+				stuckRuns = append(stuckRuns, toRunSummary(r))
+			}
+		}
+	}
+
+	quietRecurringRuns, err := t.findQuietRecurringRuns(namespace, lastRunByRecurringRun, lookbackCutoff, ec)
+	if err != nil {
+		return nil, err
+	}
+
+	failureRate := 0.0
+	if total > 0 {
+		failureRate = float64(failed) / float64(total)
+	}
+	return JSONResult(map[string]interface{}{
+		"namespace":      namespace,
+		"lookback_hours": lookbackHours,
+		"runs": map[string]interface{}{
+			"total":        total,
+			"failed":       failed,
+			"succeeded":    succeeded,
+			"running":      running,
+			"failure_rate": fmt.Sprintf("%.0f%%", failureRate*100),
+		},
+		"stuck_runs":           stuckRuns,
+		"recent_failures":      recentFailures,
+		"quiet_recurring_runs": quietRecurringRuns,
+	})
+}
+
+// findQuietRecurringRuns reports enabled recurring runs that produced no
+// run within the lookback window.
+func (t *namespaceHealthTool) findQuietRecurringRuns(namespace string, lastRunByRecurringRun map[string]int64, lookbackCutoff int64, ec *ExecContext) ([]map[string]interface{}, error) {
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Job{}, maxListPageSize, "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the recurring run listing options")
+	}
+	jobs, _, _, err := ec.ResourceManager.ListJobs(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list recurring runs for the health snapshot")
+	}
+	quiet := []map[string]interface{}{}
+	for _, job := range jobs {
+		if !job.Enabled {
+			continue
+		}
+		lastRunAt := lastRunByRecurringRun[job.UUID]
+		if lastRunAt >= lookbackCutoff {
+			continue
+		}
+		// A recurring run created inside the window may simply not have
+		// fired yet.
+		if job.CreatedAtInSec >= lookbackCutoff {
+			continue
+		}
+		entry := map[string]interface{}{
+			"recurring_run_id": job.UUID,
+			"display_name":     job.DisplayName,
+		}
+		if lastRunAt > 0 {
+			entry["last_run_created_at"] = lastRunAt
+		}
+		quiet = append(quiet, entry)
+		if len(quiet) >= maxHealthReportedItems {
+			break
+		}
+	}
+	return quiet, nil
+}
+
+func isFailedRunState(state model.RuntimeState) bool {
+	switch state {
+	case model.RuntimeStateFailed, model.RuntimeStateFailedV1, model.RuntimeStateErrorV1:
+		return true
+	default:
+		return false
+	}
+}
+
+func isActiveRunState(state model.RuntimeState) bool {
+	switch state {
+	case model.RuntimeStateRunning, model.RuntimeStateRunningV1, model.RuntimeStatePending, model.RuntimeStatePendingV1:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+const (
+	// maxClusteredFailedRuns bounds how many failed runs have their logs
+	// fetched for clustering.
+	maxClusteredFailedRuns = 10
+	// maxClusteredLogBytes bounds the log bytes kept per task.
+	maxClusteredLogBytes = 256 * 1024
+	maxReportedClusters  = 10
+	maxExamplesCluster   = 3
+)
+
+var (
+	errorLineRegexp = regexp.MustCompile(`(?i)\b(error|exception|fatal|panic|traceback|failed)\b`)
+	// Fingerprint normalizers: hex identifiers (UUIDs, digests, pointers)
+	// first, then remaining digit runs, so "retry 3 of 5" and "retry 4 of
+	// 5" share a signature.
+	hexTokenRegexp = regexp.MustCompile(`\b[0-9a-fA-F]{8,}(-[0-9a-fA-F]{4,}){0,4}\b`)
+	digitRegexp    = regexp.MustCompile(`\d+`)
+)
+
+// logClusterTool clusters error lines across the failed runs of a
+// pipeline so repeated failure causes stand out without fetching every
+// log into the conversation.
+type logClusterTool struct{}
+
+func (t *logClusterTool) Name() string { return "cluster_failure_logs" }
+
+func (t *logClusterTool) Description() string {
+	return "Cluster error lines across a pipeline's recent failed runs into signatures with occurrence counts, to find the most common failure cause."
+}
+
+type logClusterArgs struct {
+	PipelineID string `json:"pipeline_id" required:"true" description:"Pipeline whose failed runs to analyze."`
+	Namespace  string `json:"namespace" description:"Namespace the pipeline's runs live in."`
+}
+
+func (t *logClusterTool) InputSchema() map[string]interface{} { return SchemaFor(&logClusterArgs{}) }
+
+func (t *logClusterTool) Mutating() bool { return false }
+
+func (t *logClusterTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a logClusterArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	pipelineId := a.PipelineID
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize the log clustering")
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Run{}, flakinessRunSampleSize, "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the run listing options")
+	}
+	runs, _, _, err := ec.ResourceManager.ListRuns(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list runs for the log clustering")
+	}
+
+	clusters := map[string]*logCluster{}
+	var scannedRuns, scannedTasks int
+	for _, r := range runs {
+		if scannedRuns >= maxClusteredFailedRuns {
+			break
+		}
+		if r.PipelineId != pipelineId || !isFailedRunState(r.State) {
+			continue
+		}
+		scannedRuns++
+		tasks, err := listRunTasks(r.UUID, ec)
+		if err != nil {
+			// Task details may be unavailable for old runs; skip rather
+			// than failing the whole analysis.
+			ec.Warnf("Skipped run %v: its task details are unavailable", r.UUID)
+			continue
+		}
+		for _, task := range tasks {
+			if !isFailedRunState(task.State) || task.PodName == "" {
+				continue
+			}
+			content, truncated, err := readBoundedLog(ctx, ec, r.UUID, task.PodName)
+			if err != nil {
+				glog.V(4).Infof("Skipping logs of pod %v in run %v: %v", task.PodName, r.UUID, err)
+				ec.Warnf("Skipped logs of pod %v in run %v: they could not be read", task.PodName, r.UUID)
+				continue
+			}
+			if truncated {
+				ec.Warnf("Log of pod %v in run %v was truncated at %v bytes for clustering", task.PodName, r.UUID, maxClusteredLogBytes)
+			}
+			scannedTasks++
+			clusterErrorLines(content, r.UUID, task.Name, clusters)
+		}
+	}
+
+	ranked := rankClusters(clusters)
+	return JSONResult(map[string]interface{}{
+		"pipeline_id":    pipelineId,
+		"scanned_runs":   scannedRuns,
+		"scanned_tasks":  scannedTasks,
+		"error_clusters": ranked,
+	})
+}
+
+// logCluster aggregates error lines that normalize to one signature.
+type logCluster struct {
+	Signature string   `json:"signature"`
+	Count     int      `json:"count"`
+	RunIDs    []string `json:"run_ids"`
+	TaskNames []string `json:"task_names"`
+	Examples  []string `json:"examples"`
+
+	seenRuns  map[string]bool
+	seenTasks map[string]bool
+}
+
+func listRunTasks(runId string, ec *ExecContext) ([]*model.Task, error) {
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.RunResourceType, ID: runId},
+	}
+	opts, err := list.NewOptions(&model.Task{}, maxListPageSize, "", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the task listing options")
+	}
+	tasks, _, _, err := ec.ResourceManager.ListTasks(filterContext, opts)
+	return tasks, err
+}
+
+// readBoundedLog fetches a task's log, keeping at most
+// maxClusteredLogBytes of it, and reports whether it was truncated.
+func readBoundedLog(ctx context.Context, ec *ExecContext, runId string, podName string) (string, bool, error) {
+	var buf bytes.Buffer
+	dst := &cappedWriter{buf: &buf, limit: maxClusteredLogBytes}
+	if err := ec.ResourceManager.ReadLog(ctx, runId, podName, false, dst); err != nil {
+		return "", false, err
+	}
+	return buf.String(), dst.truncated, nil
+}
+
+// cappedWriter keeps the first limit bytes and discards the rest, so
+// oversized logs cannot exhaust memory.
+type cappedWriter struct {
+	buf       *bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if remaining := w.limit - w.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			w.buf.Write(p[:remaining])
+			w.truncated = true
+		} else {
+			w.buf.Write(p)
+		}
+	} else if len(p) > 0 {
+		w.truncated = true
+	}
+	return len(p), nil
+}
+
+// clusterErrorLines adds each error-looking line of content to the
+// cluster keyed by its fingerprint.
+func clusterErrorLines(content string, runId string, taskName string, clusters map[string]*logCluster) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 64*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !errorLineRegexp.MatchString(line) {
+			continue
+		}
+		signature := fingerprintLogLine(line)
+		cluster := clusters[signature]
+		if cluster == nil {
+			cluster = &logCluster{
+				Signature: signature,
+				seenRuns:  map[string]bool{},
+				seenTasks: map[string]bool{},
+			}
+			clusters[signature] = cluster
+		}
+		cluster.Count++
+		if !cluster.seenRuns[runId] {
+			cluster.seenRuns[runId] = true
+			cluster.RunIDs = append(cluster.RunIDs, runId)
+		}
+		if taskName != "" && !cluster.seenTasks[taskName] {
+			cluster.seenTasks[taskName] = true
+			cluster.TaskNames = append(cluster.TaskNames, taskName)
+		}
+		if len(cluster.Examples) < maxExamplesCluster {
+			cluster.Examples = append(cluster.Examples, line)
+		}
+	}
+}
+
+// fingerprintLogLine normalizes the volatile parts of a log line so
+// repeated occurrences of the same failure share a signature.
+func fingerprintLogLine(line string) string {
+	s := hexTokenRegexp.ReplaceAllString(line, "#")
+	s = digitRegexp.ReplaceAllString(s, "#")
+	s = whitespaceSequenceRegexp.ReplaceAllString(s, " ")
+	return s
+}
+
+var whitespaceSequenceRegexp = regexp.MustCompile(`\s+`)
+
+// logDownloadLinkTool returns a time-limited download link for a task's
+// full log, for when the log is too large to paste into the chat.
+type logDownloadLinkTool struct{}
+
+func (t *logDownloadLinkTool) Name() string { return "get_log_download_link" }
+
+func (t *logDownloadLinkTool) Description() string {
+	return "Generate a time-limited download link for the full log of a task, instead of truncating a large log in chat. Share the returned URL with the user."
+}
+
+type logDownloadLinkArgs struct {
+	RunID    string `json:"run_id" required:"true" description:"Run the task belongs to."`
+	TaskName string `json:"task_name" description:"Task whose log to link. Omit node_id when set."`
+	NodeID   string `json:"node_id" description:"Pod name of the task, if already known."`
+}
+
+func (t *logDownloadLinkTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&logDownloadLinkArgs{})
+}
+
+func (t *logDownloadLinkTool) Mutating() bool { return false }
+
+func (t *logDownloadLinkTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a logDownloadLinkArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	runId := a.RunID
+	if ec.SignedLogLink == nil {
+		return nil, util.NewInternalServerError(errors.New("no link signer configured"), "Log download links are not available on this server")
+	}
+	if err := checkRunAccess(ctx, ec, runId, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize the log download link")
+	}
+	nodeId, err := resolveTaskPod(ec, runId, a.TaskName, a.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	link, expiresAt := ec.SignedLogLink(runId, nodeId)
+	return JSONResult(map[string]interface{}{
+		"run_id":       runId,
+		"node_id":      nodeId,
+		"download_url": link,
+		"expires_at":   expiresAt,
+		"note":         "Present this link to the user; it expires and is not tied to their login session.",
+	})
+}
+
+// resolveTaskPod resolves the pod a log request targets: the given
+// node ID when the caller already knows it, otherwise the pod of the
+// named task.
+func resolveTaskPod(ec *ExecContext, runId, taskName, nodeId string) (string, error) {
+	if nodeId != "" {
+		return nodeId, nil
+	}
+	if taskName == "" {
+		return "", util.NewInvalidInputError("Either task_name or node_id is required")
+	}
+	tasks, err := listRunTasks(runId, ec)
+	if err != nil {
+		return "", util.Wrap(err, "Failed to list the run's tasks")
+	}
+	for _, task := range tasks {
+		if task.Name == taskName {
+			return task.PodName, nil
+		}
+	}
+	return "", util.NewResourceNotFoundError("Task", taskName)
+}
+
+const (
+	// defaultTaskLogTailLines and maxTaskLogTailLines bound the tail-N a
+	// get_task_logs call may request.
+	defaultTaskLogTailLines = 200
+	maxTaskLogTailLines     = 2000
+	// defaultTaskLogBytes and maxTaskLogBytes bound the bytes returned
+	// into the chat context.
+	defaultTaskLogBytes = 64 * 1024
+	maxTaskLogBytes     = 256 * 1024
+)
+
+// boundedTailLines clamps a requested tail into the allowed range.
+func boundedTailLines(tailLines int) int64 {
+	switch {
+	case tailLines <= 0:
+		return defaultTaskLogTailLines
+	case tailLines > maxTaskLogTailLines:
+		return maxTaskLogTailLines
+	default:
+		return int64(tailLines)
+	}
+}
+
+// boundedLogBytes clamps a requested byte limit into the allowed range.
+func boundedLogBytes(maxBytes int) int {
+	switch {
+	case maxBytes <= 0:
+		return defaultTaskLogBytes
+	case maxBytes > maxTaskLogBytes:
+		return maxTaskLogBytes
+	default:
+		return maxBytes
+	}
+}
+
+// getTaskLogsTool fetches a bounded slice of one task's log, so failure
+// analysis reads the relevant tail instead of dumping the whole run log
+// into the context window.
+type getTaskLogsTool struct{}
+
+func (t *getTaskLogsTool) Name() string { return "get_task_logs" }
+
+func (t *getTaskLogsTool) Description() string {
+	return "Fetch the log of one task of a run, bounded by tail lines and bytes. Defaults to the main container; sidecar containers can be selected."
+}
+
+type getTaskLogsArgs struct {
+	RunID     string `json:"run_id" required:"true" description:"Run the task belongs to."`
+	TaskName  string `json:"task_name" description:"Task whose log to fetch. Omit node_id when set."`
+	NodeID    string `json:"node_id" description:"Pod name of the task, if already known."`
+	Container string `json:"container" description:"Container to read, e.g. a sidecar. Defaults to main."`
+	TailLines int    `json:"tail_lines" description:"Return only the last N lines (default 200, max 2000)."`
+	MaxBytes  int    `json:"max_bytes" description:"Cap the returned bytes (default 65536, max 262144)."`
+}
+
+func (t *getTaskLogsTool) InputSchema() map[string]interface{} { return SchemaFor(&getTaskLogsArgs{}) }
+
+func (t *getTaskLogsTool) Mutating() bool { return false }
+
+func (t *getTaskLogsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getTaskLogsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the task logs")
+	}
+	nodeId, err := resolveTaskPod(ec, a.RunID, a.TaskName, a.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	container := a.Container
+	if container == "" {
+		container = "main"
+	}
+	var buf bytes.Buffer
+	dst := &cappedWriter{buf: &buf, limit: boundedLogBytes(a.MaxBytes)}
+	if err := ec.ResourceManager.ReadContainerLog(ctx, a.RunID, nodeId, container, boundedTailLines(a.TailLines), dst); err != nil {
+		return nil, util.Wrapf(err, "Failed to read the logs of pod %v in run %v", nodeId, a.RunID)
+	}
+	result := map[string]interface{}{
+		"run_id":    a.RunID,
+		"node_id":   nodeId,
+		"container": container,
+		"log":       buf.String(),
+	}
+	if dst.truncated {
+		result["truncated"] = true
+		result["note"] = "The log was cut off at the byte limit; use get_log_download_link for the full log."
+	}
+	return JSONResult(result)
+}
+
+func rankClusters(clusters map[string]*logCluster) []*logCluster {
+	ranked := []*logCluster{}
+	for _, c := range clusters {
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Signature < ranked[j].Signature
+	})
+	if len(ranked) > maxReportedClusters {
+		ranked = ranked[:maxReportedClusters]
+	}
+	return ranked
+}
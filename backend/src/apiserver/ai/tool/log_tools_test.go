@@ -0,0 +1,68 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprintLogLine_NormalizesVolatileTokens(t *testing.T) {
+	a := fingerprintLogLine("Error: upload of chunk 3 failed for run 0a1b2c3d-4e5f-6789-abcd-ef0123456789")
+	b := fingerprintLogLine("Error: upload of chunk 17 failed for run ffee0011-2233-4455-6677-889900aabbcc")
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, fingerprintLogLine("Error: download of chunk 3 failed"))
+}
+
+func TestClusterErrorLines_CountsAndExamples(t *testing.T) {
+	clusters := map[string]*logCluster{}
+	clusterErrorLines("connect error to 10.0.0.1\nall good here\nconnect error to 10.0.0.2\n", "run-1", "train", clusters)
+	clusterErrorLines("connect error to 10.0.0.3\n", "run-2", "train", clusters)
+
+	assert.Len(t, clusters, 1)
+	for _, c := range clusters {
+		assert.Equal(t, 3, c.Count)
+		assert.Equal(t, []string{"run-1", "run-2"}, c.RunIDs)
+		assert.Equal(t, []string{"train"}, c.TaskNames)
+		assert.Len(t, c.Examples, 3)
+	}
+}
+
+func TestCappedWriter_DiscardsBeyondLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := &cappedWriter{buf: &buf, limit: 4}
+	n, err := w.Write([]byte("abcdef"))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+	n, err = w.Write([]byte("gh"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "abcd", buf.String())
+}
+
+func TestBoundedTailLines_ClampsIntoRange(t *testing.T) {
+	assert.Equal(t, int64(defaultTaskLogTailLines), boundedTailLines(0))
+	assert.Equal(t, int64(defaultTaskLogTailLines), boundedTailLines(-5))
+	assert.Equal(t, int64(50), boundedTailLines(50))
+	assert.Equal(t, int64(maxTaskLogTailLines), boundedTailLines(maxTaskLogTailLines+1))
+}
+
+func TestBoundedLogBytes_ClampsIntoRange(t *testing.T) {
+	assert.Equal(t, defaultTaskLogBytes, boundedLogBytes(0))
+	assert.Equal(t, 1024, boundedLogBytes(1024))
+	assert.Equal(t, maxTaskLogBytes, boundedLogBytes(maxTaskLogBytes*2))
+}
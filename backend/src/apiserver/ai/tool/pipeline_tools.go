@@ -0,0 +1,431 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/template"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// maxInlinePipelineSpecBytes caps the spec upload_pipeline accepts
+// inline. Compiled IR specs are typically a few kilobytes; anything
+// near this limit belongs in a package URL instead.
+const maxInlinePipelineSpecBytes = 512 * 1024
+
+type pipelineSummary struct {
+	PipelineID  string `json:"pipeline_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	Status      string `json:"status,omitempty"`
+}
+
+func toPipelineSummary(p *model.Pipeline) pipelineSummary {
+	return pipelineSummary{
+		PipelineID:  p.UUID,
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+		Description: string(p.Description),
+		Namespace:   p.Namespace,
+		CreatedAt:   p.CreatedAtInSec,
+		Status:      string(p.Status),
+	}
+}
+
+type pipelineVersionSummary struct {
+	PipelineVersionID string `json:"pipeline_version_id"`
+	PipelineID        string `json:"pipeline_id"`
+	Name              string `json:"name"`
+	DisplayName       string `json:"display_name,omitempty"`
+	Description       string `json:"description,omitempty"`
+	CreatedAt         int64  `json:"created_at"`
+	CodeSourceURL     string `json:"code_source_url,omitempty"`
+}
+
+func toPipelineVersionSummary(v *model.PipelineVersion) pipelineVersionSummary {
+	return pipelineVersionSummary{
+		PipelineVersionID: v.UUID,
+		PipelineID:        v.PipelineId,
+		Name:              v.Name,
+		DisplayName:       v.DisplayName,
+		Description:       string(v.Description),
+		CreatedAt:         v.CreatedAtInSec,
+		CodeSourceURL:     v.CodeSourceUrl,
+	}
+}
+
+type listPipelinesTool struct{}
+
+type listPipelinesArgs struct {
+	Namespace string `json:"namespace" description:"Namespace to list pipelines in."`
+	PageSize  int    `json:"page_size" description:"Maximum number of pipelines to return (default 20, max 100)."`
+}
+
+func (t *listPipelinesTool) Name() string { return "list_pipelines" }
+
+func (t *listPipelinesTool) Description() string {
+	return "List the pipelines registered in a namespace, newest first."
+}
+
+func (t *listPipelinesTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&listPipelinesArgs{})
+}
+
+func (t *listPipelinesTool) Mutating() bool { return false }
+
+func (t *listPipelinesTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listPipelinesArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypePipelines, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize listing pipelines")
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	opts, err := list.NewOptions(&model.Pipeline{}, boundedPageSize(a.PageSize), "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the pipeline listing options")
+	}
+	pipelines, totalSize, _, err := ec.ResourceManager.ListPipelines(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list pipelines")
+	}
+	summaries := []pipelineSummary{}
+	for _, p := range pipelines {
+		summaries = append(summaries, toPipelineSummary(p))
+	}
+	return JSONResult(map[string]interface{}{
+		"pipelines":  summaries,
+		"total_size": totalSize,
+	})
+}
+
+type getPipelineTool struct{}
+
+type getPipelineArgs struct {
+	PipelineID string `json:"pipeline_id" required:"true" description:"ID of the pipeline to fetch."`
+}
+
+func (t *getPipelineTool) Name() string { return "get_pipeline" }
+
+func (t *getPipelineTool) Description() string {
+	return "Get the details of a pipeline by ID, including its latest version."
+}
+
+func (t *getPipelineTool) InputSchema() map[string]interface{} { return SchemaFor(&getPipelineArgs{}) }
+
+func (t *getPipelineTool) Mutating() bool { return false }
+
+func (t *getPipelineTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getPipelineArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	pipeline, err := ec.ResourceManager.GetPipeline(a.PipelineID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get pipeline %v", a.PipelineID)
+	}
+	if err := checkNamespaceAccess(ctx, ec, ec.ResourceManager.ReplaceNamespace(pipeline.Namespace), common.RbacResourceTypePipelines, common.RbacResourceVerbGet); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the pipeline")
+	}
+	result := map[string]interface{}{"pipeline": toPipelineSummary(pipeline)}
+	if latest, err := ec.ResourceManager.GetLatestPipelineVersion(a.PipelineID); err == nil {
+		result["latest_version"] = map[string]interface{}{
+			"pipeline_version_id": latest.UUID,
+			"name":                latest.Name,
+			"display_name":        latest.DisplayName,
+			"created_at":          latest.CreatedAtInSec,
+			"description":         string(latest.Description),
+		}
+	}
+	return JSONResult(result)
+}
+
+type getPipelineVersionTool struct{}
+
+type getPipelineVersionArgs struct {
+	PipelineVersionID string `json:"pipeline_version_id" required:"true" description:"ID of the pipeline version to fetch."`
+}
+
+func (t *getPipelineVersionTool) Name() string { return "get_pipeline_version" }
+
+func (t *getPipelineVersionTool) Description() string {
+	return "Get the details of a pipeline version by ID, including its declared parameters."
+}
+
+func (t *getPipelineVersionTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&getPipelineVersionArgs{})
+}
+
+func (t *getPipelineVersionTool) Mutating() bool { return false }
+
+func (t *getPipelineVersionTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getPipelineVersionArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	version, err := ec.ResourceManager.GetPipelineVersion(a.PipelineVersionID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get pipeline version %v", a.PipelineVersionID)
+	}
+	if err := authorizePipelineRead(ctx, ec, version.PipelineId); err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{"pipeline_version": toPipelineVersionSummary(version)}
+	// The stored parameters are a JSON array; surface them structured so
+	// the model can line them up against create_run arguments.
+	var parameters interface{}
+	if json.Unmarshal([]byte(version.Parameters), &parameters) == nil {
+		result["parameters"] = parameters
+	}
+	return JSONResult(result)
+}
+
+type listPipelineVersionsTool struct{}
+
+type listPipelineVersionsArgs struct {
+	PipelineID string `json:"pipeline_id" required:"true" description:"Pipeline whose versions to list."`
+	PageSize   int    `json:"page_size" description:"Maximum number of versions to return (default 20, max 100)."`
+}
+
+func (t *listPipelineVersionsTool) Name() string { return "list_pipeline_versions" }
+
+func (t *listPipelineVersionsTool) Description() string {
+	return "List the versions of a pipeline, newest first."
+}
+
+func (t *listPipelineVersionsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&listPipelineVersionsArgs{})
+}
+
+func (t *listPipelineVersionsTool) Mutating() bool { return false }
+
+func (t *listPipelineVersionsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listPipelineVersionsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := authorizePipelineRead(ctx, ec, a.PipelineID); err != nil {
+		return nil, err
+	}
+	opts, err := list.NewOptions(&model.PipelineVersion{}, boundedPageSize(a.PageSize), "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the pipeline version listing options")
+	}
+	versions, totalSize, _, err := ec.ResourceManager.ListPipelineVersions(a.PipelineID, opts)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to list the versions of pipeline %v", a.PipelineID)
+	}
+	summaries := []pipelineVersionSummary{}
+	for _, v := range versions {
+		summaries = append(summaries, toPipelineVersionSummary(v))
+	}
+	return JSONResult(map[string]interface{}{
+		"pipeline_versions": summaries,
+		"total_size":        totalSize,
+	})
+}
+
+// authorizePipelineRead resolves the version's pipeline and checks read
+// access in its namespace.
+func authorizePipelineRead(ctx context.Context, ec *ExecContext, pipelineId string) error {
+	pipeline, err := ec.ResourceManager.GetPipeline(pipelineId)
+	if err != nil {
+		return util.Wrapf(err, "Failed to get pipeline %v", pipelineId)
+	}
+	if err := checkNamespaceAccess(ctx, ec, ec.ResourceManager.ReplaceNamespace(pipeline.Namespace), common.RbacResourceTypePipelines, common.RbacResourceVerbGet); err != nil {
+		return util.Wrap(err, "Failed to authorize reading the pipeline")
+	}
+	return nil
+}
+
+type uploadPipelineTool struct{}
+
+type uploadPipelineArgs struct {
+	Name        string `json:"name" required:"true" description:"Name to register the pipeline under."`
+	Spec        string `json:"spec" required:"true" description:"Inline pipeline spec: the IR YAML (or JSON) produced by the KFP SDK compiler."`
+	Description string `json:"description" description:"Optional description of the pipeline."`
+	Namespace   string `json:"namespace" description:"Namespace to register the pipeline in. Defaults to the session namespace."`
+}
+
+func (t *uploadPipelineTool) Name() string { return "upload_pipeline" }
+
+func (t *uploadPipelineTool) Description() string {
+	return "Register a new pipeline from an inline spec, creating the pipeline and its first version. " +
+		"Use create_pipeline_version to add a version to an existing pipeline instead."
+}
+
+func (t *uploadPipelineTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&uploadPipelineArgs{})
+}
+
+func (t *uploadPipelineTool) Mutating() bool { return true }
+
+// plan decodes and bounds the arguments and parses the spec, shared by
+// Validate and Execute so the confirmation validates exactly what would
+// be registered.
+func (t *uploadPipelineTool) plan(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*uploadPipelineArgs, template.Template, error) {
+	var a uploadPipelineArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, nil, err
+	}
+	if strings.TrimSpace(a.Spec) == "" {
+		return nil, nil, util.NewInvalidInputError("The pipeline spec must not be empty")
+	}
+	if len(a.Spec) > maxInlinePipelineSpecBytes {
+		return nil, nil, util.NewInvalidInputError(
+			"The pipeline spec is %v bytes; the inline limit is %v. Host larger packages at a URL and use create_pipeline_version", len(a.Spec), maxInlinePipelineSpecBytes)
+	}
+	if a.Namespace == "" {
+		a.Namespace = ec.Namespace
+	}
+	a.Namespace = ec.ResourceManager.ReplaceNamespace(a.Namespace)
+	if err := checkNamespaceAccess(ctx, ec, a.Namespace, common.RbacResourceTypePipelines, common.RbacResourceVerbCreate); err != nil {
+		return nil, nil, util.Wrap(err, "Failed to authorize registering a pipeline")
+	}
+	tmpl, err := template.New([]byte(a.Spec), template.TemplateOptions{})
+	if err != nil {
+		return nil, nil, util.Wrap(err, "The pipeline spec is not a valid pipeline template")
+	}
+	return &a, tmpl, nil
+}
+
+// Validate parses the spec before the user is asked to confirm, so an
+// invalid spec fails here instead of after approval, and the
+// confirmation names the pipeline the spec itself declares.
+func (t *uploadPipelineTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	a, tmpl, err := t.plan(ctx, ec, args)
+	if err != nil {
+		return "", err
+	}
+	summary := map[string]interface{}{
+		"action":     "upload",
+		"name":       a.Name,
+		"namespace":  a.Namespace,
+		"spec_bytes": len(a.Spec),
+	}
+	if tmpl.IsV2() {
+		summary["spec_pipeline_name"] = tmpl.V2PipelineName()
+	}
+	b, err := json.Marshal(summary)
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the pipeline upload summary")
+	}
+	return string(b), nil
+}
+
+func (t *uploadPipelineTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	a, tmpl, err := t.plan(ctx, ec, args)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, version, err := ec.ResourceManager.CreatePipelineAndPipelineVersion(
+		&model.Pipeline{
+			Name:        a.Name,
+			Description: model.LargeText(a.Description),
+			Namespace:   a.Namespace,
+		},
+		&model.PipelineVersion{
+			Name:         a.Name,
+			Description:  model.LargeText(a.Description),
+			PipelineSpec: model.LargeText(string(tmpl.Bytes())),
+		})
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to register the pipeline")
+	}
+	result, err := JSONResult(map[string]interface{}{
+		"pipeline":            toPipelineSummary(pipeline),
+		"pipeline_version_id": version.UUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "pipeline", ResourceID: pipeline.UUID, DisplayName: pipeline.Name, Action: MutationCreated}
+	return result, nil
+}
+
+type createPipelineVersionTool struct{}
+
+type createPipelineVersionArgs struct {
+	PipelineID  string `json:"pipeline_id" required:"true" description:"Pipeline to add the version to."`
+	Name        string `json:"name" required:"true" description:"Name of the new version."`
+	PackageURL  string `json:"package_url" required:"true" description:"URL of the pipeline package to import."`
+	Description string `json:"description" description:"Optional description of the version."`
+}
+
+func (t *createPipelineVersionTool) Name() string { return "create_pipeline_version" }
+
+func (t *createPipelineVersionTool) Description() string {
+	return "Register a new version of an existing pipeline from a package URL."
+}
+
+func (t *createPipelineVersionTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&createPipelineVersionArgs{})
+}
+
+func (t *createPipelineVersionTool) Mutating() bool { return true }
+
+func (t *createPipelineVersionTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a createPipelineVersionArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	pipeline, err := ec.ResourceManager.GetPipeline(a.PipelineID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get pipeline %v", a.PipelineID)
+	}
+	if err := checkNamespaceAccess(ctx, ec, ec.ResourceManager.ReplaceNamespace(pipeline.Namespace), common.RbacResourceTypePipelines, common.RbacResourceVerbCreate); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize creating a pipeline version")
+	}
+	version := &model.PipelineVersion{
+		Name:            a.Name,
+		PipelineId:      a.PipelineID,
+		Description:     model.LargeText(a.Description),
+		PipelineSpecURI: model.LargeText(a.PackageURL),
+		CodeSourceUrl:   a.PackageURL,
+	}
+	created, err := ec.ResourceManager.CreatePipelineVersion(version)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to create the pipeline version")
+	}
+	result, err := JSONResult(map[string]interface{}{
+		"pipeline_version_id": created.UUID,
+		"name":                created.Name,
+		"pipeline_id":         created.PipelineId,
+		"created_at":          created.CreatedAtInSec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "pipeline_version", ResourceID: created.UUID, DisplayName: created.Name, Action: MutationCreated}
+	return result, nil
+}
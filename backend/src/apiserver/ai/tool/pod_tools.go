@@ -0,0 +1,179 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxPodEvents bounds how many events one call returns into the chat
+// context; crash-looping pods can accumulate hundreds of repeats.
+const maxPodEvents = 30
+
+// getRunPodEventsTool surfaces the Kubernetes view of a run's pod: its
+// phase and conditions, per-container states and the events recorded
+// for it. The real cause of many failures — ImagePullBackOff, OOMKilled,
+// Pending on unschedulable resources — never appears in container logs,
+// only here.
+type getRunPodEventsTool struct{}
+
+type getRunPodEventsArgs struct {
+	RunID    string `json:"run_id" required:"true" description:"Run the pod belongs to."`
+	TaskName string `json:"task_name" description:"Task whose pod to describe. Omit node_id when set."`
+	NodeID   string `json:"node_id" description:"Pod name of the task, if already known."`
+}
+
+func (t *getRunPodEventsTool) Name() string { return "get_run_pod_events" }
+
+func (t *getRunPodEventsTool) Description() string {
+	return "Get the Kubernetes events and pod status (phase, conditions, container states) for one of a run's pods. " +
+		"Use this when a failure's cause is not in the container logs, e.g. image pull errors, OOM kills or pods stuck Pending."
+}
+
+func (t *getRunPodEventsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&getRunPodEventsArgs{})
+}
+
+func (t *getRunPodEventsTool) Mutating() bool { return false }
+
+// podEventSummary is the model-facing view of one Kubernetes event.
+type podEventSummary struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+	// Count is how often the event repeated; crash loops show up here.
+	Count       int32 `json:"count,omitempty"`
+	LastSeenAt  int64 `json:"last_seen_at,omitempty"`
+	FirstSeenAt int64 `json:"first_seen_at,omitempty"`
+}
+
+// podConditionSummary is the model-facing view of one pod condition.
+type podConditionSummary struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// containerStateSummary is the model-facing view of one container's
+// state, flattened from the k8s waiting/running/terminated union.
+type containerStateSummary struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// ExitCode is set for terminated containers; 137 is the classic OOM
+	// kill signature alongside reason OOMKilled.
+	ExitCode     *int32 `json:"exit_code,omitempty"`
+	RestartCount int32  `json:"restart_count"`
+}
+
+func toContainerStateSummary(status corev1.ContainerStatus) containerStateSummary {
+	summary := containerStateSummary{Name: status.Name, RestartCount: status.RestartCount}
+	switch {
+	case status.State.Waiting != nil:
+		summary.State = "waiting"
+		summary.Reason = status.State.Waiting.Reason
+		summary.Message = status.State.Waiting.Message
+	case status.State.Terminated != nil:
+		summary.State = "terminated"
+		summary.Reason = status.State.Terminated.Reason
+		summary.Message = status.State.Terminated.Message
+		exitCode := status.State.Terminated.ExitCode
+		summary.ExitCode = &exitCode
+	case status.State.Running != nil:
+		summary.State = "running"
+	}
+	return summary
+}
+
+// summarizePodStatus flattens the pod's status into the model-facing
+// shape. Conditions with status True and no message are routine and
+// omitted; the interesting ones carry a reason.
+func summarizePodStatus(pod *corev1.Pod) map[string]interface{} {
+	conditions := []podConditionSummary{}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue && condition.Reason == "" && condition.Message == "" {
+			continue
+		}
+		conditions = append(conditions, podConditionSummary{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+	containers := []containerStateSummary{}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		containers = append(containers, toContainerStateSummary(status))
+	}
+	return map[string]interface{}{
+		"phase":      string(pod.Status.Phase),
+		"reason":     pod.Status.Reason,
+		"message":    pod.Status.Message,
+		"conditions": conditions,
+		"containers": containers,
+	}
+}
+
+func (t *getRunPodEventsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getRunPodEventsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the run's pod events")
+	}
+	nodeId, err := resolveTaskPod(ec, a.RunID, a.TaskName, a.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	pod, events, err := ec.ResourceManager.DescribeRunPod(ctx, a.RunID, nodeId)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to describe pod %v of run %v", nodeId, a.RunID)
+	}
+
+	summaries := []podEventSummary{}
+	for _, event := range events {
+		if len(summaries) == maxPodEvents {
+			ec.Warnf("Only the first %v of %v events of pod %v are shown", maxPodEvents, len(events), nodeId)
+			break
+		}
+		summaries = append(summaries, podEventSummary{
+			Type:        event.Type,
+			Reason:      event.Reason,
+			Message:     event.Message,
+			Count:       event.Count,
+			LastSeenAt:  event.LastTimestamp.Unix(),
+			FirstSeenAt: event.FirstTimestamp.Unix(),
+		})
+	}
+	result := map[string]interface{}{
+		"run_id":  a.RunID,
+		"node_id": nodeId,
+		"events":  summaries,
+	}
+	if pod != nil {
+		result["pod"] = summarizePodStatus(pod)
+	} else {
+		result["pod_missing"] = "The pod no longer exists; only its recorded events are available."
+	}
+	return JSONResult(result)
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestToContainerStateSummary_FlattensTheStateUnion(t *testing.T) {
+	waiting := toContainerStateSummary(corev1.ContainerStatus{
+		Name:         "main",
+		RestartCount: 3,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason:  "ImagePullBackOff",
+			Message: "Back-off pulling image",
+		}},
+	})
+	assert.Equal(t, "waiting", waiting.State)
+	assert.Equal(t, "ImagePullBackOff", waiting.Reason)
+	assert.Equal(t, int32(3), waiting.RestartCount)
+	assert.Nil(t, waiting.ExitCode)
+
+	terminated := toContainerStateSummary(corev1.ContainerStatus{
+		Name: "main",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:   "OOMKilled",
+			ExitCode: 137,
+		}},
+	})
+	assert.Equal(t, "terminated", terminated.State)
+	assert.Equal(t, "OOMKilled", terminated.Reason)
+	require.NotNil(t, terminated.ExitCode)
+	assert.Equal(t, int32(137), *terminated.ExitCode)
+
+	running := toContainerStateSummary(corev1.ContainerStatus{
+		Name:  "main",
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	})
+	assert.Equal(t, "running", running.State)
+	assert.Empty(t, running.Reason)
+}
+
+func TestSummarizePodStatus_KeepsOnlyInterestingConditions(t *testing.T) {
+	status := summarizePodStatus(&corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{
+			// Routine healthy condition: dropped.
+			{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse,
+				Reason: "Unschedulable", Message: "0/3 nodes have enough memory"},
+		},
+	}})
+
+	assert.Equal(t, "Pending", status["phase"])
+	conditions, ok := status["conditions"].([]podConditionSummary)
+	require.True(t, ok)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, "PodScheduled", conditions[0].Type)
+	assert.Equal(t, "Unschedulable", conditions[0].Reason)
+}
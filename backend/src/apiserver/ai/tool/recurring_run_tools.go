@@ -0,0 +1,343 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	apiv2beta1 "github.com/kubeflow/pipelines/backend/api/v2beta1/go_client"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// recurringRunSummary is the model-facing view of a recurring run (a
+// job in storage terms). Like runSummary it omits the pipeline spec,
+// which is far too large for a chat context.
+type recurringRunSummary struct {
+	RecurringRunID  string `json:"recurring_run_id"`
+	DisplayName     string `json:"display_name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ExperimentID    string `json:"experiment_id,omitempty"`
+	Enabled         bool   `json:"enabled"`
+	CronSchedule    string `json:"cron_schedule,omitempty"`
+	IntervalSeconds int64  `json:"interval_seconds,omitempty"`
+	MaxConcurrency  int64  `json:"max_concurrency,omitempty"`
+	NoCatchup       bool   `json:"no_catchup,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at,omitempty"`
+	PipelineID      string `json:"pipeline_id,omitempty"`
+	VersionID       string `json:"pipeline_version_id,omitempty"`
+	Description     string `json:"description,omitempty"`
+}
+
+func toRecurringRunSummary(j *model.Job) recurringRunSummary {
+	summary := recurringRunSummary{
+		RecurringRunID: j.UUID,
+		DisplayName:    j.DisplayName,
+		Namespace:      j.Namespace,
+		ExperimentID:   j.ExperimentId,
+		Enabled:        j.Enabled,
+		MaxConcurrency: j.MaxConcurrency,
+		NoCatchup:      j.NoCatchup,
+		CreatedAt:      j.CreatedAtInSec,
+		UpdatedAt:      j.UpdatedAtInSec,
+		PipelineID:     j.PipelineSpec.PipelineId,
+		VersionID:      j.PipelineSpec.PipelineVersionId,
+		Description:    j.Description,
+	}
+	if j.Trigger.CronSchedule.Cron != nil {
+		summary.CronSchedule = *j.Trigger.CronSchedule.Cron
+	}
+	if j.Trigger.PeriodicSchedule.IntervalSecond != nil {
+		summary.IntervalSeconds = *j.Trigger.PeriodicSchedule.IntervalSecond
+	}
+	return summary
+}
+
+type listRecurringRunsTool struct{}
+
+type listRecurringRunsArgs struct {
+	Namespace    string `json:"namespace" description:"Namespace to list recurring runs in."`
+	ExperimentID string `json:"experiment_id" description:"Restrict results to this experiment."`
+	PageSize     int    `json:"page_size" description:"Maximum number of recurring runs to return (default 20, max 100)."`
+}
+
+func (t *listRecurringRunsTool) Name() string { return "list_recurring_runs" }
+
+func (t *listRecurringRunsTool) Description() string {
+	return "List recurring runs (scheduled jobs) in a namespace, newest first, including whether each is enabled and its schedule."
+}
+
+func (t *listRecurringRunsTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&listRecurringRunsArgs{})
+}
+
+func (t *listRecurringRunsTool) Mutating() bool { return false }
+
+func (t *listRecurringRunsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listRecurringRunsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeJobs, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize listing recurring runs")
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	if a.ExperimentID != "" {
+		if err := ec.ResourceManager.CheckExperimentBelongsToNamespace(a.ExperimentID, namespace); err != nil {
+			return nil, util.Wrap(err, "Failed to list recurring runs due to namespace mismatch")
+		}
+		filterContext = &model.FilterContext{
+			ReferenceKey: &model.ReferenceKey{Type: model.ExperimentResourceType, ID: a.ExperimentID},
+		}
+	}
+
+	opts, err := list.NewOptions(&model.Job{}, boundedPageSize(a.PageSize), "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the recurring run listing options")
+	}
+	jobs, totalSize, _, err := ec.ResourceManager.ListJobs(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list recurring runs")
+	}
+
+	summaries := []recurringRunSummary{}
+	for _, j := range jobs {
+		summaries = append(summaries, toRecurringRunSummary(j))
+	}
+	return JSONResult(map[string]interface{}{
+		"recurring_runs": summaries,
+		"total_size":     totalSize,
+	})
+}
+
+// checkRecurringRunAccess mirrors the access check of the recurring run
+// gRPC server: the job resolves the namespace the RBAC check runs in.
+func checkRecurringRunAccess(ctx context.Context, ec *ExecContext, jobId string, resourceAttributes *authorizationv1.ResourceAttributes) error {
+	if !common.IsMultiUserMode() {
+		return nil
+	}
+	if jobId != "" {
+		job, err := ec.ResourceManager.GetJob(jobId)
+		if err != nil {
+			return util.Wrapf(err, "Failed to authorize with the recurring run ID %v", jobId)
+		}
+		resourceAttributes.Namespace = job.Namespace
+		if resourceAttributes.Name == "" {
+			resourceAttributes.Name = job.K8SName
+		}
+	}
+	if ec.ResourceManager.IsEmptyNamespace(resourceAttributes.Namespace) {
+		return util.NewInvalidInputError("A recurring run cannot have an empty namespace in multi-user mode")
+	}
+	resourceAttributes.Group = common.RbacPipelinesGroup
+	resourceAttributes.Version = common.RbacPipelinesVersion
+	resourceAttributes.Resource = common.RbacResourceTypeJobs
+	return ec.ResourceManager.IsAuthorized(ctx, resourceAttributes)
+}
+
+// verifyRecurringRunForMutation fetches the recurring run and matches it
+// against the user's description, like verifyRunForMutation does for
+// runs.
+func verifyRecurringRunForMutation(ec *ExecContext, jobId, expectedName string) (*model.Job, error) {
+	job, err := ec.ResourceManager.GetJob(jobId)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to fetch recurring run %v for verification", jobId)
+	}
+	if ec.Namespace != "" && job.Namespace != "" && job.Namespace != ec.Namespace {
+		return nil, util.NewPermissionDeniedError(errors.New("namespace mismatch"),
+			"Recurring run %v belongs to namespace %v, not the session namespace %v", job.UUID, job.Namespace, ec.Namespace)
+	}
+	if expectedName != "" && job.DisplayName != expectedName {
+		return nil, util.NewInvalidInputError(
+			"Recurring run %v is named %q, not %q; refusing to proceed against a recurring run that does not match the user's description",
+			job.UUID, job.DisplayName, expectedName)
+	}
+	return job, nil
+}
+
+// marshalRecurringRunVerification renders the verification summary
+// attached to the confirmation request.
+func marshalRecurringRunVerification(action string, job *model.Job) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{"action": action, "verified_recurring_run": toRecurringRunSummary(job)})
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the recurring run verification summary")
+	}
+	return string(b), nil
+}
+
+// recurringRunModeTool enables or disables a recurring run's schedule.
+// The two directions are separate tools so each carries its own RBAC
+// verb and shows up by name in confirmations and the audit log.
+type recurringRunModeTool struct {
+	enable bool
+}
+
+type recurringRunModeArgs struct {
+	RecurringRunID      string `json:"recurring_run_id" required:"true" description:"ID of the recurring run."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the recurring run."`
+}
+
+func (t *recurringRunModeTool) Name() string {
+	if t.enable {
+		return "enable_recurring_run"
+	}
+	return "disable_recurring_run"
+}
+
+func (t *recurringRunModeTool) Description() string {
+	if t.enable {
+		return "Enable a recurring run, so its schedule resumes creating runs."
+	}
+	return "Disable a recurring run, so its schedule stops creating new runs. Already-running runs keep executing."
+}
+
+func (t *recurringRunModeTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&recurringRunModeArgs{})
+}
+
+func (t *recurringRunModeTool) Mutating() bool { return true }
+
+// Annotations: re-applying the same mode is a no-op.
+func (t *recurringRunModeTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Idempotent: true}
+}
+
+func (t *recurringRunModeTool) verb() string {
+	if t.enable {
+		return common.RbacResourceVerbEnable
+	}
+	return common.RbacResourceVerbDisable
+}
+
+// Validate fetches the recurring run before the user is asked to
+// confirm, so the confirmation shows the schedule as it actually exists.
+func (t *recurringRunModeTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a recurringRunModeArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRecurringRunAccess(ctx, ec, a.RecurringRunID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return "", util.Wrapf(err, "Failed to authorize changing the recurring run's mode")
+	}
+	job, err := verifyRecurringRunForMutation(ec, a.RecurringRunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if job.Enabled == t.enable {
+		return "", util.NewFailedPreconditionError(errors.New("recurring run already in the requested mode"),
+			"Recurring run %v already has enabled=%v; there is nothing to change", a.RecurringRunID, job.Enabled)
+	}
+	return marshalRecurringRunVerification(t.Name(), job)
+}
+
+func (t *recurringRunModeTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a recurringRunModeArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRecurringRunAccess(ctx, ec, a.RecurringRunID, &authorizationv1.ResourceAttributes{Verb: t.verb()}); err != nil {
+		return nil, util.Wrapf(err, "Failed to authorize changing the recurring run's mode")
+	}
+	if err := ec.ResourceManager.ChangeJobMode(ctx, a.RecurringRunID, t.enable); err != nil {
+		return nil, util.Wrapf(err, "Failed to set enabled=%v on recurring run %v", t.enable, a.RecurringRunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"recurring_run_id": a.RecurringRunID, "enabled": t.enable})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "recurring_run", ResourceID: a.RecurringRunID, Action: MutationUpdated}
+	return result, nil
+}
+
+type deleteRecurringRunTool struct{}
+
+type deleteRecurringRunArgs struct {
+	RecurringRunID      string `json:"recurring_run_id" required:"true" description:"ID of the recurring run to delete."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the recurring run."`
+}
+
+func (t *deleteRecurringRunTool) Name() string { return "delete_recurring_run" }
+
+func (t *deleteRecurringRunTool) Description() string {
+	return "Permanently delete a recurring run and its schedule. Runs it already created are kept. This cannot be undone."
+}
+
+func (t *deleteRecurringRunTool) InputSchema() map[string]interface{} {
+	return SchemaFor(&deleteRecurringRunArgs{})
+}
+
+func (t *deleteRecurringRunTool) Mutating() bool { return true }
+
+// Annotations: deletion destroys the schedule for good.
+func (t *deleteRecurringRunTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Destructive: true, Idempotent: true}
+}
+
+// Validate fetches the recurring run before the user is asked to
+// confirm. Deleting a schedule that is still enabled is almost never
+// what the user wants, so the check insists it is disabled first.
+func (t *deleteRecurringRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a deleteRecurringRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRecurringRunAccess(ctx, ec, a.RecurringRunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbDelete}); err != nil {
+		return "", util.Wrap(err, "Failed to authorize deleting the recurring run")
+	}
+	job, err := verifyRecurringRunForMutation(ec, a.RecurringRunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if job.Enabled {
+		return "", util.NewFailedPreconditionError(errors.New("recurring run still enabled"),
+			"Recurring run %v is still enabled; disable it before deleting it", a.RecurringRunID)
+	}
+	return marshalRecurringRunVerification("delete", job)
+}
+
+func (t *deleteRecurringRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a deleteRecurringRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRecurringRunAccess(ctx, ec, a.RecurringRunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbDelete}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize deleting the recurring run")
+	}
+	if err := ec.ResourceManager.DeleteJob(ctx, a.RecurringRunID, apiv2beta1.DeletePropagationPolicy_DELETE_PROPAGATION_POLICY_UNSPECIFIED); err != nil {
+		return nil, util.Wrapf(err, "Failed to delete recurring run %v", a.RecurringRunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"recurring_run_id": a.RecurringRunID, "deleted": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "recurring_run", ResourceID: a.RecurringRunID, Action: MutationDeleted}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRecurringRunSummary_ResolvesTheSchedule(t *testing.T) {
+	cron := "0 0 * * *"
+	interval := int64(3600)
+
+	cronJob := &model.Job{UUID: "j1", DisplayName: "nightly", Enabled: true}
+	cronJob.Trigger.CronSchedule.Cron = &cron
+	summary := toRecurringRunSummary(cronJob)
+	assert.Equal(t, "0 0 * * *", summary.CronSchedule)
+	assert.Zero(t, summary.IntervalSeconds)
+	assert.True(t, summary.Enabled)
+
+	periodicJob := &model.Job{UUID: "j2"}
+	periodicJob.Trigger.PeriodicSchedule.IntervalSecond = &interval
+	summary = toRecurringRunSummary(periodicJob)
+	assert.Empty(t, summary.CronSchedule)
+	assert.Equal(t, int64(3600), summary.IntervalSeconds)
+}
+
+func TestRecurringRunModeTool_NamesAndVerbsFollowTheDirection(t *testing.T) {
+	enable := &recurringRunModeTool{enable: true}
+	assert.Equal(t, "enable_recurring_run", enable.Name())
+	assert.Equal(t, common.RbacResourceVerbEnable, enable.verb())
+
+	disable := &recurringRunModeTool{enable: false}
+	assert.Equal(t, "disable_recurring_run", disable.Name())
+	assert.Equal(t, common.RbacResourceVerbDisable, disable.verb())
+	assert.True(t, disable.Mutating())
+}
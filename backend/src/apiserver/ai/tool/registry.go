@@ -0,0 +1,168 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"sync"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Registry holds the tools available to the assistant. It is safe for
+// concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+	// order preserves registration order so tool listings are stable.
+	order []string
+}
+
+// NewRegistry returns a registry pre-populated with the builtin tools.
+func NewRegistry() *Registry {
+	r := &Registry{tools: map[string]Tool{}}
+	for _, t := range builtinTools() {
+		// Builtin names are unique by construction.
+		_ = r.Register(t)
+	}
+	return r
+}
+
+// Register adds a tool. Registering a duplicate name is an error.
+func (r *Registry) Register(t Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[t.Name()]; ok {
+		return util.NewInvalidInputError("A tool named %q is already registered", t.Name())
+	}
+	r.tools[t.Name()] = t
+	r.order = append(r.order, t.Name())
+	return nil
+}
+
+// StripMutating removes every mutating tool. Read-only deployments call
+// this once at startup, so no serving surface sharing the registry can
+// ever resolve a mutating tool, whatever mode a session claims.
+func (r *Registry) StripMutating() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.order[:0]
+	for _, name := range r.order {
+		if r.tools[name].Mutating() {
+			delete(r.tools, name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	r.order = kept
+}
+
+// Get returns the named tool, or nil if it is not registered.
+func (r *Registry) Get(name string) Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tools[name]
+}
+
+// ListForMode returns the tools available in the given mode in
+// registration order. Ask mode excludes mutating tools.
+func (r *Registry) ListForMode(mode Mode) []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Tool
+	for _, name := range r.order {
+		t := r.tools[name]
+		if mode != ModeAgent && t.Mutating() {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// Annotated is implemented by tools whose behavior hints differ from
+// the defaults derived from Mutating().
+type Annotated interface {
+	Annotations() provider.ToolAnnotations
+}
+
+// AnnotationsFor returns the tool's behavior hints. Unless the tool
+// says otherwise, non-mutating tools are read-only and idempotent, and
+// mutating tools are neither destructive nor idempotent.
+func AnnotationsFor(t Tool) provider.ToolAnnotations {
+	if a, ok := t.(Annotated); ok {
+		return a.Annotations()
+	}
+	readOnly := !t.Mutating()
+	return provider.ToolAnnotations{
+		ReadOnly:    readOnly,
+		Destructive: false,
+		Idempotent:  readOnly,
+	}
+}
+
+// Definitions converts tools into the provider-facing form.
+func Definitions(tools []Tool) []provider.ToolDefinition {
+	var defs []provider.ToolDefinition
+	for _, t := range tools {
+		defs = append(defs, provider.ToolDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			InputSchema: t.InputSchema(),
+			Annotations: AnnotationsFor(t),
+		})
+	}
+	return defs
+}
+
+func builtinTools() []Tool {
+	return []Tool{
+		&listRunsTool{},
+		&getRunTool{},
+		&getRunArtifactsTool{},
+		&listArtifactsTool{},
+		&getArtifactTool{},
+		&compareRunsTool{},
+		&namespaceHealthTool{},
+		&pipelineFlakinessTool{},
+		&logClusterTool{},
+		&getTaskLogsTool{},
+		&getRunPodEventsTool{},
+		&logDownloadLinkTool{},
+		&listRecurringRunsTool{},
+		&listExperimentsTool{},
+		&getExperimentTool{},
+		&ensureDefaultExperimentTool{},
+		&listPipelinesTool{},
+		&getPipelineTool{},
+		&getPipelineVersionTool{},
+		&listPipelineVersionsTool{},
+		&createRunTool{},
+		&uploadPipelineTool{},
+		&createPipelineVersionTool{},
+		&stopRunTool{},
+		&retryRunTool{},
+		&annotateRunTool{},
+		&runArchiveTool{archive: true},
+		&runArchiveTool{archive: false},
+		&experimentArchiveTool{archive: true},
+		&experimentArchiveTool{archive: false},
+		&recurringRunModeTool{enable: true},
+		&recurringRunModeTool{enable: false},
+		&deleteRunTool{},
+		&deleteRecurringRunTool{},
+		&cleanupRunsTool{},
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotationsFor_DefaultsFromMutating(t *testing.T) {
+	assert.Equal(t,
+		provider.ToolAnnotations{ReadOnly: true, Idempotent: true},
+		AnnotationsFor(&listRunsTool{}))
+	assert.Equal(t,
+		provider.ToolAnnotations{},
+		AnnotationsFor(&createRunTool{}))
+}
+
+func TestAnnotationsFor_ToolOverrides(t *testing.T) {
+	assert.Equal(t,
+		provider.ToolAnnotations{Destructive: true, Idempotent: true},
+		AnnotationsFor(&deleteRunTool{}))
+	assert.Equal(t,
+		provider.ToolAnnotations{Idempotent: true},
+		AnnotationsFor(&stopRunTool{}))
+}
+
+func TestDefinitions_CarryAnnotations(t *testing.T) {
+	defs := Definitions([]Tool{&listRunsTool{}, &deleteRunTool{}})
+	assert.True(t, defs[0].Annotations.ReadOnly)
+	assert.True(t, defs[1].Annotations.Destructive)
+}
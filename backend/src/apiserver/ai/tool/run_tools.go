@@ -0,0 +1,693 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/list"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+const (
+	// defaultListPageSize bounds tool listings so results stay small
+	// enough for the model context window.
+	defaultListPageSize = 20
+	maxListPageSize     = 100
+)
+
+// runSummary is the model-facing view of a run. It deliberately omits
+// the runtime manifests, which are far too large for a chat context.
+type runSummary struct {
+	RunID          string `json:"run_id"`
+	DisplayName    string `json:"display_name"`
+	ExperimentID   string `json:"experiment_id,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	State          string `json:"state"`
+	StorageState   string `json:"storage_state,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+	FinishedAt     int64  `json:"finished_at,omitempty"`
+	PipelineID     string `json:"pipeline_id,omitempty"`
+	VersionID      string `json:"pipeline_version_id,omitempty"`
+	Parameters     string `json:"parameters,omitempty"`
+	Description    string `json:"description,omitempty"`
+	RecurringRunID string `json:"recurring_run_id,omitempty"`
+}
+
+func toRunSummary(r *model.Run) runSummary {
+	return runSummary{
+		RunID:          r.UUID,
+		DisplayName:    r.DisplayName,
+		ExperimentID:   r.ExperimentId,
+		Namespace:      r.Namespace,
+		State:          string(r.State),
+		StorageState:   string(r.StorageState),
+		CreatedAt:      r.CreatedAtInSec,
+		FinishedAt:     r.FinishedAtInSec,
+		PipelineID:     r.PipelineId,
+		VersionID:      r.PipelineVersionId,
+		Parameters:     string(r.RuntimeConfig.Parameters),
+		Description:    r.Description,
+		RecurringRunID: r.RecurringRunId,
+	}
+}
+
+// boundedPageSize clamps a requested listing page size into the range
+// the tools allow.
+func boundedPageSize(pageSize int) int {
+	switch {
+	case pageSize <= 0:
+		return defaultListPageSize
+	case pageSize > maxListPageSize:
+		return maxListPageSize
+	default:
+		return pageSize
+	}
+}
+
+type listRunsTool struct{}
+
+type listRunsArgs struct {
+	Namespace    string `json:"namespace" description:"Namespace to list runs in."`
+	ExperimentID string `json:"experiment_id" description:"Restrict results to this experiment."`
+	PageSize     int    `json:"page_size" description:"Maximum number of runs to return (default 20, max 100)."`
+}
+
+func (t *listRunsTool) Name() string { return "list_runs" }
+
+func (t *listRunsTool) Description() string {
+	return "List pipeline runs in a namespace, newest first. Optionally restrict to one experiment."
+}
+
+func (t *listRunsTool) InputSchema() map[string]interface{} { return SchemaFor(&listRunsArgs{}) }
+
+func (t *listRunsTool) Mutating() bool { return false }
+
+func (t *listRunsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a listRunsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = ec.Namespace
+	}
+	namespace = ec.ResourceManager.ReplaceNamespace(namespace)
+
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, common.RbacResourceVerbList); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize listing runs")
+	}
+
+	filterContext := &model.FilterContext{
+		ReferenceKey: &model.ReferenceKey{Type: model.NamespaceResourceType, ID: namespace},
+	}
+	if a.ExperimentID != "" {
+		if err := ec.ResourceManager.CheckExperimentBelongsToNamespace(a.ExperimentID, namespace); err != nil {
+			return nil, util.Wrap(err, "Failed to list runs due to namespace mismatch")
+		}
+		filterContext = &model.FilterContext{
+			ReferenceKey: &model.ReferenceKey{Type: model.ExperimentResourceType, ID: a.ExperimentID},
+		}
+	}
+
+	opts, err := list.NewOptions(&model.Run{}, boundedPageSize(a.PageSize), "created_at desc", nil)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to build the run listing options")
+	}
+	runs, totalSize, _, err := ec.ResourceManager.ListRuns(filterContext, opts)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to list runs")
+	}
+
+	summaries := []runSummary{}
+	for _, r := range runs {
+		summaries = append(summaries, toRunSummary(r))
+	}
+	return JSONResult(map[string]interface{}{
+		"runs":       summaries,
+		"total_size": totalSize,
+	})
+}
+
+type getRunTool struct{}
+
+type getRunArgs struct {
+	RunID string `json:"run_id" required:"true" description:"ID of the run to fetch."`
+}
+
+func (t *getRunTool) Name() string { return "get_run" }
+
+func (t *getRunTool) Description() string {
+	return "Get the details of a single pipeline run by ID, including state, timing and parameters."
+}
+
+func (t *getRunTool) InputSchema() map[string]interface{} { return SchemaFor(&getRunArgs{}) }
+
+func (t *getRunTool) Mutating() bool { return false }
+
+func (t *getRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a getRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize reading the run")
+	}
+	run, err := ec.ResourceManager.GetRun(a.RunID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get run %v", a.RunID)
+	}
+	summary := toRunSummary(run)
+	result := map[string]interface{}{"run": summary}
+	if len(run.StateHistory) > 0 {
+		result["state_history"] = run.StateHistory
+	}
+	return JSONResult(result)
+}
+
+type compareRunsTool struct{}
+
+type compareRunsArgs struct {
+	RunIDs []string `json:"run_ids" required:"true" description:"IDs of the runs to compare (2 to 10)."`
+}
+
+func (t *compareRunsTool) Name() string { return "compare_runs" }
+
+func (t *compareRunsTool) Description() string {
+	return "Compare two or more runs side by side: states, timing, pipeline versions and parameters."
+}
+
+func (t *compareRunsTool) InputSchema() map[string]interface{} { return SchemaFor(&compareRunsArgs{}) }
+
+func (t *compareRunsTool) Mutating() bool { return false }
+
+func (t *compareRunsTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a compareRunsArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if len(a.RunIDs) < 2 || len(a.RunIDs) > 10 {
+		return nil, util.NewInvalidInputError("compare_runs requires between 2 and 10 run_ids, got %v", len(a.RunIDs))
+	}
+	summaries := []runSummary{}
+	for _, runId := range a.RunIDs {
+		if runId == "" {
+			return nil, util.NewInvalidInputError("run_ids must be non-empty strings")
+		}
+		if err := checkRunAccess(ctx, ec, runId, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbGet}); err != nil {
+			return nil, util.Wrap(err, "Failed to authorize reading a compared run")
+		}
+		run, err := ec.ResourceManager.GetRun(runId)
+		if err != nil {
+			return nil, util.Wrapf(err, "Failed to get run %v", runId)
+		}
+		summaries = append(summaries, toRunSummary(run))
+	}
+	return JSONResult(map[string]interface{}{"runs": summaries})
+}
+
+type createRunTool struct{}
+
+type createRunArgs struct {
+	DisplayName       string                 `json:"display_name" required:"true" description:"Human readable name for the run."`
+	ExperimentID      string                 `json:"experiment_id" required:"true" description:"Experiment to create the run in. If the user has not named an experiment, call ensure_default_experiment first to resolve or create the namespace's Default experiment."`
+	PipelineVersionID string                 `json:"pipeline_version_id" description:"Pipeline version to run. If omitted, the latest version of pipeline_id is used."`
+	PipelineID        string                 `json:"pipeline_id" description:"Pipeline to run, used when pipeline_version_id is omitted."`
+	Parameters        map[string]interface{} `json:"parameters" description:"Runtime parameters keyed by parameter name."`
+}
+
+func (t *createRunTool) Name() string { return "create_run" }
+
+func (t *createRunTool) Description() string {
+	return "Create and start a new pipeline run from a pipeline version in an experiment."
+}
+
+func (t *createRunTool) InputSchema() map[string]interface{} { return SchemaFor(&createRunArgs{}) }
+
+func (t *createRunTool) Mutating() bool { return true }
+
+// runSpec is the fully resolved form of a create_run call. It is shown
+// to the user in the confirmation request, so the resolved names are
+// included next to the IDs.
+type runSpec struct {
+	DisplayName         string                 `json:"display_name"`
+	ExperimentId        string                 `json:"experiment_id"`
+	Namespace           string                 `json:"namespace,omitempty"`
+	PipelineId          string                 `json:"pipeline_id,omitempty"`
+	PipelineVersionId   string                 `json:"pipeline_version_id"`
+	PipelineVersionName string                 `json:"pipeline_version_name,omitempty"`
+	Parameters          map[string]interface{} `json:"parameters,omitempty"`
+
+	parametersJSON string
+}
+
+// resolve performs the server-side dry run shared by Validate and
+// Execute: it authorizes the call and resolves the experiment, the
+// pipeline version (including a latest-version reference) and the
+// parameter payload without creating anything.
+func (t *createRunTool) resolve(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*runSpec, error) {
+	var a createRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.PipelineVersionID == "" && a.PipelineID == "" {
+		return nil, util.NewInvalidInputError("Either pipeline_version_id or pipeline_id is required")
+	}
+
+	namespace, err := ec.ResourceManager.GetNamespaceFromExperimentId(a.ExperimentID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to resolve the namespace of experiment %v", a.ExperimentID)
+	}
+	if err := checkNamespaceAccess(ctx, ec, namespace, common.RbacResourceTypeRuns, common.RbacResourceVerbCreate); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize creating a run")
+	}
+
+	pipelineVersionId := a.PipelineVersionID
+	versionName := ""
+	if pipelineVersionId == "" {
+		pipelineVersion, err := ec.ResourceManager.GetLatestPipelineVersion(a.PipelineID)
+		if err != nil {
+			return nil, util.Wrapf(err, "Failed to resolve the latest version of pipeline %v", a.PipelineID)
+		}
+		pipelineVersionId = pipelineVersion.UUID
+		versionName = pipelineVersion.Name
+	} else {
+		pipelineVersion, err := ec.ResourceManager.GetPipelineVersion(pipelineVersionId)
+		if err != nil {
+			return nil, util.Wrapf(err, "Failed to get pipeline version %v", pipelineVersionId)
+		}
+		versionName = pipelineVersion.Name
+	}
+
+	spec := &runSpec{
+		DisplayName:         a.DisplayName,
+		ExperimentId:        a.ExperimentID,
+		Namespace:           namespace,
+		PipelineId:          a.PipelineID,
+		PipelineVersionId:   pipelineVersionId,
+		PipelineVersionName: versionName,
+	}
+	if len(a.Parameters) > 0 {
+		b, err := json.Marshal(a.Parameters)
+		if err != nil {
+			return nil, util.NewInvalidInputError("Failed to serialize run parameters: %s", err.Error())
+		}
+		spec.Parameters = a.Parameters
+		spec.parametersJSON = string(b)
+	}
+	return spec, nil
+}
+
+// Validate dry-runs the call so the confirmation request shows the user
+// exactly what would be submitted.
+func (t *createRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	spec, err := t.resolve(ctx, ec, args)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the resolved run spec")
+	}
+	return string(b), nil
+}
+
+func (t *createRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	spec, err := t.resolve(ctx, ec, args)
+	if err != nil {
+		return nil, err
+	}
+	run := &model.Run{
+		DisplayName:  spec.DisplayName,
+		ExperimentId: spec.ExperimentId,
+		Namespace:    spec.Namespace,
+		PipelineSpec: model.PipelineSpec{
+			PipelineId:        spec.PipelineId,
+			PipelineVersionId: spec.PipelineVersionId,
+			RuntimeConfig: model.RuntimeConfig{
+				Parameters: model.LargeText(spec.parametersJSON),
+			},
+		},
+	}
+	created, err := ec.ResourceManager.CreateRun(ctx, run)
+	if err != nil {
+		return nil, util.Wrap(err, "Failed to create the run")
+	}
+	result, err := JSONResult(map[string]interface{}{"run": toRunSummary(created)})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: created.UUID, DisplayName: created.DisplayName, Action: MutationCreated}
+	return result, nil
+}
+
+// The destructive run tools verify their target before the user is
+// asked to approve anything: the run is fetched and checked against what
+// the user described, and the verified facts ride along in the
+// confirmation request, so the user approves reality rather than the
+// model's recollection of it. A mismatch rejects the call outright.
+
+// matchRunDescription checks a fetched run against the session scope and
+// the description the user gave.
+func matchRunDescription(run *model.Run, expectedName, sessionNamespace string) error {
+	if sessionNamespace != "" && run.Namespace != "" && run.Namespace != sessionNamespace {
+		return util.NewPermissionDeniedError(errors.New("namespace mismatch"),
+			"Run %v belongs to namespace %v, not the session namespace %v", run.UUID, run.Namespace, sessionNamespace)
+	}
+	if expectedName != "" && run.DisplayName != expectedName {
+		return util.NewInvalidInputError(
+			"Run %v is named %q, not %q; refusing to proceed against a run that does not match the user's description",
+			run.UUID, run.DisplayName, expectedName)
+	}
+	return nil
+}
+
+// runFinished reports whether the run reached a terminal state. "ERROR"
+// covers the uppercased v1 error state, which has no v2 constant.
+func runFinished(state model.RuntimeState) bool {
+	switch model.RuntimeState(strings.ToUpper(string(state))) {
+	case model.RuntimeStateSucceeded, model.RuntimeStateFailed, model.RuntimeStateCanceled, model.RuntimeStateSkipped, "ERROR":
+		return true
+	}
+	return false
+}
+
+// verifyRunForMutation fetches the run and matches it against the
+// user's description.
+func verifyRunForMutation(ec *ExecContext, runId, expectedName string) (*model.Run, error) {
+	run, err := ec.ResourceManager.GetRun(runId)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to fetch run %v for verification", runId)
+	}
+	if err := matchRunDescription(run, expectedName, ec.Namespace); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// marshalVerification renders the verification summary attached to the
+// confirmation request.
+func marshalVerification(action string, run *model.Run) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{"action": action, "verified_run": toRunSummary(run)})
+	if err != nil {
+		return "", util.NewInternalServerError(err, "Failed to marshal the run verification summary")
+	}
+	return string(b), nil
+}
+
+type stopRunTool struct{}
+
+type stopRunArgs struct {
+	RunID               string `json:"run_id" required:"true" description:"ID of the run to terminate."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the run."`
+}
+
+func (t *stopRunTool) Name() string { return "stop_run" }
+
+func (t *stopRunTool) Description() string {
+	return "Terminate a running pipeline run. The run keeps its history but stops executing."
+}
+
+func (t *stopRunTool) InputSchema() map[string]interface{} { return SchemaFor(&stopRunArgs{}) }
+
+func (t *stopRunTool) Mutating() bool { return true }
+
+// Annotations: terminating an already-terminated run is a no-op.
+func (t *stopRunTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Idempotent: true}
+}
+
+// Validate fetches the run before the user is asked to confirm, so the
+// confirmation shows the run as it actually exists.
+func (t *stopRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a stopRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbTerminate}); err != nil {
+		return "", util.Wrap(err, "Failed to authorize terminating the run")
+	}
+	run, err := verifyRunForMutation(ec, a.RunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if runFinished(run.State) {
+		return "", util.NewFailedPreconditionError(errors.New("run already finished"),
+			"Run %v already finished with state %v; there is nothing to terminate", a.RunID, run.State)
+	}
+	return marshalVerification("terminate", run)
+}
+
+func (t *stopRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a stopRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbTerminate}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize terminating the run")
+	}
+	if err := ec.ResourceManager.TerminateRun(ctx, a.RunID); err != nil {
+		return nil, util.Wrapf(err, "Failed to terminate run %v", a.RunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"run_id": a.RunID, "terminated": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: a.RunID, Action: MutationStopped}
+	return result, nil
+}
+
+type retryRunTool struct{}
+
+type retryRunArgs struct {
+	RunID               string `json:"run_id" required:"true" description:"ID of the failed run to retry."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the run."`
+}
+
+func (t *retryRunTool) Name() string { return "retry_run" }
+
+func (t *retryRunTool) Description() string {
+	return "Retry a failed pipeline run from where it stopped. The run keeps its ID and history; completed steps are not re-executed."
+}
+
+func (t *retryRunTool) InputSchema() map[string]interface{} { return SchemaFor(&retryRunArgs{}) }
+
+func (t *retryRunTool) Mutating() bool { return true }
+
+// Validate fetches the run before the user is asked to confirm. Only a
+// run that already failed can be retried; a running run must be left
+// alone and a succeeded one has nothing to redo.
+func (t *retryRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a retryRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbRetry}); err != nil {
+		return "", util.Wrap(err, "Failed to authorize retrying the run")
+	}
+	run, err := verifyRunForMutation(ec, a.RunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if !runFinished(run.State) {
+		return "", util.NewFailedPreconditionError(errors.New("run still executing"),
+			"Run %v is still %v; wait for it to finish or terminate it first", a.RunID, run.State)
+	}
+	if model.RuntimeState(strings.ToUpper(string(run.State))) == model.RuntimeStateSucceeded {
+		return "", util.NewFailedPreconditionError(errors.New("run succeeded"),
+			"Run %v succeeded; there is nothing to retry", a.RunID)
+	}
+	return marshalVerification("retry", run)
+}
+
+func (t *retryRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a retryRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbRetry}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize retrying the run")
+	}
+	if err := ec.ResourceManager.RetryRun(ctx, a.RunID); err != nil {
+		return nil, util.Wrapf(err, "Failed to retry run %v", a.RunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"run_id": a.RunID, "retried": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: a.RunID, Action: MutationUpdated}
+	return result, nil
+}
+
+// maxRunAnnotationChars caps the analysis text annotate_run may append,
+// so a rambling model cannot bloat the run row.
+const maxRunAnnotationChars = 4000
+
+// runAnnotationHeader separates appended analyses from the original
+// description (and from each other).
+const runAnnotationHeader = "--- Assistant analysis ---"
+
+type annotateRunTool struct{}
+
+type annotateRunArgs struct {
+	RunID               string `json:"run_id" required:"true" description:"ID of the run to annotate."`
+	Analysis            string `json:"analysis" required:"true" description:"Analysis summary to preserve with the run, e.g. a diagnosis of why it failed."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the run."`
+}
+
+func (t *annotateRunTool) Name() string { return "annotate_run" }
+
+func (t *annotateRunTool) Description() string {
+	return "Append an analysis summary to a run's description, so the findings stay with the run for teammates who never open this chat."
+}
+
+func (t *annotateRunTool) InputSchema() map[string]interface{} { return SchemaFor(&annotateRunArgs{}) }
+
+func (t *annotateRunTool) Mutating() bool { return true }
+
+// decode parses and bounds the arguments shared by Validate and Execute.
+func (t *annotateRunTool) decode(args map[string]interface{}) (*annotateRunArgs, error) {
+	var a annotateRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(a.Analysis) == "" {
+		return nil, util.NewInvalidInputError("The analysis text must not be empty")
+	}
+	if len(a.Analysis) > maxRunAnnotationChars {
+		return nil, util.NewInvalidInputError("The analysis text is %v characters; the limit is %v", len(a.Analysis), maxRunAnnotationChars)
+	}
+	return &a, nil
+}
+
+// annotatedDescription appends the analysis under a header, keeping
+// whatever description the run already has.
+func annotatedDescription(existing, analysis string) string {
+	analysis = strings.TrimSpace(analysis)
+	if existing == "" {
+		return runAnnotationHeader + "\n" + analysis
+	}
+	return existing + "\n\n" + runAnnotationHeader + "\n" + analysis
+}
+
+// Validate fetches the run before the user is asked to confirm, so the
+// approval shows which run the analysis would be attached to.
+func (t *annotateRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	a, err := t.decode(args)
+	if err != nil {
+		return "", err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbUpdate}); err != nil {
+		return "", util.Wrap(err, "Failed to authorize updating the run")
+	}
+	run, err := verifyRunForMutation(ec, a.RunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	return marshalVerification("annotate", run)
+}
+
+func (t *annotateRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	a, err := t.decode(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbUpdate}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize updating the run")
+	}
+	run, err := ec.ResourceManager.GetRun(a.RunID)
+	if err != nil {
+		return nil, util.Wrapf(err, "Failed to get run %v", a.RunID)
+	}
+	if err := ec.ResourceManager.UpdateRunDescription(a.RunID, annotatedDescription(run.Description, a.Analysis)); err != nil {
+		return nil, util.Wrapf(err, "Failed to annotate run %v", a.RunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"run_id": a.RunID, "annotated": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: a.RunID, DisplayName: run.DisplayName, Action: MutationUpdated}
+	return result, nil
+}
+
+type deleteRunTool struct{}
+
+type deleteRunArgs struct {
+	RunID               string `json:"run_id" required:"true" description:"ID of the run to delete."`
+	ExpectedDisplayName string `json:"expected_display_name" description:"Display name the user referred to. Verification fails if it does not match the run."`
+}
+
+func (t *deleteRunTool) Name() string { return "delete_run" }
+
+func (t *deleteRunTool) Description() string {
+	return "Permanently delete a pipeline run and its stored details. This cannot be undone."
+}
+
+func (t *deleteRunTool) InputSchema() map[string]interface{} { return SchemaFor(&deleteRunArgs{}) }
+
+func (t *deleteRunTool) Mutating() bool { return true }
+
+// Annotations: deletion destroys the run's stored details for good.
+func (t *deleteRunTool) Annotations() provider.ToolAnnotations {
+	return provider.ToolAnnotations{Destructive: true, Idempotent: true}
+}
+
+// Validate fetches the run before the user is asked to confirm. Deleting
+// a run that is still executing is almost never what the user wants, so
+// the check insists the run has finished first.
+func (t *deleteRunTool) Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error) {
+	var a deleteRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbDelete}); err != nil {
+		return "", util.Wrap(err, "Failed to authorize deleting the run")
+	}
+	run, err := verifyRunForMutation(ec, a.RunID, a.ExpectedDisplayName)
+	if err != nil {
+		return "", err
+	}
+	if !runFinished(run.State) {
+		return "", util.NewFailedPreconditionError(errors.New("run still executing"),
+			"Run %v is still %v; stop it before deleting it", a.RunID, run.State)
+	}
+	return marshalVerification("delete", run)
+}
+
+func (t *deleteRunTool) Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error) {
+	var a deleteRunArgs
+	if err := DecodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if err := checkRunAccess(ctx, ec, a.RunID, &authorizationv1.ResourceAttributes{Verb: common.RbacResourceVerbDelete}); err != nil {
+		return nil, util.Wrap(err, "Failed to authorize deleting the run")
+	}
+	if err := ec.ResourceManager.DeleteRun(ctx, a.RunID); err != nil {
+		return nil, util.Wrapf(err, "Failed to delete run %v", a.RunID)
+	}
+	result, err := JSONResult(map[string]interface{}{"run_id": a.RunID, "deleted": true})
+	if err != nil {
+		return nil, err
+	}
+	result.Mutation = &Mutation{ResourceType: "run", ResourceID: a.RunID, Action: MutationDeleted}
+	return result, nil
+}
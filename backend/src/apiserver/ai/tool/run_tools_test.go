@@ -0,0 +1,93 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRunDescription_RejectsMismatches(t *testing.T) {
+	run := &model.Run{UUID: "run-1", DisplayName: "nightly-training", Namespace: "team-a"}
+
+	assert.NoError(t, matchRunDescription(run, "", ""))
+	assert.NoError(t, matchRunDescription(run, "nightly-training", "team-a"))
+
+	err := matchRunDescription(run, "weekly-eval", "team-a")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nightly-training")
+
+	err = matchRunDescription(run, "", "team-b")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "team-a")
+}
+
+func TestMatchRunDescription_IgnoresNamespaceWhenEitherSideIsUnset(t *testing.T) {
+	run := &model.Run{UUID: "run-1", DisplayName: "nightly-training"}
+	assert.NoError(t, matchRunDescription(run, "", "team-a"))
+
+	run.Namespace = "team-a"
+	assert.NoError(t, matchRunDescription(run, "", ""))
+}
+
+func TestRunFinished(t *testing.T) {
+	finished := []model.RuntimeState{
+		model.RuntimeStateSucceeded,
+		model.RuntimeStateFailed,
+		model.RuntimeStateCanceled,
+		model.RuntimeStateSkipped,
+		model.RuntimeStateSucceededV1,
+		model.RuntimeStateErrorV1,
+	}
+	for _, state := range finished {
+		assert.True(t, runFinished(state), "state %v", state)
+	}
+
+	running := []model.RuntimeState{
+		model.RuntimeStatePending,
+		model.RuntimeStateRunning,
+		model.RuntimeStateTerminatingV1,
+		"",
+	}
+	for _, state := range running {
+		assert.False(t, runFinished(state), "state %v", state)
+	}
+}
+
+func TestAnnotatedDescription(t *testing.T) {
+	assert.Equal(t,
+		"--- Assistant analysis ---\nthe run ran out of memory",
+		annotatedDescription("", "the run ran out of memory\n"))
+	assert.Equal(t,
+		"original description\n\n--- Assistant analysis ---\nthe run ran out of memory",
+		annotatedDescription("original description", "the run ran out of memory"))
+}
+
+func TestAnnotateRunArgs_Bounds(t *testing.T) {
+	tool := &annotateRunTool{}
+
+	_, err := tool.decode(map[string]interface{}{"run_id": "r1", "analysis": "  \n"})
+	assert.Error(t, err)
+
+	_, err = tool.decode(map[string]interface{}{"run_id": "r1", "analysis": strings.Repeat("x", maxRunAnnotationChars+1)})
+	assert.Error(t, err)
+
+	a, err := tool.decode(map[string]interface{}{"run_id": "r1", "analysis": "looks like a flaky node"})
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", a.RunID)
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tool defines the tools the AI assistant can execute against
+// Kubeflow Pipelines resources and the registry that exposes them to
+// chat sessions. Read-only tools are available in every mode; mutating
+// tools are limited to Agent mode and gated behind user confirmation.
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/resource"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Mode controls which tools a session may use.
+type Mode string
+
+const (
+	// ModeAsk only permits read-only tools.
+	ModeAsk Mode = "ask"
+	// ModeAgent additionally permits mutating tools, each gated behind a
+	// user confirmation.
+	ModeAgent Mode = "agent"
+)
+
+// ParseMode converts a request string into a Mode, defaulting to ModeAsk.
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", string(ModeAsk):
+		return ModeAsk, nil
+	case string(ModeAgent):
+		return ModeAgent, nil
+	default:
+		return "", util.NewInvalidInputError("Invalid assistant mode %q. Supported modes: ask, agent", s)
+	}
+}
+
+// ExecContext carries per-invocation state into tool executions.
+type ExecContext struct {
+	ResourceManager *resource.ResourceManager
+	// UserID is the authenticated identity of the chat user.
+	UserID string
+	// Namespace scopes the session in multi-user mode.
+	Namespace string
+	// SignedLogLink mints a time-limited log download URL for a run's pod
+	// and returns it with its expiry epoch second. Nil when the serving
+	// surface does not offer downloads.
+	SignedLogLink func(runId string, nodeId string) (string, int64)
+	// Warn surfaces a non-fatal execution warning (truncation, skipped
+	// resources) to the caller. Nil when the serving surface does not
+	// relay warnings.
+	Warn func(message string)
+	// Progress relays item-level progress of batch tools to the caller.
+	// Nil when the serving surface does not relay progress.
+	Progress func(update ProgressUpdate)
+	// Timeout bounds a single tool invocation; zero means no deadline.
+	Timeout time.Duration
+	// ApprovedValidationJSON is the dry-run preview the user approved,
+	// set before Execute when a confirmation round trip included one.
+	// Tools whose preview resolves to a concrete resource set execute
+	// exactly that set rather than re-resolving their criteria, which may
+	// drift while the confirmation is pending.
+	ApprovedValidationJSON string
+}
+
+// ProgressUpdate reports one processed item of a batch tool execution.
+type ProgressUpdate struct {
+	// Completed is the number of items processed so far, out of Total.
+	Completed int
+	Total     int
+	// Item identifies the item just processed, e.g. a run ID.
+	Item string
+	// Err carries the item's failure message, if it failed.
+	Err string
+}
+
+// Warnf reports a non-fatal warning through the Warn hook, if one is
+// set.
+func (ec *ExecContext) Warnf(format string, args ...interface{}) {
+	if ec.Warn != nil {
+		ec.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// ReportProgress relays a batch progress update through the Progress
+// hook, if one is set.
+func (ec *ExecContext) ReportProgress(update ProgressUpdate) {
+	if ec.Progress != nil {
+		ec.Progress(update)
+	}
+}
+
+// BoundContext derives the context a tool invocation runs under,
+// applying the per-tool timeout when one is configured. Not every
+// resource manager call honors a context, so page-scanning tools
+// additionally check the returned context between pages.
+func (ec *ExecContext) BoundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ec.Timeout > 0 {
+		return context.WithTimeout(ctx, ec.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Mutation actions reported by mutating tools.
+const (
+	MutationCreated = "created"
+	MutationUpdated = "updated"
+	MutationStopped = "stopped"
+	MutationDeleted = "deleted"
+)
+
+// Mutation describes the resource a mutating tool created or changed,
+// so the caller can update conversation state without re-reading the
+// backend. Re-reading right after a write is unreliable anyway: listings
+// are eventually consistent and may not surface the change yet.
+type Mutation struct {
+	// ResourceType is e.g. "run" or "pipeline_version".
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	DisplayName  string `json:"display_name,omitempty"`
+	// Action is one of the Mutation* constants.
+	Action string `json:"action"`
+}
+
+// Result is the outcome of a tool execution that is fed back to the
+// model.
+type Result struct {
+	// Content is the payload presented to the model, usually JSON.
+	Content string
+	// IsError marks results that describe a failure.
+	IsError bool
+	// Mutation is set by mutating tools on success to describe the
+	// affected resource.
+	Mutation *Mutation
+}
+
+// JSONResult marshals v into a Result. Marshaling failures are reported
+// as internal errors so they surface like any other tool failure.
+func JSONResult(v interface{}) (*Result, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to marshal the tool result")
+	}
+	return &Result{Content: string(b)}, nil
+}
+
+// Validator is implemented by mutating tools that can dry-run a call
+// before the user is asked to approve it. The returned JSON describes
+// what the call resolved to and is shown alongside the confirmation
+// request; a validation failure rejects the call without a confirmation
+// round trip, so users are not asked to approve calls that would fail
+// trivially.
+type Validator interface {
+	Validate(ctx context.Context, ec *ExecContext, args map[string]interface{}) (string, error)
+}
+
+// Tool is a single capability exposed to the model.
+type Tool interface {
+	Name() string
+	Description() string
+	// InputSchema returns the JSON schema for the tool arguments.
+	InputSchema() map[string]interface{}
+	// Mutating reports whether the tool changes cluster state. Mutating
+	// tools require Agent mode and user confirmation.
+	Mutating() bool
+	Execute(ctx context.Context, ec *ExecContext, args map[string]interface{}) (*Result, error)
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A large fraction of chat traffic is greetings and generic "what is a
+// pipeline?" questions that need no cluster data. Classifying those with
+// cheap keyword heuristics lets the turn skip tool definitions entirely,
+// which shrinks the prompt and removes the chance of a pointless tool
+// round trip. The classifier errs toward NOT trivial: a false negative
+// just sends the tools along as before, while a false positive would
+// leave the model unable to look anything up.
+
+// trivialMaxChars bounds trivial queries; long messages carry detail
+// that usually references concrete resources.
+const trivialMaxChars = 160
+
+var (
+	// greetingWords start messages that are social rather than technical.
+	greetingWords = map[string]bool{
+		"hi": true, "hello": true, "hey": true, "thanks": true, "thank": true,
+		"ok": true, "okay": true, "cool": true, "great": true, "bye": true, "goodbye": true,
+	}
+	// docQuestionPrefixes open questions answerable from general
+	// Kubeflow Pipelines knowledge.
+	docQuestionPrefixes = []string{
+		"what is", "what are", "what does", "what's the difference",
+		"how does", "how do i", "how can i", "can you explain", "explain", "define",
+	}
+	// specificityMarkers indicate the question is about the user's own
+	// resources or current state, which needs tools.
+	specificityMarkers = []string{
+		"my ", "our ", "this ", "these ", "that run", "status", "failed", "failing",
+		"error", "latest", "current", "today", "yesterday", "right now", "again", "stuck",
+	}
+	// resourceIdRegexp matches UUID-ish tokens, a strong sign the user
+	// refers to a concrete resource.
+	resourceIdRegexp = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}`)
+)
+
+// isTrivialQuery reports whether a user message can be answered without
+// any tools: a greeting or a pure documentation question that references
+// no concrete resources.
+func isTrivialQuery(message string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(message))
+	if normalized == "" || len(normalized) > trivialMaxChars {
+		return false
+	}
+	if resourceIdRegexp.MatchString(normalized) {
+		return false
+	}
+	for _, marker := range specificityMarkers {
+		if strings.Contains(normalized, marker) {
+			return false
+		}
+	}
+	words := strings.Fields(strings.Map(stripPunct, normalized))
+	if len(words) == 0 {
+		return false
+	}
+	if greetingWords[words[0]] && len(words) <= 4 {
+		return true
+	}
+	for _, prefix := range docQuestionPrefixes {
+		if strings.HasPrefix(normalized, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripPunct(r rune) rune {
+	switch r {
+	case '!', '?', '.', ',', ';', ':':
+		return ' '
+	}
+	return r
+}
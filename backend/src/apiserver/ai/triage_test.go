@@ -0,0 +1,44 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTrivialQuery(t *testing.T) {
+	tests := []struct {
+		message string
+		trivial bool
+	}{
+		{"hi", true},
+		{"Hello!", true},
+		{"thanks a lot", true},
+		{"What is a pipeline version?", true},
+		{"how do I schedule a recurring run", true},
+		{"explain the difference between archiving and deleting", true},
+		{"", false},
+		{"what is the status of my run?", false},
+		{"why did run 0f3b6a2e-9c41 fail", false},
+		{"hello, please delete all runs older than a week", false},
+		{"list my experiments", false},
+		{"how do I fix this failing run", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.trivial, isTrivialQuery(test.message), "message: %q", test.message)
+	}
+}
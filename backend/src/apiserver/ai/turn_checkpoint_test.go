@@ -0,0 +1,148 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointRecordingStore records the mid-turn marker of every saved
+// snapshot, so tests can see checkpoints that the turn-end save
+// overwrites.
+type checkpointRecordingStore struct {
+	*fakeSessionStore
+	checkpoints []int64
+}
+
+func (s *checkpointRecordingStore) SaveSession(session *model.AISession) error {
+	s.checkpoints = append(s.checkpoints, session.TurnCheckpointAtInSec)
+	return s.fakeSessionStore.SaveSession(session)
+}
+
+// checkpointModel requests one tool call on the opening invocation and
+// answers with text afterwards.
+type checkpointModel struct{}
+
+func (m *checkpointModel) Name() string { return "checkpoint-stub" }
+
+func (m *checkpointModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	if req.System == suggestionsSystemPrompt {
+		return nil
+	}
+	if len(req.Messages) == 1 {
+		return cb(provider.StreamEvent{Type: provider.StreamEventToolCall,
+			ToolCall: &provider.ToolCall{ID: "tc1", Name: "bench_result", ArgumentsJSON: "{}"}})
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "done"}})
+}
+
+func TestStreamChat_CheckpointsAtIterationBoundaries(t *testing.T) {
+	store := &checkpointRecordingStore{fakeSessionStore: newFakeSessionStore()}
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, maxMessages: 50}
+	m.UseStore(store)
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: &checkpointModel{}}
+	require.NoError(t, server.registry.Register(&benchResultTool{content: "payload"}))
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	err = server.StreamChat(context.Background(), session, "list my failed runs", func(Event) error { return nil })
+	require.NoError(t, err)
+
+	// The tool iteration checkpointed mid-turn; the turn-end snapshot is
+	// clean again, so a later restore sees a completed turn.
+	midTurn := 0
+	for _, checkpoint := range store.checkpoints {
+		if checkpoint != 0 {
+			midTurn++
+		}
+	}
+	assert.NotZero(t, midTurn)
+	require.NotEmpty(t, store.checkpoints)
+	assert.Zero(t, store.checkpoints[len(store.checkpoints)-1])
+	assert.Zero(t, store.rows[session.ID].TurnCheckpointAtInSec)
+}
+
+func TestSessionManager_RestoreSurfacesAnInterruptedTurn(t *testing.T) {
+	m := &SessionManager{
+		sessions:    map[string]*Session{},
+		userUsage:   map[string]provider.Usage{},
+		maxSessions: 10,
+		maxMessages: 50,
+	}
+	store := newFakeSessionStore()
+	m.UseStore(store)
+
+	session, err := m.GetOrCreate("", "alice", "team-a", "ask", "")
+	require.NoError(t, err)
+	session.AppendMessage(provider.Message{Role: provider.RoleUser, Content: "investigate the failure"})
+	session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: "looking", ToolCalls: []provider.ToolCall{{ID: "tc1", Name: "get_run"}}})
+	session.AppendMessage(provider.Message{Role: provider.RoleTool, Content: "run details", ToolCallID: "tc1"})
+	m.checkpoint(session)
+	require.NotZero(t, store.rows[session.ID].TurnCheckpointAtInSec)
+
+	// Drop the session from memory, as a crash mid-turn would.
+	delete(m.sessions, session.ID)
+
+	restored, err := m.Get(session.ID, "alice")
+	require.NoError(t, err)
+	assert.False(t, restored.interruptedTurn().IsZero())
+	// The resume view carries the marker, so the client can offer to
+	// continue; the checkpointed history is intact.
+	detail := restored.detail()
+	assert.NotZero(t, detail.InterruptedTurnAt)
+	require.Len(t, detail.Messages, 3)
+
+	// Completing a turn clears the notice and the next snapshot.
+	require.NoError(t, restored.beginTurn())
+	restored.endTurn()
+	m.persist(restored)
+	assert.True(t, restored.interruptedTurn().IsZero())
+	assert.Zero(t, store.rows[restored.ID].TurnCheckpointAtInSec)
+}
+
+func TestStreamChat_ReportsTheInterruptedTurnOnce(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, maxMessages: 50}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry(), model: &checkpointModel{}}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+	session.mu.Lock()
+	session.interruptedTurnAt = time.Now().Add(-time.Minute)
+	session.mu.Unlock()
+	// Seed history so the model's text branch answers immediately.
+	session.AppendMessage(provider.Message{Role: provider.RoleAssistant, Content: "earlier"})
+
+	var interruptions []*TurnInterruptedPayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeTurnInterrupted {
+			interruptions = append(interruptions, e.Payload.(*TurnInterruptedPayload))
+		}
+		return nil
+	}
+	require.NoError(t, server.StreamChat(context.Background(), session, "continue the investigation", emit))
+	require.Len(t, interruptions, 1)
+	assert.NotZero(t, interruptions[0].CheckpointedAt)
+
+	// The completed turn consumed the notice.
+	require.NoError(t, server.StreamChat(context.Background(), session, "and then?", emit))
+	assert.Len(t, interruptions, 1)
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// The model frequently mangles unit math when summarizing run details
+// (seconds to hours, bytes to GiB, millicores to cores). Tool results
+// therefore carry human-readable renderings alongside the raw values,
+// mirroring what annotateTimestamps does for epoch fields.
+
+// annotateUnits decodes a JSON tool result and adds human-readable
+// siblings for durations, byte sizes and Kubernetes resource
+// quantities. Non-JSON content is returned unchanged.
+func annotateUnits(content string) string {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+		return content
+	}
+	annotated := annotateUnitValues(decoded)
+	out, err := json.Marshal(annotated)
+	if err != nil {
+		return content
+	}
+	return string(out)
+}
+
+func annotateUnitValues(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		annotateObjectUnits(typed)
+		for key, child := range typed {
+			typed[key] = annotateUnitValues(child)
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = annotateUnitValues(child)
+		}
+		return typed
+	default:
+		return v
+	}
+}
+
+// annotateObjectUnits adds renderings for the unit-bearing fields of a
+// single JSON object.
+func annotateObjectUnits(obj map[string]interface{}) {
+	for key, child := range obj {
+		switch {
+		case strings.HasSuffix(key, "_bytes"):
+			if size, ok := child.(float64); ok && size >= 0 {
+				obj[key+"_human"] = formatBytes(int64(size))
+			}
+		case strings.HasSuffix(key, "_seconds"):
+			if seconds, ok := child.(float64); ok && seconds >= 0 {
+				obj[key+"_human"] = formatDuration(time.Duration(seconds) * time.Second)
+			}
+		case key == "cpu" || strings.HasSuffix(key, "_cpu"):
+			if quantity, ok := child.(string); ok {
+				if human, ok := formatCPUQuantity(quantity); ok {
+					obj[key+"_human"] = human
+				}
+			}
+		case key == "memory" || strings.HasSuffix(key, "_memory"):
+			if quantity, ok := child.(string); ok {
+				if human, ok := formatMemoryQuantity(quantity); ok {
+					obj[key+"_human"] = human
+				}
+			}
+		}
+	}
+	// Runs carry created_at/finished_at epochs; derive the wall-clock
+	// duration so the model does not subtract epochs itself.
+	createdAt, createdOk := obj["created_at"].(float64)
+	finishedAt, finishedOk := obj["finished_at"].(float64)
+	if createdOk && finishedOk && createdAt >= minPlausibleEpoch && finishedAt >= createdAt {
+		obj["duration_human"] = formatDuration(time.Duration(finishedAt-createdAt) * time.Second)
+	}
+}
+
+// formatDuration renders a duration as e.g. "2h 5m 30s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	var parts []string
+	if hours := int(d.Hours()); hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes := int(d.Minutes()) % 60; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds := int(d.Seconds()) % 60; seconds > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatBytes renders a byte count using binary units, e.g. "1.5 MiB".
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// formatCPUQuantity renders a Kubernetes CPU quantity such as "1500m"
+// as "1.5 cores".
+func formatCPUQuantity(s string) (string, bool) {
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return "", false
+	}
+	cores := float64(quantity.MilliValue()) / 1000
+	if cores == 1 {
+		return "1 core", true
+	}
+	return fmt.Sprintf("%g cores", cores), true
+}
+
+// formatMemoryQuantity renders a Kubernetes memory quantity such as
+// "2Gi" or "512000000" as a binary byte size.
+func formatMemoryQuantity(s string) (string, bool) {
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return "", false
+	}
+	return formatBytes(quantity.Value()), true
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateUnits_DurationFromRunTimestamps(t *testing.T) {
+	content := `{"run":{"created_at":1718822400,"finished_at":1718829930}}`
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(annotateUnits(content)), &decoded))
+	run := decoded["run"].(map[string]interface{})
+	assert.Equal(t, "2h 5m 30s", run["duration_human"])
+}
+
+func TestAnnotateUnits_BytesAndSeconds(t *testing.T) {
+	content := `{"size_bytes":1572864,"timeout_seconds":90}`
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(annotateUnits(content)), &decoded))
+	assert.Equal(t, "1.5 MiB", decoded["size_bytes_human"])
+	assert.Equal(t, "1m 30s", decoded["timeout_seconds_human"])
+	// Raw values stay in place.
+	assert.Equal(t, float64(1572864), decoded["size_bytes"])
+}
+
+func TestAnnotateUnits_ResourceQuantities(t *testing.T) {
+	content := `{"resources":{"cpu":"1500m","memory":"2Gi"}}`
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(annotateUnits(content)), &decoded))
+	resources := decoded["resources"].(map[string]interface{})
+	assert.Equal(t, "1.5 cores", resources["cpu_human"])
+	assert.Equal(t, "2.0 GiB", resources["memory_human"])
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "45s", formatDuration(45*time.Second))
+	assert.Equal(t, "2m", formatDuration(2*time.Minute))
+	assert.Equal(t, "1h 1s", formatDuration(time.Hour+time.Second))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.0 KiB", formatBytes(1024))
+	assert.Equal(t, "2.0 GiB", formatBytes(2<<30))
+}
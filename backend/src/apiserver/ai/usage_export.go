@@ -0,0 +1,177 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+)
+
+// UsageStore persists per-invocation token consumption for chargeback.
+// The database-backed implementation lives in the storage package.
+type UsageStore interface {
+	AddRecord(record *model.AIUsageRecord) error
+	ListRecords(query *model.AIUsageQuery) ([]*model.AIUsageRecord, error)
+}
+
+// usageRecordBuffer bounds the records awaiting their database write;
+// beyond it new records are dropped rather than stalling chat turns.
+const usageRecordBuffer = 256
+
+// usageRecorder hands usage records to the store off the turn's
+// critical path, the same arrangement the audit logger uses.
+type usageRecorder struct {
+	store   UsageStore
+	records chan *model.AIUsageRecord
+	pending sync.WaitGroup
+}
+
+func newUsageRecorder(store UsageStore) *usageRecorder {
+	r := &usageRecorder{store: store, records: make(chan *model.AIUsageRecord, usageRecordBuffer)}
+	go r.writeLoop()
+	return r
+}
+
+// record queues one usage record for persistence, filling in its
+// identity and timestamp. A full buffer drops the record with a log
+// entry.
+func (r *usageRecorder) record(record *model.AIUsageRecord) {
+	if record.UUID == "" {
+		record.UUID = uuid.NewString()
+	}
+	if record.CreatedAtInSec == 0 {
+		record.CreatedAtInSec = time.Now().Unix()
+	}
+	r.pending.Add(1)
+	select {
+	case r.records <- record:
+	default:
+		r.pending.Done()
+		glog.Warningf("The AI usage record buffer is full; dropping the record for user %v", record.UserID)
+	}
+}
+
+// flush blocks until every queued record reached the store.
+func (r *usageRecorder) flush() {
+	r.pending.Wait()
+}
+
+func (r *usageRecorder) writeLoop() {
+	for record := range r.records {
+		if err := r.store.AddRecord(record); err != nil {
+			glog.Errorf("Failed to persist AI usage record %v for user %v: %v", record.UUID, record.UserID, err)
+		}
+		r.pending.Done()
+	}
+}
+
+// UseUsageStore attaches the durable usage record store. Like the
+// session store it is wired once at startup, before serving begins.
+func (s *AIServer) UseUsageStore(store UsageStore) {
+	s.usage = newUsageRecorder(store)
+}
+
+// UsageExport handles GET /apis/v2beta1/ai/usage/export. It exports the
+// persisted usage records as CSV for chargeback pipelines, filtered by
+// the query parameters user_id, namespace, since/until (epoch seconds)
+// and limit. The endpoint is administrative; the caller needs the same
+// access that administering the assistant configuration takes.
+func (h *SSEHandler) UsageExport(w http.ResponseWriter, r *http.Request) {
+	setWriteDeadline(w, h.currentWriteTimeout())
+	userId, err := h.userIdentity(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := h.aiServer.authorizeConfigAdmin(r.Context(), userId); err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	recorder := h.aiServer.usage
+	if recorder == nil {
+		h.writeError(w, http.StatusNotImplemented, fmt.Errorf("usage export is not enabled on this server"))
+		return
+	}
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "csv":
+	case "parquet":
+		h.writeError(w, http.StatusNotImplemented, fmt.Errorf("parquet export is not implemented; use format=csv"))
+		return
+	default:
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("unknown format %q; supported formats: csv", format))
+		return
+	}
+	query, err := parseUsageQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	records, err := recorder.store.ListRecords(query)
+	if err != nil {
+		h.writeError(w, httpStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="ai-usage.csv"`)
+	writer := csv.NewWriter(w)
+	// A write failure mid-export is the client hanging up; there is no
+	// useful error to deliver at that point.
+	_ = writer.Write([]string{"time", "user_id", "namespace", "session_id", "model", "input_tokens", "output_tokens"})
+	for _, record := range records {
+		_ = writer.Write([]string{
+			strconv.FormatInt(record.CreatedAtInSec, 10),
+			record.UserID,
+			record.Namespace,
+			record.SessionUUID,
+			record.Model,
+			strconv.Itoa(record.InputTokens),
+			strconv.Itoa(record.OutputTokens),
+		})
+	}
+	writer.Flush()
+}
+
+// parseUsageQuery translates the endpoint's query parameters into a
+// store query.
+func parseUsageQuery(values url.Values) (*model.AIUsageQuery, error) {
+	query := &model.AIUsageQuery{
+		UserID:    values.Get("user_id"),
+		Namespace: values.Get("namespace"),
+	}
+	var err error
+	if query.SinceInSec, err = epochParam(values, "since"); err != nil {
+		return nil, err
+	}
+	if query.UntilInSec, err = epochParam(values, "until"); err != nil {
+		return nil, err
+	}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q; expected a positive integer", raw)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
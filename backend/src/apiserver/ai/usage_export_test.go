@@ -0,0 +1,136 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUsageStore collects added records and remembers the last query.
+type fakeUsageStore struct {
+	mu        sync.Mutex
+	records   []*model.AIUsageRecord
+	lastQuery *model.AIUsageQuery
+}
+
+func (f *fakeUsageStore) AddRecord(record *model.AIUsageRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = append(f.records, record)
+	return nil
+}
+
+func (f *fakeUsageStore) ListRecords(query *model.AIUsageQuery) ([]*model.AIUsageRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastQuery = query
+	return append([]*model.AIUsageRecord{}, f.records...), nil
+}
+
+func (f *fakeUsageStore) added() []*model.AIUsageRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.AIUsageRecord{}, f.records...)
+}
+
+func usageExportFixture(store UsageStore) (*AIServer, *SSEHandler) {
+	m := &SessionManager{sessions: map[string]*Session{}, maxSessions: 10, userUsage: map[string]provider.Usage{}}
+	server := &AIServer{sessionManager: m, registry: tool.NewRegistry()}
+	if store != nil {
+		server.UseUsageStore(store)
+	}
+	return server, NewSSEHandler(server, &config.Config{Limits: config.Limits{ChatRateLimit: 10}}, nil)
+}
+
+func TestRecordUsage_PersistsADurableRecord(t *testing.T) {
+	store := &fakeUsageStore{}
+	server, _ := usageExportFixture(store)
+	session, err := server.sessionManager.GetOrCreate("", "alice", "team-a", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var turnUsage provider.Usage
+	server.recordUsage(session, &turnUsage, provider.Usage{InputTokens: 120, OutputTokens: 40}, "small")
+	server.usage.flush()
+
+	records := store.added()
+	require.Len(t, records, 1)
+	assert.Equal(t, "alice", records[0].UserID)
+	assert.Equal(t, "team-a", records[0].Namespace)
+	assert.Equal(t, session.ID, records[0].SessionUUID)
+	assert.Equal(t, 120, records[0].InputTokens)
+	assert.Equal(t, 40, records[0].OutputTokens)
+	assert.Equal(t, "small", records[0].Model)
+	assert.NotEmpty(t, records[0].UUID)
+	assert.NotZero(t, records[0].CreatedAtInSec)
+}
+
+func TestUsageExport_WritesCSV(t *testing.T) {
+	store := &fakeUsageStore{records: []*model.AIUsageRecord{{
+		UUID:           "record-1",
+		UserID:         "alice",
+		Namespace:      "team-a",
+		SessionUUID:    "session-1",
+		InputTokens:    120,
+		OutputTokens:   40,
+		CreatedAtInSec: 100,
+	}}}
+	_, h := usageExportFixture(store)
+	w := httptest.NewRecorder()
+
+	h.UsageExport(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/usage/export?user_id=alice&since=50", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "time,user_id,namespace,session_id,model,input_tokens,output_tokens", lines[0])
+	assert.Equal(t, "100,alice,team-a,session-1,,120,40", lines[1])
+	require.NotNil(t, store.lastQuery)
+	assert.Equal(t, "alice", store.lastQuery.UserID)
+	assert.Equal(t, int64(50), store.lastQuery.SinceInSec)
+}
+
+func TestUsageExport_ReportsWhenDisabled(t *testing.T) {
+	_, h := usageExportFixture(nil)
+	w := httptest.NewRecorder()
+
+	h.UsageExport(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/usage/export", nil))
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestUsageExport_RejectsUnknownFormats(t *testing.T) {
+	_, h := usageExportFixture(&fakeUsageStore{})
+
+	w := httptest.NewRecorder()
+	h.UsageExport(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/usage/export?format=xlsx", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Parquet is acknowledged but not implemented yet.
+	w = httptest.NewRecorder()
+	h.UsageExport(w, httptest.NewRequest(http.MethodGet, "/apis/v2beta1/ai/usage/export?format=parquet", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/quota"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tokenizer"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usageStubModel streams a fixed text and reports fixed token usage.
+type usageStubModel struct {
+	text  string
+	usage provider.Usage
+}
+
+func (m *usageStubModel) Name() string { return "stub" }
+
+func (m *usageStubModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: m.text}}); err != nil {
+		return err
+	}
+	if err := cb(provider.StreamEvent{Type: provider.StreamEventUsage, Usage: &m.usage}); err != nil {
+		return err
+	}
+	return cb(provider.StreamEvent{Type: provider.StreamEventDone})
+}
+
+func TestAIServer_UsageIsAccumulatedAndStreamed(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, userUsage: map[string]provider.Usage{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &usageStubModel{text: "hello", usage: provider.Usage{InputTokens: 100, OutputTokens: 25}},
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var usage []*UsagePayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeUsage {
+			usage = append(usage, e.Payload.(*UsagePayload))
+		}
+		return nil
+	}
+	require.NoError(t, server.StreamChat(context.Background(), session, "first question", emit))
+	require.NoError(t, server.StreamChat(context.Background(), session, "second question", emit))
+
+	// One usage event per turn; the session totals accumulate across turns.
+	require.Len(t, usage, 2)
+	assert.Equal(t, 100, usage[0].InputTokens)
+	assert.Equal(t, 25, usage[0].OutputTokens)
+	assert.Equal(t, 100, usage[0].SessionInputTokens)
+	assert.Equal(t, 200, usage[1].SessionInputTokens)
+	assert.Equal(t, 50, usage[1].SessionOutputTokens)
+
+	report := m.UsageForUser("alice")
+	assert.Equal(t, 200, report.InputTokens)
+	assert.Equal(t, 50, report.OutputTokens)
+	require.Len(t, report.Sessions, 1)
+	assert.Equal(t, session.ID, report.Sessions[0].SessionID)
+	assert.Equal(t, 200, report.Sessions[0].InputTokens)
+}
+
+func TestAIServer_UsageIsEstimatedWhenTheProviderReportsNone(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, userUsage: map[string]provider.Usage{}, maxSessions: 10}
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &fallbackStubModel{name: "local", text: "hello there"},
+		tokenizer:      tokenizer.ForProvider("local"),
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var usage []*UsagePayload
+	emit := func(e Event) error {
+		if e.Type == EventTypeUsage {
+			usage = append(usage, e.Payload.(*UsagePayload))
+		}
+		return nil
+	}
+	require.NoError(t, server.StreamChat(context.Background(), session, "what runs failed today?", emit))
+
+	require.Len(t, usage, 1)
+	assert.Equal(t, server.tokenizer.CountText("hello there"), usage[0].OutputTokens)
+	assert.Greater(t, usage[0].InputTokens, 0)
+}
+
+func TestAIServer_QuotaExceededRejectsTheTurn(t *testing.T) {
+	m := &SessionManager{sessions: map[string]*Session{}, userUsage: map[string]provider.Usage{}, maxSessions: 10}
+	tracker := quota.NewTracker(quota.Limits{UserDailyTokens: 100})
+	server := &AIServer{
+		sessionManager: m,
+		registry:       tool.NewRegistry(),
+		model:          &usageStubModel{text: "hello", usage: provider.Usage{InputTokens: 90, OutputTokens: 20}},
+		quota:          tracker,
+	}
+	session, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	var events []Event
+	emit := func(e Event) error {
+		events = append(events, e)
+		return nil
+	}
+	// The first turn is under budget and pushes the user over; the second
+	// is rejected before reaching the model.
+	require.NoError(t, server.StreamChat(context.Background(), session, "first question", emit))
+	messagesAfterFirst := session.messageCount()
+	events = nil
+	require.NoError(t, server.StreamChat(context.Background(), session, "second question", emit))
+
+	require.Len(t, events, 2)
+	assert.Equal(t, EventTypeQuotaExceeded, events[0].Type)
+	payload := events[0].Payload.(*QuotaExceededPayload)
+	assert.Equal(t, quota.ScopeUser, payload.Scope)
+	assert.Equal(t, "alice", payload.Subject)
+	assert.Equal(t, 110, payload.SpentTokens)
+	assert.Equal(t, EventTypeDone, events[1].Type)
+	// The rejected message never entered the history.
+	assert.Equal(t, messagesAfterFirst, session.messageCount())
+}
+
+func TestUsageForUser_SurvivesSessionEviction(t *testing.T) {
+	m := &SessionManager{
+		sessions:      map[string]*Session{},
+		lostApprovals: map[string][]LostApproval{},
+		userUsage:     map[string]provider.Usage{},
+		maxSessions:   1,
+	}
+	first, err := m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+	first.addUsage(provider.Usage{InputTokens: 10, OutputTokens: 5})
+	m.recordUsage("alice", provider.Usage{InputTokens: 10, OutputTokens: 5})
+
+	// Creating a second session evicts the first over the session cap; the
+	// user total must not shrink with it.
+	_, err = m.GetOrCreate("", "alice", "ns", tool.ModeAsk, "")
+	require.NoError(t, err)
+
+	report := m.UsageForUser("alice")
+	assert.Equal(t, 10, report.InputTokens)
+	assert.Equal(t, 5, report.OutputTokens)
+	require.Len(t, report.Sessions, 1)
+	assert.Equal(t, 0, report.Sessions[0].InputTokens)
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// streamWatchdog wraps a ChatModel and aborts calls whose stream goes
+// quiet, so a hung upstream connection does not leave the SSE client
+// spinning on "Thinking..." forever.
+type streamWatchdog struct {
+	model        provider.ChatModel
+	stallTimeout time.Duration
+}
+
+func newStreamWatchdog(model provider.ChatModel, stallTimeout time.Duration) *streamWatchdog {
+	return &streamWatchdog{model: model, stallTimeout: stallTimeout}
+}
+
+// SetModel forwards model switches to the wrapped provider, so the
+// watchdog stays transparent to configuration reloads.
+func (w *streamWatchdog) SetModel(model string) {
+	if selector, ok := w.model.(provider.ModelSelector); ok {
+		selector.SetModel(model)
+	}
+}
+
+func (w *streamWatchdog) Name() string { return w.model.Name() }
+
+// StreamChat forwards to the wrapped model, resetting a stall timer on
+// every stream event. When the timer fires, the provider call is
+// canceled and a retryable provider_stalled error is returned.
+func (w *streamWatchdog) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stalled atomic.Bool
+	activity := make(chan struct{}, 1)
+	wrapped := func(event provider.StreamEvent) error {
+		if stalled.Load() {
+			// The turn already failed; drop late events from the canceled
+			// provider call.
+			return nil
+		}
+		select {
+		case activity <- struct{}{}:
+		default:
+		}
+		return cb(event)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- w.model.StreamChat(wctx, req, wrapped) }()
+
+	timer := time.NewTimer(w.stallTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-activity:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(w.stallTimeout)
+		case <-timer.C:
+			stalled.Store(true)
+			cancel()
+			<-done
+			glog.Errorf("Model provider %v stalled: no stream event for %v", w.model.Name(), w.stallTimeout)
+			return util.NewUnavailableServerError(errors.New("provider_stalled"),
+				"The model provider stalled: no stream event for %v. Please retry", w.stallTimeout)
+		}
+	}
+}
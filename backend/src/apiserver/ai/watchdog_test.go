@@ -0,0 +1,88 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// scriptedModel emits the given events with a fixed delay before each.
+type scriptedModel struct {
+	events []provider.StreamEvent
+	delay  time.Duration
+}
+
+func (m *scriptedModel) Name() string { return "scripted" }
+
+func (m *scriptedModel) StreamChat(ctx context.Context, req *provider.ChatRequest, cb provider.StreamCallback) error {
+	for _, event := range m.events {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := cb(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestStreamWatchdog_PassesThroughHealthyStreams(t *testing.T) {
+	model := &scriptedModel{
+		events: []provider.StreamEvent{
+			{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "a"}},
+			{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "b"}},
+			{Type: provider.StreamEventDone},
+		},
+		delay: 5 * time.Millisecond,
+	}
+	watchdog := newStreamWatchdog(model, 250*time.Millisecond)
+
+	var text string
+	err := watchdog.StreamChat(context.Background(), &provider.ChatRequest{}, func(event provider.StreamEvent) error {
+		if event.Type == provider.StreamEventTextDelta {
+			text += event.Delta.Text
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ab", text)
+}
+
+func TestStreamWatchdog_AbortsStalledStreams(t *testing.T) {
+	model := &scriptedModel{
+		events: []provider.StreamEvent{{Type: provider.StreamEventTextDelta, Delta: &provider.Delta{Text: "a"}}},
+		delay:  time.Hour,
+	}
+	watchdog := newStreamWatchdog(model, 20*time.Millisecond)
+
+	err := watchdog.StreamChat(context.Background(), &provider.ChatRequest{}, func(event provider.StreamEvent) error {
+		return nil
+	})
+	require.Error(t, err)
+	userErr, ok := err.(*util.UserError)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, userErr.ExternalStatusCode())
+	assert.Contains(t, err.Error(), "stalled")
+}
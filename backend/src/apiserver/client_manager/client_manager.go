@@ -104,6 +104,9 @@ type ClientManager struct {
 	resourceReferenceStore    storage.ResourceReferenceStoreInterface
 	dBStatusStore             storage.DBStatusStoreInterface
 	defaultExperimentStore    storage.DefaultExperimentStoreInterface
+	aiSessionStore            storage.AISessionStoreInterface
+	aiAuditStore              storage.AIAuditStoreInterface
+	aiUsageStore              storage.AIUsageStoreInterface
 	objectStore               storage.ObjectStore
 	execClient                util.ExecutionClient
 	swfClient                 client.SwfClientInterface
@@ -166,6 +169,18 @@ func (c *ClientManager) DefaultExperimentStore() storage.DefaultExperimentStoreI
 	return c.defaultExperimentStore
 }
 
+func (c *ClientManager) AISessionStore() storage.AISessionStoreInterface {
+	return c.aiSessionStore
+}
+
+func (c *ClientManager) AIAuditStore() storage.AIAuditStoreInterface {
+	return c.aiAuditStore
+}
+
+func (c *ClientManager) AIUsageStore() storage.AIUsageStoreInterface {
+	return c.aiUsageStore
+}
+
 func (c *ClientManager) ObjectStore() storage.ObjectStore {
 	return c.objectStore
 }
@@ -288,6 +303,9 @@ func (c *ClientManager) init(options *Options) error {
 	c.resourceReferenceStore = storage.NewResourceReferenceStore(db, pipelineStoreForRef)
 	c.dBStatusStore = storage.NewDBStatusStore(db)
 	c.defaultExperimentStore = storage.NewDefaultExperimentStore(db)
+	c.aiSessionStore = storage.NewAISessionStore(db)
+	c.aiAuditStore = storage.NewAIAuditStore(db)
+	c.aiUsageStore = storage.NewAIUsageStore(db)
 
 	// Use default value of client QPS (5) & burst (10) defined in
 	// k8s.io/client-go/rest/config.go#RESTClientFor
@@ -580,6 +598,9 @@ func autoMigrate(db *gorm.DB) error {
 		&model.RunMetric{},
 		&model.Task{},
 		&model.ResourceReference{},
+		&model.AISession{},
+		&model.AIAuditEvent{},
+		&model.AIUsageRecord{},
 	); err != nil {
 		return fmt.Errorf("AutoMigrate failed: %w", err)
 	}
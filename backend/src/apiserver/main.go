@@ -37,6 +37,11 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	apiv1beta1 "github.com/kubeflow/pipelines/backend/api/v1beta1/go_client"
 	apiv2beta1 "github.com/kubeflow/pipelines/backend/api/v2beta1/go_client"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/ai"
+	aiconfig "github.com/kubeflow/pipelines/backend/src/apiserver/ai/config"
+	aimcp "github.com/kubeflow/pipelines/backend/src/apiserver/ai/mcp"
+	aiprovider "github.com/kubeflow/pipelines/backend/src/apiserver/ai/provider"
+	airatelimit "github.com/kubeflow/pipelines/backend/src/apiserver/ai/ratelimit"
 	cm "github.com/kubeflow/pipelines/backend/src/apiserver/client_manager"
 	"github.com/kubeflow/pipelines/backend/src/apiserver/common"
 	"github.com/kubeflow/pipelines/backend/src/apiserver/config"
@@ -220,15 +225,63 @@ func main() {
 		}
 	}
 
+	// The AI assistant serves browsers over SSE from the HTTP proxy and
+	// SDK/CLI clients over gRPC from the RPC server; it is built once so
+	// both surfaces share sessions, identities and rate limits.
+	aiConfig, err := aiconfig.Load()
+	if err != nil {
+		glog.Fatalf("Failed to load the AI assistant configuration: %v", err)
+	}
+	var aiServer *ai.AIServer
+	var sseHandler *ai.SSEHandler
+	if aiConfig.Enabled {
+		chatModel, err := aiprovider.NewChatModelChain(
+			aiConfig.Provider.Name,
+			aiConfig.Provider.Fallbacks,
+			&aiprovider.Options{
+				Model:           aiConfig.Provider.Model,
+				APIKey:          aiConfig.Provider.APIKey,
+				Keys:            providerKeyRing(aiConfig.Provider.Keys),
+				BaseURL:         aiConfig.Provider.BaseURL,
+				CredentialsJSON: aiConfig.Provider.CredentialsJSON,
+				MaxRetries:      aiConfig.Provider.MaxRetries,
+			})
+		if err != nil {
+			glog.Fatalf("Failed to initialize the AI assistant chat model: %v", err)
+		}
+		aiServer = ai.NewAIServer(resourceManager, chatModel, aiConfig)
+		if aiConfig.Sessions.Persist {
+			aiServer.SessionManager().UseStore(resourceManager.AISessionStore())
+		}
+		aiServer.UseAuditLog(resourceManager.AIAuditStore())
+		aiServer.UseUsageStore(resourceManager.AIUsageStore())
+		aiServer.SessionManager().UseAttachments(ai.NewAttachmentStore(resourceManager.ObjectStore()))
+		if aiConfig.Sandbox {
+			glog.Warningf("The AI assistant runs in sandbox mode: tools operate on seeded fake data, not this cluster")
+			aiServer.UseSandbox(ai.NewSandboxResourceManager())
+		}
+		sseHandler = ai.NewSSEHandler(aiServer, aiConfig, airatelimit.FromConfig(aiConfig, airatelimit.ScopeChat))
+	}
+
 	wg.Add(1)
 	go reconcileSwfCrs(resourceManager, backgroundCtx, &wg)
-	go startRPCServer(resourceManager, tlsCfg)
+	go startRPCServer(resourceManager, tlsCfg, aiServer, sseHandler)
 	// This is blocking
-	startHTTPProxy(resourceManager, *usePipelinesKubernetesStorage, tlsCfg)
+	startHTTPProxy(resourceManager, *usePipelinesKubernetesStorage, tlsCfg, aiConfig, aiServer, sseHandler)
 	backgroundCancel()
 	wg.Wait()
 }
 
+// providerKeyRing converts the configured weighted API keys into the
+// provider package's key type.
+func providerKeyRing(keys []aiconfig.ProviderKey) []aiprovider.WeightedKey {
+	out := make([]aiprovider.WeightedKey, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, aiprovider.WeightedKey{Key: key.Key, Weight: key.Weight})
+	}
+	return out
+}
+
 func reconcileSwfCrs(resourceManager *resource.ResourceManager, ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	err := resourceManager.ReconcileSwfCrs(ctx)
@@ -246,7 +299,7 @@ func grpcCustomMatcher(key string) (string, bool) {
 	return strings.ToLower(key), false
 }
 
-func startRPCServer(resourceManager *resource.ResourceManager, tlsCfg *tls.Config) {
+func startRPCServer(resourceManager *resource.ResourceManager, tlsCfg *tls.Config, aiServer *ai.AIServer, sseHandler *ai.SSEHandler) {
 	var s *grpc.Server
 
 	grpc_prometheus.EnableHandlingTimeHistogram(
@@ -315,6 +368,9 @@ func startRPCServer(resourceManager *resource.ResourceManager, tlsCfg *tls.Confi
 	apiv2beta1.RegisterRecurringRunServiceServer(s, JobServer)
 	apiv2beta1.RegisterRunServiceServer(s, RunServer)
 	apiv2beta1.RegisterReportServiceServer(s, ReportServer)
+	if aiServer != nil {
+		apiv2beta1.RegisterAIServiceServer(s, ai.NewGRPCServer(aiServer, sseHandler))
+	}
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
@@ -324,7 +380,7 @@ func startRPCServer(resourceManager *resource.ResourceManager, tlsCfg *tls.Confi
 	glog.Info("RPC server started")
 }
 
-func startHTTPProxy(resourceManager *resource.ResourceManager, usePipelinesKubernetesStorage bool, tlsCfg *tls.Config) {
+func startHTTPProxy(resourceManager *resource.ResourceManager, usePipelinesKubernetesStorage bool, tlsCfg *tls.Config, aiConfig *aiconfig.Config, aiServer *ai.AIServer, sseHandler *ai.SSEHandler) {
 	glog.Info("Starting Http Proxy")
 
 	ctx := context.Background()
@@ -380,6 +436,51 @@ func startHTTPProxy(resourceManager *resource.ResourceManager, usePipelinesKuber
 		io.WriteString(w, `{"commit_sha":"`+common.GetStringConfigWithDefault("COMMIT_SHA", "unknown")+`", "tag_name":"`+common.GetStringConfigWithDefault("TAG_NAME", "unknown")+`", "multi_user":`+strconv.FormatBool(common.IsMultiUserMode())+`, "pipeline_store": "`+pipelineStore+`"}`)
 	})
 
+	// AI assistant endpoints stream server-sent events, which grpc-gateway
+	// cannot proxy, so they are registered as native HTTP handlers.
+	if aiConfig.Enabled {
+		topMux.HandleFunc("/apis/v2beta1/ai/chat", sseHandler.Chat).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/chat/approve", ai.WithCompression(sseHandler.Approve)).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/chat/cancel", ai.WithCompression(sseHandler.Cancel)).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/compare-runs", ai.WithCompression(sseHandler.CompareRuns)).Methods(http.MethodPost)
+		topMux.HandleFunc(ai.LogDownloadPath, ai.WithCompression(sseHandler.DownloadLog)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions", ai.WithCompression(sseHandler.ListSessions)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}", ai.WithCompression(sseHandler.GetSession)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}", ai.WithCompression(sseHandler.DeleteSession)).Methods(http.MethodDelete)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/restore", ai.WithCompression(sseHandler.RestoreSession)).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/export", ai.WithCompression(sseHandler.ExportSession)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/attachments", ai.WithCompression(sseHandler.UploadAttachment)).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/attachments", ai.WithCompression(sseHandler.ListAttachments)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/attachments/{hash}", ai.WithCompression(sseHandler.GetAttachment)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/debug/prompt", ai.WithCompression(sseHandler.DebugPrompt)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/pending", ai.WithCompression(sseHandler.PendingConfirmation)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/confirmations/{tool_call_id}/arguments", ai.WithCompression(sseHandler.ConfirmationArguments)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/sessions/{session_id}/mode", ai.WithCompression(sseHandler.SwitchMode)).Methods(http.MethodPost)
+		topMux.HandleFunc("/apis/v2beta1/ai/quick_actions", ai.WithCompression(sseHandler.QuickActions)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/usage", ai.WithCompression(sseHandler.Usage)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/usage/export", ai.WithCompression(sseHandler.UsageExport)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/audit", ai.WithCompression(sseHandler.AuditLog)).Methods(http.MethodGet)
+		mcpServer := aimcp.NewServer(resourceManager, aiServer.Registry(), sseHandler.UserIdentity, aiConfig,
+			airatelimit.FromConfig(aiConfig, airatelimit.ScopeMCP))
+		mcpServer.UseAuditLog(aiServer.AuditLog())
+		topMux.HandleFunc(aimcp.Path, mcpServer.Handle).Methods(http.MethodPost)
+
+		// Apply safe configuration changes (rate limits, session limits,
+		// model selection, API keys) without a restart.
+		aiConfigStore := aiconfig.NewStore(aiConfig)
+		bundleHandler := ai.NewConfigBundleHandler(aiServer, aiConfigStore, sseHandler.UserIdentity)
+		topMux.HandleFunc("/apis/v2beta1/ai/config/bundle", ai.WithCompression(bundleHandler.Export)).Methods(http.MethodGet)
+		topMux.HandleFunc("/apis/v2beta1/ai/config/bundle", ai.WithCompression(bundleHandler.Import)).Methods(http.MethodPost)
+		aiConfigStore.Subscribe(aiServer.ApplyConfig)
+		aiConfigStore.Subscribe(sseHandler.ApplyConfig)
+		aiConfigStore.Subscribe(mcpServer.ApplyConfig)
+		registerConfigChangeCallback(func() {
+			if err := aiConfigStore.Reload(); err != nil {
+				glog.Errorf("Failed to apply the AI assistant configuration change: %v", err)
+			}
+		})
+	}
+
 	// log streaming is provided via HTTP.
 	runLogServer := server.NewRunLogServer(resourceManager)
 	topMux.HandleFunc("/apis/v1alpha1/runs/{run_id}/nodes/{node_id}/log", runLogServer.ReadRunLogV1)
@@ -509,11 +610,35 @@ func initConfig() {
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		// Read in config again
 		viper.ReadInConfig()
+		notifyConfigChange()
 	})
 
 	proxy.InitializeConfigWithEnv()
 }
 
+// Callbacks run after the configuration file has been re-read, so
+// subsystems can apply settings that are safe to change at runtime.
+var (
+	configChangeMu        sync.Mutex
+	configChangeCallbacks []func()
+)
+
+func registerConfigChangeCallback(cb func()) {
+	configChangeMu.Lock()
+	defer configChangeMu.Unlock()
+	configChangeCallbacks = append(configChangeCallbacks, cb)
+}
+
+func notifyConfigChange() {
+	configChangeMu.Lock()
+	callbacks := make([]func(), len(configChangeCallbacks))
+	copy(callbacks, configChangeCallbacks)
+	configChangeMu.Unlock()
+	for _, cb := range callbacks {
+		cb()
+	}
+}
+
 // getPVCSpec retrieves the default workspace PersistentVolumeClaimSpec from the config.
 // This default is used for workspace PVCs when users do not specify their own configuration.
 func getPVCSpec() (*corev1.PersistentVolumeClaimSpec, error) {
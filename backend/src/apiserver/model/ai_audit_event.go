@@ -0,0 +1,69 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// AIAuditEvent is one persisted tool invocation of the AI assistant,
+// recorded for security review. Unlike the bounded in-memory action
+// trace a session keeps for its own introspection, audit events are
+// durable and queryable across sessions and users.
+type AIAuditEvent struct {
+	UUID        string `gorm:"column:UUID; not null; primaryKey; type:varchar(191);"`
+	UserID      string `gorm:"column:UserID; not null; index; type:varchar(191);"`
+	SessionUUID string `gorm:"column:SessionUUID; not null; index; type:varchar(191);"`
+	Namespace   string `gorm:"column:Namespace; not null;"`
+	Tool        string `gorm:"column:Tool; not null; type:varchar(191);"`
+	// Arguments is the raw JSON argument object the model supplied.
+	Arguments LargeText `gorm:"column:Arguments; default:null;"`
+	Mutating  bool      `gorm:"column:Mutating; not null;"`
+	// Outcome is the invocation's audited result, e.g. "succeeded",
+	// "failed", "denied_by_user" or "unauthorized".
+	Outcome string `gorm:"column:Outcome; not null; type:varchar(191);"`
+	// Detail carries the compacted failure or denial reason.
+	Detail string `gorm:"column:Detail; not null;"`
+	// ApprovedBy is the resolved display name of the user who confirmed
+	// a mutating call, empty when no confirmation was involved.
+	ApprovedBy string `gorm:"column:ApprovedBy; not null;"`
+	// DurationMs is the wall time from the call's arrival to its
+	// recorded outcome, including any confirmation wait.
+	DurationMs     int64 `gorm:"column:DurationMs; not null;"`
+	CreatedAtInSec int64 `gorm:"column:CreatedAtInSec; not null; index;"`
+}
+
+// TableName overrides GORM's table name inference.
+func (AIAuditEvent) TableName() string {
+	return "ai_audit_events"
+}
+
+func (e AIAuditEvent) PrimaryKeyColumnName() string {
+	return "UUID"
+}
+
+// AIAuditQuery filters an audit event listing. Zero values leave the
+// corresponding dimension unfiltered.
+type AIAuditQuery struct {
+	UserID      string
+	SessionUUID string
+	Tool        string
+	Outcome     string
+	// MutatingOnly restricts the listing to mutating invocations.
+	MutatingOnly bool
+	// SinceInSec and UntilInSec bound CreatedAtInSec inclusively below
+	// and exclusively above.
+	SinceInSec int64
+	UntilInSec int64
+	// Limit caps the number of returned events; the store applies its
+	// default when zero and its maximum beyond that.
+	Limit int
+}
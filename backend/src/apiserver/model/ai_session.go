@@ -0,0 +1,52 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// AISession is the persisted snapshot of one AI assistant chat session,
+// so conversations survive an apiserver restart and can be resumed on
+// another replica. The conversation history and the pending confirmation
+// are stored as the JSON the assistant already exchanges with clients.
+type AISession struct {
+	UUID           string `gorm:"column:UUID; not null; primaryKey; type:varchar(191);"`
+	UserID         string `gorm:"column:UserID; not null; index; type:varchar(191);"`
+	Namespace      string `gorm:"column:Namespace; not null;"`
+	Mode           string `gorm:"column:Mode; not null;"`
+	Timezone       string `gorm:"column:Timezone; not null;"`
+	CreatedAtInSec int64  `gorm:"column:CreatedAtInSec; not null;"`
+	UpdatedAtInSec int64  `gorm:"column:UpdatedAtInSec; not null;"`
+	// DeletedAtInSec marks a soft-deleted session awaiting purge; zero
+	// means the session is live. Soft-deleted sessions stay restorable
+	// by their owner until the configured retention elapses.
+	DeletedAtInSec int64 `gorm:"column:DeletedAtInSec; not null; default:0;"`
+	// Messages is the serialized conversation history.
+	Messages LargeText `gorm:"column:Messages; default:null;"`
+	// PendingConfirmation is the serialized confirmation payload the
+	// session is blocked on, or empty when none is pending.
+	PendingConfirmation LargeText `gorm:"column:PendingConfirmation; default:null;"`
+	// TurnCheckpointAtInSec is set on snapshots written mid-turn, at
+	// tool loop iteration boundaries. A non-zero value on a restored
+	// session means the process died before the turn completed; the
+	// history ends at the last completed iteration.
+	TurnCheckpointAtInSec int64 `gorm:"column:TurnCheckpointAtInSec; not null; default:0;"`
+}
+
+// TableName overrides GORM's table name inference.
+func (AISession) TableName() string {
+	return "ai_sessions"
+}
+
+func (s AISession) PrimaryKeyColumnName() string {
+	return "UUID"
+}
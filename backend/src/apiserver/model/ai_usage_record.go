@@ -0,0 +1,55 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// AIUsageRecord is one persisted model invocation's token consumption,
+// recorded for chargeback. Unlike the in-memory usage report and quota
+// counters, which reset with the apiserver, usage records survive
+// restarts and can be exported over arbitrary time ranges.
+type AIUsageRecord struct {
+	UUID         string `gorm:"column:UUID; not null; primaryKey; type:varchar(191);"`
+	UserID       string `gorm:"column:UserID; not null; index; type:varchar(191);"`
+	Namespace    string `gorm:"column:Namespace; not null; index; type:varchar(191);"`
+	SessionUUID  string `gorm:"column:SessionUUID; not null; type:varchar(191);"`
+	InputTokens  int    `gorm:"column:InputTokens; not null;"`
+	OutputTokens int    `gorm:"column:OutputTokens; not null;"`
+	// Model is the model identifier the invocation was billed against,
+	// which a degraded turn can change mid-session.
+	Model          string `gorm:"column:Model; not null;"`
+	CreatedAtInSec int64  `gorm:"column:CreatedAtInSec; not null; index;"`
+}
+
+// TableName overrides GORM's table name inference.
+func (AIUsageRecord) TableName() string {
+	return "ai_usage_records"
+}
+
+func (r AIUsageRecord) PrimaryKeyColumnName() string {
+	return "UUID"
+}
+
+// AIUsageQuery filters a usage record listing. Zero values leave the
+// corresponding dimension unfiltered.
+type AIUsageQuery struct {
+	UserID    string
+	Namespace string
+	// SinceInSec and UntilInSec bound CreatedAtInSec inclusively below
+	// and exclusively above.
+	SinceInSec int64
+	UntilInSec int64
+	// Limit caps the number of returned records; the store applies its
+	// default when zero and its maximum beyond that.
+	Limit int
+}
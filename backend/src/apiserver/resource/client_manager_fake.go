@@ -34,6 +34,9 @@ type FakeClientManager struct {
 	resourceReferenceStore        storage.ResourceReferenceStoreInterface
 	dBStatusStore                 storage.DBStatusStoreInterface
 	defaultExperimentStore        storage.DefaultExperimentStoreInterface
+	aiSessionStore                storage.AISessionStoreInterface
+	aiAuditStore                  storage.AIAuditStoreInterface
+	aiUsageStore                  storage.AIUsageStoreInterface
 	objectStore                   storage.ObjectStore
 	ExecClientFake                *client.FakeExecClient
 	swfClientFake                 *client.FakeSwfClient
@@ -75,6 +78,9 @@ func NewFakeClientManager(time util.TimeInterface, uuid util.UUIDGeneratorInterf
 		resourceReferenceStore:        storage.NewResourceReferenceStore(db, nil),
 		dBStatusStore:                 storage.NewDBStatusStore(db),
 		defaultExperimentStore:        storage.NewDefaultExperimentStore(db),
+		aiSessionStore:                storage.NewAISessionStore(db),
+		aiAuditStore:                  storage.NewAIAuditStore(db),
+		aiUsageStore:                  storage.NewAIUsageStore(db),
 		objectStore:                   newFakeObjectStore(),
 		swfClientFake:                 client.NewFakeSwfClient(),
 		k8sCoreClientFake:             client.NewFakeKuberneteCoresClient(),
@@ -162,6 +168,18 @@ func (f *FakeClientManager) DBStatusStore() storage.DBStatusStoreInterface {
 	return f.dBStatusStore
 }
 
+func (f *FakeClientManager) AISessionStore() storage.AISessionStoreInterface {
+	return f.aiSessionStore
+}
+
+func (f *FakeClientManager) AIAuditStore() storage.AIAuditStoreInterface {
+	return f.aiAuditStore
+}
+
+func (f *FakeClientManager) AIUsageStore() storage.AIUsageStoreInterface {
+	return f.aiUsageStore
+}
+
 func (f *FakeClientManager) DefaultExperimentStore() storage.DefaultExperimentStoreInterface {
 	return f.defaultExperimentStore
 }
@@ -104,6 +104,9 @@ type ClientManagerInterface interface {
 	ResourceReferenceStore() storage.ResourceReferenceStoreInterface
 	DBStatusStore() storage.DBStatusStoreInterface
 	DefaultExperimentStore() storage.DefaultExperimentStoreInterface
+	AISessionStore() storage.AISessionStoreInterface
+	AIAuditStore() storage.AIAuditStoreInterface
+	AIUsageStore() storage.AIUsageStoreInterface
 	ObjectStore() storage.ObjectStore
 	ExecClient() util.ExecutionClient
 	SwfClient() client.SwfClientInterface
@@ -132,6 +135,9 @@ type ResourceManager struct {
 	resourceReferenceStore    storage.ResourceReferenceStoreInterface
 	dBStatusStore             storage.DBStatusStoreInterface
 	defaultExperimentStore    storage.DefaultExperimentStoreInterface
+	aiSessionStore            storage.AISessionStoreInterface
+	aiAuditStore              storage.AIAuditStoreInterface
+	aiUsageStore              storage.AIUsageStoreInterface
 	objectStore               storage.ObjectStore
 	execClient                util.ExecutionClient
 	swfClient                 client.SwfClientInterface
@@ -155,6 +161,9 @@ func NewResourceManager(clientManager ClientManagerInterface, options *ResourceM
 		resourceReferenceStore:    clientManager.ResourceReferenceStore(),
 		dBStatusStore:             clientManager.DBStatusStore(),
 		defaultExperimentStore:    clientManager.DefaultExperimentStore(),
+		aiSessionStore:            clientManager.AISessionStore(),
+		aiAuditStore:              clientManager.AIAuditStore(),
+		aiUsageStore:              clientManager.AIUsageStore(),
 		objectStore:               clientManager.ObjectStore(),
 		execClient:                clientManager.ExecClient(),
 		swfClient:                 clientManager.SwfClient(),
@@ -737,6 +746,17 @@ func (r *ResourceManager) ArchiveRun(runId string) error {
 	return nil
 }
 
+// Replaces the description of a run with a given id.
+func (r *ResourceManager) UpdateRunDescription(runId string, description string) error {
+	if _, err := r.GetRun(runId); err != nil {
+		return util.Wrapf(err, "Failed to update the description of run %v as it failed to be retrieved", runId)
+	}
+	if err := r.runStore.UpdateRunDescription(runId, description); err != nil {
+		return util.Wrapf(err, "Failed to update the description of run %v", runId)
+	}
+	return nil
+}
+
 // Un-archives a run with a given id.
 func (r *ResourceManager) UnarchiveRun(runId string) error {
 	run, err := r.GetRun(runId)
@@ -1001,6 +1021,79 @@ func (r *ResourceManager) ReadLog(ctx context.Context, runId string, nodeId stri
 	return nil
 }
 
+// ReadContainerLog fetches the log of one container of a run's pod,
+// optionally limited to the last tailLines lines. Unlike ReadLog it only
+// falls back to the log archive for the main container, since the
+// archive does not keep sidecar output.
+func (r *ResourceManager) ReadContainerLog(ctx context.Context, runId string, nodeId string, container string, tailLines int64, dst io.Writer) error {
+	run, err := r.GetRun(runId)
+	if err != nil {
+		return util.NewBadRequestError(err, "Failed to read logs for run %v due to run fetching error", runId)
+	}
+	namespace, err := r.getNamespaceFromRunId(runId)
+	if err != nil {
+		return util.NewBadRequestError(err, "Failed to read logs for run %v due to namespace fetching error", runId)
+	}
+	if container == "" {
+		container = "main"
+	}
+	logOptions := corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: false,
+	}
+	if tailLines > 0 {
+		logOptions.TailLines = &tailLines
+	}
+	req := r.k8sCoreClient.PodClient(namespace).GetLogs(nodeId, &logOptions)
+	podLogs, err := req.Stream(ctx)
+	if err != nil {
+		if container == "main" && r.logArchive != nil {
+			if archiveErr := r.readRunLogFromArchive(string(run.WorkflowRuntimeManifest), nodeId, dst); archiveErr == nil {
+				return nil
+			}
+		}
+		return util.NewBadRequestError(err, "Failed to read logs of container %v in pod %v for run %v", container, nodeId, runId)
+	}
+	defer podLogs.Close()
+	if _, err := io.Copy(dst, podLogs); err != nil && !errors.Is(err, io.EOF) {
+		return util.NewBadRequestError(err, "Failed to read logs of container %v in pod %v for run %v", container, nodeId, runId)
+	}
+	return nil
+}
+
+// DescribeRunPod fetches the current status of one of a run's pods and
+// the Kubernetes events recorded for it. The pod may already be garbage
+// collected while its events linger (or vice versa), so a nil pod with
+// events — or a pod with no events — is a normal outcome; an error is
+// returned only when neither could be read.
+func (r *ResourceManager) DescribeRunPod(ctx context.Context, runId string, podName string) (*corev1.Pod, []corev1.Event, error) {
+	namespace, err := r.getNamespaceFromRunId(runId)
+	if err != nil {
+		return nil, nil, util.NewBadRequestError(err, "Failed to describe pod %v for run %v due to namespace fetching error", podName, runId)
+	}
+	pod, podErr := r.k8sCoreClient.PodClient(namespace).Get(ctx, podName, v1.GetOptions{})
+	if podErr != nil {
+		if !apierrors.IsNotFound(podErr) {
+			return nil, nil, util.NewInternalServerError(podErr, "Failed to get pod %v for run %v", podName, runId)
+		}
+		pod = nil
+	}
+	eventList, eventErr := r.k8sCoreClient.GetClientSet().CoreV1().Events(namespace).List(ctx, v1.ListOptions{
+		FieldSelector: "involvedObject.name=" + podName,
+	})
+	if eventErr != nil {
+		if pod == nil {
+			return nil, nil, util.NewInternalServerError(eventErr, "Failed to list the events of pod %v for run %v", podName, runId)
+		}
+		glog.Warningf("Failed to list the events of pod %v for run %v: %v", podName, runId, eventErr)
+		return pod, nil, nil
+	}
+	if pod == nil && len(eventList.Items) == 0 {
+		return nil, nil, util.NewResourceNotFoundError("Pod", podName)
+	}
+	return pod, eventList.Items, nil
+}
+
 // Fetches execution logs from a pod.
 func (r *ResourceManager) readRunLogFromPod(ctx context.Context, namespace string, nodeId string, follow bool, dst io.Writer) error {
 	logOptions := corev1.PodLogOptions{
@@ -1701,6 +1794,23 @@ func (r *ResourceManager) ObjectStore() storage.ObjectStore {
 	return r.objectStore
 }
 
+// AISessionStore returns the persistence backend for AI assistant chat sessions.
+func (r *ResourceManager) AISessionStore() storage.AISessionStoreInterface {
+	return r.aiSessionStore
+}
+
+// AIAuditStore returns the persistence backend for AI assistant tool
+// invocation audit events.
+func (r *ResourceManager) AIAuditStore() storage.AIAuditStoreInterface {
+	return r.aiAuditStore
+}
+
+// AIUsageStore returns the persistence backend for AI assistant usage
+// records.
+func (r *ResourceManager) AIUsageStore() storage.AIUsageStoreInterface {
+	return r.aiUsageStore
+}
+
 // Fetches the default experiment id.
 func (r *ResourceManager) GetDefaultExperimentId() (string, error) {
 	return r.defaultExperimentStore.GetDefaultExperimentId()
@@ -0,0 +1,126 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Listing bounds: the default applies when a query names no limit, the
+// maximum caps what a client may request.
+const (
+	defaultAIAuditPageSize = 100
+	maxAIAuditPageSize     = 1000
+)
+
+var aiAuditColumns = []string{
+	"UUID", "UserID", "SessionUUID", "Namespace", "Tool", "Arguments",
+	"Mutating", "Outcome", "Detail", "ApprovedBy", "DurationMs", "CreatedAtInSec",
+}
+
+type AIAuditStoreInterface interface {
+	AddEvent(event *model.AIAuditEvent) error
+	ListEvents(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error)
+}
+
+// AIAuditStore persists the AI assistant's tool invocations. Events are
+// append-only; nothing updates or deletes them through this store.
+type AIAuditStore struct {
+	db *DB
+}
+
+// NewAIAuditStore creates the store on the shared database.
+func NewAIAuditStore(db *DB) *AIAuditStore {
+	return &AIAuditStore{db: db}
+}
+
+// AddEvent appends one audit event.
+func (s *AIAuditStore) AddEvent(event *model.AIAuditEvent) error {
+	insertSql, args, err := sq.
+		Insert("ai_audit_events").
+		Columns(aiAuditColumns...).
+		Values(event.UUID, event.UserID, event.SessionUUID, event.Namespace, event.Tool, event.Arguments,
+			event.Mutating, event.Outcome, event.Detail, event.ApprovedBy, event.DurationMs, event.CreatedAtInSec).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to insert AI audit event %v", event.UUID)
+	}
+	if _, err := s.db.Exec(insertSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to insert AI audit event %v", event.UUID)
+	}
+	return nil
+}
+
+// ListEvents returns matching events newest-first.
+func (s *AIAuditStore) ListEvents(query *model.AIAuditQuery) ([]*model.AIAuditEvent, error) {
+	builder := sq.
+		Select(aiAuditColumns...).
+		From("ai_audit_events")
+	if query.UserID != "" {
+		builder = builder.Where(sq.Eq{"UserID": query.UserID})
+	}
+	if query.SessionUUID != "" {
+		builder = builder.Where(sq.Eq{"SessionUUID": query.SessionUUID})
+	}
+	if query.Tool != "" {
+		builder = builder.Where(sq.Eq{"Tool": query.Tool})
+	}
+	if query.Outcome != "" {
+		builder = builder.Where(sq.Eq{"Outcome": query.Outcome})
+	}
+	if query.MutatingOnly {
+		builder = builder.Where(sq.Eq{"Mutating": true})
+	}
+	if query.SinceInSec > 0 {
+		builder = builder.Where(sq.GtOrEq{"CreatedAtInSec": query.SinceInSec})
+	}
+	if query.UntilInSec > 0 {
+		builder = builder.Where(sq.Lt{"CreatedAtInSec": query.UntilInSec})
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAIAuditPageSize
+	}
+	if limit > maxAIAuditPageSize {
+		limit = maxAIAuditPageSize
+	}
+	querySql, args, err := builder.
+		OrderBy("CreatedAtInSec DESC", "UUID DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to create query to list AI audit events")
+	}
+	rows, err := s.db.Query(querySql, args...)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI audit events")
+	}
+	defer rows.Close()
+	events := []*model.AIAuditEvent{}
+	for rows.Next() {
+		var event model.AIAuditEvent
+		if err := rows.Scan(&event.UUID, &event.UserID, &event.SessionUUID, &event.Namespace, &event.Tool, &event.Arguments,
+			&event.Mutating, &event.Outcome, &event.Detail, &event.ApprovedBy, &event.DurationMs, &event.CreatedAtInSec); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to scan an AI audit event")
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI audit events")
+	}
+	return events, nil
+}
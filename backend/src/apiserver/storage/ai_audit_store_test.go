@@ -0,0 +1,138 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAIAuditEvent(uuid string, createdAt int64) *model.AIAuditEvent {
+	return &model.AIAuditEvent{
+		UUID:           uuid,
+		UserID:         "user@example.com",
+		SessionUUID:    "session-1",
+		Namespace:      "team-a",
+		Tool:           "list_runs",
+		Arguments:      `{"experiment_id":"exp-1"}`,
+		Outcome:        "succeeded",
+		DurationMs:     42,
+		CreatedAtInSec: createdAt,
+	}
+}
+
+func TestAddAndListAIAuditEvents(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIAuditStore(db)
+
+	event := testAIAuditEvent("event-1", 100)
+	require.Nil(t, store.AddEvent(event))
+
+	events, err := store.ListEvents(&model.AIAuditQuery{})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event, events[0])
+}
+
+func TestListAIAuditEvents_NewestFirst(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIAuditStore(db)
+
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-1", 100)))
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-2", 300)))
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-3", 200)))
+
+	events, err := store.ListEvents(&model.AIAuditQuery{})
+	assert.Nil(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "event-2", events[0].UUID)
+	assert.Equal(t, "event-3", events[1].UUID)
+	assert.Equal(t, "event-1", events[2].UUID)
+}
+
+func TestListAIAuditEvents_Filters(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIAuditStore(db)
+
+	mine := testAIAuditEvent("event-1", 100)
+	require.Nil(t, store.AddEvent(mine))
+	theirs := testAIAuditEvent("event-2", 200)
+	theirs.UserID = "other@example.com"
+	theirs.Tool = "create_run"
+	theirs.Mutating = true
+	theirs.Outcome = "failed"
+	require.Nil(t, store.AddEvent(theirs))
+
+	events, err := store.ListEvents(&model.AIAuditQuery{UserID: "user@example.com"})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-1", events[0].UUID)
+
+	events, err = store.ListEvents(&model.AIAuditQuery{Tool: "create_run"})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-2", events[0].UUID)
+
+	events, err = store.ListEvents(&model.AIAuditQuery{MutatingOnly: true})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-2", events[0].UUID)
+
+	events, err = store.ListEvents(&model.AIAuditQuery{Outcome: "failed"})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-2", events[0].UUID)
+}
+
+func TestListAIAuditEvents_TimeWindowIsInclusiveExclusive(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIAuditStore(db)
+
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-1", 100)))
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-2", 200)))
+	require.Nil(t, store.AddEvent(testAIAuditEvent("event-3", 300)))
+
+	events, err := store.ListEvents(&model.AIAuditQuery{SinceInSec: 200, UntilInSec: 300})
+	assert.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-2", events[0].UUID)
+}
+
+func TestListAIAuditEvents_LimitIsCapped(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIAuditStore(db)
+
+	for i := 0; i < 5; i++ {
+		require.Nil(t, store.AddEvent(testAIAuditEvent(fmt.Sprintf("event-%v", i), int64(100+i))))
+	}
+
+	events, err := store.ListEvents(&model.AIAuditQuery{Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+
+	// An oversized limit is clamped to the maximum rather than rejected.
+	events, err = store.ListEvents(&model.AIAuditQuery{Limit: maxAIAuditPageSize + 1})
+	assert.Nil(t, err)
+	assert.Len(t, events, 5)
+}
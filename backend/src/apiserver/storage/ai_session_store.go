@@ -0,0 +1,233 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+var aiSessionColumns = []string{
+	"UUID", "UserID", "Namespace", "Mode", "Timezone",
+	"CreatedAtInSec", "UpdatedAtInSec", "DeletedAtInSec", "Messages", "PendingConfirmation",
+}
+
+type AISessionStoreInterface interface {
+	SaveSession(session *model.AISession) error
+	GetSession(sessionId string) (*model.AISession, error)
+	ListSessions(userId string) ([]*model.AISession, error)
+	ListDeletedSessions(userId string) ([]*model.AISession, error)
+	DeleteSession(sessionId string) error
+	SoftDeleteSession(sessionId string, deletedAtInSec int64) error
+	RestoreSession(sessionId string) error
+	PurgeDeletedSessions(deletedBeforeInSec int64) error
+}
+
+// AISessionStore persists AI assistant chat sessions. Sessions are
+// written as whole snapshots: the assistant serializes each turn, so a
+// row is only ever replaced, never partially updated.
+type AISessionStore struct {
+	db *DB
+}
+
+// NewAISessionStore creates the store on the shared database.
+func NewAISessionStore(db *DB) *AISessionStore {
+	return &AISessionStore{db: db}
+}
+
+// SaveSession inserts the session snapshot or replaces an existing one.
+func (s *AISessionStore) SaveSession(session *model.AISession) error {
+	updateSql, updateArgs, err := sq.
+		Update("ai_sessions").
+		SetMap(sq.Eq{
+			"UserID":         session.UserID,
+			"Namespace":      session.Namespace,
+			"Mode":           session.Mode,
+			"Timezone":       session.Timezone,
+			"CreatedAtInSec": session.CreatedAtInSec,
+			"UpdatedAtInSec": session.UpdatedAtInSec,
+			// A fresh snapshot means the session is live; saving clears any
+			// soft-delete marker left by a concurrent cleanup.
+			"DeletedAtInSec":      0,
+			"Messages":            session.Messages,
+			"PendingConfirmation": session.PendingConfirmation,
+		}).
+		Where(sq.Eq{"UUID": session.UUID}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to update AI session %v", session.UUID)
+	}
+	result, err := s.db.Exec(updateSql, updateArgs...)
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to update AI session %v", session.UUID)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+	insertSql, insertArgs, err := sq.
+		Insert("ai_sessions").
+		Columns(aiSessionColumns...).
+		Values(session.UUID, session.UserID, session.Namespace, session.Mode, session.Timezone,
+			session.CreatedAtInSec, session.UpdatedAtInSec, 0, session.Messages, session.PendingConfirmation).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to insert AI session %v", session.UUID)
+	}
+	if _, err := s.db.Exec(insertSql, insertArgs...); err != nil {
+		return util.NewInternalServerError(err, "Failed to insert AI session %v", session.UUID)
+	}
+	return nil
+}
+
+// GetSession returns the stored session snapshot. Soft-deleted rows are
+// returned too — the restore flow needs them — with DeletedAtInSec set.
+func (s *AISessionStore) GetSession(sessionId string) (*model.AISession, error) {
+	querySql, args, err := sq.
+		Select(aiSessionColumns...).
+		From("ai_sessions").
+		Where(sq.Eq{"UUID": sessionId}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to create query to get AI session %v", sessionId)
+	}
+	rows, err := s.db.Query(querySql, args...)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to get AI session %v", sessionId)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to get AI session %v", sessionId)
+		}
+		return nil, util.NewResourceNotFoundError("Session", sessionId)
+	}
+	var session model.AISession
+	if err := rows.Scan(&session.UUID, &session.UserID, &session.Namespace, &session.Mode, &session.Timezone,
+		&session.CreatedAtInSec, &session.UpdatedAtInSec, &session.DeletedAtInSec, &session.Messages, &session.PendingConfirmation); err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to scan AI session %v", sessionId)
+	}
+	return &session, nil
+}
+
+// ListSessions returns the user's live stored sessions, most recently
+// updated first. Soft-deleted sessions are excluded.
+func (s *AISessionStore) ListSessions(userId string) ([]*model.AISession, error) {
+	return s.listSessions(userId, sq.Eq{"UserID": userId, "DeletedAtInSec": 0})
+}
+
+// ListDeletedSessions returns the user's soft-deleted sessions that have
+// not been purged yet, most recently updated first.
+func (s *AISessionStore) ListDeletedSessions(userId string) ([]*model.AISession, error) {
+	return s.listSessions(userId, sq.And{sq.Eq{"UserID": userId}, sq.Gt{"DeletedAtInSec": 0}})
+}
+
+func (s *AISessionStore) listSessions(userId string, where sq.Sqlizer) ([]*model.AISession, error) {
+	querySql, args, err := sq.
+		Select(aiSessionColumns...).
+		From("ai_sessions").
+		Where(where).
+		OrderBy("UpdatedAtInSec DESC").
+		ToSql()
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to create query to list AI sessions of user %v", userId)
+	}
+	rows, err := s.db.Query(querySql, args...)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI sessions of user %v", userId)
+	}
+	defer rows.Close()
+	sessions := []*model.AISession{}
+	for rows.Next() {
+		var session model.AISession
+		if err := rows.Scan(&session.UUID, &session.UserID, &session.Namespace, &session.Mode, &session.Timezone,
+			&session.CreatedAtInSec, &session.UpdatedAtInSec, &session.DeletedAtInSec, &session.Messages, &session.PendingConfirmation); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to scan an AI session of user %v", userId)
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI sessions of user %v", userId)
+	}
+	return sessions, nil
+}
+
+// DeleteSession removes the stored session. Deleting a session that is
+// not stored is not an error.
+func (s *AISessionStore) DeleteSession(sessionId string) error {
+	deleteSql, args, err := sq.
+		Delete("ai_sessions").
+		Where(sq.Eq{"UUID": sessionId}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to delete AI session %v", sessionId)
+	}
+	if _, err := s.db.Exec(deleteSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to delete AI session %v", sessionId)
+	}
+	return nil
+}
+
+// SoftDeleteSession marks the stored session deleted without removing
+// the row, so the owner can restore it until the retention elapses.
+// Marking a session that is not stored or already marked is not an
+// error.
+func (s *AISessionStore) SoftDeleteSession(sessionId string, deletedAtInSec int64) error {
+	updateSql, args, err := sq.
+		Update("ai_sessions").
+		Set("DeletedAtInSec", deletedAtInSec).
+		Where(sq.Eq{"UUID": sessionId, "DeletedAtInSec": 0}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to soft-delete AI session %v", sessionId)
+	}
+	if _, err := s.db.Exec(updateSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to soft-delete AI session %v", sessionId)
+	}
+	return nil
+}
+
+// RestoreSession clears the soft-delete marker of a stored session.
+func (s *AISessionStore) RestoreSession(sessionId string) error {
+	updateSql, args, err := sq.
+		Update("ai_sessions").
+		Set("DeletedAtInSec", 0).
+		Where(sq.Eq{"UUID": sessionId}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to restore AI session %v", sessionId)
+	}
+	if _, err := s.db.Exec(updateSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to restore AI session %v", sessionId)
+	}
+	return nil
+}
+
+// PurgeDeletedSessions removes soft-deleted sessions whose marker is at
+// or before the cutoff, ending their restore window.
+func (s *AISessionStore) PurgeDeletedSessions(deletedBeforeInSec int64) error {
+	deleteSql, args, err := sq.
+		Delete("ai_sessions").
+		Where(sq.And{sq.Gt{"DeletedAtInSec": 0}, sq.LtOrEq{"DeletedAtInSec": deletedBeforeInSec}}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to purge deleted AI sessions")
+	}
+	if _, err := s.db.Exec(deleteSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to purge deleted AI sessions")
+	}
+	return nil
+}
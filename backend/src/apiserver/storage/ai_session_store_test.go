@@ -0,0 +1,201 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAISession() *model.AISession {
+	return &model.AISession{
+		UUID:           "session-1",
+		UserID:         "user@example.com",
+		Namespace:      "team-a",
+		Mode:           "suggest",
+		Timezone:       "UTC",
+		CreatedAtInSec: 100,
+		UpdatedAtInSec: 100,
+		Messages:       `[{"role":"user","content":"list my runs"}]`,
+	}
+}
+
+func TestSaveAndGetAISession(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	session := testAISession()
+	err := store.SaveSession(session)
+	assert.Nil(t, err)
+
+	stored, err := store.GetSession("session-1")
+	assert.Nil(t, err)
+	assert.Equal(t, session, stored)
+}
+
+func TestSaveAISession_UpdatesExistingRow(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	session := testAISession()
+	err := store.SaveSession(session)
+	assert.Nil(t, err)
+
+	session.Messages = `[{"role":"user","content":"list my runs"},{"role":"assistant","content":"here they are"}]`
+	session.UpdatedAtInSec = 200
+	err = store.SaveSession(session)
+	assert.Nil(t, err)
+
+	stored, err := store.GetSession("session-1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(200), stored.UpdatedAtInSec)
+	assert.Equal(t, session.Messages, stored.Messages)
+}
+
+func TestGetAISession_NotFound(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	_, err := store.GetSession("missing")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestListAISessions(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	older := testAISession()
+	require.Nil(t, store.SaveSession(older))
+	newer := testAISession()
+	newer.UUID = "session-2"
+	newer.UpdatedAtInSec = 300
+	require.Nil(t, store.SaveSession(newer))
+	other := testAISession()
+	other.UUID = "session-3"
+	other.UserID = "someone-else@example.com"
+	require.Nil(t, store.SaveSession(other))
+
+	sessions, err := store.ListSessions("user@example.com")
+	assert.Nil(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "session-2", sessions[0].UUID)
+	assert.Equal(t, "session-1", sessions[1].UUID)
+
+	sessions, err = store.ListSessions("nobody@example.com")
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestDeleteAISession(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	err := store.SaveSession(testAISession())
+	assert.Nil(t, err)
+	err = store.DeleteSession("session-1")
+	assert.Nil(t, err)
+	_, err = store.GetSession("session-1")
+	assert.NotNil(t, err)
+
+	// Deleting a session that is not stored is not an error.
+	err = store.DeleteSession("session-1")
+	assert.Nil(t, err)
+}
+
+func TestSoftDeleteAndRestoreAISession(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	require.Nil(t, store.SaveSession(testAISession()))
+	err := store.SoftDeleteSession("session-1", 500)
+	assert.Nil(t, err)
+
+	// The row survives with the marker set, but leaves the live listing.
+	stored, err := store.GetSession("session-1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), stored.DeletedAtInSec)
+	live, err := store.ListSessions("user@example.com")
+	assert.Nil(t, err)
+	assert.Empty(t, live)
+	deleted, err := store.ListDeletedSessions("user@example.com")
+	assert.Nil(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "session-1", deleted[0].UUID)
+
+	// A second soft delete keeps the original deletion time.
+	err = store.SoftDeleteSession("session-1", 900)
+	assert.Nil(t, err)
+	stored, err = store.GetSession("session-1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), stored.DeletedAtInSec)
+
+	err = store.RestoreSession("session-1")
+	assert.Nil(t, err)
+	live, err = store.ListSessions("user@example.com")
+	assert.Nil(t, err)
+	require.Len(t, live, 1)
+	assert.Zero(t, live[0].DeletedAtInSec)
+}
+
+func TestSaveAISession_ClearsTheSoftDeleteMarker(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	session := testAISession()
+	require.Nil(t, store.SaveSession(session))
+	require.Nil(t, store.SoftDeleteSession("session-1", 500))
+
+	require.Nil(t, store.SaveSession(session))
+	stored, err := store.GetSession("session-1")
+	assert.Nil(t, err)
+	assert.Zero(t, stored.DeletedAtInSec)
+}
+
+func TestPurgeDeletedAISessions(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAISessionStore(db)
+
+	old := testAISession()
+	require.Nil(t, store.SaveSession(old))
+	recent := testAISession()
+	recent.UUID = "session-2"
+	require.Nil(t, store.SaveSession(recent))
+	live := testAISession()
+	live.UUID = "session-3"
+	require.Nil(t, store.SaveSession(live))
+	require.Nil(t, store.SoftDeleteSession("session-1", 100))
+	require.Nil(t, store.SoftDeleteSession("session-2", 900))
+
+	err := store.PurgeDeletedSessions(500)
+	assert.Nil(t, err)
+	_, err = store.GetSession("session-1")
+	assert.NotNil(t, err)
+	_, err = store.GetSession("session-2")
+	assert.Nil(t, err)
+	_, err = store.GetSession("session-3")
+	assert.Nil(t, err)
+}
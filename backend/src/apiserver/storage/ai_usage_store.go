@@ -0,0 +1,118 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/kubeflow/pipelines/backend/src/common/util"
+)
+
+// Listing bounds. Exports aggregate over long windows, so the pages are
+// larger than the audit log's.
+const (
+	defaultAIUsagePageSize = 1000
+	maxAIUsagePageSize     = 100000
+)
+
+var aiUsageColumns = []string{
+	"UUID", "UserID", "Namespace", "SessionUUID", "InputTokens", "OutputTokens", "Model", "CreatedAtInSec",
+}
+
+type AIUsageStoreInterface interface {
+	AddRecord(record *model.AIUsageRecord) error
+	ListRecords(query *model.AIUsageQuery) ([]*model.AIUsageRecord, error)
+}
+
+// AIUsageStore persists the AI assistant's per-invocation token
+// consumption. Records are append-only; nothing updates or deletes them
+// through this store.
+type AIUsageStore struct {
+	db *DB
+}
+
+// NewAIUsageStore creates the store on the shared database.
+func NewAIUsageStore(db *DB) *AIUsageStore {
+	return &AIUsageStore{db: db}
+}
+
+// AddRecord appends one usage record.
+func (s *AIUsageStore) AddRecord(record *model.AIUsageRecord) error {
+	insertSql, args, err := sq.
+		Insert("ai_usage_records").
+		Columns(aiUsageColumns...).
+		Values(record.UUID, record.UserID, record.Namespace, record.SessionUUID,
+			record.InputTokens, record.OutputTokens, record.Model, record.CreatedAtInSec).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err, "Failed to create query to insert AI usage record %v", record.UUID)
+	}
+	if _, err := s.db.Exec(insertSql, args...); err != nil {
+		return util.NewInternalServerError(err, "Failed to insert AI usage record %v", record.UUID)
+	}
+	return nil
+}
+
+// ListRecords returns matching records oldest-first, the order a
+// chargeback export wants them in.
+func (s *AIUsageStore) ListRecords(query *model.AIUsageQuery) ([]*model.AIUsageRecord, error) {
+	builder := sq.
+		Select(aiUsageColumns...).
+		From("ai_usage_records")
+	if query.UserID != "" {
+		builder = builder.Where(sq.Eq{"UserID": query.UserID})
+	}
+	if query.Namespace != "" {
+		builder = builder.Where(sq.Eq{"Namespace": query.Namespace})
+	}
+	if query.SinceInSec > 0 {
+		builder = builder.Where(sq.GtOrEq{"CreatedAtInSec": query.SinceInSec})
+	}
+	if query.UntilInSec > 0 {
+		builder = builder.Where(sq.Lt{"CreatedAtInSec": query.UntilInSec})
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAIUsagePageSize
+	}
+	if limit > maxAIUsagePageSize {
+		limit = maxAIUsagePageSize
+	}
+	querySql, args, err := builder.
+		OrderBy("CreatedAtInSec ASC", "UUID ASC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to create query to list AI usage records")
+	}
+	rows, err := s.db.Query(querySql, args...)
+	if err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI usage records")
+	}
+	defer rows.Close()
+	records := []*model.AIUsageRecord{}
+	for rows.Next() {
+		var record model.AIUsageRecord
+		if err := rows.Scan(&record.UUID, &record.UserID, &record.Namespace, &record.SessionUUID,
+			&record.InputTokens, &record.OutputTokens, &record.Model, &record.CreatedAtInSec); err != nil {
+			return nil, util.NewInternalServerError(err, "Failed to scan an AI usage record")
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.NewInternalServerError(err, "Failed to list AI usage records")
+	}
+	return records, nil
+}
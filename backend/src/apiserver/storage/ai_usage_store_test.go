@@ -0,0 +1,110 @@
+// Copyright 2025 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/kubeflow/pipelines/backend/src/apiserver/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAIUsageRecord(uuid string, createdAt int64) *model.AIUsageRecord {
+	return &model.AIUsageRecord{
+		UUID:           uuid,
+		UserID:         "user@example.com",
+		Namespace:      "team-a",
+		SessionUUID:    "session-1",
+		InputTokens:    120,
+		OutputTokens:   40,
+		Model:          "claude-3-5-sonnet",
+		CreatedAtInSec: createdAt,
+	}
+}
+
+func TestAddAndListAIUsageRecords(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIUsageStore(db)
+
+	record := testAIUsageRecord("record-1", 100)
+	require.Nil(t, store.AddRecord(record))
+
+	records, err := store.ListRecords(&model.AIUsageQuery{})
+	assert.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, record, records[0])
+}
+
+func TestListAIUsageRecords_OldestFirst(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIUsageStore(db)
+
+	require.Nil(t, store.AddRecord(testAIUsageRecord("record-1", 300)))
+	require.Nil(t, store.AddRecord(testAIUsageRecord("record-2", 100)))
+	require.Nil(t, store.AddRecord(testAIUsageRecord("record-3", 200)))
+
+	records, err := store.ListRecords(&model.AIUsageQuery{})
+	assert.Nil(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "record-2", records[0].UUID)
+	assert.Equal(t, "record-3", records[1].UUID)
+	assert.Equal(t, "record-1", records[2].UUID)
+}
+
+func TestListAIUsageRecords_FiltersAndWindow(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIUsageStore(db)
+
+	mine := testAIUsageRecord("record-1", 100)
+	require.Nil(t, store.AddRecord(mine))
+	theirs := testAIUsageRecord("record-2", 200)
+	theirs.UserID = "other@example.com"
+	theirs.Namespace = "team-b"
+	require.Nil(t, store.AddRecord(theirs))
+
+	records, err := store.ListRecords(&model.AIUsageQuery{UserID: "user@example.com"})
+	assert.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "record-1", records[0].UUID)
+
+	records, err = store.ListRecords(&model.AIUsageQuery{Namespace: "team-b"})
+	assert.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "record-2", records[0].UUID)
+
+	// The window is inclusive below and exclusive above.
+	records, err = store.ListRecords(&model.AIUsageQuery{SinceInSec: 100, UntilInSec: 200})
+	assert.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "record-1", records[0].UUID)
+}
+
+func TestListAIUsageRecords_Limit(t *testing.T) {
+	db := NewFakeDBOrFatal()
+	defer db.Close()
+	store := NewAIUsageStore(db)
+
+	require.Nil(t, store.AddRecord(testAIUsageRecord("record-1", 100)))
+	require.Nil(t, store.AddRecord(testAIUsageRecord("record-2", 200)))
+
+	records, err := store.ListRecords(&model.AIUsageQuery{Limit: 1})
+	assert.Nil(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "record-1", records[0].UUID)
+}
@@ -40,6 +40,9 @@ func NewFakeDB() (*DB, error) {
 		&model.Task{},
 		&model.DBStatus{},
 		&model.DefaultExperiment{},
+		&model.AISession{},
+		&model.AIAuditEvent{},
+		&model.AIUsageRecord{},
 	); err != nil {
 		return nil, util.Wrap(err, "Failed to automigrate models")
 	}
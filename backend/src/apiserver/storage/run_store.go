@@ -81,6 +81,9 @@ type RunStoreInterface interface {
 	// Note: only state, runtime manifest can be updated. Does not update dependent tasks.
 	UpdateRun(run *model.Run) (err error)
 
+	// Replaces a run's description.
+	UpdateRunDescription(runId string, description string) error
+
 	// Archives a run.
 	ArchiveRun(runId string) error
 
@@ -607,6 +610,31 @@ func (s *RunStore) UpdateRun(run *model.Run) error {
 	return nil
 }
 
+func (s *RunStore) UpdateRunDescription(runId string, description string) error {
+	sql, args, err := sq.
+		Update("run_details").
+		SetMap(sq.Eq{
+			"Description": description,
+		}).
+		Where(sq.Eq{"UUID": runId}).
+		ToSql()
+	if err != nil {
+		return util.NewInternalServerError(err,
+			"Failed to create query to update the description of run %s. error: '%v'", runId, err.Error())
+	}
+
+	// No RowsAffected check: MySQL reports 0 affected rows when the new
+	// description equals the stored one, so it cannot distinguish a
+	// missing run from a no-op update. Existence is the caller's concern,
+	// as for ArchiveRun.
+	_, err = s.db.Exec(sql, args...)
+	if err != nil {
+		return util.NewInternalServerError(err,
+			"Failed to update the description of run %s. error: '%v'", runId, err.Error())
+	}
+	return nil
+}
+
 func (s *RunStore) ArchiveRun(runId string) error {
 	sql, args, err := sq.
 		Update("run_details").
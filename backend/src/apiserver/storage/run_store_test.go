@@ -1202,6 +1202,32 @@ func TestListRuns_WithMetrics(t *testing.T) {
 	assert.Equal(t, expectedRuns, runs, "Unexpected Run listed")
 }
 
+func TestUpdateRunDescription(t *testing.T) {
+	db, runStore := initializeRunStore()
+	defer db.Close()
+
+	err := runStore.UpdateRunDescription("1", "diagnosed: OOM in the training step")
+	assert.Nil(t, err)
+	run, err := runStore.GetRun("1")
+	assert.Nil(t, err)
+	assert.Equal(t, "diagnosed: OOM in the training step", run.Description)
+}
+
+func TestUpdateRunDescription_SameValueIsIdempotent(t *testing.T) {
+	db, runStore := initializeRunStore()
+	defer db.Close()
+
+	// Re-applying the stored description succeeds even though the UPDATE
+	// affects no rows on MySQL.
+	err := runStore.UpdateRunDescription("1", "diagnosed: OOM in the training step")
+	assert.Nil(t, err)
+	err = runStore.UpdateRunDescription("1", "diagnosed: OOM in the training step")
+	assert.Nil(t, err)
+	run, err := runStore.GetRun("1")
+	assert.Nil(t, err)
+	assert.Equal(t, "diagnosed: OOM in the training step", run.Description)
+}
+
 func TestArchiveRun(t *testing.T) {
 	db, runStore := initializeRunStore()
 	defer db.Close()